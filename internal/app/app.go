@@ -2,35 +2,97 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
 
 	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/api/proto/v1"
+	handlerDeliverContact "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/deliver_contact"
+	handlerDeliverCvEmail "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/deliver_cv_email"
+	handlerDeliverWebhook "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/deliver_webhook"
 	handlerDowloadCv "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/download_cv"
+	handlerGetAsset "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/get_asset"
 	handlerGetContent "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/get_content"
+	handlerGetCvStamp "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/get_cv_stamp"
 	handlerGetCvToken "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/get_cv_token"
+	handlerGetExperimentResults "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/get_experiment_results"
+	handlerGetStats "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/get_stats"
+	handlerGetStatsJson "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/get_stats_json"
+	handlerManageAccessRequests "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/manage_access_requests"
+	handlerManageCvLockouts "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/manage_cv_lockouts"
+	handlerManageCvTokens "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/manage_cv_tokens"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/middleware"
+	handlerQueryAuditEvents "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/query_audit_events"
+	handlerRequestCvToken "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/request_cv_token"
+	handlerSubmitAccessRequest "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/submit_access_request"
+	handlerSubmitContact "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/submit_contact"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/credential"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	processDeliverContact "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/deliver_contact"
+	processDeliverCvEmail "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/deliver_cv_email"
+	processDeliverWebhook "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/deliver_webhook"
 	processDownloadCv "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/download_cv"
+	taskDownloadCv "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/download_cv/task"
+	processGetAsset "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_asset"
 	processGetContent "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_content"
+	processGetCvStamp "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_cv_stamp"
 	processGetCvToken "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_cv_token"
 	taskGetCvToken "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_cv_token/task"
+	processGetExperimentResults "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_experiment_results"
+	processGetStats "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_stats"
+	processManageAccessRequests "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/manage_access_requests"
+	processManageCvLockouts "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/manage_cv_lockouts"
+	processManageCvTokens "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/manage_cv_tokens"
+	processQueryAuditEvents "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/query_audit_events"
+	processSubmitAccessRequest "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/submit_access_request"
+	taskSubmitAccessRequest "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/submit_access_request/task"
+	processSubmitContact "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/submit_contact"
+	taskSubmitContact "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/submit_contact/task"
 	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/registry"
+	serviceAlert "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/alert"
+	serviceAudit "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/audit"
+	serviceDiskcache "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/diskcache"
+	serviceOutbox "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/outbox"
 	serviceRabbitmq "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/rabbitmq"
 	serviceRedis "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/redis"
+	serviceSmtp "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/smtp"
+	serviceStats "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/stats"
+	serviceWebhook "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/webhook"
 )
 
+type outboxWorker struct {
+	outbox  *serviceOutbox.Outbox
+	deliver serviceOutbox.DeliverFunc
+}
+
 type App struct {
-	grpcServer      *grpc.Server
-	httpServer      *http.Server
-	rabbitBroker    *serviceRabbitmq.Broker
-	cancelConsumers context.CancelFunc
+	grpcServer          *grpc.Server
+	httpServer          *http.Server
+	rabbitBroker        *serviceRabbitmq.Broker
+	outboxWorkers       []outboxWorker
+	auditStore          serviceAudit.Store
+	auditJanitor        *serviceAudit.Janitor
+	cancelConsumers     context.CancelFunc
+	cancelOutboxWorkers context.CancelFunc
+	cancelAuditJanitor  context.CancelFunc
 }
 
 func Build(cfg *registry.Config) (*App, error) {
+	if cfg.Env == registry.EnvProduction {
+		for name, site := range cfg.Sites {
+			if err := credential.RequireHashed(site.Cv.Credentials); err != nil {
+				return nil, fmt.Errorf("site %s: %w, generate a hash with the hash-password command", name, err)
+			}
+		}
+	}
+
 	maxRetries := cfg.Infrastructure.Retry.MaxAttempts
 	retryDelay := cfg.Infrastructure.Retry.DelaySeconds
 	var err error
@@ -68,22 +130,140 @@ func Build(cfg *registry.Config) (*App, error) {
 		return nil, err
 	}
 
-	getContentProcess, err := processGetContent.NewProcess(cfg.Content.Files, cfg.Content.DefaultLang)
+	assetCache, err := serviceDiskcache.NewCache(cfg.Assets.CacheDir)
 	if err != nil {
 		return nil, err
 	}
 
-	verifyCaptchaTask := taskGetCvToken.NewVerifyCaptchaTask(redisClient)
-	validatePasswordTask := taskGetCvToken.NewValidatePasswordTask(cfg.Cv.Password, redisClient, cfg.Captcha.TtlMinutes)
-	deleteCaptchaTask := taskGetCvToken.NewDeleteCaptchaTask(redisClient)
-	createTokenTask := taskGetCvToken.NewCreateTokenTask(redisClient, cfg.Cv.TokenTTL)
-	getCvTokenProcess := processGetCvToken.NewProcess(verifyCaptchaTask, validatePasswordTask, deleteCaptchaTask, createTokenTask, cfg.Cv.Files)
+	var auditStore serviceAudit.Store
+	switch cfg.Audit.Storage {
+	case audit.StorageFile:
+		auditStore, err = serviceAudit.NewFileStore(cfg.Audit.Path)
+	case audit.StorageSQLite:
+		auditStore, err = serviceAudit.NewSQLiteStore(cfg.Audit.Path)
+	default:
+		auditStore = serviceAudit.NewStreamStore(redisClient)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("audit storage %s: %w", cfg.Audit.Storage, err)
+	}
+
+	getContentProcesses := make(map[string]handlerGetContent.GetContentProcess, len(cfg.Sites))
+	getExperimentResultsProcesses := make(map[string]handlerGetExperimentResults.GetExperimentResultsProcess, len(cfg.Sites))
+	getCvTokenSites := make(map[string]handlerGetCvToken.Site, len(cfg.Sites))
+	requestCvTokenProcesses := make(map[string]handlerRequestCvToken.GetCVTokenProcess, len(cfg.Sites))
+	downloadCvSites := make(map[string]handlerDowloadCv.Site, len(cfg.Sites))
+	getStatsProcesses := make(map[string]handlerGetStats.GetStatsProcess, len(cfg.Sites))
+	getStatsJsonProcesses := make(map[string]handlerGetStatsJson.GetStatsProcess, len(cfg.Sites))
+	manageCvTokensProcesses := make(map[string]handlerManageCvTokens.ManageCVTokensProcess, len(cfg.Sites))
+	manageCvLockoutsProcesses := make(map[string]handlerManageCvLockouts.ManageCVLockoutsProcess, len(cfg.Sites))
+	getCvStampProcesses := make(map[string]handlerGetCvStamp.GetCVStampProcess, len(cfg.Sites))
+	queryAuditEventsProcesses := make(map[string]handlerQueryAuditEvents.QueryAuditEventsProcess, len(cfg.Sites))
+	getAssetProcesses := make(map[string]handlerGetAsset.GetAssetProcess, len(cfg.Sites))
+	submitContactProcesses := make(map[string]handlerSubmitContact.SubmitContactProcess, len(cfg.Sites))
+	submitAccessRequestProcesses := make(map[string]handlerSubmitAccessRequest.SubmitAccessRequestProcess, len(cfg.Sites))
+	manageAccessRequestsProcesses := make(map[string]handlerManageAccessRequests.ManageAccessRequestsProcess, len(cfg.Sites))
+	contactOutbox := serviceOutbox.NewOutbox(redisClient, "contact", cfg.Outbox.MaxAttempts, cfg.Outbox.RetryDelay)
+	cvEmailOutbox := serviceOutbox.NewOutbox(redisClient, "cv_email", cfg.Outbox.MaxAttempts, cfg.Outbox.RetryDelay)
+	cvEmailSites := make(map[string]processDeliverCvEmail.Site, len(cfg.Sites))
+	webhookOutbox := serviceOutbox.NewOutbox(redisClient, "webhook", cfg.Outbox.MaxAttempts, cfg.Outbox.RetryDelay)
+	for name, site := range cfg.Sites {
+		siteStore := redisClient.WithPrefix("site:" + name + ":")
+		statsRecorder := serviceStats.NewRecorder(siteStore, cfg.Stats.RetentionDays)
+		auditRecorder := serviceAudit.NewRecorder(auditStore, redisClient, name)
+		notifier := serviceWebhook.NewNotifier(webhookOutbox, cfg.Webhooks.Endpoints, name)
+
+		getAssetProcess, err := processGetAsset.NewProcess(site.Content.AssetsDir, cfg.Assets.Widths, assetCache)
+		if err != nil {
+			return nil, fmt.Errorf("site %s: %w", name, err)
+		}
+		getAssetProcesses[name] = getAssetProcess
+
+		getContentProcess, err := processGetContent.NewProcess(site.Content.Files, site.Content.DefaultLang, getAssetProcess, siteStore, statsRecorder)
+		if err != nil {
+			return nil, fmt.Errorf("site %s: %w", name, err)
+		}
+		getContentProcesses[name] = getContentProcess
+		getExperimentResultsProcesses[name] = processGetExperimentResults.NewProcess(getContentProcess.Experiments(), siteStore)
+
+		verifyCaptchaTask := taskGetCvToken.NewVerifyCaptchaTask(redisClient)
+		validatePasswordTask := taskGetCvToken.NewValidatePasswordTask(site.Cv.Credentials, redisClient, siteStore)
+		redeemCaptchaTask := taskGetCvToken.NewRedeemCaptchaTask(redisClient)
+		newCreateTokenTask := func(ttl time.Duration) processGetCvToken.CreateTokenTask {
+			if site.Cv.TokenMode == cvtoken.ModeSigned {
+				return taskGetCvToken.NewCreateSignedTokenTask(site.Cv.SigningKeys[0], name, ttl)
+			}
+			return taskGetCvToken.NewCreateTokenTask(siteStore, ttl, site.Cv.MaxDownloads)
+		}
+		createTokenTask := newCreateTokenTask(site.Cv.TokenTTL)
+		rateLimitTask := taskGetCvToken.NewRateLimitTask(siteStore, site.Cv.RateLimit.PerIP, site.Cv.RateLimit.PerCaptcha, site.Cv.RateLimit.Global)
+		verifyPowTask := taskGetCvToken.NewVerifyPowTask(site.Cv.Pow.Secret, site.Cv.Pow.Difficulty, siteStore)
+		lockoutTask := taskGetCvToken.NewLockoutTask(siteStore, serviceAlert.NewLogAlerter(name), site.Cv.Lockout)
+		emailLinkTask := taskGetCvToken.NewEmailLinkTask(cvEmailOutbox, name, site.Cv.Email.Enabled, site.Cv.Email.AllowedDomains)
+		if site.Cv.Email.Enabled {
+			cvEmailSites[name] = processDeliverCvEmail.Site{ContentFiles: site.Content.Files, LinkURL: site.Cv.Email.LinkURL}
+		}
+		getCvTokenProcess, err := processGetCvToken.NewProcess(site.Cv.Pipeline, rateLimitTask, lockoutTask, verifyPowTask, verifyCaptchaTask, validatePasswordTask, redeemCaptchaTask, createTokenTask, emailLinkTask, newCreateTokenTask(site.Cv.Email.TokenTTL), site.Cv.Files, statsRecorder, auditRecorder, notifier)
+		if err != nil {
+			return nil, fmt.Errorf("site %s: %w", name, err)
+		}
+		getCvTokenSites[name] = handlerGetCvToken.Site{Process: getCvTokenProcess, ReplyCache: siteStore}
+		requestCvTokenProcesses[name] = getCvTokenProcess
+
+		downloadCvSites[name] = handlerDowloadCv.Site{
+			Process: processDownloadCv.NewProcess(
+				taskDownloadCv.NewConsumeStoredTokenTask(siteStore, site.Cv.DownloadGrace),
+				taskDownloadCv.NewConsumeSignedTokenTask(name, site.Cv.SigningKeys, siteStore, siteStore, site.Cv.DownloadGrace),
+				taskDownloadCv.NewStampPdfTask(siteStore, site.Cv.Watermark.Enabled, site.Cv.Watermark.Retention),
+				site.Cv.Files,
+				statsRecorder,
+				auditRecorder,
+				notifier,
+			),
+			DownloadName: site.Cv.DownloadName,
+		}
+
+		getStatsProcess := processGetStats.NewProcess(statsRecorder)
+		getStatsProcesses[name] = getStatsProcess
+		getStatsJsonProcesses[name] = getStatsProcess
+		manageCvTokensProcesses[name] = processManageCvTokens.NewProcess(siteStore, auditRecorder, site.Cv.TokenMode, max(site.Cv.TokenTTL, site.Cv.Email.TokenTTL, site.Cv.AccessRequests.TokenTTL))
+		manageCvLockoutsProcesses[name] = processManageCvLockouts.NewProcess(siteStore, auditRecorder)
+		getCvStampProcesses[name] = processGetCvStamp.NewProcess(siteStore)
+		queryAuditEventsProcesses[name] = processQueryAuditEvents.NewProcess(auditRecorder)
+
+		contactRateLimitTask := taskSubmitContact.NewRateLimitTask(siteStore, cfg.Contact.RateLimit.Window, cfg.Contact.RateLimit.PerCaptcha, cfg.Contact.RateLimit.PerEmail)
+		enqueueMessageTask := taskSubmitContact.NewEnqueueMessageTask(contactOutbox, name)
+		submitContactProcesses[name] = processSubmitContact.NewProcess(verifyCaptchaTask, contactRateLimitTask, enqueueMessageTask)
+
+		storeRequestTask := taskSubmitAccessRequest.NewStoreRequestTask(siteStore, site.Cv.AccessRequests.TTL)
+		submitAccessRequestProcesses[name] = processSubmitAccessRequest.NewProcess(site.Cv.AccessRequests.Enabled, redeemCaptchaTask, emailLinkTask, storeRequestTask, site.Cv.Files, auditRecorder, notifier)
+		manageAccessRequestsProcesses[name] = processManageAccessRequests.NewProcess(siteStore, newCreateTokenTask(site.Cv.AccessRequests.TokenTTL), emailLinkTask, site.Cv.Files, auditRecorder, notifier)
+	}
 
-	downloadCvProcess := processDownloadCv.NewProcess(redisClient, cfg.Cv.Files)
+	smtpSender := serviceSmtp.NewSender(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From)
+	deliverContactHandler := handlerDeliverContact.NewHandler(processDeliverContact.NewProcess(smtpSender, cfg.Contact.Recipients))
+	deliverCvEmailProcess, err := processDeliverCvEmail.NewProcess(smtpSender, cvEmailSites)
+	if err != nil {
+		return nil, err
+	}
+	deliverCvEmailHandler := handlerDeliverCvEmail.NewHandler(deliverCvEmailProcess)
+	deliverWebhookHandler := handlerDeliverWebhook.NewHandler(processDeliverWebhook.NewProcess(serviceWebhook.NewSender(cfg.Webhooks.Timeout), cfg.Webhooks.Endpoints))
 
-	getContentHandler := handlerGetContent.NewHandler(getContentProcess)
-	downloadCvHandler := handlerDowloadCv.NewHandler(downloadCvProcess)
-	getCvTokenHandler := handlerGetCvToken.NewHandler(getCvTokenProcess)
+	getContentHandler := handlerGetContent.NewHandler(getContentProcesses, cfg.DefaultSite)
+	getExperimentResultsHandler := handlerGetExperimentResults.NewHandler(getExperimentResultsProcesses, cfg.DefaultSite)
+	downloadCvHandler := handlerDowloadCv.NewHandler(downloadCvSites, cfg.DefaultSite)
+	getCvTokenHandler := handlerGetCvToken.NewHandler(getCvTokenSites, cfg.DefaultSite, cfg.Cv.ReplyWindow)
+	requestCvTokenHandler := handlerRequestCvToken.NewHandler(requestCvTokenProcesses, cfg.DefaultSite)
+	getStatsHandler := handlerGetStats.NewHandler(getStatsProcesses, cfg.DefaultSite)
+	getStatsJsonHandler := handlerGetStatsJson.NewHandler(getStatsJsonProcesses, cfg.DefaultSite)
+	manageCvTokensHandler := handlerManageCvTokens.NewHandler(manageCvTokensProcesses, cfg.DefaultSite)
+	manageCvLockoutsHandler := handlerManageCvLockouts.NewHandler(manageCvLockoutsProcesses, cfg.DefaultSite)
+	getCvStampHandler := handlerGetCvStamp.NewHandler(getCvStampProcesses, cfg.DefaultSite)
+	queryAuditEventsHandler := handlerQueryAuditEvents.NewHandler(queryAuditEventsProcesses, cfg.DefaultSite)
+	getAssetHandler := handlerGetAsset.NewHandler(getAssetProcesses, cfg.DefaultSite)
+	submitContactHandler := handlerSubmitContact.NewHandler(submitContactProcesses, cfg.DefaultSite)
+	submitAccessRequestHandler := handlerSubmitAccessRequest.NewHandler(submitAccessRequestProcesses, cfg.DefaultSite)
+	manageAccessRequestsHandler := handlerManageAccessRequests.NewHandler(manageAccessRequestsProcesses, cfg.DefaultSite)
 
 	consumerCount := cfg.RabbitMQ.Consumers.DefaultCount
 	if consumerCount <= 0 {
@@ -91,12 +271,32 @@ func Build(cfg *registry.Config) (*App, error) {
 	}
 
 	rabbitBroker.RegisterConsumer(cfg.RabbitMQ.Topology.Queues["cv_requests"].Name, consumerCount, getCvTokenHandler.Handle)
+	rabbitBroker.RegisterConsumer(cfg.RabbitMQ.Topology.Queues["contact_requests"].Name, consumerCount, submitContactHandler.Handle)
+	rabbitBroker.RegisterConsumer(cfg.RabbitMQ.Topology.Queues["access_requests"].Name, consumerCount, submitAccessRequestHandler.Handle)
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(middleware.RequireAdminTokenRPC(
+		cfg.Admin.Token,
+		contentv1.StatsService_ServiceDesc.ServiceName,
+		contentv1.AuditService_ServiceDesc.ServiceName,
+	)))
 	contentv1.RegisterContentServiceServer(grpcServer, getContentHandler)
+	contentv1.RegisterExperimentServiceServer(grpcServer, getExperimentResultsHandler)
+	contentv1.RegisterStatsServiceServer(grpcServer, getStatsHandler)
+	contentv1.RegisterAuditServiceServer(grpcServer, queryAuditEventsHandler)
+	contentv1.RegisterCvTokenServiceServer(grpcServer, requestCvTokenHandler)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/download/cv", downloadCvHandler.Handle)
+	mux.HandleFunc("/assets/", getAssetHandler.Handle)
+	mux.HandleFunc("/stats", middleware.RequireAdminToken(cfg.Admin.Token, getStatsJsonHandler.Handle))
+	mux.HandleFunc(handlerManageCvTokens.Path, middleware.RequireAdminToken(cfg.Admin.Token, manageCvTokensHandler.Handle))
+	mux.HandleFunc(handlerManageCvTokens.Path+"/", middleware.RequireAdminToken(cfg.Admin.Token, manageCvTokensHandler.Handle))
+	mux.HandleFunc(handlerManageCvLockouts.Path, middleware.RequireAdminToken(cfg.Admin.Token, manageCvLockoutsHandler.Handle))
+	mux.HandleFunc(handlerManageCvLockouts.Path+"/", middleware.RequireAdminToken(cfg.Admin.Token, manageCvLockoutsHandler.Handle))
+	mux.HandleFunc(handlerManageAccessRequests.Path, middleware.RequireAdminToken(cfg.Admin.Token, manageAccessRequestsHandler.Handle))
+	mux.HandleFunc(handlerManageAccessRequests.Path+"/", middleware.RequireAdminToken(cfg.Admin.Token, manageAccessRequestsHandler.Handle))
+	mux.HandleFunc(handlerGetCvStamp.Path, middleware.RequireAdminToken(cfg.Admin.Token, getCvStampHandler.Handle))
+	mux.HandleFunc(handlerGetCvStamp.Path+"/", middleware.RequireAdminToken(cfg.Admin.Token, getCvStampHandler.Handle))
 
 	httpServer := &http.Server{
 		Addr: ":" + cfg.Server.HTTPPort,
@@ -110,6 +310,13 @@ func Build(cfg *registry.Config) (*App, error) {
 		grpcServer:   grpcServer,
 		httpServer:   httpServer,
 		rabbitBroker: rabbitBroker,
+		outboxWorkers: []outboxWorker{
+			{outbox: contactOutbox, deliver: deliverContactHandler.Handle},
+			{outbox: cvEmailOutbox, deliver: deliverCvEmailHandler.Handle},
+			{outbox: webhookOutbox, deliver: deliverWebhookHandler.Handle},
+		},
+		auditStore:   auditStore,
+		auditJanitor: serviceAudit.NewJanitor(auditStore, redisClient, cfg.Audit.RetentionDays),
 	}, nil
 }
 
@@ -134,12 +341,46 @@ func (a *App) RunRabbitMQConsumers() error {
 	return a.rabbitBroker.Start(ctx)
 }
 
+func (a *App) RunOutboxWorkers() error {
+	log.Println("INFO: starting outbox workers")
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancelOutboxWorkers = cancel
+
+	var wg sync.WaitGroup
+	for _, w := range a.outboxWorkers {
+		wg.Add(1)
+		go func(w outboxWorker) {
+			defer wg.Done()
+			if err := w.outbox.Run(ctx, w.deliver); err != nil {
+				log.Printf("ERROR: outbox worker %s stopped: %v", w.outbox.Name(), err)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func (a *App) RunAuditJanitor() error {
+	log.Println("INFO: starting audit janitor")
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancelAuditJanitor = cancel
+	return a.auditJanitor.Run(ctx)
+}
+
 func (a *App) Shutdown(ctx context.Context) {
 	log.Println("INFO: shutting down servers...")
 	if a.cancelConsumers != nil {
 		a.cancelConsumers()
 	}
+	if a.cancelOutboxWorkers != nil {
+		a.cancelOutboxWorkers()
+	}
+	if a.cancelAuditJanitor != nil {
+		a.cancelAuditJanitor()
+	}
 	a.grpcServer.GracefulStop()
 	_ = a.httpServer.Shutdown(ctx)
 	_ = a.rabbitBroker.Shutdown()
+	_ = a.auditStore.Close()
 }
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	handlerExportTranslations "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/export_translations"
+	handlerHashPassword "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/hash_password"
+	handlerImportTranslations "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/handler/import_translations"
+	processExportTranslations "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/export_translations"
+	processHashPassword "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/hash_password"
+	processImportTranslations "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/import_translations"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/registry"
+)
+
+type CommandHandler interface {
+	Handle(ctx context.Context, args []string) error
+}
+
+func RunCommand(ctx context.Context, cfg *registry.Config, name string, args []string) error {
+	commands := map[string]func() CommandHandler{
+		"export-translations": func() CommandHandler {
+			sites := make(map[string]handlerExportTranslations.Site, len(cfg.Sites))
+			for name, site := range cfg.Sites {
+				sites[name] = handlerExportTranslations.Site{
+					Process:     processExportTranslations.NewProcess(site.Content.Files),
+					DefaultLang: site.Content.DefaultLang,
+				}
+			}
+			return handlerExportTranslations.NewHandler(sites, cfg.DefaultSite, os.Stdout)
+		},
+		"hash-password": func() CommandHandler {
+			return handlerHashPassword.NewHandler(processHashPassword.NewProcess(), os.Stdin, os.Stdout)
+		},
+		"import-translations": func() CommandHandler {
+			sites := make(map[string]handlerImportTranslations.Site, len(cfg.Sites))
+			for name, site := range cfg.Sites {
+				sites[name] = handlerImportTranslations.Site{
+					Process:     processImportTranslations.NewProcess(site.Content.Files),
+					DefaultLang: site.Content.DefaultLang,
+				}
+			}
+			return handlerImportTranslations.NewHandler(sites, cfg.DefaultSite, os.Stdout)
+		},
+	}
+
+	build, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	return build().Handle(ctx, args)
+}
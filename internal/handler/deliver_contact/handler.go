@@ -0,0 +1,29 @@
+package deliver_contact
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/contact"
+)
+
+type DeliverContactProcess interface {
+	Process(ctx context.Context, submission contact.Submission) error
+}
+
+type Handler struct {
+	deliverContactProcess DeliverContactProcess
+}
+
+func NewHandler(process DeliverContactProcess) *Handler {
+	return &Handler{deliverContactProcess: process}
+}
+
+func (h *Handler) Handle(ctx context.Context, payload json.RawMessage) error {
+	var submission contact.Submission
+	if err := json.Unmarshal(payload, &submission); err != nil {
+		return err
+	}
+
+	return h.deliverContactProcess.Process(ctx, submission)
+}
@@ -0,0 +1,51 @@
+package deliver_contact
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/contact"
+)
+
+type mockDeliverContactProcess struct {
+	submissions []contact.Submission
+}
+
+func (m *mockDeliverContactProcess) Process(ctx context.Context, submission contact.Submission) error {
+	m.submissions = append(m.submissions, submission)
+	return nil
+}
+
+func TestHandler_DeliverContact(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			payload: `{"site":"main","message":{"name":"Jan","email":"jan@example.com","message":"Hello"},"receivedAt":"2026-03-01T12:00:00Z"}`,
+		},
+		{
+			name:    "malformed payload",
+			payload: `{"site":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockDeliverContactProcess{}
+			h := NewHandler(m)
+
+			err := h.Handle(context.Background(), json.RawMessage(tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (len(m.submissions) != 1 || m.submissions[0].Message.Email != "jan@example.com") {
+				t.Errorf("Handle() submissions = %+v", m.submissions)
+			}
+		})
+	}
+}
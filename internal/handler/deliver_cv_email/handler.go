@@ -0,0 +1,29 @@
+package deliver_cv_email
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvmail"
+)
+
+type DeliverCvEmailProcess interface {
+	Process(ctx context.Context, delivery cvmail.Delivery) error
+}
+
+type Handler struct {
+	deliverCvEmailProcess DeliverCvEmailProcess
+}
+
+func NewHandler(process DeliverCvEmailProcess) *Handler {
+	return &Handler{deliverCvEmailProcess: process}
+}
+
+func (h *Handler) Handle(ctx context.Context, payload json.RawMessage) error {
+	var delivery cvmail.Delivery
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return err
+	}
+
+	return h.deliverCvEmailProcess.Process(ctx, delivery)
+}
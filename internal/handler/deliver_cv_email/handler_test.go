@@ -0,0 +1,51 @@
+package deliver_cv_email
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvmail"
+)
+
+type mockDeliverCvEmailProcess struct {
+	deliveries []cvmail.Delivery
+}
+
+func (m *mockDeliverCvEmailProcess) Process(ctx context.Context, delivery cvmail.Delivery) error {
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+
+func TestHandler_DeliverCvEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			payload: `{"site":"main","email":"anna@acme.com","lang":"en","token":"3f9a","expiresAt":"2026-03-01T13:00:00Z","requestedAt":"2026-03-01T12:00:00Z"}`,
+		},
+		{
+			name:    "malformed payload",
+			payload: `{"site":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockDeliverCvEmailProcess{}
+			h := NewHandler(m)
+
+			err := h.Handle(context.Background(), json.RawMessage(tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (len(m.deliveries) != 1 || m.deliveries[0].Email != "anna@acme.com" || m.deliveries[0].Token != "3f9a") {
+				t.Errorf("Handle() deliveries = %+v", m.deliveries)
+			}
+		})
+	}
+}
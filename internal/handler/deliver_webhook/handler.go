@@ -0,0 +1,29 @@
+package deliver_webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/webhook"
+)
+
+type DeliverWebhookProcess interface {
+	Process(ctx context.Context, delivery webhook.Delivery) error
+}
+
+type Handler struct {
+	deliverWebhookProcess DeliverWebhookProcess
+}
+
+func NewHandler(process DeliverWebhookProcess) *Handler {
+	return &Handler{deliverWebhookProcess: process}
+}
+
+func (h *Handler) Handle(ctx context.Context, payload json.RawMessage) error {
+	var delivery webhook.Delivery
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return err
+	}
+
+	return h.deliverWebhookProcess.Process(ctx, delivery)
+}
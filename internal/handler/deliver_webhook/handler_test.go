@@ -0,0 +1,52 @@
+package deliver_webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/webhook"
+)
+
+type mockDeliverWebhookProcess struct {
+	deliveries []webhook.Delivery
+}
+
+func (m *mockDeliverWebhookProcess) Process(ctx context.Context, delivery webhook.Delivery) error {
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+
+func TestHandler_DeliverWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			payload: `{"endpoint":"owner","event":{"id":"e1","time":"2026-03-01T12:00:00Z","type":"token_redeemed","site":"main"}}`,
+		},
+		{
+			name:    "malformed payload",
+			payload: `{"endpoint":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockDeliverWebhookProcess{}
+			h := NewHandler(m)
+
+			err := h.Handle(context.Background(), json.RawMessage(tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (len(m.deliveries) != 1 || m.deliveries[0].Endpoint != "owner" || m.deliveries[0].Event.Type != audit.EventTokenRedeemed) {
+				t.Errorf("Handle() deliveries = %+v", m.deliveries)
+			}
+		})
+	}
+}
@@ -1,22 +1,44 @@
 package download_cv
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
 	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processDownloadCv "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/download_cv"
+	"github.com/google/uuid"
+)
+
+const (
+	ClientHeader    = "X-Client-Fingerprint"
+	RequestIDHeader = "X-Request-ID"
 )
 
 type DownloadCVProcess interface {
-	Process(ctx context.Context, token, lang string) (string, error)
+	Process(ctx context.Context, token, lang, client string, resume bool, stampID string) (processDownloadCv.Document, error)
+}
+
+type Site struct {
+	Process      DownloadCVProcess
+	DownloadName string
 }
 
 type Handler struct {
-	downloadCVProcess DownloadCVProcess
+	sites       map[string]Site
+	defaultSite string
 }
 
-func NewHandler(cvProcess DownloadCVProcess) *Handler {
-	return &Handler{downloadCVProcess: cvProcess}
+func NewHandler(sites map[string]Site, defaultSite string) *Handler {
+	return &Handler{
+		sites:       sites,
+		defaultSite: defaultSite,
+	}
 }
 
 func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
@@ -37,13 +59,49 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	filePath, err := h.downloadCVProcess.Process(r.Context(), token, lang)
+	site := r.URL.Query().Get("site")
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	cvSite, ok := h.sites[site]
+	if !ok {
+		errors.WriteJSON(w, errors.ErrSiteNotFound)
+		return
+	}
+
+	requestID := r.Header.Get(RequestIDHeader)
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	w.Header().Set(RequestIDHeader, requestID)
+	ctx := audit.WithCorrelationID(r.Context(), requestID)
+
+	document, err := cvSite.Process.Process(ctx, token, lang, r.Header.Get(ClientHeader), r.Header.Get("Range") != "", ifRangeETag(r))
 	if err != nil {
 		errors.WriteJSON(w, err)
 		return
 	}
+	if !document.Resumed {
+		r.Header.Del("Range")
+	}
 
-	w.Header().Set("Content-Disposition", "attachment; filename=\"cv_adrian_janczenia.pdf\"")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", cvSite.DownloadName))
 	w.Header().Set("Content-Type", "application/pdf")
-	http.ServeFile(w, r, filePath)
+	if document.Content == nil {
+		http.ServeFile(w, r, document.FilePath)
+		return
+	}
+
+	w.Header().Set("ETag", strconv.Quote(document.StampID))
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(document.Content))
+}
+
+func ifRangeETag(r *http.Request) string {
+	etag := strings.TrimPrefix(r.Header.Get("If-Range"), "W/")
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return ""
+	}
+
+	return etag[1 : len(etag)-1]
 }
@@ -4,17 +4,21 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
 	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processDownloadCv "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/download_cv"
 )
 
 type mockDownloadCVProcess struct {
-	processFunc func(ctx context.Context, token, lang string) (string, error)
+	processFunc func(ctx context.Context, token, lang, client string, resume bool, stampID string) (processDownloadCv.Document, error)
 }
 
-func (m *mockDownloadCVProcess) Process(ctx context.Context, token, lang string) (string, error) {
-	return m.processFunc(ctx, token, lang)
+func (m *mockDownloadCVProcess) Process(ctx context.Context, token, lang, client string, resume bool, stampID string) (processDownloadCv.Document, error) {
+	return m.processFunc(ctx, token, lang, client, resume, stampID)
 }
 
 func TestHandler_DownloadCV(t *testing.T) {
@@ -22,7 +26,8 @@ func TestHandler_DownloadCV(t *testing.T) {
 		name        string
 		method      string
 		url         string
-		processFunc func(context.Context, string, string) (string, error)
+		header      http.Header
+		processFunc func(context.Context, string, string, string, bool, string) (processDownloadCv.Document, error)
 		wantStatus  int
 	}{
 		{
@@ -47,17 +52,40 @@ func TestHandler_DownloadCV(t *testing.T) {
 			name:   "process error",
 			method: http.MethodGet,
 			url:    "/download/cv?token=abc&lang=pl",
-			processFunc: func(ctx context.Context, t, l string) (string, error) {
-				return "", errors.ErrCVExpired
+			processFunc: func(ctx context.Context, t, l, c string, r bool, s string) (processDownloadCv.Document, error) {
+				return processDownloadCv.Document{}, errors.ErrCVExpired
 			},
 			wantStatus: http.StatusGone,
 		},
+		{
+			name:   "client fingerprint passed on",
+			method: http.MethodGet,
+			url:    "/download/cv?token=abc&lang=pl",
+			header: http.Header{ClientHeader: []string{"203.0.113.7"}},
+			processFunc: func(ctx context.Context, t, l, c string, r bool, s string) (processDownloadCv.Document, error) {
+				if c != "203.0.113.7" {
+					return processDownloadCv.Document{}, errors.ErrInternalServerError
+				}
+				return processDownloadCv.Document{}, errors.ErrCVTokenMismatch
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "unknown site",
+			method:     http.MethodGet,
+			url:        "/download/cv?token=abc&lang=pl&site=other",
+			wantStatus: http.StatusNotFound,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			h := NewHandler(&mockDownloadCVProcess{processFunc: tt.processFunc})
+			sites := map[string]Site{"main": {Process: &mockDownloadCVProcess{processFunc: tt.processFunc}, DownloadName: "cv.pdf"}}
+			h := NewHandler(sites, "main")
 			req := httptest.NewRequest(tt.method, tt.url, nil)
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
 			w := httptest.NewRecorder()
 
 			h.Handle(w, req)
@@ -68,3 +96,195 @@ func TestHandler_DownloadCV(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_DownloadCV_SiteSelection(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "cv.pdf")
+	os.WriteFile(filePath, []byte("%PDF-1.4"), 0644)
+
+	processFor := func(site string) *mockDownloadCVProcess {
+		return &mockDownloadCVProcess{processFunc: func(ctx context.Context, token, lang, client string, resume bool, stampID string) (processDownloadCv.Document, error) {
+			if token != site+"-token" {
+				return processDownloadCv.Document{}, errors.ErrCVExpired
+			}
+			return processDownloadCv.Document{FilePath: filePath}, nil
+		}}
+	}
+	h := NewHandler(map[string]Site{
+		"main":  {Process: processFor("main"), DownloadName: "cv_main.pdf"},
+		"other": {Process: processFor("other"), DownloadName: "cv_other.pdf"},
+	}, "main")
+
+	tests := []struct {
+		name            string
+		url             string
+		wantStatus      int
+		wantDisposition string
+	}{
+		{
+			name:            "default site",
+			url:             "/download/cv?token=main-token&lang=pl",
+			wantStatus:      http.StatusOK,
+			wantDisposition: `attachment; filename="cv_main.pdf"`,
+		},
+		{
+			name:            "explicit site",
+			url:             "/download/cv?token=other-token&lang=pl&site=other",
+			wantStatus:      http.StatusOK,
+			wantDisposition: `attachment; filename="cv_other.pdf"`,
+		},
+		{
+			name:       "token from another site",
+			url:        "/download/cv?token=main-token&lang=pl&site=other",
+			wantStatus: http.StatusGone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.Handle(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Handle() status = %v, wantStatus %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Content-Disposition"); got != tt.wantDisposition {
+				t.Errorf("Handle() Content-Disposition = %v, want %v", got, tt.wantDisposition)
+			}
+		})
+	}
+}
+
+func TestHandler_DownloadCV_RangeResume(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "cv.pdf")
+	os.WriteFile(filePath, []byte("%PDF-1.4 resumable"), 0644)
+
+	var resumed []bool
+	process := &mockDownloadCVProcess{processFunc: func(ctx context.Context, token, lang, client string, resume bool, stampID string) (processDownloadCv.Document, error) {
+		resumed = append(resumed, resume)
+		return processDownloadCv.Document{FilePath: filePath, Resumed: resume}, nil
+	}}
+	h := NewHandler(map[string]Site{"main": {Process: process, DownloadName: "cv.pdf"}}, "main")
+
+	w := httptest.NewRecorder()
+	h.Handle(w, httptest.NewRequest(http.MethodGet, "/download/cv?token=abc&lang=pl", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Handle() status = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/download/cv?token=abc&lang=pl", nil)
+	req.Header.Set("Range", "bytes=9-")
+	w = httptest.NewRecorder()
+	h.Handle(w, req)
+	if w.Code != http.StatusPartialContent || w.Body.String() != "resumable" {
+		t.Errorf("Handle() = %v %q, want %v %q", w.Code, w.Body.String(), http.StatusPartialContent, "resumable")
+	}
+
+	if len(resumed) != 2 || resumed[0] || !resumed[1] {
+		t.Errorf("Handle() resume flags = %v, want [false true]", resumed)
+	}
+}
+
+func TestHandler_DownloadCV_Stamped(t *testing.T) {
+	stamped := map[string]string{"abc": "%PDF-1.4 stamped 4b1c", "def": "%PDF-1.4 stamped 9d0e"}
+	var stampIDs []string
+	process := &mockDownloadCVProcess{processFunc: func(ctx context.Context, token, lang, client string, resume bool, stampID string) (processDownloadCv.Document, error) {
+		stampIDs = append(stampIDs, stampID)
+		id := stamped[token][17:]
+		return processDownloadCv.Document{FilePath: "/app/missing.pdf", Content: []byte(stamped[token]), StampID: id, Resumed: resume && stampID == id}, nil
+	}}
+	h := NewHandler(map[string]Site{"main": {Process: process, DownloadName: "cv.pdf"}}, "main")
+
+	w := httptest.NewRecorder()
+	h.Handle(w, httptest.NewRequest(http.MethodGet, "/download/cv?token=abc&lang=pl", nil))
+	if w.Code != http.StatusOK || w.Body.String() != stamped["abc"] || w.Header().Get("ETag") != `"4b1c"` {
+		t.Fatalf("Handle() = %v %q etag %s, want the stamped copy", w.Code, w.Body.String(), w.Header().Get("ETag"))
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/pdf" {
+		t.Errorf("Handle() Content-Type = %v, want application/pdf", got)
+	}
+
+	tests := []struct {
+		name        string
+		token       string
+		ifRange     string
+		wantStampID string
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			name:        "same stamp resumes",
+			token:       "abc",
+			ifRange:     `"4b1c"`,
+			wantStampID: "4b1c",
+			wantStatus:  http.StatusPartialContent,
+			wantBody:    "stamped 4b1c",
+		},
+		{
+			name:        "different stamp restarts",
+			token:       "def",
+			ifRange:     `"4b1c"`,
+			wantStampID: "4b1c",
+			wantStatus:  http.StatusOK,
+			wantBody:    stamped["def"],
+		},
+		{
+			name:       "range without a stamp restarts",
+			token:      "abc",
+			wantStatus: http.StatusOK,
+			wantBody:   stamped["abc"],
+		},
+		{
+			name:       "date validator restarts",
+			token:      "abc",
+			ifRange:    "Sun, 01 Mar 2026 12:00:00 GMT",
+			wantStatus: http.StatusOK,
+			wantBody:   stamped["abc"],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stampIDs = nil
+			req := httptest.NewRequest(http.MethodGet, "/download/cv?token="+tt.token+"&lang=pl", nil)
+			req.Header.Set("Range", "bytes=9-")
+			if tt.ifRange != "" {
+				req.Header.Set("If-Range", tt.ifRange)
+			}
+			w := httptest.NewRecorder()
+			h.Handle(w, req)
+
+			if w.Code != tt.wantStatus || w.Body.String() != tt.wantBody {
+				t.Errorf("Handle() = %v %q, want %v %q", w.Code, w.Body.String(), tt.wantStatus, tt.wantBody)
+			}
+			if len(stampIDs) != 1 || stampIDs[0] != tt.wantStampID {
+				t.Errorf("Handle() passed stamp IDs %v, want %q", stampIDs, tt.wantStampID)
+			}
+		})
+	}
+}
+
+func TestHandler_DownloadCV_RequestID(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "cv.pdf")
+	os.WriteFile(filePath, []byte("%PDF-1.4"), 0644)
+
+	var correlationIDs []string
+	process := &mockDownloadCVProcess{processFunc: func(ctx context.Context, token, lang, client string, resume bool, stampID string) (processDownloadCv.Document, error) {
+		correlationIDs = append(correlationIDs, audit.CorrelationID(ctx))
+		return processDownloadCv.Document{FilePath: filePath}, nil
+	}}
+	h := NewHandler(map[string]Site{"main": {Process: process, DownloadName: "cv.pdf"}}, "main")
+
+	req := httptest.NewRequest(http.MethodGet, "/download/cv?token=abc&lang=pl", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	w := httptest.NewRecorder()
+	h.Handle(w, req)
+	if correlationIDs[0] != "req-1" || w.Header().Get(RequestIDHeader) != "req-1" {
+		t.Errorf("Handle() correlation ID = %q, header %q, want req-1", correlationIDs[0], w.Header().Get(RequestIDHeader))
+	}
+
+	w = httptest.NewRecorder()
+	h.Handle(w, httptest.NewRequest(http.MethodGet, "/download/cv?token=abc&lang=pl", nil))
+	if correlationIDs[1] == "" || w.Header().Get(RequestIDHeader) != correlationIDs[1] {
+		t.Errorf("Handle() generated correlation ID = %q, header %q", correlationIDs[1], w.Header().Get(RequestIDHeader))
+	}
+}
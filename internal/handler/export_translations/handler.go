@@ -0,0 +1,85 @@
+package export_translations
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/translation"
+)
+
+type ExportTranslationsProcess interface {
+	Process(ctx context.Context, w io.Writer, sourceLang, targetLang string, format translation.Format) error
+}
+
+type Site struct {
+	Process     ExportTranslationsProcess
+	DefaultLang string
+}
+
+type Handler struct {
+	sites       map[string]Site
+	defaultSite string
+	stdout      io.Writer
+}
+
+func NewHandler(sites map[string]Site, defaultSite string, stdout io.Writer) *Handler {
+	return &Handler{
+		sites:       sites,
+		defaultSite: defaultSite,
+		stdout:      stdout,
+	}
+}
+
+func (h *Handler) Handle(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export-translations", flag.ContinueOnError)
+	siteName := fs.String("site", h.defaultSite, "site whose content files are used")
+	sourceLang := fs.String("source", "", "source language (defaults to the site's default language)")
+	targetLang := fs.String("target", "", "target language")
+	formatName := fs.String("format", "", "xliff or po (defaults to the -out extension, then xliff)")
+	out := fs.String("out", "", "output file (defaults to stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *targetLang == "" {
+		return errors.New("-target is required")
+	}
+
+	site, ok := h.sites[*siteName]
+	if !ok {
+		return fmt.Errorf("unknown site %q", *siteName)
+	}
+	if *sourceLang == "" {
+		*sourceLang = site.DefaultLang
+	}
+
+	format := translation.FormatXLIFF
+	var err error
+	switch {
+	case *formatName != "":
+		format, err = translation.ParseFormat(*formatName)
+	case *out != "":
+		format, err = translation.FormatFromPath(*out)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return site.Process.Process(ctx, h.stdout, *sourceLang, *targetLang, format)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := site.Process.Process(ctx, f, *sourceLang, *targetLang, format); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
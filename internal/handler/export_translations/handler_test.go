@@ -0,0 +1,82 @@
+package export_translations
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/translation"
+)
+
+type mockExportTranslationsProcess struct {
+	processFunc func(ctx context.Context, w io.Writer, sourceLang, targetLang string, format translation.Format) error
+}
+
+func (m *mockExportTranslationsProcess) Process(ctx context.Context, w io.Writer, sourceLang, targetLang string, format translation.Format) error {
+	return m.processFunc(ctx, w, sourceLang, targetLang, format)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantOut string
+		wantErr bool
+	}{
+		{
+			name:    "defaults to xliff on stdout",
+			args:    []string{"-target", "en"},
+			wantOut: "main: pl->en xliff",
+		},
+		{
+			name:    "explicit format",
+			args:    []string{"-source", "en", "-target", "pl", "-format", "po"},
+			wantOut: "main: en->pl po",
+		},
+		{
+			name:    "other site uses its default language",
+			args:    []string{"-site", "blog", "-target", "en"},
+			wantOut: "blog: de->en xliff",
+		},
+		{
+			name:    "unknown site",
+			args:    []string{"-site", "other", "-target", "en"},
+			wantErr: true,
+		},
+		{
+			name:    "missing target",
+			args:    []string{},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			args:    []string{"-target", "en", "-format", "csv"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			process := func(site string) *mockExportTranslationsProcess {
+				return &mockExportTranslationsProcess{processFunc: func(ctx context.Context, w io.Writer, s, tl string, f translation.Format) error {
+					_, err := io.WriteString(w, site+": "+s+"->"+tl+" "+string(f))
+					return err
+				}}
+			}
+			h := NewHandler(map[string]Site{
+				"main": {Process: process("main"), DefaultLang: "pl"},
+				"blog": {Process: process("blog"), DefaultLang: "de"},
+			}, "main", &out)
+
+			err := h.Handle(context.Background(), tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("Handle() output = %q, want %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package get_asset
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processGetAsset "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_asset"
+)
+
+const cacheControl = "public, max-age=86400"
+
+type GetAssetProcess interface {
+	Process(ctx context.Context, name string, width int, format string) (*processGetAsset.Asset, error)
+}
+
+type Handler struct {
+	getAssetProcesses map[string]GetAssetProcess
+	defaultSite       string
+}
+
+func NewHandler(processes map[string]GetAssetProcess, defaultSite string) *Handler {
+	return &Handler{
+		getAssetProcesses: processes,
+		defaultSite:       defaultSite,
+	}
+}
+
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		errors.WriteJSON(w, errors.ErrMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/assets/")
+	if name == "" || name == r.URL.Path {
+		errors.WriteJSON(w, errors.ErrAssetNotFound)
+		return
+	}
+
+	width := 0
+	if raw := r.URL.Query().Get("w"); raw != "" {
+		var err error
+		if width, err = strconv.Atoi(raw); err != nil || width <= 0 {
+			errors.WriteJSON(w, errors.ErrInvalidInput)
+			return
+		}
+	}
+
+	site := r.URL.Query().Get("site")
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	process, ok := h.getAssetProcesses[site]
+	if !ok {
+		errors.WriteJSON(w, errors.ErrSiteNotFound)
+		return
+	}
+
+	a, err := process.Process(r.Context(), name, width, r.URL.Query().Get("format"))
+	if err != nil {
+		errors.WriteJSON(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", a.ETag)
+	w.Header().Set("Cache-Control", cacheControl)
+	if matchesETag(r.Header.Get("If-None-Match"), a.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(a.Data)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(a.Data)
+	}
+}
+
+func matchesETag(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package get_asset
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processGetAsset "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_asset"
+)
+
+type mockGetAssetProcess struct {
+	processFunc func(ctx context.Context, name string, width int, format string) (*processGetAsset.Asset, error)
+}
+
+func (m *mockGetAssetProcess) Process(ctx context.Context, name string, width int, format string) (*processGetAsset.Asset, error) {
+	return m.processFunc(ctx, name, width, format)
+}
+
+func TestHandler_GetAsset(t *testing.T) {
+	found := func(ctx context.Context, name string, width int, format string) (*processGetAsset.Asset, error) {
+		if name != "projects/cover.png" || width != 320 || format != "jpeg" {
+			return nil, errors.ErrAssetNotFound
+		}
+		return &processGetAsset.Asset{Data: []byte("img"), ContentType: "image/jpeg", ETag: `"abc"`}, nil
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		ifNoneMatch string
+		processFunc func(context.Context, string, int, string) (*processGetAsset.Asset, error)
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			name:        "success",
+			method:      http.MethodGet,
+			url:         "/assets/projects/cover.png?w=320&format=jpeg",
+			processFunc: found,
+			wantStatus:  http.StatusOK,
+			wantBody:    "img",
+		},
+		{
+			name:        "not modified",
+			method:      http.MethodGet,
+			url:         "/assets/projects/cover.png?w=320&format=jpeg",
+			ifNoneMatch: `"old", "abc"`,
+			processFunc: found,
+			wantStatus:  http.StatusNotModified,
+		},
+		{
+			name:        "head",
+			method:      http.MethodHead,
+			url:         "/assets/projects/cover.png?w=320&format=jpeg",
+			processFunc: found,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodPost,
+			url:        "/assets/profile.png",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "invalid width",
+			method:     http.MethodGet,
+			url:        "/assets/profile.png?w=abc",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "unknown asset",
+			method:      http.MethodGet,
+			url:         "/assets/missing.png",
+			processFunc: found,
+			wantStatus:  http.StatusNotFound,
+		},
+		{
+			name:       "unknown site",
+			method:     http.MethodGet,
+			url:        "/assets/profile.png?site=other",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(map[string]GetAssetProcess{"main": &mockGetAssetProcess{processFunc: tt.processFunc}}, "main")
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+
+			h.Handle(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Handle() status = %v, wantStatus %v", w.Code, tt.wantStatus)
+			}
+			if w.Code < http.StatusBadRequest && w.Body.String() != tt.wantBody {
+				t.Errorf("Handle() body = %q, want %q", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
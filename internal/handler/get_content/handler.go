@@ -6,25 +6,40 @@ import (
 
 	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/api/proto/v1"
 	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/experiment"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type GetContentProcess interface {
-	Process(ctx context.Context, lang string) ([]byte, error)
+	Process(ctx context.Context, lang, visitorID string) ([]byte, []experiment.Assignment, error)
 }
 
 type Handler struct {
 	contentv1.UnimplementedContentServiceServer
-	getContentProcess GetContentProcess
+	getContentProcesses map[string]GetContentProcess
+	defaultSite         string
 }
 
-func NewHandler(process GetContentProcess) *Handler {
-	return &Handler{getContentProcess: process}
+func NewHandler(processes map[string]GetContentProcess, defaultSite string) *Handler {
+	return &Handler{
+		getContentProcesses: processes,
+		defaultSite:         defaultSite,
+	}
 }
 
 func (h *Handler) Handle(ctx context.Context, req *contentv1.GetContentRequest) (*contentv1.GetContentResponse, error) {
-	content, err := h.getContentProcess.Process(ctx, req.GetLang())
+	site := req.GetSite()
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	process, ok := h.getContentProcesses[site]
+	if !ok {
+		return nil, status.Error(codes.NotFound, appErrors.ErrSiteNotFound.Slug)
+	}
+
+	content, assignments, err := process.Process(ctx, req.GetLang(), req.GetVisitorId())
 	if err != nil {
 		var appErr *appErrors.AppError
 		if errors.As(err, &appErr) {
@@ -35,5 +50,10 @@ func (h *Handler) Handle(ctx context.Context, req *contentv1.GetContentRequest)
 		return nil, status.Error(codes.Internal, appErrors.ErrInternalServerError.Slug)
 	}
 
-	return &contentv1.GetContentResponse{JsonContent: content}, nil
+	res := &contentv1.GetContentResponse{JsonContent: content}
+	for _, a := range assignments {
+		res.Variants = append(res.Variants, &contentv1.VariantAssignment{Experiment: a.Experiment, Variant: a.Variant})
+	}
+
+	return res, nil
 }
@@ -7,31 +7,33 @@ import (
 
 	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/api/proto/v1"
 	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/experiment"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type mockGetContentProcess struct {
-	processFunc func(ctx context.Context, lang string) ([]byte, error)
+	processFunc func(ctx context.Context, lang, visitorID string) ([]byte, []experiment.Assignment, error)
 }
 
-func (m *mockGetContentProcess) Process(ctx context.Context, lang string) ([]byte, error) {
-	return m.processFunc(ctx, lang)
+func (m *mockGetContentProcess) Process(ctx context.Context, lang, visitorID string) ([]byte, []experiment.Assignment, error) {
+	return m.processFunc(ctx, lang, visitorID)
 }
 
 func TestHandler_GetContent(t *testing.T) {
 	tests := []struct {
-		name        string
-		req         *contentv1.GetContentRequest
-		processFunc func(context.Context, string) ([]byte, error)
-		wantCode    codes.Code
-		wantRes     []byte
+		name         string
+		req          *contentv1.GetContentRequest
+		processFunc  func(context.Context, string, string) ([]byte, []experiment.Assignment, error)
+		wantVariants []*contentv1.VariantAssignment
+		wantCode     codes.Code
+		wantRes      []byte
 	}{
 		{
 			name: "successful response",
 			req:  &contentv1.GetContentRequest{Lang: "pl"},
-			processFunc: func(ctx context.Context, l string) ([]byte, error) {
-				return []byte(`{"ok": true}`), nil
+			processFunc: func(ctx context.Context, l, v string) ([]byte, []experiment.Assignment, error) {
+				return []byte(`{"ok": true}`), nil, nil
 			},
 			wantCode: codes.OK,
 			wantRes:  []byte(`{"ok": true}`),
@@ -39,8 +41,8 @@ func TestHandler_GetContent(t *testing.T) {
 		{
 			name: "content not found",
 			req:  &contentv1.GetContentRequest{Lang: "fr"},
-			processFunc: func(ctx context.Context, l string) ([]byte, error) {
-				return nil, appErrors.ErrContentNotFound
+			processFunc: func(ctx context.Context, l, v string) ([]byte, []experiment.Assignment, error) {
+				return nil, nil, appErrors.ErrContentNotFound
 			},
 			wantCode: codes.NotFound,
 			wantRes:  nil,
@@ -48,17 +50,45 @@ func TestHandler_GetContent(t *testing.T) {
 		{
 			name: "internal error",
 			req:  &contentv1.GetContentRequest{Lang: "en"},
-			processFunc: func(ctx context.Context, l string) ([]byte, error) {
-				return nil, errors.New("fs error")
+			processFunc: func(ctx context.Context, l, v string) ([]byte, []experiment.Assignment, error) {
+				return nil, nil, errors.New("fs error")
 			},
 			wantCode: codes.Internal,
 			wantRes:  nil,
 		},
+		{
+			name: "explicit site",
+			req:  &contentv1.GetContentRequest{Lang: "pl", Site: "main"},
+			processFunc: func(ctx context.Context, l, v string) ([]byte, []experiment.Assignment, error) {
+				return []byte(`{"site": "main"}`), nil, nil
+			},
+			wantCode: codes.OK,
+			wantRes:  []byte(`{"site": "main"}`),
+		},
+		{
+			name: "variant assignments",
+			req:  &contentv1.GetContentRequest{Lang: "pl", VisitorId: "visitor"},
+			processFunc: func(ctx context.Context, l, v string) ([]byte, []experiment.Assignment, error) {
+				if v != "visitor" {
+					return nil, nil, errors.New("missing visitor")
+				}
+				return []byte(`{}`), []experiment.Assignment{{Experiment: "headline", Variant: "b"}}, nil
+			},
+			wantCode:     codes.OK,
+			wantRes:      []byte(`{}`),
+			wantVariants: []*contentv1.VariantAssignment{{Experiment: "headline", Variant: "b"}},
+		},
+		{
+			name:     "unknown site",
+			req:      &contentv1.GetContentRequest{Lang: "pl", Site: "other"},
+			wantCode: codes.NotFound,
+			wantRes:  nil,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			h := NewHandler(&mockGetContentProcess{processFunc: tt.processFunc})
+			h := NewHandler(map[string]GetContentProcess{"main": &mockGetContentProcess{processFunc: tt.processFunc}}, "main")
 			res, err := h.Handle(context.Background(), tt.req)
 
 			if tt.wantCode == codes.OK {
@@ -68,6 +98,14 @@ func TestHandler_GetContent(t *testing.T) {
 				if string(res.JsonContent) != string(tt.wantRes) {
 					t.Errorf("Handle() got = %v, want %v", string(res.JsonContent), string(tt.wantRes))
 				}
+				if len(res.Variants) != len(tt.wantVariants) {
+					t.Fatalf("Handle() got %d variants, want %d", len(res.Variants), len(tt.wantVariants))
+				}
+				for i, v := range res.Variants {
+					if v.Experiment != tt.wantVariants[i].Experiment || v.Variant != tt.wantVariants[i].Variant {
+						t.Errorf("Handle() variant = %v, want %v", v, tt.wantVariants[i])
+					}
+				}
 			} else {
 				st, ok := status.FromError(err)
 				if !ok || st.Code() != tt.wantCode {
@@ -0,0 +1,56 @@
+package get_cv_stamp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pdfstamp"
+)
+
+const Path = "/admin/cv-stamps"
+
+type GetCVStampProcess interface {
+	Process(ctx context.Context, id string) (pdfstamp.Stamp, error)
+}
+
+type Handler struct {
+	getCVStampProcesses map[string]GetCVStampProcess
+	defaultSite         string
+}
+
+func NewHandler(processes map[string]GetCVStampProcess, defaultSite string) *Handler {
+	return &Handler{
+		getCVStampProcesses: processes,
+		defaultSite:         defaultSite,
+	}
+}
+
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errors.WriteJSON(w, errors.ErrMethodNotAllowed)
+		return
+	}
+
+	site := r.URL.Query().Get("site")
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	process, ok := h.getCVStampProcesses[site]
+	if !ok {
+		errors.WriteJSON(w, errors.ErrSiteNotFound)
+		return
+	}
+
+	stamp, err := process.Process(r.Context(), strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, Path), "/"))
+	if err != nil {
+		errors.WriteJSON(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stamp)
+}
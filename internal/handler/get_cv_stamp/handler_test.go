@@ -0,0 +1,85 @@
+package get_cv_stamp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pdfstamp"
+)
+
+type mockGetCVStampProcess struct{}
+
+func (m *mockGetCVStampProcess) Process(ctx context.Context, id string) (pdfstamp.Stamp, error) {
+	switch id {
+	case "":
+		return pdfstamp.Stamp{}, errors.ErrInvalidInput
+	case "4b1c":
+		return pdfstamp.Stamp{ID: "4b1c", Token: "3f9a", Credential: "acme", Lang: "pl", Document: "cv_pl.pdf", StampedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}, nil
+	default:
+		return pdfstamp.Stamp{}, errors.ErrStampNotFound
+	}
+}
+
+func TestHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "found",
+			method:     http.MethodGet,
+			url:        "/admin/cv-stamps/4b1c",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":"4b1c","token":"3f9a","credential":"acme","lang":"pl","document":"cv_pl.pdf","stampedAt":"2026-03-01T12:00:00Z"}`,
+		},
+		{
+			name:       "unknown",
+			method:     http.MethodGet,
+			url:        "/admin/cv-stamps/9d0e",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `error_cv_stamp_not_found`,
+		},
+		{
+			name:       "missing id",
+			method:     http.MethodGet,
+			url:        "/admin/cv-stamps",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown site",
+			method:     http.MethodGet,
+			url:        "/admin/cv-stamps/4b1c?site=other",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `error_site_not_found`,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodDelete,
+			url:        "/admin/cv-stamps/4b1c",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(map[string]GetCVStampProcess{"main": &mockGetCVStampProcess{}}, "main")
+			w := httptest.NewRecorder()
+			h.Handle(w, httptest.NewRequest(tt.method, tt.url, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Handle() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("Handle() body = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
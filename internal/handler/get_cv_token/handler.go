@@ -2,35 +2,73 @@ package get_cv_token
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"log"
+	"time"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
 	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pow"
+	processGetCvToken "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_cv_token"
 	"github.com/rabbitmq/amqp091-go"
 )
 
 type GetCVTokenProcess interface {
-	Process(ctx context.Context, password, lang, captchaID string) (string, error)
+	Process(ctx context.Context, req processGetCvToken.Request) (string, error)
 }
 
+type ReplyCache interface {
+	ClaimReply(ctx context.Context, key string, lease time.Duration) (string, bool, error)
+	StoreReply(ctx context.Context, key, reply string, ttl time.Duration) error
+	ReleaseReply(ctx context.Context, key string) error
+}
+
+type Site struct {
+	Process    GetCVTokenProcess
+	ReplyCache ReplyCache
+}
+
+const (
+	replyKeyPrefix    = "cv_token_reply:"
+	replyLease        = 30 * time.Second
+	replyPollInterval = 100 * time.Millisecond
+)
+
 type Handler struct {
-	getCVTokenProcess GetCVTokenProcess
+	sites       map[string]Site
+	defaultSite string
+	replyWindow time.Duration
 }
 
 type requestPayload struct {
 	Password  string `json:"password"`
 	Lang      string `json:"lang"`
 	CaptchaID string `json:"captchaId"`
+	Site      string `json:"site"`
+	Client    string `json:"client"`
+	IP        string `json:"ip"`
+	Pow       struct {
+		Challenge string `json:"challenge"`
+		Signature string `json:"signature"`
+		Nonce     string `json:"nonce"`
+	} `json:"pow"`
+	Email string `json:"email"`
 }
 
 type responsePayload struct {
-	Token string `json:"token,omitempty"`
-	Error string `json:"error,omitempty"`
+	Token      string `json:"token,omitempty"`
+	Error      string `json:"error,omitempty"`
+	RetryAfter int    `json:"retryAfter,omitempty"`
 }
 
-func NewHandler(cvProcess GetCVTokenProcess) *Handler {
+func NewHandler(sites map[string]Site, defaultSite string, replyWindow time.Duration) *Handler {
 	return &Handler{
-		getCVTokenProcess: cvProcess,
+		sites:       sites,
+		defaultSite: defaultSite,
+		replyWindow: replyWindow,
 	}
 }
 
@@ -40,7 +78,44 @@ func (c *Handler) Handle(ctx context.Context, d amqp091.Delivery) (any, error) {
 		return nil, appErrors.ErrInvalidInput
 	}
 
-	token, err := c.getCVTokenProcess.Process(ctx, req.Password, req.Lang, req.CaptchaID)
+	site := req.Site
+	if site == "" {
+		site = c.defaultSite
+	}
+
+	correlationID := d.CorrelationId
+	if correlationID == "" {
+		correlationID = d.MessageId
+	}
+	ctx = audit.WithCorrelationID(ctx, correlationID)
+
+	cvSite, ok := c.sites[site]
+	if !ok {
+		return responsePayload{Error: appErrors.ErrSiteNotFound.Slug}, nil
+	}
+
+	replyKey := ""
+	if correlationID != "" && c.replyWindow > 0 && cvSite.ReplyCache != nil {
+		replyKey = replyKeyPrefix + correlationID + ":" + payloadHash(d.Body)
+		cached, err := c.claimReply(ctx, cvSite.ReplyCache, replyKey)
+		if err != nil {
+			return nil, err
+		}
+		if cached != nil {
+			log.Printf("INFO: replaying CV token reply for correlation ID %s (redelivered: %v)", correlationID, d.Redelivered)
+			return *cached, nil
+		}
+	}
+
+	token, err := cvSite.Process.Process(ctx, processGetCvToken.Request{
+		Password:  req.Password,
+		Lang:      req.Lang,
+		CaptchaID: req.CaptchaID,
+		Client:    req.Client,
+		IP:        req.IP,
+		Pow:       pow.Solution{Challenge: req.Pow.Challenge, Signature: req.Pow.Signature, Nonce: req.Pow.Nonce},
+		Email:     req.Email,
+	})
 
 	response := responsePayload{}
 	if err != nil {
@@ -50,9 +125,63 @@ func (c *Handler) Handle(ctx context.Context, d amqp091.Delivery) (any, error) {
 		} else {
 			response.Error = appErrors.ErrInternalServerError.Slug
 		}
+		response.RetryAfter = appErrors.RetryAfterSeconds(err)
 	} else {
 		response.Token = token
 	}
 
+	if replyKey != "" {
+		c.storeReply(ctx, cvSite.ReplyCache, replyKey, response)
+	}
+
 	return response, nil
 }
+
+func (c *Handler) claimReply(ctx context.Context, cache ReplyCache, key string) (*responsePayload, error) {
+	for {
+		reply, claimed, err := cache.ClaimReply(ctx, key, replyLease)
+		if err != nil {
+			log.Printf("ERROR: could not claim CV token reply %s, handling the request uncached: %v", key, err)
+			return nil, nil
+		}
+		if claimed {
+			return nil, nil
+		}
+		if reply != "" {
+			var cached responsePayload
+			if err := json.Unmarshal([]byte(reply), &cached); err != nil {
+				log.Printf("ERROR: could not decode cached CV token reply %s: %v", key, err)
+				return nil, appErrors.ErrInternalServerError
+			}
+			return &cached, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(replyPollInterval):
+		}
+	}
+}
+
+func (c *Handler) storeReply(ctx context.Context, cache ReplyCache, key string, response responsePayload) {
+	if response.Error == appErrors.ErrInternalServerError.Slug {
+		if err := cache.ReleaseReply(ctx, key); err != nil {
+			log.Printf("ERROR: could not release CV token reply %s: %v", key, err)
+		}
+		return
+	}
+
+	data, err := json.Marshal(response)
+	if err == nil {
+		err = cache.StoreReply(ctx, key, string(data), c.replyWindow)
+	}
+	if err != nil {
+		log.Printf("ERROR: could not cache CV token reply %s: %v", key, err)
+	}
+}
+
+func payloadHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:16])
+}
@@ -2,32 +2,40 @@ package get_cv_token
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
 	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pow"
+	processGetCvToken "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_cv_token"
 	"github.com/rabbitmq/amqp091-go"
 )
 
 type mockGetCVTokenProcess struct {
-	processFunc func(ctx context.Context, password, lang, captchaID string) (string, error)
+	processFunc func(ctx context.Context, req processGetCvToken.Request) (string, error)
 }
 
-func (m *mockGetCVTokenProcess) Process(ctx context.Context, p, l, c string) (string, error) {
-	return m.processFunc(ctx, p, l, c)
+func (m *mockGetCVTokenProcess) Process(ctx context.Context, req processGetCvToken.Request) (string, error) {
+	return m.processFunc(ctx, req)
 }
 
 func TestHandler_Handle(t *testing.T) {
 	tests := []struct {
 		name        string
 		body        string
-		processFunc func(context.Context, string, string, string) (string, error)
+		processFunc func(context.Context, processGetCvToken.Request) (string, error)
 		wantToken   string
 		wantError   string
+		wantRetry   int
 	}{
 		{
 			name: "success",
 			body: `{"password":"p","lang":"pl","captchaId":"c"}`,
-			processFunc: func(ctx context.Context, p, l, c string) (string, error) {
+			processFunc: func(ctx context.Context, req processGetCvToken.Request) (string, error) {
 				return "t123", nil
 			},
 			wantToken: "t123",
@@ -35,11 +43,64 @@ func TestHandler_Handle(t *testing.T) {
 		{
 			name: "process error",
 			body: `{"password":"p","lang":"pl","captchaId":"c"}`,
-			processFunc: func(ctx context.Context, p, l, c string) (string, error) {
+			processFunc: func(ctx context.Context, req processGetCvToken.Request) (string, error) {
 				return "", appErrors.ErrInvalidPassword
 			},
 			wantError: "error_cv_auth",
 		},
+		{
+			name: "explicit site",
+			body: `{"password":"p","lang":"pl","captchaId":"c","site":"main"}`,
+			processFunc: func(ctx context.Context, req processGetCvToken.Request) (string, error) {
+				return "t456", nil
+			},
+			wantToken: "t456",
+		},
+		{
+			name: "request passed on",
+			body: `{"password":"p","lang":"pl","captchaId":"c","client":"fp","ip":"203.0.113.7","pow":{"challenge":"ch","signature":"sig","nonce":"42"},"email":"anna@acme.com"}`,
+			processFunc: func(ctx context.Context, req processGetCvToken.Request) (string, error) {
+				want := processGetCvToken.Request{
+					Password:  "p",
+					Lang:      "pl",
+					CaptchaID: "c",
+					Client:    "fp",
+					IP:        "203.0.113.7",
+					Pow:       pow.Solution{Challenge: "ch", Signature: "sig", Nonce: "42"},
+					Email:     "anna@acme.com",
+				}
+				if req != want {
+					return "", appErrors.ErrInternalServerError
+				}
+				return "t789", nil
+			},
+			wantToken: "t789",
+		},
+		{
+			name: "correlation ID passed on",
+			body: `{"password":"p","lang":"pl","captchaId":"c"}`,
+			processFunc: func(ctx context.Context, req processGetCvToken.Request) (string, error) {
+				if audit.CorrelationID(ctx) != "corr-1" {
+					return "", appErrors.ErrInternalServerError
+				}
+				return "t000", nil
+			},
+			wantToken: "t000",
+		},
+		{
+			name: "rate limited",
+			body: `{"password":"p","lang":"pl","captchaId":"c"}`,
+			processFunc: func(ctx context.Context, req processGetCvToken.Request) (string, error) {
+				return "", appErrors.WithRetryAfter(appErrors.ErrRateLimited, 90*time.Second)
+			},
+			wantError: "error_rate_limited",
+			wantRetry: 90,
+		},
+		{
+			name:      "unknown site",
+			body:      `{"password":"p","lang":"pl","captchaId":"c","site":"other"}`,
+			wantError: "error_site_not_found",
+		},
 		{
 			name:      "unmarshal error",
 			body:      `invalid`,
@@ -50,9 +111,9 @@ func TestHandler_Handle(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			m := &mockGetCVTokenProcess{processFunc: tt.processFunc}
-			h := NewHandler(m)
+			h := NewHandler(map[string]Site{"main": {Process: m}}, "main", 0)
 
-			d := amqp091.Delivery{Body: []byte(tt.body)}
+			d := amqp091.Delivery{Body: []byte(tt.body), CorrelationId: "corr-1"}
 			res, err := h.Handle(context.Background(), d)
 
 			if tt.body == "invalid" {
@@ -69,6 +130,251 @@ func TestHandler_Handle(t *testing.T) {
 			if payload.Error != tt.wantError {
 				t.Errorf("Handle() got error = %v, want %v", payload.Error, tt.wantError)
 			}
+			if payload.RetryAfter != tt.wantRetry {
+				t.Errorf("Handle() got retry after = %v, want %v", payload.RetryAfter, tt.wantRetry)
+			}
+		})
+	}
+}
+
+type mockReplyCache struct {
+	mu       sync.Mutex
+	replies  map[string]string
+	pending  int
+	err      error
+	released []string
+	ttls     []time.Duration
+}
+
+func (m *mockReplyCache) ClaimReply(ctx context.Context, key string, lease time.Duration) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return "", false, m.err
+	}
+	if m.pending > 0 {
+		m.pending--
+		return "", false, nil
+	}
+	reply, ok := m.replies[key]
+	if ok {
+		return reply, false, nil
+	}
+	return "", true, nil
+}
+
+func (m *mockReplyCache) StoreReply(ctx context.Context, key, reply string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replies[key] = reply
+	m.ttls = append(m.ttls, ttl)
+	return m.err
+}
+
+func (m *mockReplyCache) ReleaseReply(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.released = append(m.released, key)
+	return nil
+}
+
+func TestHandler_Handle_Redelivery(t *testing.T) {
+	body := []byte(`{"password":"p","lang":"pl","captchaId":"c"}`)
+	otherBody := []byte(`{"password":"other","lang":"pl","captchaId":"c2"}`)
+
+	tests := []struct {
+		name       string
+		cache      *mockReplyCache
+		window     time.Duration
+		deliveries []amqp091.Delivery
+		results    []error
+		wantCalls  int
+		wantTokens []string
+		wantErrors []string
+	}{
+		{
+			name:   "redelivered message replays the original token",
+			cache:  &mockReplyCache{replies: map[string]string{}},
+			window: time.Hour,
+			deliveries: []amqp091.Delivery{
+				{Body: body, CorrelationId: "corr-1"},
+				{Body: body, CorrelationId: "corr-1", Redelivered: true},
+			},
+			results:    []error{nil, appErrors.ErrCaptchaNotFound},
+			wantCalls:  1,
+			wantTokens: []string{"t1", "t1"},
+			wantErrors: []string{"", ""},
+		},
+		{
+			name:   "message ID used without correlation ID",
+			cache:  &mockReplyCache{replies: map[string]string{}},
+			window: time.Hour,
+			deliveries: []amqp091.Delivery{
+				{Body: body, MessageId: "msg-1"},
+				{Body: body, MessageId: "msg-1", Redelivered: true},
+				{Body: body, MessageId: "msg-2"},
+			},
+			results:    []error{nil, appErrors.ErrCaptchaNotFound},
+			wantCalls:  2,
+			wantTokens: []string{"t1", "t1", ""},
+			wantErrors: []string{"", "", "error_captcha_not_found"},
+		},
+		{
+			name:   "rejection is replayed",
+			cache:  &mockReplyCache{replies: map[string]string{}},
+			window: time.Hour,
+			deliveries: []amqp091.Delivery{
+				{Body: body, CorrelationId: "corr-1"},
+				{Body: body, CorrelationId: "corr-1"},
+			},
+			results:    []error{appErrors.ErrInvalidPassword, nil},
+			wantCalls:  1,
+			wantTokens: []string{"", ""},
+			wantErrors: []string{"error_cv_auth", "error_cv_auth"},
+		},
+		{
+			name:   "server error is not cached",
+			cache:  &mockReplyCache{replies: map[string]string{}},
+			window: time.Hour,
+			deliveries: []amqp091.Delivery{
+				{Body: body, CorrelationId: "corr-1"},
+				{Body: body, CorrelationId: "corr-1"},
+			},
+			results:    []error{appErrors.ErrInternalServerError, nil},
+			wantCalls:  2,
+			wantTokens: []string{"", "t2"},
+			wantErrors: []string{"error_cv_server", ""},
+		},
+		{
+			name:   "duplicate waits for the pending reply",
+			cache:  &mockReplyCache{replies: map[string]string{"cv_token_reply:corr-1:" + payloadHash(body): `{"token":"t0"}`}, pending: 2},
+			window: time.Hour,
+			deliveries: []amqp091.Delivery{
+				{Body: body, CorrelationId: "corr-1", Redelivered: true},
+			},
+			wantTokens: []string{"t0"},
+			wantErrors: []string{""},
+		},
+		{
+			name:   "reused correlation ID with another payload is a new request",
+			cache:  &mockReplyCache{replies: map[string]string{}},
+			window: time.Hour,
+			deliveries: []amqp091.Delivery{
+				{Body: body, CorrelationId: "corr-1"},
+				{Body: otherBody, CorrelationId: "corr-1"},
+			},
+			results:    []error{nil, appErrors.ErrInvalidPassword},
+			wantCalls:  2,
+			wantTokens: []string{"t1", ""},
+			wantErrors: []string{"", "error_cv_auth"},
+		},
+		{
+			name:   "cache failure handles the request uncached",
+			cache:  &mockReplyCache{replies: map[string]string{}, err: errors.New("redis error")},
+			window: time.Hour,
+			deliveries: []amqp091.Delivery{
+				{Body: body, CorrelationId: "corr-1"},
+				{Body: body, CorrelationId: "corr-1"},
+			},
+			results:    []error{nil, nil},
+			wantCalls:  2,
+			wantTokens: []string{"t1", "t2"},
+			wantErrors: []string{"", ""},
+		},
+		{
+			name:  "cache disabled",
+			cache: &mockReplyCache{replies: map[string]string{}},
+			deliveries: []amqp091.Delivery{
+				{Body: body, CorrelationId: "corr-1"},
+				{Body: body, CorrelationId: "corr-1"},
+			},
+			results:    []error{nil, nil},
+			wantCalls:  2,
+			wantTokens: []string{"t1", "t2"},
+			wantErrors: []string{"", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			m := &mockGetCVTokenProcess{processFunc: func(ctx context.Context, req processGetCvToken.Request) (string, error) {
+				err := tt.results[calls]
+				calls++
+				if err != nil {
+					return "", err
+				}
+				return "t" + strconv.Itoa(calls), nil
+			}}
+			h := NewHandler(map[string]Site{"main": {Process: m, ReplyCache: tt.cache}}, "main", tt.window)
+
+			for i, d := range tt.deliveries {
+				res, err := h.Handle(context.Background(), d)
+				if err != nil {
+					t.Fatalf("Handle() delivery %d error = %v", i, err)
+				}
+				payload := res.(responsePayload)
+				if payload.Token != tt.wantTokens[i] || payload.Error != tt.wantErrors[i] {
+					t.Errorf("Handle() delivery %d = %+v, want token %q and error %q", i, payload, tt.wantTokens[i], tt.wantErrors[i])
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Handle() ran the process %d times, want %d", calls, tt.wantCalls)
+			}
+			for _, ttl := range tt.cache.ttls {
+				if ttl != tt.window {
+					t.Errorf("Handle() cached a reply for %v, want %v", ttl, tt.window)
+				}
+			}
 		})
 	}
 }
+
+func TestHandler_Handle_RepliesScopedPerSite(t *testing.T) {
+	process := func(token string) *mockGetCVTokenProcess {
+		return &mockGetCVTokenProcess{processFunc: func(ctx context.Context, req processGetCvToken.Request) (string, error) {
+			return token, nil
+		}}
+	}
+	mainCache := &mockReplyCache{replies: map[string]string{}}
+	blogCache := &mockReplyCache{replies: map[string]string{}}
+	h := NewHandler(map[string]Site{
+		"main": {Process: process("main-token"), ReplyCache: mainCache},
+		"blog": {Process: process("blog-token"), ReplyCache: blogCache},
+	}, "main", time.Hour)
+
+	deliveries := []struct {
+		body      string
+		wantToken string
+	}{
+		{body: `{"password":"p","lang":"pl","captchaId":"c","site":"main"}`, wantToken: "main-token"},
+		{body: `{"password":"p","lang":"pl","captchaId":"c","site":"blog"}`, wantToken: "blog-token"},
+	}
+	for i, d := range deliveries {
+		res, err := h.Handle(context.Background(), amqp091.Delivery{Body: []byte(d.body), CorrelationId: "corr-1"})
+		if err != nil {
+			t.Fatalf("Handle() delivery %d error = %v", i, err)
+		}
+		if got := res.(responsePayload).Token; got != d.wantToken {
+			t.Errorf("Handle() delivery %d token = %q, want %q", i, got, d.wantToken)
+		}
+	}
+	if len(mainCache.replies) != 1 || len(blogCache.replies) != 1 {
+		t.Errorf("Handle() cached %d replies for main and %d for blog, want 1 each", len(mainCache.replies), len(blogCache.replies))
+	}
+}
+
+func TestHandler_Handle_PendingReplyTimesOut(t *testing.T) {
+	cache := &mockReplyCache{replies: map[string]string{}, pending: 1000}
+	m := &mockGetCVTokenProcess{processFunc: func(ctx context.Context, req processGetCvToken.Request) (string, error) {
+		return "", errors.New("must not run while the original request is pending")
+	}}
+	h := NewHandler(map[string]Site{"main": {Process: m, ReplyCache: cache}}, "main", time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	d := amqp091.Delivery{Body: []byte(`{"password":"p","lang":"pl","captchaId":"c"}`), CorrelationId: "corr-1", Redelivered: true}
+	if _, err := h.Handle(ctx, d); err == nil {
+		t.Error("Handle() expected an error while the original reply is pending")
+	}
+}
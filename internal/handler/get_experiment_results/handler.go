@@ -0,0 +1,62 @@
+package get_experiment_results
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/api/proto/v1"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processGetExperimentResults "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_experiment_results"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type GetExperimentResultsProcess interface {
+	Process(ctx context.Context, name string) ([]processGetExperimentResults.Result, error)
+}
+
+type Handler struct {
+	contentv1.UnimplementedExperimentServiceServer
+	getExperimentResultsProcesses map[string]GetExperimentResultsProcess
+	defaultSite                   string
+}
+
+func NewHandler(processes map[string]GetExperimentResultsProcess, defaultSite string) *Handler {
+	return &Handler{
+		getExperimentResultsProcesses: processes,
+		defaultSite:                   defaultSite,
+	}
+}
+
+func (h *Handler) Handle(ctx context.Context, req *contentv1.GetExperimentResultsRequest) (*contentv1.GetExperimentResultsResponse, error) {
+	site := req.GetSite()
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	process, ok := h.getExperimentResultsProcesses[site]
+	if !ok {
+		return nil, status.Error(codes.NotFound, appErrors.ErrSiteNotFound.Slug)
+	}
+
+	results, err := process.Process(ctx, req.GetExperiment())
+	if err != nil {
+		if errors.Is(err, appErrors.ErrExperimentNotFound) {
+			return nil, status.Error(codes.NotFound, appErrors.ErrExperimentNotFound.Slug)
+		}
+		return nil, status.Error(codes.Internal, appErrors.ErrInternalServerError.Slug)
+	}
+
+	res := &contentv1.GetExperimentResultsResponse{}
+	for _, r := range results {
+		res.Results = append(res.Results, &contentv1.VariantResult{
+			Experiment:     r.Experiment,
+			Variant:        r.Variant,
+			Weight:         int32(r.Weight),
+			Exposures:      r.Exposures,
+			UniqueVisitors: r.UniqueVisitors,
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,79 @@
+package get_experiment_results
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/api/proto/v1"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processGetExperimentResults "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_experiment_results"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockGetExperimentResultsProcess struct {
+	processFunc func(ctx context.Context, name string) ([]processGetExperimentResults.Result, error)
+}
+
+func (m *mockGetExperimentResultsProcess) Process(ctx context.Context, name string) ([]processGetExperimentResults.Result, error) {
+	return m.processFunc(ctx, name)
+}
+
+func TestHandler_GetExperimentResults(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *contentv1.GetExperimentResultsRequest
+		processFunc func(context.Context, string) ([]processGetExperimentResults.Result, error)
+		wantCode    codes.Code
+		wantResults int
+	}{
+		{
+			name: "success",
+			req:  &contentv1.GetExperimentResultsRequest{Experiment: "headline"},
+			processFunc: func(ctx context.Context, name string) ([]processGetExperimentResults.Result, error) {
+				return []processGetExperimentResults.Result{
+					{Experiment: name, Variant: "a", Weight: 1, Exposures: 5, UniqueVisitors: 4},
+					{Experiment: name, Variant: "b", Weight: 1, Exposures: 6, UniqueVisitors: 5},
+				}, nil
+			},
+			wantCode:    codes.OK,
+			wantResults: 2,
+		},
+		{
+			name: "experiment not found",
+			req:  &contentv1.GetExperimentResultsRequest{Experiment: "footer"},
+			processFunc: func(ctx context.Context, name string) ([]processGetExperimentResults.Result, error) {
+				return nil, appErrors.ErrExperimentNotFound
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "internal error",
+			req:  &contentv1.GetExperimentResultsRequest{},
+			processFunc: func(ctx context.Context, name string) ([]processGetExperimentResults.Result, error) {
+				return nil, errors.New("redis error")
+			},
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "unknown site",
+			req:      &contentv1.GetExperimentResultsRequest{Site: "other"},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(map[string]GetExperimentResultsProcess{"main": &mockGetExperimentResultsProcess{processFunc: tt.processFunc}}, "main")
+			res, err := h.Handle(context.Background(), tt.req)
+
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Handle() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+			if tt.wantCode == codes.OK && len(res.Results) != tt.wantResults {
+				t.Errorf("Handle() got %d results, want %d", len(res.Results), tt.wantResults)
+			}
+		})
+	}
+}
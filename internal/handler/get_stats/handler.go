@@ -0,0 +1,59 @@
+package get_stats
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/api/proto/v1"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processGetStats "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_stats"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type GetStatsProcess interface {
+	Process(ctx context.Context, from, to string) (*processGetStats.Stats, error)
+}
+
+type Handler struct {
+	contentv1.UnimplementedStatsServiceServer
+	getStatsProcesses map[string]GetStatsProcess
+	defaultSite       string
+}
+
+func NewHandler(processes map[string]GetStatsProcess, defaultSite string) *Handler {
+	return &Handler{
+		getStatsProcesses: processes,
+		defaultSite:       defaultSite,
+	}
+}
+
+func (h *Handler) Handle(ctx context.Context, req *contentv1.GetStatsRequest) (*contentv1.GetStatsResponse, error) {
+	site := req.GetSite()
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	process, ok := h.getStatsProcesses[site]
+	if !ok {
+		return nil, status.Error(codes.NotFound, appErrors.ErrSiteNotFound.Slug)
+	}
+
+	stats, err := process.Process(ctx, req.GetFrom(), req.GetTo())
+	if err != nil {
+		if errors.Is(err, appErrors.ErrInvalidInput) {
+			return nil, status.Error(codes.InvalidArgument, appErrors.ErrInvalidInput.Slug)
+		}
+		return nil, status.Error(codes.Internal, appErrors.ErrInternalServerError.Slug)
+	}
+
+	res := &contentv1.GetStatsResponse{Totals: stats.Totals}
+	for _, d := range stats.Days {
+		res.Days = append(res.Days, &contentv1.DailyStats{
+			Date:     d.Date.Format("2006-01-02"),
+			Counters: d.Counters,
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,83 @@
+package get_stats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/api/proto/v1"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processGetStats "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_stats"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockGetStatsProcess struct {
+	processFunc func(ctx context.Context, from, to string) (*processGetStats.Stats, error)
+}
+
+func (m *mockGetStatsProcess) Process(ctx context.Context, from, to string) (*processGetStats.Stats, error) {
+	return m.processFunc(ctx, from, to)
+}
+
+func TestHandler_GetStats(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *contentv1.GetStatsRequest
+		processFunc func(context.Context, string, string) (*processGetStats.Stats, error)
+		wantCode    codes.Code
+		wantDate    string
+	}{
+		{
+			name: "success",
+			req:  &contentv1.GetStatsRequest{From: "2026-03-01", To: "2026-03-01"},
+			processFunc: func(ctx context.Context, from, to string) (*processGetStats.Stats, error) {
+				return &processGetStats.Stats{
+					Days:   []processGetStats.Day{{Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Counters: map[string]int64{"content_requested:pl": 3}}},
+					Totals: map[string]int64{"content_requested:pl": 3},
+				}, nil
+			},
+			wantCode: codes.OK,
+			wantDate: "2026-03-01",
+		},
+		{
+			name: "invalid range",
+			req:  &contentv1.GetStatsRequest{From: "2026-03-02", To: "2026-03-01"},
+			processFunc: func(ctx context.Context, from, to string) (*processGetStats.Stats, error) {
+				return nil, appErrors.ErrInvalidInput
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "internal error",
+			req:  &contentv1.GetStatsRequest{},
+			processFunc: func(ctx context.Context, from, to string) (*processGetStats.Stats, error) {
+				return nil, errors.New("redis error")
+			},
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "unknown site",
+			req:      &contentv1.GetStatsRequest{Site: "other"},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(map[string]GetStatsProcess{"main": &mockGetStatsProcess{processFunc: tt.processFunc}}, "main")
+			res, err := h.Handle(context.Background(), tt.req)
+
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Handle() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+			if tt.wantCode != codes.OK {
+				return
+			}
+			if len(res.Days) != 1 || res.Days[0].Date != tt.wantDate || res.Totals["content_requested:pl"] != 3 {
+				t.Errorf("Handle() got = %+v", res)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package get_stats_json
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processGetStats "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_stats"
+)
+
+type GetStatsProcess interface {
+	Process(ctx context.Context, from, to string) (*processGetStats.Stats, error)
+}
+
+type Handler struct {
+	getStatsProcesses map[string]GetStatsProcess
+	defaultSite       string
+}
+
+type dayPayload struct {
+	Date     string           `json:"date"`
+	Counters map[string]int64 `json:"counters"`
+}
+
+type responsePayload struct {
+	Site   string           `json:"site"`
+	Days   []dayPayload     `json:"days"`
+	Totals map[string]int64 `json:"totals"`
+}
+
+func NewHandler(processes map[string]GetStatsProcess, defaultSite string) *Handler {
+	return &Handler{
+		getStatsProcesses: processes,
+		defaultSite:       defaultSite,
+	}
+}
+
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errors.WriteJSON(w, errors.ErrMethodNotAllowed)
+		return
+	}
+
+	site := r.URL.Query().Get("site")
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	process, ok := h.getStatsProcesses[site]
+	if !ok {
+		errors.WriteJSON(w, errors.ErrSiteNotFound)
+		return
+	}
+
+	stats, err := process.Process(r.Context(), r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		errors.WriteJSON(w, err)
+		return
+	}
+
+	response := responsePayload{Site: site, Days: []dayPayload{}, Totals: stats.Totals}
+	for _, d := range stats.Days {
+		response.Days = append(response.Days, dayPayload{Date: d.Date.Format("2006-01-02"), Counters: d.Counters})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
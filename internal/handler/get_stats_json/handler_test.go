@@ -0,0 +1,84 @@
+package get_stats_json
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processGetStats "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_stats"
+)
+
+type mockGetStatsProcess struct {
+	processFunc func(ctx context.Context, from, to string) (*processGetStats.Stats, error)
+}
+
+func (m *mockGetStatsProcess) Process(ctx context.Context, from, to string) (*processGetStats.Stats, error) {
+	return m.processFunc(ctx, from, to)
+}
+
+func TestHandler_GetStatsJson(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		processFunc func(context.Context, string, string) (*processGetStats.Stats, error)
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			name:   "success",
+			method: http.MethodGet,
+			url:    "/stats?from=2026-03-01&to=2026-03-01",
+			processFunc: func(ctx context.Context, from, to string) (*processGetStats.Stats, error) {
+				return &processGetStats.Stats{
+					Days:   []processGetStats.Day{{Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Counters: map[string]int64{"cv_token:issued": 2}}},
+					Totals: map[string]int64{"cv_token:issued": 2},
+				}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"site":"main","days":[{"date":"2026-03-01","counters":{"cv_token:issued":2}}],"totals":{"cv_token:issued":2}}`,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodPost,
+			url:        "/stats",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "invalid range",
+			method: http.MethodGet,
+			url:    "/stats?from=yesterday",
+			processFunc: func(ctx context.Context, from, to string) (*processGetStats.Stats, error) {
+				return nil, errors.ErrInvalidInput
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown site",
+			method:     http.MethodGet,
+			url:        "/stats?site=other",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(map[string]GetStatsProcess{"main": &mockGetStatsProcess{processFunc: tt.processFunc}}, "main")
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			h.Handle(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Handle() status = %v, wantStatus %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && strings.TrimSpace(w.Body.String()) != tt.wantBody {
+				t.Errorf("Handle() body = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package hash_password
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/password"
+)
+
+type HashPasswordProcess interface {
+	Process(ctx context.Context, plain string, algorithm password.Algorithm) (string, error)
+}
+
+type Handler struct {
+	hashPasswordProcess HashPasswordProcess
+	stdin               io.Reader
+	stdout              io.Writer
+}
+
+func NewHandler(process HashPasswordProcess, stdin io.Reader, stdout io.Writer) *Handler {
+	return &Handler{
+		hashPasswordProcess: process,
+		stdin:               stdin,
+		stdout:              stdout,
+	}
+}
+
+func (h *Handler) Handle(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("hash-password", flag.ContinueOnError)
+	algorithmName := fs.String("algorithm", string(password.AlgorithmArgon2id), "argon2id or bcrypt")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("the password is read from stdin, unexpected argument %q", fs.Arg(0))
+	}
+
+	algorithm, err := password.ParseAlgorithm(*algorithmName)
+	if err != nil {
+		return err
+	}
+
+	line, err := bufio.NewReader(h.stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	hash, err := h.hashPasswordProcess.Process(ctx, strings.TrimRight(line, "\r\n"), algorithm)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(h.stdout, hash)
+	return err
+}
@@ -0,0 +1,74 @@
+package hash_password
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/password"
+)
+
+type mockHashPasswordProcess struct {
+	processFunc func(ctx context.Context, plain string, algorithm password.Algorithm) (string, error)
+}
+
+func (m *mockHashPasswordProcess) Process(ctx context.Context, plain string, algorithm password.Algorithm) (string, error) {
+	return m.processFunc(ctx, plain, algorithm)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		wantOut string
+		wantErr bool
+	}{
+		{
+			name:    "defaults to argon2id",
+			stdin:   "secret\n",
+			wantOut: "argon2id:secret\n",
+		},
+		{
+			name:    "bcrypt without trailing newline",
+			args:    []string{"-algorithm", "bcrypt"},
+			stdin:   "secret",
+			wantOut: "bcrypt:secret\n",
+		},
+		{
+			name:    "strips windows line ending only",
+			stdin:   " secret \r\nignored\n",
+			wantOut: "argon2id: secret \n",
+		},
+		{
+			name:    "unsupported algorithm",
+			args:    []string{"-algorithm", "md5"},
+			stdin:   "secret\n",
+			wantErr: true,
+		},
+		{
+			name:    "password passed as argument",
+			args:    []string{"secret"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			m := &mockHashPasswordProcess{processFunc: func(ctx context.Context, plain string, algorithm password.Algorithm) (string, error) {
+				return string(algorithm) + ":" + plain, nil
+			}}
+			h := NewHandler(m, strings.NewReader(tt.stdin), &out)
+
+			err := h.Handle(context.Background(), tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("Handle() output = %q, want %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package import_translations
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/translation"
+	processImportTranslations "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/import_translations"
+)
+
+type ImportTranslationsProcess interface {
+	Process(ctx context.Context, r io.Reader, format translation.Format, sourceLang, targetLang string, opts processImportTranslations.Options) (*processImportTranslations.Report, error)
+}
+
+type Site struct {
+	Process     ImportTranslationsProcess
+	DefaultLang string
+}
+
+type Handler struct {
+	sites       map[string]Site
+	defaultSite string
+	stdout      io.Writer
+}
+
+func NewHandler(sites map[string]Site, defaultSite string, stdout io.Writer) *Handler {
+	return &Handler{
+		sites:       sites,
+		defaultSite: defaultSite,
+		stdout:      stdout,
+	}
+}
+
+func (h *Handler) Handle(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import-translations", flag.ContinueOnError)
+	siteName := fs.String("site", h.defaultSite, "site whose content files are used")
+	sourceLang := fs.String("source", "", "source language (defaults to the site's default language)")
+	targetLang := fs.String("target", "", "target language")
+	formatName := fs.String("format", "", "xliff or po (defaults to the -in extension)")
+	in := fs.String("in", "", "translation file to import")
+	dryRun := fs.Bool("dry-run", false, "validate and report without writing the content file")
+	strict := fs.Bool("strict", false, "fail when any entry is stale or untranslated")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *targetLang == "" || *in == "" {
+		return errors.New("-target and -in are required")
+	}
+
+	site, ok := h.sites[*siteName]
+	if !ok {
+		return fmt.Errorf("unknown site %q", *siteName)
+	}
+	if *sourceLang == "" {
+		*sourceLang = site.DefaultLang
+	}
+
+	var format translation.Format
+	var err error
+	if *formatName != "" {
+		format, err = translation.ParseFormat(*formatName)
+	} else {
+		format, err = translation.FormatFromPath(*in)
+	}
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	opts := processImportTranslations.Options{DryRun: *dryRun, Strict: *strict}
+	report, err := site.Process.Process(ctx, f, format, *sourceLang, *targetLang, opts)
+	if report != nil {
+		h.writeReport(report)
+	}
+
+	return err
+}
+
+func (h *Handler) writeReport(report *processImportTranslations.Report) {
+	fmt.Fprintf(h.stdout, "translated: %d\n", len(report.Translated))
+	fmt.Fprintf(h.stdout, "untranslated: %d\n", len(report.Untranslated))
+	for _, path := range report.Untranslated {
+		fmt.Fprintf(h.stdout, "  %s\n", path)
+	}
+	fmt.Fprintf(h.stdout, "stale: %d\n", len(report.Stale))
+	for _, path := range report.Stale {
+		fmt.Fprintf(h.stdout, "  %s\n", path)
+	}
+}
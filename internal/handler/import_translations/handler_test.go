@@ -0,0 +1,100 @@
+package import_translations
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/translation"
+	processImportTranslations "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/import_translations"
+)
+
+type mockImportTranslationsProcess struct {
+	processFunc func(ctx context.Context, r io.Reader, format translation.Format, sourceLang, targetLang string, opts processImportTranslations.Options) (*processImportTranslations.Report, error)
+}
+
+func (m *mockImportTranslationsProcess) Process(ctx context.Context, r io.Reader, format translation.Format, sourceLang, targetLang string, opts processImportTranslations.Options) (*processImportTranslations.Report, error) {
+	return m.processFunc(ctx, r, format, sourceLang, targetLang, opts)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	in := filepath.Join(t.TempDir(), "en.po")
+	os.WriteFile(in, []byte(""), 0644)
+
+	tests := []struct {
+		name        string
+		args        []string
+		processFunc func(context.Context, io.Reader, translation.Format, string, string, processImportTranslations.Options) (*processImportTranslations.Report, error)
+		wantOut     string
+		wantErr     bool
+	}{
+		{
+			name: "success",
+			args: []string{"-target", "en", "-in", in, "-dry-run"},
+			processFunc: func(ctx context.Context, r io.Reader, f translation.Format, s, tl string, o processImportTranslations.Options) (*processImportTranslations.Report, error) {
+				if f != translation.FormatPO || s != "pl" || tl != "en" || !o.DryRun {
+					return nil, errors.New("unexpected arguments")
+				}
+				return &processImportTranslations.Report{Translated: []string{"a"}, Stale: []string{"b"}}, nil
+			},
+			wantOut: "translated: 1\nuntranslated: 0\nstale: 1\n  b\n",
+		},
+		{
+			name: "strict failure still reports",
+			args: []string{"-target", "en", "-in", in, "-strict"},
+			processFunc: func(ctx context.Context, r io.Reader, f translation.Format, s, tl string, o processImportTranslations.Options) (*processImportTranslations.Report, error) {
+				return &processImportTranslations.Report{Untranslated: []string{"a"}}, processImportTranslations.ErrIncomplete
+			},
+			wantOut: "translated: 0\nuntranslated: 1\n  a\nstale: 0\n",
+			wantErr: true,
+		},
+		{
+			name: "other site uses its default language",
+			args: []string{"-site", "blog", "-target", "en", "-in", in},
+			processFunc: func(ctx context.Context, r io.Reader, f translation.Format, s, tl string, o processImportTranslations.Options) (*processImportTranslations.Report, error) {
+				if s != "de" {
+					return nil, errors.New("unexpected source language")
+				}
+				return &processImportTranslations.Report{}, nil
+			},
+			wantOut: "translated: 0\nuntranslated: 0\nstale: 0\n",
+		},
+		{
+			name:    "unknown site",
+			args:    []string{"-site", "other", "-target", "en", "-in", in},
+			wantErr: true,
+		},
+		{
+			name:    "missing target",
+			args:    []string{"-in", in},
+			wantErr: true,
+		},
+		{
+			name:    "unknown extension",
+			args:    []string{"-target", "en", "-in", "en.csv"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			h := NewHandler(map[string]Site{
+				"main": {Process: &mockImportTranslationsProcess{processFunc: tt.processFunc}, DefaultLang: "pl"},
+				"blog": {Process: &mockImportTranslationsProcess{processFunc: tt.processFunc}, DefaultLang: "de"},
+			}, "main", &out)
+
+			err := h.Handle(context.Background(), tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("Handle() output = %q, want %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package manage_access_requests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/accessrequest"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processManageAccessRequests "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/manage_access_requests"
+)
+
+const Path = "/admin/cv-access-requests"
+
+type ManageAccessRequestsProcess interface {
+	List(ctx context.Context) ([]accessrequest.Request, error)
+	Inspect(ctx context.Context, id string) (*accessrequest.Request, error)
+	Approve(ctx context.Context, id string) (*processManageAccessRequests.Approval, error)
+	Deny(ctx context.Context, id string) error
+}
+
+type Handler struct {
+	manageAccessRequestsProcesses map[string]ManageAccessRequestsProcess
+	defaultSite                   string
+}
+
+type requestPayload struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Company   string `json:"company"`
+	Reason    string `json:"reason"`
+	Lang      string `json:"lang"`
+	Email     string `json:"email,omitempty"`
+	Client    string `json:"client,omitempty"`
+	CreatedAt string `json:"createdAt"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+type listPayload struct {
+	Site     string           `json:"site"`
+	Requests []requestPayload `json:"requests"`
+}
+
+type approvalPayload struct {
+	ID        string `json:"id"`
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expiresAt"`
+	Emailed   bool   `json:"emailed"`
+}
+
+type denialPayload struct {
+	Denied int `json:"denied"`
+}
+
+func NewHandler(processes map[string]ManageAccessRequestsProcess, defaultSite string) *Handler {
+	return &Handler{
+		manageAccessRequestsProcesses: processes,
+		defaultSite:                   defaultSite,
+	}
+}
+
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	site := r.URL.Query().Get("site")
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	process, ok := h.manageAccessRequestsProcesses[site]
+	if !ok {
+		errors.WriteJSON(w, errors.ErrSiteNotFound)
+		return
+	}
+
+	id, action, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, Path), "/"), "/")
+
+	var response any
+	var err error
+	switch {
+	case r.Method == http.MethodGet && id == "":
+		var requests []accessrequest.Request
+		if requests, err = process.List(r.Context()); err == nil {
+			list := listPayload{Site: site, Requests: []requestPayload{}}
+			for _, req := range requests {
+				list.Requests = append(list.Requests, toPayload(req))
+			}
+			response = list
+		}
+	case r.Method == http.MethodGet && action == "":
+		var req *accessrequest.Request
+		if req, err = process.Inspect(r.Context(), id); err == nil {
+			response = toPayload(*req)
+		}
+	case r.Method == http.MethodPost && action == "approve":
+		var approval *processManageAccessRequests.Approval
+		if approval, err = process.Approve(r.Context(), id); err == nil {
+			response = approvalPayload{
+				ID:        approval.Request.ID,
+				Token:     approval.Issue.Token,
+				ExpiresAt: approval.Issue.ExpiresAt.UTC().Format(time.RFC3339),
+				Emailed:   approval.Emailed,
+			}
+		}
+	case r.Method == http.MethodPost && action == "deny":
+		if err = process.Deny(r.Context(), id); err == nil {
+			response = denialPayload{Denied: 1}
+		}
+	default:
+		err = errors.ErrMethodNotAllowed
+	}
+
+	if err != nil {
+		errors.WriteJSON(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func toPayload(req accessrequest.Request) requestPayload {
+	return requestPayload{
+		ID:        req.ID,
+		Name:      req.Name,
+		Company:   req.Company,
+		Reason:    req.Reason,
+		Lang:      req.Lang,
+		Email:     req.Email,
+		Client:    req.Client,
+		CreatedAt: req.CreatedAt.UTC().Format(time.RFC3339),
+		ExpiresAt: req.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,156 @@
+package manage_access_requests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/accessrequest"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processManageAccessRequests "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/manage_access_requests"
+)
+
+var pending = accessrequest.Request{
+	ID:        "5f2c",
+	Name:      "Anna",
+	Company:   "Acme",
+	Reason:    "Hiring a Go developer.",
+	Lang:      "en",
+	CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	ExpiresAt: time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC),
+}
+
+type mockManageAccessRequestsProcess struct {
+	calls []string
+}
+
+func (m *mockManageAccessRequestsProcess) List(ctx context.Context) ([]accessrequest.Request, error) {
+	m.calls = append(m.calls, "list")
+	return []accessrequest.Request{pending}, nil
+}
+
+func (m *mockManageAccessRequestsProcess) Inspect(ctx context.Context, id string) (*accessrequest.Request, error) {
+	m.calls = append(m.calls, "inspect:"+id)
+	if id != pending.ID {
+		return nil, errors.ErrAccessRequestNotFound
+	}
+	return &pending, nil
+}
+
+func (m *mockManageAccessRequestsProcess) Approve(ctx context.Context, id string) (*processManageAccessRequests.Approval, error) {
+	m.calls = append(m.calls, "approve:"+id)
+	if id != pending.ID {
+		return nil, errors.ErrAccessRequestNotFound
+	}
+	return &processManageAccessRequests.Approval{
+		Request: pending,
+		Issue:   cvtoken.Issue{Token: "token", ExpiresAt: time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)},
+		Emailed: true,
+	}, nil
+}
+
+func (m *mockManageAccessRequestsProcess) Deny(ctx context.Context, id string) error {
+	m.calls = append(m.calls, "deny:"+id)
+	if id != pending.ID {
+		return errors.ErrAccessRequestNotFound
+	}
+	return nil
+}
+
+func TestHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		wantStatus int
+		wantCall   string
+		wantBody   string
+	}{
+		{
+			name:       "list",
+			method:     http.MethodGet,
+			url:        "/admin/cv-access-requests",
+			wantStatus: http.StatusOK,
+			wantCall:   "list",
+			wantBody:   `"requests":[{"id":"5f2c","name":"Anna","company":"Acme","reason":"Hiring a Go developer.","lang":"en","createdAt":"2026-03-01T12:00:00Z","expiresAt":"2026-03-04T12:00:00Z"}]`,
+		},
+		{
+			name:       "inspect",
+			method:     http.MethodGet,
+			url:        "/admin/cv-access-requests/5f2c",
+			wantStatus: http.StatusOK,
+			wantCall:   "inspect:5f2c",
+			wantBody:   `"id":"5f2c"`,
+		},
+		{
+			name:       "approve",
+			method:     http.MethodPost,
+			url:        "/admin/cv-access-requests/5f2c/approve",
+			wantStatus: http.StatusOK,
+			wantCall:   "approve:5f2c",
+			wantBody:   `{"id":"5f2c","token":"token","expiresAt":"2026-03-02T12:00:00Z","emailed":true}`,
+		},
+		{
+			name:       "approve unknown",
+			method:     http.MethodPost,
+			url:        "/admin/cv-access-requests/other/approve",
+			wantStatus: http.StatusNotFound,
+			wantCall:   "approve:other",
+			wantBody:   `error_access_request_not_found`,
+		},
+		{
+			name:       "deny",
+			method:     http.MethodPost,
+			url:        "/admin/cv-access-requests/5f2c/deny",
+			wantStatus: http.StatusOK,
+			wantCall:   "deny:5f2c",
+			wantBody:   `{"denied":1}`,
+		},
+		{
+			name:       "unknown action",
+			method:     http.MethodPost,
+			url:        "/admin/cv-access-requests/5f2c/ignore",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodDelete,
+			url:        "/admin/cv-access-requests/5f2c",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "unknown site",
+			method:     http.MethodGet,
+			url:        "/admin/cv-access-requests?site=other",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockManageAccessRequestsProcess{}
+			h := NewHandler(map[string]ManageAccessRequestsProcess{"main": m}, "main")
+			w := httptest.NewRecorder()
+
+			h.Handle(w, httptest.NewRequest(tt.method, tt.url, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Handle() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantCall != "" && (len(m.calls) != 1 || m.calls[0] != tt.wantCall) {
+				t.Errorf("Handle() calls = %v, want %s", m.calls, tt.wantCall)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("Handle() body = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+			if w.Code == http.StatusOK && !json.Valid(w.Body.Bytes()) {
+				t.Errorf("Handle() body is not JSON: %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package manage_cv_lockouts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+)
+
+const Path = "/admin/cv-lockouts"
+
+type ManageCVLockoutsProcess interface {
+	List(ctx context.Context) ([]lockout.State, error)
+	Clear(ctx context.Context, client string) error
+}
+
+type Handler struct {
+	manageCVLockoutsProcesses map[string]ManageCVLockoutsProcess
+	defaultSite               string
+	now                       func() time.Time
+}
+
+type lockoutPayload struct {
+	Client      string `json:"client"`
+	Failures    int    `json:"failures"`
+	Lockouts    int    `json:"lockouts"`
+	Locked      bool   `json:"locked"`
+	LockedUntil string `json:"lockedUntil,omitempty"`
+	LastFailure string `json:"lastFailure"`
+}
+
+type listPayload struct {
+	Site     string           `json:"site"`
+	Lockouts []lockoutPayload `json:"lockouts"`
+}
+
+type clearPayload struct {
+	Cleared int `json:"cleared"`
+}
+
+func NewHandler(processes map[string]ManageCVLockoutsProcess, defaultSite string) *Handler {
+	return &Handler{
+		manageCVLockoutsProcesses: processes,
+		defaultSite:               defaultSite,
+		now:                       time.Now,
+	}
+}
+
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	site := r.URL.Query().Get("site")
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	process, ok := h.manageCVLockoutsProcesses[site]
+	if !ok {
+		errors.WriteJSON(w, errors.ErrSiteNotFound)
+		return
+	}
+
+	client := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, Path), "/")
+
+	var response any
+	var err error
+	switch {
+	case r.Method == http.MethodGet && client == "":
+		var states []lockout.State
+		if states, err = process.List(r.Context()); err == nil {
+			list := listPayload{Site: site, Lockouts: []lockoutPayload{}}
+			for _, state := range states {
+				list.Lockouts = append(list.Lockouts, h.toPayload(state))
+			}
+			response = list
+		}
+	case r.Method == http.MethodDelete:
+		if err = process.Clear(r.Context(), client); err == nil {
+			response = clearPayload{Cleared: 1}
+		}
+	default:
+		err = errors.ErrMethodNotAllowed
+	}
+
+	if err != nil {
+		errors.WriteJSON(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) toPayload(state lockout.State) lockoutPayload {
+	payload := lockoutPayload{
+		Client:      state.Client,
+		Failures:    state.Failures,
+		Lockouts:    state.Level,
+		Locked:      state.Locked(h.now()),
+		LastFailure: state.LastFailure.UTC().Format(time.RFC3339),
+	}
+	if !state.LockedUntil.IsZero() {
+		payload.LockedUntil = state.LockedUntil.UTC().Format(time.RFC3339)
+	}
+
+	return payload
+}
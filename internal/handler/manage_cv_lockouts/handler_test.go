@@ -0,0 +1,120 @@
+package manage_cv_lockouts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+)
+
+type mockManageCVLockoutsProcess struct {
+	calls []string
+}
+
+func (m *mockManageCVLockoutsProcess) List(ctx context.Context) ([]lockout.State, error) {
+	m.calls = append(m.calls, "list")
+	return []lockout.State{{
+		Client:      "fp",
+		Failures:    1,
+		Level:       2,
+		LockedUntil: time.Date(2026, 3, 1, 12, 10, 0, 0, time.UTC),
+		LastFailure: time.Date(2026, 3, 1, 11, 50, 0, 0, time.UTC),
+	}}, nil
+}
+
+func (m *mockManageCVLockoutsProcess) Clear(ctx context.Context, client string) error {
+	m.calls = append(m.calls, "clear:"+client)
+	switch client {
+	case "":
+		return errors.ErrInvalidInput
+	case "fp":
+		return nil
+	default:
+		return errors.ErrLockoutNotFound
+	}
+}
+
+func TestHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		wantStatus int
+		wantCall   string
+		wantBody   string
+	}{
+		{
+			name:       "list",
+			method:     http.MethodGet,
+			url:        "/admin/cv-lockouts",
+			wantStatus: http.StatusOK,
+			wantCall:   "list",
+			wantBody:   `"lockouts":[{"client":"fp","failures":1,"lockouts":2,"locked":true,"lockedUntil":"2026-03-01T12:10:00Z","lastFailure":"2026-03-01T11:50:00Z"}]`,
+		},
+		{
+			name:       "clear",
+			method:     http.MethodDelete,
+			url:        "/admin/cv-lockouts/fp",
+			wantStatus: http.StatusOK,
+			wantCall:   "clear:fp",
+			wantBody:   `{"cleared":1}`,
+		},
+		{
+			name:       "clear unknown",
+			method:     http.MethodDelete,
+			url:        "/admin/cv-lockouts/other",
+			wantStatus: http.StatusNotFound,
+			wantCall:   "clear:other",
+			wantBody:   `error_lockout_not_found`,
+		},
+		{
+			name:       "clear without client",
+			method:     http.MethodDelete,
+			url:        "/admin/cv-lockouts",
+			wantStatus: http.StatusBadRequest,
+			wantCall:   "clear:",
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodPost,
+			url:        "/admin/cv-lockouts",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "unknown site",
+			method:     http.MethodGet,
+			url:        "/admin/cv-lockouts?site=other",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockManageCVLockoutsProcess{}
+			h := NewHandler(map[string]ManageCVLockoutsProcess{"main": m}, "main")
+			h.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+			w := httptest.NewRecorder()
+
+			h.Handle(w, httptest.NewRequest(tt.method, tt.url, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Handle() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantCall != "" && (len(m.calls) != 1 || m.calls[0] != tt.wantCall) {
+				t.Errorf("Handle() calls = %v, want %s", m.calls, tt.wantCall)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("Handle() body = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+			if w.Code == http.StatusOK && !json.Valid(w.Body.Bytes()) {
+				t.Errorf("Handle() body is not JSON: %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package manage_cv_tokens
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processManageCvTokens "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/manage_cv_tokens"
+)
+
+const Path = "/admin/cv-tokens"
+
+type ManageCVTokensProcess interface {
+	List(ctx context.Context, credential string) ([]processManageCvTokens.TokenInfo, error)
+	Inspect(ctx context.Context, token string) (*processManageCvTokens.TokenInfo, error)
+	Revoke(ctx context.Context, token string) error
+	RevokeCredential(ctx context.Context, credential string) (int, error)
+}
+
+type Handler struct {
+	manageCVTokensProcesses map[string]ManageCVTokensProcess
+	defaultSite             string
+}
+
+type tokenPayload struct {
+	Token       string `json:"token"`
+	Lang        string `json:"lang"`
+	Document    string `json:"document"`
+	Credential  string `json:"credential"`
+	CaptchaID   string `json:"captchaId"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	IssuedAt    string `json:"issuedAt"`
+	FirstUsedAt string `json:"firstUsedAt,omitempty"`
+	Remaining   int64  `json:"remainingDownloads"`
+	TTLSeconds  int64  `json:"ttlSeconds"`
+}
+
+type listPayload struct {
+	Site   string         `json:"site"`
+	Tokens []tokenPayload `json:"tokens"`
+}
+
+type revokePayload struct {
+	Revoked int `json:"revoked"`
+}
+
+func NewHandler(processes map[string]ManageCVTokensProcess, defaultSite string) *Handler {
+	return &Handler{
+		manageCVTokensProcesses: processes,
+		defaultSite:             defaultSite,
+	}
+}
+
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	site := r.URL.Query().Get("site")
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	process, ok := h.manageCVTokensProcesses[site]
+	if !ok {
+		errors.WriteJSON(w, errors.ErrSiteNotFound)
+		return
+	}
+
+	token := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, Path), "/")
+	credential := r.URL.Query().Get("credential")
+
+	var response any
+	var err error
+	switch {
+	case r.Method == http.MethodGet && token == "":
+		var tokens []processManageCvTokens.TokenInfo
+		if tokens, err = process.List(r.Context(), credential); err == nil {
+			list := listPayload{Site: site, Tokens: []tokenPayload{}}
+			for _, info := range tokens {
+				list.Tokens = append(list.Tokens, toPayload(info))
+			}
+			response = list
+		}
+	case r.Method == http.MethodGet:
+		var info *processManageCvTokens.TokenInfo
+		if info, err = process.Inspect(r.Context(), token); err == nil {
+			response = toPayload(*info)
+		}
+	case r.Method == http.MethodDelete && token == "":
+		var n int
+		if n, err = process.RevokeCredential(r.Context(), credential); err == nil {
+			response = revokePayload{Revoked: n}
+		}
+	case r.Method == http.MethodDelete:
+		if err = process.Revoke(r.Context(), token); err == nil {
+			response = revokePayload{Revoked: 1}
+		}
+	default:
+		err = errors.ErrMethodNotAllowed
+	}
+
+	if err != nil {
+		errors.WriteJSON(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func toPayload(info processManageCvTokens.TokenInfo) tokenPayload {
+	payload := tokenPayload{
+		Token:       info.Token,
+		Lang:        info.Metadata.Lang,
+		Document:    info.Metadata.Document,
+		Credential:  info.Metadata.Credential,
+		CaptchaID:   info.Metadata.CaptchaID,
+		Fingerprint: info.Metadata.Fingerprint,
+		IssuedAt:    info.Metadata.IssuedAt.Format(time.RFC3339),
+		Remaining:   info.Remaining,
+		TTLSeconds:  int64(info.TTL / time.Second),
+	}
+	if !info.FirstUsed.IsZero() {
+		payload.FirstUsedAt = info.FirstUsed.UTC().Format(time.RFC3339)
+	}
+
+	return payload
+}
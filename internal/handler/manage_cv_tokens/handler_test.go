@@ -0,0 +1,149 @@
+package manage_cv_tokens
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processManageCvTokens "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/manage_cv_tokens"
+)
+
+type mockManageCVTokensProcess struct {
+	calls []string
+}
+
+func (m *mockManageCVTokensProcess) List(ctx context.Context, credential string) ([]processManageCvTokens.TokenInfo, error) {
+	m.calls = append(m.calls, "list:"+credential)
+	return []processManageCvTokens.TokenInfo{info("t1")}, nil
+}
+
+func (m *mockManageCVTokensProcess) Inspect(ctx context.Context, token string) (*processManageCvTokens.TokenInfo, error) {
+	m.calls = append(m.calls, "inspect:"+token)
+	if token != "t1" {
+		return nil, errors.ErrCVTokenNotFound
+	}
+	i := info(token)
+	return &i, nil
+}
+
+func (m *mockManageCVTokensProcess) Revoke(ctx context.Context, token string) error {
+	m.calls = append(m.calls, "revoke:"+token)
+	return nil
+}
+
+func (m *mockManageCVTokensProcess) RevokeCredential(ctx context.Context, credential string) (int, error) {
+	m.calls = append(m.calls, "revoke-credential:"+credential)
+	if credential == "" {
+		return 0, errors.ErrInvalidInput
+	}
+	return 3, nil
+}
+
+func info(token string) processManageCvTokens.TokenInfo {
+	return processManageCvTokens.TokenInfo{
+		Token:     token,
+		Metadata:  cvtoken.Metadata{Lang: "pl", Credential: "acme", IssuedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+		Remaining: 2,
+		TTL:       45 * time.Second,
+	}
+}
+
+func TestHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		wantStatus int
+		wantCall   string
+		wantBody   string
+	}{
+		{
+			name:       "list",
+			method:     http.MethodGet,
+			url:        "/admin/cv-tokens?credential=acme",
+			wantStatus: http.StatusOK,
+			wantCall:   "list:acme",
+			wantBody:   `"tokens":[{"token":"t1","lang":"pl","document":"","credential":"acme","captchaId":"","issuedAt":"2026-03-01T12:00:00Z","remainingDownloads":2,"ttlSeconds":45}]`,
+		},
+		{
+			name:       "inspect",
+			method:     http.MethodGet,
+			url:        "/admin/cv-tokens/t1",
+			wantStatus: http.StatusOK,
+			wantCall:   "inspect:t1",
+			wantBody:   `"token":"t1"`,
+		},
+		{
+			name:       "inspect unknown",
+			method:     http.MethodGet,
+			url:        "/admin/cv-tokens/t2",
+			wantStatus: http.StatusNotFound,
+			wantCall:   "inspect:t2",
+			wantBody:   `error_cv_token_not_found`,
+		},
+		{
+			name:       "revoke",
+			method:     http.MethodDelete,
+			url:        "/admin/cv-tokens/t1",
+			wantStatus: http.StatusOK,
+			wantCall:   "revoke:t1",
+			wantBody:   `{"revoked":1}`,
+		},
+		{
+			name:       "revoke credential",
+			method:     http.MethodDelete,
+			url:        "/admin/cv-tokens?credential=acme",
+			wantStatus: http.StatusOK,
+			wantCall:   "revoke-credential:acme",
+			wantBody:   `{"revoked":3}`,
+		},
+		{
+			name:       "revoke everything is refused",
+			method:     http.MethodDelete,
+			url:        "/admin/cv-tokens",
+			wantStatus: http.StatusBadRequest,
+			wantCall:   "revoke-credential:",
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodPost,
+			url:        "/admin/cv-tokens",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "unknown site",
+			method:     http.MethodGet,
+			url:        "/admin/cv-tokens?site=other",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockManageCVTokensProcess{}
+			h := NewHandler(map[string]ManageCVTokensProcess{"main": m}, "main")
+			w := httptest.NewRecorder()
+
+			h.Handle(w, httptest.NewRequest(tt.method, tt.url, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Handle() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantCall != "" && (len(m.calls) != 1 || m.calls[0] != tt.wantCall) {
+				t.Errorf("Handle() calls = %v, want %s", m.calls, tt.wantCall)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("Handle() body = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+			if w.Code == http.StatusOK && !json.Valid(w.Body.Bytes()) {
+				t.Errorf("Handle() body is not JSON: %s", w.Body.String())
+			}
+		})
+	}
+}
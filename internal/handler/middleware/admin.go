@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func RequireAdminToken(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !validAdminToken(token, r.Header.Get("Authorization")) {
+			errors.WriteJSON(w, errors.ErrUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// RequireAdminTokenRPC checks the authorization metadata of unary calls to the given gRPC services and lets calls to other services through.
+func RequireAdminTokenRPC(token string, services ...string) grpc.UnaryServerInterceptor {
+	protected := make(map[string]bool, len(services))
+	for _, service := range services {
+		protected[service] = true
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		service, _, _ := strings.Cut(strings.TrimPrefix(info.FullMethod, "/"), "/")
+		if protected[service] {
+			var authorization string
+			if md, ok := metadata.FromIncomingContext(ctx); ok {
+				if values := md.Get("authorization"); len(values) > 0 {
+					authorization = values[0]
+				}
+			}
+			if !validAdminToken(token, authorization) {
+				return nil, status.Error(codes.Unauthenticated, errors.ErrUnauthorized.Slug)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func validAdminToken(token, authorization string) bool {
+	given, ok := strings.CutPrefix(authorization, "Bearer ")
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequireAdminToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		wantStatus    int
+	}{
+		{
+			name:          "valid token",
+			token:         "secret",
+			authorization: "Bearer secret",
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "wrong token",
+			token:         "secret",
+			authorization: "Bearer guess",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:       "missing header",
+			token:      "secret",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "no token configured",
+			token:         "",
+			authorization: "Bearer ",
+			wantStatus:    http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := RequireAdminToken(tt.token, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			h(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("RequireAdminToken() status = %v, wantStatus %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRequireAdminTokenRPC(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		method        string
+		authorization string
+		wantCode      codes.Code
+	}{
+		{
+			name:          "valid token",
+			token:         "secret",
+			method:        "/content.v1.StatsService/Handle",
+			authorization: "Bearer secret",
+			wantCode:      codes.OK,
+		},
+		{
+			name:          "wrong token",
+			token:         "secret",
+			method:        "/content.v1.AuditService/Handle",
+			authorization: "Bearer guess",
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:     "missing metadata",
+			token:    "secret",
+			method:   "/content.v1.StatsService/Handle",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:          "no token configured",
+			token:         "",
+			method:        "/content.v1.StatsService/Handle",
+			authorization: "Bearer ",
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:     "unprotected service",
+			token:    "secret",
+			method:   "/content.v1.ContentService/GetContent",
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := RequireAdminTokenRPC(tt.token, "content.v1.StatsService", "content.v1.AuditService")
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+			}
+
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req any) (any, error) {
+				return "ok", nil
+			})
+
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("RequireAdminTokenRPC() code = %v, wantCode %v", code, tt.wantCode)
+			}
+		})
+	}
+}
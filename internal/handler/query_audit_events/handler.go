@@ -0,0 +1,82 @@
+package query_audit_events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/api/proto/v1"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processQueryAuditEvents "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/query_audit_events"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type QueryAuditEventsProcess interface {
+	Process(ctx context.Context, q processQueryAuditEvents.Query) ([]audit.Event, error)
+}
+
+type Handler struct {
+	contentv1.UnimplementedAuditServiceServer
+	queryAuditEventsProcesses map[string]QueryAuditEventsProcess
+	defaultSite               string
+}
+
+func NewHandler(processes map[string]QueryAuditEventsProcess, defaultSite string) *Handler {
+	return &Handler{
+		queryAuditEventsProcesses: processes,
+		defaultSite:               defaultSite,
+	}
+}
+
+func (h *Handler) Handle(ctx context.Context, req *contentv1.QueryAuditEventsRequest) (*contentv1.QueryAuditEventsResponse, error) {
+	site := req.GetSite()
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	process, ok := h.queryAuditEventsProcesses[site]
+	if !ok {
+		return nil, status.Error(codes.NotFound, appErrors.ErrSiteNotFound.Slug)
+	}
+
+	events, err := process.Process(ctx, processQueryAuditEvents.Query{
+		From:          req.GetFrom(),
+		To:            req.GetTo(),
+		Types:         req.GetTypes(),
+		Credential:    req.GetCredential(),
+		CorrelationID: req.GetCorrelationId(),
+		Limit:         int(req.GetLimit()),
+	})
+	if err != nil {
+		if errors.Is(err, appErrors.ErrInvalidInput) {
+			return nil, status.Error(codes.InvalidArgument, appErrors.ErrInvalidInput.Slug)
+		}
+		return nil, status.Error(codes.Internal, appErrors.ErrInternalServerError.Slug)
+	}
+
+	res := &contentv1.QueryAuditEventsResponse{}
+	for _, e := range events {
+		event := &contentv1.AuditEvent{
+			Id:            e.ID,
+			Time:          e.Time.Format(time.RFC3339Nano),
+			Type:          string(e.Type),
+			Site:          e.Site,
+			CorrelationId: e.CorrelationID,
+			Lang:          e.Lang,
+			Document:      e.Document,
+			Credential:    e.Credential,
+			CaptchaId:     e.CaptchaID,
+			Client:        e.Client,
+			Token:         e.Token,
+			Detail:        e.Detail,
+		}
+		if !e.ExpiresAt.IsZero() {
+			event.ExpiresAt = e.ExpiresAt.Format(time.RFC3339)
+		}
+		res.Events = append(res.Events, event)
+	}
+
+	return res, nil
+}
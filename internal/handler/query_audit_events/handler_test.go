@@ -0,0 +1,96 @@
+package query_audit_events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/api/proto/v1"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processQueryAuditEvents "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/query_audit_events"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockQueryAuditEventsProcess struct {
+	processFunc func(ctx context.Context, q processQueryAuditEvents.Query) ([]audit.Event, error)
+}
+
+func (m *mockQueryAuditEventsProcess) Process(ctx context.Context, q processQueryAuditEvents.Query) ([]audit.Event, error) {
+	return m.processFunc(ctx, q)
+}
+
+func TestHandler_QueryAuditEvents(t *testing.T) {
+	at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		req         *contentv1.QueryAuditEventsRequest
+		processFunc func(context.Context, processQueryAuditEvents.Query) ([]audit.Event, error)
+		wantCode    codes.Code
+		wantEvents  int
+	}{
+		{
+			name: "success",
+			req:  &contentv1.QueryAuditEventsRequest{From: "2026-10-19", Types: []string{"token_issued"}, Credential: "acme", CorrelationId: "c1", Limit: 10},
+			processFunc: func(ctx context.Context, q processQueryAuditEvents.Query) ([]audit.Event, error) {
+				if q.From != "2026-10-19" || len(q.Types) != 1 || q.Credential != "acme" || q.CorrelationID != "c1" || q.Limit != 10 {
+					return nil, errors.New("query not passed on")
+				}
+				return []audit.Event{
+					{ID: "1", Time: at, Type: audit.EventTokenIssued, Credential: "acme", ExpiresAt: at.Add(time.Hour)},
+					{ID: "2", Time: at, Type: audit.EventTokenRedeemed},
+				}, nil
+			},
+			wantCode:   codes.OK,
+			wantEvents: 2,
+		},
+		{
+			name: "invalid query",
+			req:  &contentv1.QueryAuditEventsRequest{From: "yesterday"},
+			processFunc: func(ctx context.Context, q processQueryAuditEvents.Query) ([]audit.Event, error) {
+				return nil, appErrors.ErrInvalidInput
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "internal error",
+			req:  &contentv1.QueryAuditEventsRequest{},
+			processFunc: func(ctx context.Context, q processQueryAuditEvents.Query) ([]audit.Event, error) {
+				return nil, appErrors.ErrInternalServerError
+			},
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "unknown site",
+			req:      &contentv1.QueryAuditEventsRequest{Site: "other"},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(map[string]QueryAuditEventsProcess{"main": &mockQueryAuditEventsProcess{processFunc: tt.processFunc}}, "main")
+
+			res, err := h.Handle(context.Background(), tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Handle() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if len(res.Events) != tt.wantEvents {
+				t.Fatalf("Handle() events = %d, want %d", len(res.Events), tt.wantEvents)
+			}
+			first := res.Events[0]
+			if first.Time != "2026-10-19T12:00:00Z" || first.Type != "token_issued" || first.ExpiresAt != "2026-10-19T13:00:00Z" {
+				t.Errorf("Handle() event = %v", first)
+			}
+			if res.Events[1].ExpiresAt != "" {
+				t.Errorf("Handle() expiresAt = %q, want empty", res.Events[1].ExpiresAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package request_cv_token
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/api/proto/v1"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pow"
+	processGetCvToken "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_cv_token"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const errorDomain = "content-service"
+
+type GetCVTokenProcess interface {
+	Process(ctx context.Context, req processGetCvToken.Request) (string, error)
+}
+
+type Handler struct {
+	contentv1.UnimplementedCvTokenServiceServer
+	getCVTokenProcesses map[string]GetCVTokenProcess
+	defaultSite         string
+}
+
+func NewHandler(processes map[string]GetCVTokenProcess, defaultSite string) *Handler {
+	return &Handler{
+		getCVTokenProcesses: processes,
+		defaultSite:         defaultSite,
+	}
+}
+
+func (h *Handler) Handle(ctx context.Context, req *contentv1.RequestCvTokenRequest) (*contentv1.RequestCvTokenResponse, error) {
+	site := req.GetSite()
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	process, ok := h.getCVTokenProcesses[site]
+	if !ok {
+		return nil, statusError(appErrors.ErrSiteNotFound)
+	}
+
+	token, err := process.Process(ctx, processGetCvToken.Request{
+		Password:  req.GetPassword(),
+		Lang:      req.GetLang(),
+		CaptchaID: req.GetCaptchaId(),
+		Client:    req.GetClient(),
+		IP:        req.GetIp(),
+		Pow: pow.Solution{
+			Challenge: req.GetPow().GetChallenge(),
+			Signature: req.GetPow().GetSignature(),
+			Nonce:     req.GetPow().GetNonce(),
+		},
+		Email: req.GetEmail(),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &contentv1.RequestCvTokenResponse{Token: token}, nil
+}
+
+func statusError(err error) error {
+	var appErr *appErrors.AppError
+	if !errors.As(err, &appErr) {
+		appErr = appErrors.ErrInternalServerError
+	}
+
+	st := status.New(grpcCode(appErr.HTTPStatus), appErr.Slug)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: appErr.Slug, Domain: errorDomain}}
+	if seconds := appErrors.RetryAfterSeconds(err); seconds > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(time.Duration(seconds) * time.Second)})
+	}
+	if withDetails, detailsErr := st.WithDetails(details...); detailsErr == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
+
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound, http.StatusGone:
+		return codes.NotFound
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
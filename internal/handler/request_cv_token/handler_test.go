@@ -0,0 +1,164 @@
+package request_cv_token
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/api/proto/v1"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pow"
+	processGetCvToken "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/get_cv_token"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockGetCVTokenProcess struct {
+	processFunc func(ctx context.Context, req processGetCvToken.Request) (string, error)
+}
+
+func (m *mockGetCVTokenProcess) Process(ctx context.Context, req processGetCvToken.Request) (string, error) {
+	return m.processFunc(ctx, req)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *contentv1.RequestCvTokenRequest
+		processErr  error
+		wantToken   string
+		wantCode    codes.Code
+		wantSlug    string
+		wantRetry   time.Duration
+		wantRequest processGetCvToken.Request
+	}{
+		{
+			name: "success",
+			req: &contentv1.RequestCvTokenRequest{
+				Password:  "p",
+				Lang:      "pl",
+				CaptchaId: "c",
+				Client:    "fp",
+				Ip:        "203.0.113.7",
+				Pow:       &contentv1.PowSolution{Challenge: "ch", Signature: "sig", Nonce: "42"},
+				Email:     "anna@acme.com",
+			},
+			wantToken: "token",
+			wantCode:  codes.OK,
+			wantRequest: processGetCvToken.Request{
+				Password:  "p",
+				Lang:      "pl",
+				CaptchaID: "c",
+				Client:    "fp",
+				IP:        "203.0.113.7",
+				Pow:       pow.Solution{Challenge: "ch", Signature: "sig", Nonce: "42"},
+				Email:     "anna@acme.com",
+			},
+		},
+		{
+			name:        "request without pow",
+			req:         &contentv1.RequestCvTokenRequest{Password: "p", Lang: "pl", CaptchaId: "c", Site: "main"},
+			wantToken:   "token",
+			wantCode:    codes.OK,
+			wantRequest: processGetCvToken.Request{Password: "p", Lang: "pl", CaptchaID: "c"},
+		},
+		{
+			name:       "wrong password",
+			req:        &contentv1.RequestCvTokenRequest{Password: "p", Lang: "pl", CaptchaId: "c"},
+			processErr: appErrors.ErrInvalidPassword,
+			wantCode:   codes.Unauthenticated,
+			wantSlug:   "error_cv_auth",
+		},
+		{
+			name:       "captcha not solved",
+			req:        &contentv1.RequestCvTokenRequest{Password: "p", Lang: "pl", CaptchaId: "c"},
+			processErr: appErrors.ErrCaptchaNotSolved,
+			wantCode:   codes.PermissionDenied,
+			wantSlug:   "error_captcha_invalid",
+		},
+		{
+			name:       "captcha not found",
+			req:        &contentv1.RequestCvTokenRequest{Password: "p", Lang: "pl", CaptchaId: "c"},
+			processErr: appErrors.ErrCaptchaNotFound,
+			wantCode:   codes.NotFound,
+			wantSlug:   "error_captcha_not_found",
+		},
+		{
+			name:       "unsupported language",
+			req:        &contentv1.RequestCvTokenRequest{Password: "p", Lang: "de", CaptchaId: "c"},
+			processErr: appErrors.ErrUnsupportedLanguage,
+			wantCode:   codes.InvalidArgument,
+			wantSlug:   "error_message",
+		},
+		{
+			name:       "rate limited",
+			req:        &contentv1.RequestCvTokenRequest{Password: "p", Lang: "pl", CaptchaId: "c"},
+			processErr: appErrors.WithRetryAfter(appErrors.ErrRateLimited, 90*time.Second),
+			wantCode:   codes.ResourceExhausted,
+			wantSlug:   "error_rate_limited",
+			wantRetry:  90 * time.Second,
+		},
+		{
+			name:       "unexpected error",
+			req:        &contentv1.RequestCvTokenRequest{Password: "p", Lang: "pl", CaptchaId: "c"},
+			processErr: errors.New("boom"),
+			wantCode:   codes.Internal,
+			wantSlug:   "error_cv_server",
+		},
+		{
+			name:     "unknown site",
+			req:      &contentv1.RequestCvTokenRequest{Password: "p", Lang: "pl", CaptchaId: "c", Site: "other"},
+			wantCode: codes.NotFound,
+			wantSlug: "error_site_not_found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockGetCVTokenProcess{processFunc: func(ctx context.Context, req processGetCvToken.Request) (string, error) {
+				if tt.processErr != nil {
+					return "", tt.processErr
+				}
+				if req != tt.wantRequest {
+					return "", errors.New("request not passed on")
+				}
+				return "token", nil
+			}}
+			h := NewHandler(map[string]GetCVTokenProcess{"main": m}, "main")
+
+			res, err := h.Handle(context.Background(), tt.req)
+			st := status.Convert(err)
+			if st.Code() != tt.wantCode {
+				t.Fatalf("Handle() code = %v, want %v (%v)", st.Code(), tt.wantCode, err)
+			}
+			if err == nil {
+				if res.GetToken() != tt.wantToken {
+					t.Errorf("Handle() token = %s, want %s", res.GetToken(), tt.wantToken)
+				}
+				return
+			}
+
+			if st.Message() != tt.wantSlug {
+				t.Errorf("Handle() message = %s, want %s", st.Message(), tt.wantSlug)
+			}
+			var gotSlug string
+			var gotRetry time.Duration
+			for _, d := range st.Details() {
+				switch d := d.(type) {
+				case *errdetails.ErrorInfo:
+					gotSlug = d.GetReason()
+				case *errdetails.RetryInfo:
+					gotRetry = d.GetRetryDelay().AsDuration()
+				}
+			}
+			if gotSlug != tt.wantSlug {
+				t.Errorf("Handle() error info reason = %s, want %s", gotSlug, tt.wantSlug)
+			}
+			if gotRetry != tt.wantRetry {
+				t.Errorf("Handle() retry delay = %v, want %v", gotRetry, tt.wantRetry)
+			}
+		})
+	}
+}
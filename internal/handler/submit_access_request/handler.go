@@ -0,0 +1,85 @@
+package submit_access_request
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processSubmitAccessRequest "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/submit_access_request"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+type SubmitAccessRequestProcess interface {
+	Process(ctx context.Context, req processSubmitAccessRequest.Request) (string, error)
+}
+
+type Handler struct {
+	submitAccessRequestProcesses map[string]SubmitAccessRequestProcess
+	defaultSite                  string
+}
+
+type requestPayload struct {
+	Name      string `json:"name"`
+	Company   string `json:"company"`
+	Reason    string `json:"reason"`
+	Lang      string `json:"lang"`
+	Email     string `json:"email"`
+	CaptchaID string `json:"captchaId"`
+	Site      string `json:"site"`
+	Client    string `json:"client"`
+}
+
+type responsePayload struct {
+	RequestID string `json:"requestId,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func NewHandler(processes map[string]SubmitAccessRequestProcess, defaultSite string) *Handler {
+	return &Handler{
+		submitAccessRequestProcesses: processes,
+		defaultSite:                  defaultSite,
+	}
+}
+
+func (h *Handler) Handle(ctx context.Context, d amqp091.Delivery) (any, error) {
+	var req requestPayload
+	if err := json.Unmarshal(d.Body, &req); err != nil {
+		return nil, appErrors.ErrInvalidInput
+	}
+
+	site := req.Site
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	var id string
+	var err error
+	if process, ok := h.submitAccessRequestProcesses[site]; ok {
+		id, err = process.Process(ctx, processSubmitAccessRequest.Request{
+			Name:      req.Name,
+			Company:   req.Company,
+			Reason:    req.Reason,
+			Lang:      req.Lang,
+			Email:     req.Email,
+			CaptchaID: req.CaptchaID,
+			Client:    req.Client,
+		})
+	} else {
+		err = appErrors.ErrSiteNotFound
+	}
+
+	response := responsePayload{}
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			response.Error = appErr.Slug
+		} else {
+			response.Error = appErrors.ErrInternalServerError.Slug
+		}
+	} else {
+		response.RequestID = id
+	}
+
+	return response, nil
+}
@@ -0,0 +1,81 @@
+package submit_access_request
+
+import (
+	"context"
+	"testing"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	processSubmitAccessRequest "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/process/submit_access_request"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+type mockSubmitAccessRequestProcess struct {
+	processFunc func(ctx context.Context, req processSubmitAccessRequest.Request) (string, error)
+}
+
+func (m *mockSubmitAccessRequestProcess) Process(ctx context.Context, req processSubmitAccessRequest.Request) (string, error) {
+	return m.processFunc(ctx, req)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	body := `{"name":"Anna","company":"Acme","reason":"Hiring","lang":"en","email":"anna@acme.com","captchaId":"c","client":"fp"}`
+
+	tests := []struct {
+		name        string
+		body        string
+		processFunc func(context.Context, processSubmitAccessRequest.Request) (string, error)
+		wantID      string
+		wantError   string
+		wantErr     error
+	}{
+		{
+			name: "success",
+			body: body,
+			processFunc: func(ctx context.Context, req processSubmitAccessRequest.Request) (string, error) {
+				want := processSubmitAccessRequest.Request{Name: "Anna", Company: "Acme", Reason: "Hiring", Lang: "en", Email: "anna@acme.com", CaptchaID: "c", Client: "fp"}
+				if req != want {
+					return "", appErrors.ErrInvalidInput
+				}
+				return "5f2c", nil
+			},
+			wantID: "5f2c",
+		},
+		{
+			name: "disabled",
+			body: body,
+			processFunc: func(ctx context.Context, req processSubmitAccessRequest.Request) (string, error) {
+				return "", appErrors.ErrAccessRequestsUnavailable
+			},
+			wantError: "error_access_requests_unavailable",
+		},
+		{
+			name:      "unknown site",
+			body:      `{"name":"Anna","site":"other"}`,
+			wantError: "error_site_not_found",
+		},
+		{
+			name:    "unmarshal error",
+			body:    `invalid`,
+			wantErr: appErrors.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(map[string]SubmitAccessRequestProcess{"main": &mockSubmitAccessRequestProcess{processFunc: tt.processFunc}}, "main")
+
+			res, err := h.Handle(context.Background(), amqp091.Delivery{Body: []byte(tt.body)})
+			if err != tt.wantErr {
+				t.Fatalf("Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			payload := res.(responsePayload)
+			if payload.RequestID != tt.wantID || payload.Error != tt.wantError {
+				t.Errorf("Handle() got = %+v", payload)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package submit_contact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+type SubmitContactProcess interface {
+	Process(ctx context.Context, name, email, message, captchaID string) error
+}
+
+type Handler struct {
+	submitContactProcesses map[string]SubmitContactProcess
+	defaultSite            string
+}
+
+type requestPayload struct {
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Message   string `json:"message"`
+	CaptchaID string `json:"captchaId"`
+	Site      string `json:"site"`
+}
+
+type responsePayload struct {
+	Accepted bool   `json:"accepted,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func NewHandler(processes map[string]SubmitContactProcess, defaultSite string) *Handler {
+	return &Handler{
+		submitContactProcesses: processes,
+		defaultSite:            defaultSite,
+	}
+}
+
+func (h *Handler) Handle(ctx context.Context, d amqp091.Delivery) (any, error) {
+	var req requestPayload
+	if err := json.Unmarshal(d.Body, &req); err != nil {
+		return nil, appErrors.ErrInvalidInput
+	}
+
+	site := req.Site
+	if site == "" {
+		site = h.defaultSite
+	}
+
+	var err error
+	if process, ok := h.submitContactProcesses[site]; ok {
+		err = process.Process(ctx, req.Name, req.Email, req.Message, req.CaptchaID)
+	} else {
+		err = appErrors.ErrSiteNotFound
+	}
+
+	response := responsePayload{}
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			response.Error = appErr.Slug
+		} else {
+			response.Error = appErrors.ErrInternalServerError.Slug
+		}
+	} else {
+		response.Accepted = true
+	}
+
+	return response, nil
+}
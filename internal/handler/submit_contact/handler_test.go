@@ -0,0 +1,74 @@
+package submit_contact
+
+import (
+	"context"
+	"testing"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+type mockSubmitContactProcess struct {
+	processFunc func(ctx context.Context, name, email, message, captchaID string) error
+}
+
+func (m *mockSubmitContactProcess) Process(ctx context.Context, name, email, message, captchaID string) error {
+	return m.processFunc(ctx, name, email, message, captchaID)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		processFunc  func(context.Context, string, string, string, string) error
+		wantAccepted bool
+		wantError    string
+		wantErr      error
+	}{
+		{
+			name: "success",
+			body: `{"name":"Jan","email":"jan@example.com","message":"Hello","captchaId":"c"}`,
+			processFunc: func(ctx context.Context, n, e, m, c string) error {
+				return nil
+			},
+			wantAccepted: true,
+		},
+		{
+			name: "rate limited",
+			body: `{"name":"Jan","email":"jan@example.com","message":"Hello","captchaId":"c"}`,
+			processFunc: func(ctx context.Context, n, e, m, c string) error {
+				return appErrors.ErrRateLimited
+			},
+			wantError: "error_rate_limited",
+		},
+		{
+			name:      "unknown site",
+			body:      `{"name":"Jan","email":"jan@example.com","message":"Hello","captchaId":"c","site":"other"}`,
+			wantError: "error_site_not_found",
+		},
+		{
+			name:    "unmarshal error",
+			body:    `invalid`,
+			wantErr: appErrors.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(map[string]SubmitContactProcess{"main": &mockSubmitContactProcess{processFunc: tt.processFunc}}, "main")
+
+			res, err := h.Handle(context.Background(), amqp091.Delivery{Body: []byte(tt.body)})
+			if err != tt.wantErr {
+				t.Fatalf("Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			payload := res.(responsePayload)
+			if payload.Accepted != tt.wantAccepted || payload.Error != tt.wantError {
+				t.Errorf("Handle() got = %+v", payload)
+			}
+		})
+	}
+}
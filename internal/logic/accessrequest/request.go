@@ -0,0 +1,84 @@
+package accessrequest
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/contact"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+const (
+	keyPrefix = "cv_access_request:"
+
+	KeyPattern = keyPrefix + "*"
+	Credential = "access-request"
+)
+
+const (
+	maxNameLength    = 100
+	maxCompanyLength = 100
+	minReasonLength  = 10
+	maxReasonLength  = 1000
+)
+
+var ErrMalformedRequest = errors.New("malformed CV access request")
+
+type Request struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Company   string    `json:"company"`
+	Reason    string    `json:"reason"`
+	Lang      string    `json:"lang"`
+	Email     string    `json:"email,omitempty"`
+	CaptchaID string    `json:"captchaId"`
+	Client    string    `json:"client,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func Key(id string) string {
+	return keyPrefix + id
+}
+
+func IDFromKey(key string) string {
+	return strings.TrimPrefix(key, keyPrefix)
+}
+
+func Sanitize(name, company, reason string) (Request, error) {
+	name = contact.CleanLine(name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return Request{}, appErrors.ErrInvalidAccessName
+	}
+
+	company = contact.CleanLine(company)
+	if company == "" || utf8.RuneCountInString(company) > maxCompanyLength {
+		return Request{}, appErrors.ErrInvalidAccessCompany
+	}
+
+	reason = contact.CleanText(reason)
+	if n := utf8.RuneCountInString(reason); n < minReasonLength || n > maxReasonLength {
+		return Request{}, appErrors.ErrInvalidAccessReason
+	}
+
+	return Request{Name: name, Company: company, Reason: reason}, nil
+}
+
+func (r Request) Encode() (string, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func Decode(value string) (Request, error) {
+	var r Request
+	if err := json.Unmarshal([]byte(value), &r); err != nil || r.ID == "" || r.Lang == "" || r.CreatedAt.IsZero() {
+		return Request{}, ErrMalformedRequest
+	}
+	return r, nil
+}
@@ -0,0 +1,103 @@
+package accessrequest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name      string
+		inName    string
+		inCompany string
+		inReason  string
+		want      Request
+		wantErr   error
+	}{
+		{
+			name:      "valid request is normalized",
+			inName:    "  Anna \t Nowak ",
+			inCompany: "Acme\r\nCorp",
+			inReason:  "Hiring a Go developer,\r\nplease share the CV.\x00 ",
+			want:      Request{Name: "Anna Nowak", Company: "Acme Corp", Reason: "Hiring a Go developer,\nplease share the CV."},
+		},
+		{
+			name:      "empty name",
+			inName:    " ",
+			inCompany: "Acme",
+			inReason:  "Hiring a Go developer.",
+			wantErr:   appErrors.ErrInvalidAccessName,
+		},
+		{
+			name:      "empty company",
+			inName:    "Anna",
+			inCompany: "​",
+			inReason:  "Hiring a Go developer.",
+			wantErr:   appErrors.ErrInvalidAccessCompany,
+		},
+		{
+			name:      "reason too short",
+			inName:    "Anna",
+			inCompany: "Acme",
+			inReason:  "CV pls",
+			wantErr:   appErrors.ErrInvalidAccessReason,
+		},
+		{
+			name:      "reason too long",
+			inName:    "Anna",
+			inCompany: "Acme",
+			inReason:  strings.Repeat("a", maxReasonLength+1),
+			wantErr:   appErrors.ErrInvalidAccessReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sanitize(tt.inName, tt.inCompany, tt.inReason)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Sanitize() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Sanitize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequest_EncodeDecode(t *testing.T) {
+	r := Request{
+		ID:        "5f2c",
+		Name:      "Anna Nowak",
+		Company:   "Acme",
+		Reason:    "Hiring a Go developer.",
+		Lang:      "en",
+		Email:     "anna@acme.com",
+		CaptchaID: "captcha-1",
+		CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		ExpiresAt: time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC),
+	}
+
+	value, err := r.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if got, err := Decode(value); err != nil || got != r {
+		t.Errorf("Decode() = %+v, %v, want %+v", got, err, r)
+	}
+
+	for _, value := range []string{"anna", "{}", `{"id":"5f2c","lang":"en"}`} {
+		if _, err := Decode(value); !errors.Is(err, ErrMalformedRequest) {
+			t.Errorf("Decode(%q) error = %v, want %v", value, err, ErrMalformedRequest)
+		}
+	}
+}
+
+func TestIDFromKey(t *testing.T) {
+	if got := IDFromKey(Key("5f2c")); got != "5f2c" {
+		t.Errorf("IDFromKey(Key()) = %q, want 5f2c", got)
+	}
+}
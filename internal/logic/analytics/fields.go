@@ -0,0 +1,55 @@
+package analytics
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	ContentFallback = "content_fallback"
+	OutcomeIssued   = "issued"
+	otherValue      = "other"
+	emptyValue      = "none"
+)
+
+var langPattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+func ContentRequested(lang string) string {
+	return "content_requested:" + normalizeLang(lang)
+}
+
+func ContentServed(lang string) string {
+	return "content_served:" + normalizeLang(lang)
+}
+
+func ContentSection(section string) string {
+	return "content_section:" + section
+}
+
+func CvTokenOutcome(outcome string) string {
+	return "cv_token:" + outcome
+}
+
+func CvTokenCredential(name string) string {
+	return "cv_token_credential:" + name
+}
+
+func CvDownloadCredential(name string) string {
+	return "cv_download_credential:" + name
+}
+
+func CvDownload(lang string) string {
+	return "cv_download:" + normalizeLang(lang)
+}
+
+func normalizeLang(lang string) string {
+	lang = strings.ToLower(lang)
+	switch {
+	case lang == "":
+		return emptyValue
+	case langPattern.MatchString(lang):
+		return lang
+	default:
+		return otherValue
+	}
+}
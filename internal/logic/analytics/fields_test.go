@@ -0,0 +1,28 @@
+package analytics
+
+import "testing"
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "requested lang", got: ContentRequested("PL"), want: "content_requested:pl"},
+		{name: "requested region", got: ContentRequested("en-gb"), want: "content_requested:en-gb"},
+		{name: "requested empty", got: ContentRequested(""), want: "content_requested:none"},
+		{name: "requested garbage", got: ContentRequested("../../etc"), want: "content_requested:other"},
+		{name: "served lang", got: ContentServed("en"), want: "content_served:en"},
+		{name: "section", got: ContentSection("profile"), want: "content_section:profile"},
+		{name: "token outcome", got: CvTokenOutcome("error_cv_auth"), want: "cv_token:error_cv_auth"},
+		{name: "download", got: CvDownload("pl"), want: "cv_download:pl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package asset
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatGIF  = "gif"
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+(/[A-Za-z0-9_-]+)*\.(jpe?g|png|gif)$`)
+
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+func FormatFromName(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".jpg", ".jpeg":
+		return FormatJPEG
+	case ".png":
+		return FormatPNG
+	case ".gif":
+		return FormatGIF
+	default:
+		return ""
+	}
+}
+
+func ParseOutputFormat(s string) (string, bool) {
+	switch strings.ToLower(s) {
+	case "":
+		return "", true
+	case "jpg", "jpeg":
+		return FormatJPEG, true
+	case "png":
+		return FormatPNG, true
+	default:
+		return "", false
+	}
+}
+
+func ContentType(format string) string {
+	return "image/" + format
+}
+
+func ETag(sourceHash string, width int, format string) string {
+	sum := sha256.Sum256([]byte(sourceHash + ":" + strconv.Itoa(width) + ":" + format))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+func ScaledHeight(width, height, targetWidth int) int {
+	h := (height*targetWidth + width/2) / width
+	if h < 1 {
+		return 1
+	}
+	return h
+}
+
+func Refs(doc any) []string {
+	seen := map[string]bool{}
+	collect(doc, seen)
+
+	refs := make([]string, 0, len(seen))
+	for name := range seen {
+		refs = append(refs, name)
+	}
+	sort.Strings(refs)
+
+	return refs
+}
+
+func collect(node any, seen map[string]bool) {
+	switch v := node.(type) {
+	case map[string]any:
+		if name, ok := v["asset"].(string); ok {
+			seen[name] = true
+		}
+		for _, child := range v {
+			collect(child, seen)
+		}
+	case []any:
+		for _, child := range v {
+			collect(child, seen)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package asset
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestValidName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "profile.jpg", want: true},
+		{name: "projects/portfolio-2026.png", want: true},
+		{name: "../secret.png", want: false},
+		{name: "/etc/photo.png", want: false},
+		{name: "photo.svg", want: false},
+		{name: "projects//photo.png", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidName(tt.name); got != tt.want {
+				t.Errorf("ValidName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefs(t *testing.T) {
+	var doc any
+	json.Unmarshal([]byte(`{
+		"profile": {"photo": {"asset": "profile.jpg", "alt": "Adrian"}},
+		"projects": [{"cover": {"asset": "projects/a.png"}}, {"cover": {"asset": "profile.jpg"}}],
+		"text": "asset"
+	}`), &doc)
+
+	want := []string{"profile.jpg", "projects/a.png"}
+	if got := Refs(doc); !reflect.DeepEqual(got, want) {
+		t.Errorf("Refs() = %v, want %v", got, want)
+	}
+}
+
+func TestETag(t *testing.T) {
+	if ETag("abc", 320, FormatJPEG) == ETag("abc", 640, FormatJPEG) {
+		t.Error("ETag() must differ between widths")
+	}
+	if ETag("abc", 320, FormatJPEG) != ETag("abc", 320, FormatJPEG) {
+		t.Error("ETag() must be stable")
+	}
+}
+
+func TestScaledHeight(t *testing.T) {
+	if got := ScaledHeight(1200, 800, 300); got != 200 {
+		t.Errorf("ScaledHeight() = %d, want 200", got)
+	}
+	if got := ScaledHeight(4000, 1, 10); got != 1 {
+		t.Errorf("ScaledHeight() = %d, want 1", got)
+	}
+}
@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventCaptchaVerified    EventType = "captcha_verified"
+	EventPasswordFailed     EventType = "password_failed"
+	EventTriesExhausted     EventType = "tries_exhausted"
+	EventTokenIssued        EventType = "token_issued"
+	EventLinkEmailed        EventType = "link_emailed"
+	EventTokenRedeemed      EventType = "token_redeemed"
+	EventTokenExpiredUnused EventType = "token_expired_unused"
+	EventTokenRevoked       EventType = "token_revoked"
+	EventClientLocked       EventType = "client_locked"
+	EventLockoutCleared     EventType = "lockout_cleared"
+	EventAccessRequested    EventType = "access_requested"
+	EventAccessApproved     EventType = "access_approved"
+	EventAccessDenied       EventType = "access_denied"
+)
+
+const (
+	StorageFile   = "jsonl"
+	StorageRedis  = "redis"
+	StorageSQLite = "sqlite"
+)
+
+var eventTypes = map[EventType]bool{
+	EventCaptchaVerified:    true,
+	EventPasswordFailed:     true,
+	EventTriesExhausted:     true,
+	EventTokenIssued:        true,
+	EventLinkEmailed:        true,
+	EventTokenRedeemed:      true,
+	EventTokenExpiredUnused: true,
+	EventTokenRevoked:       true,
+	EventClientLocked:       true,
+	EventLockoutCleared:     true,
+	EventAccessRequested:    true,
+	EventAccessApproved:     true,
+	EventAccessDenied:       true,
+}
+
+type Event struct {
+	ID            string    `json:"id"`
+	Time          time.Time `json:"time"`
+	Type          EventType `json:"type"`
+	Site          string    `json:"site"`
+	CorrelationID string    `json:"correlationId,omitempty"`
+	Lang          string    `json:"lang,omitempty"`
+	Document      string    `json:"document,omitempty"`
+	Credential    string    `json:"credential,omitempty"`
+	CaptchaID     string    `json:"captchaId,omitempty"`
+	Client        string    `json:"client,omitempty"`
+	Token         string    `json:"token,omitempty"`
+	Detail        string    `json:"detail,omitempty"`
+	ExpiresAt     time.Time `json:"expiresAt,omitzero"`
+}
+
+type Filter struct {
+	Site          string
+	From          time.Time
+	To            time.Time
+	Types         []EventType
+	Credential    string
+	CorrelationID string
+	Limit         int
+}
+
+func ValidType(t EventType) bool {
+	return eventTypes[t]
+}
+
+func (f Filter) Match(e Event) bool {
+	if f.Site != "" && e.Site != f.Site {
+		return false
+	}
+	if !f.From.IsZero() && e.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !e.Time.Before(f.To) {
+		return false
+	}
+	if f.Credential != "" && e.Credential != f.Credential {
+		return false
+	}
+	if f.CorrelationID != "" && e.CorrelationID != f.CorrelationID {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if e.Type == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+type correlationKey struct{}
+
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFilter_Match(t *testing.T) {
+	at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	event := Event{Time: at, Type: EventTokenRedeemed, Site: "main", Credential: "acme", CorrelationID: "c1"}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{name: "empty filter", filter: Filter{}, want: true},
+		{name: "all fields", filter: Filter{Site: "main", From: at, To: at.Add(time.Second), Types: []EventType{EventTokenIssued, EventTokenRedeemed}, Credential: "acme", CorrelationID: "c1"}, want: true},
+		{name: "other site", filter: Filter{Site: "other"}, want: false},
+		{name: "before range", filter: Filter{From: at.Add(time.Second)}, want: false},
+		{name: "to is exclusive", filter: Filter{To: at}, want: false},
+		{name: "other type", filter: Filter{Types: []EventType{EventTokenIssued}}, want: false},
+		{name: "other credential", filter: Filter{Credential: "default"}, want: false},
+		{name: "other correlation", filter: Filter{CorrelationID: "c2"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(event); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCorrelationID(t *testing.T) {
+	if got := CorrelationID(context.Background()); got != "" {
+		t.Errorf("CorrelationID() = %q, want empty", got)
+	}
+	if got := CorrelationID(WithCorrelationID(context.Background(), "c1")); got != "c1" {
+		t.Errorf("CorrelationID() = %q, want c1", got)
+	}
+}
@@ -0,0 +1,81 @@
+package contact
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+const (
+	maxNameLength    = 100
+	maxEmailLength   = 254
+	minMessageLength = 10
+	maxMessageLength = 5000
+)
+
+type Message struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Body  string `json:"message"`
+}
+
+type Submission struct {
+	Site       string    `json:"site"`
+	Message    Message   `json:"message"`
+	ReceivedAt time.Time `json:"receivedAt"`
+}
+
+func Sanitize(name, email, body string) (Message, error) {
+	name = CleanLine(name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return Message{}, errors.ErrInvalidContactName
+	}
+
+	email = strings.TrimSpace(email)
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email || len(email) > maxEmailLength || strings.ContainsAny(email, "\r\n") {
+		return Message{}, errors.ErrInvalidContactEmail
+	}
+
+	body = CleanText(body)
+	if n := utf8.RuneCountInString(body); n < minMessageLength || n > maxMessageLength {
+		return Message{}, errors.ErrInvalidContactBody
+	}
+
+	return Message{Name: name, Email: email, Body: body}, nil
+}
+
+func CleanLine(s string) string {
+	return strings.Join(strings.Fields(stripControl(s, false)), " ")
+}
+
+func CleanText(s string) string {
+	return strings.TrimSpace(stripControl(strings.ReplaceAll(s, "\r\n", "\n"), true))
+}
+
+func stripControl(s string, keepNewlines bool) string {
+	s = strings.ToValidUTF8(s, "")
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			if keepNewlines {
+				return r
+			}
+			return ' '
+		case r == '\r':
+			if keepNewlines {
+				return -1
+			}
+			return ' '
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			return -1
+		default:
+			return r
+		}
+	}, s)
+}
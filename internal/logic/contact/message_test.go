@@ -0,0 +1,81 @@
+package contact
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name    string
+		inName  string
+		inEmail string
+		inBody  string
+		want    Message
+		wantErr error
+	}{
+		{
+			name:    "valid message is normalized",
+			inName:  "  Jan \t Kowalski ",
+			inEmail: " jan@example.com ",
+			inBody:  "Dzień dobry,\r\nchciałbym porozmawiać.\x00 ",
+			want:    Message{Name: "Jan Kowalski", Email: "jan@example.com", Body: "Dzień dobry,\nchciałbym porozmawiać."},
+		},
+		{
+			name:    "line break in name cannot inject headers",
+			inName:  "Jan\r\nBcc: victim@example.com",
+			inEmail: "jan@example.com",
+			inBody:  "Hello there, this is long enough.",
+			want:    Message{Name: "Jan Bcc: victim@example.com", Email: "jan@example.com", Body: "Hello there, this is long enough."},
+		},
+		{
+			name:    "empty name",
+			inName:  " ​ ",
+			inEmail: "jan@example.com",
+			inBody:  "Hello there, this is long enough.",
+			wantErr: errors.ErrInvalidContactName,
+		},
+		{
+			name:    "display name in email",
+			inName:  "Jan",
+			inEmail: "Jan <jan@example.com>",
+			inBody:  "Hello there, this is long enough.",
+			wantErr: errors.ErrInvalidContactEmail,
+		},
+		{
+			name:    "email with line break",
+			inName:  "Jan",
+			inEmail: "jan@example.com\nBcc: victim@example.com",
+			inBody:  "Hello there, this is long enough.",
+			wantErr: errors.ErrInvalidContactEmail,
+		},
+		{
+			name:    "message too short",
+			inName:  "Jan",
+			inEmail: "jan@example.com",
+			inBody:  "Hi",
+			wantErr: errors.ErrInvalidContactBody,
+		},
+		{
+			name:    "message too long",
+			inName:  "Jan",
+			inEmail: "jan@example.com",
+			inBody:  strings.Repeat("a", maxMessageLength+1),
+			wantErr: errors.ErrInvalidContactBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sanitize(tt.inName, tt.inEmail, tt.inBody)
+			if err != tt.wantErr {
+				t.Fatalf("Sanitize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Sanitize() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package credential
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/password"
+)
+
+const DefaultName = "default"
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+type Credential struct {
+	Name      string
+	Password  string
+	ExpiresAt time.Time
+	Langs     []string
+	MaxUses   int
+	Revoked   bool
+}
+
+func (c Credential) Expired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt)
+}
+
+func (c Credential) AllowsLang(lang string) bool {
+	return len(c.Langs) == 0 || slices.Contains(c.Langs, lang)
+}
+
+func Match(credentials []Credential, plain string) (Credential, bool) {
+	var found Credential
+	ok := false
+	for _, c := range credentials {
+		if password.Verify(c.Password, plain) && !ok {
+			found, ok = c, true
+		}
+	}
+
+	return found, ok
+}
+
+func Validate(credentials []Credential) error {
+	seen := map[string]bool{}
+	for _, c := range credentials {
+		if !namePattern.MatchString(c.Name) {
+			return fmt.Errorf("invalid credential name %q", c.Name)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("credential %s declared more than once", c.Name)
+		}
+		seen[c.Name] = true
+		if c.Password == "" {
+			return fmt.Errorf("credential %s has no password", c.Name)
+		}
+		if err := password.Check(c.Password); err != nil {
+			return fmt.Errorf("credential %s: %w", c.Name, err)
+		}
+		if c.MaxUses < 0 {
+			return fmt.Errorf("credential %s has a negative usage cap", c.Name)
+		}
+	}
+
+	return nil
+}
+
+func RequireHashed(credentials []Credential) error {
+	for _, c := range credentials {
+		if !password.IsHashed(c.Password) {
+			return fmt.Errorf("credential %s has a plaintext password", c.Name)
+		}
+	}
+
+	return nil
+}
+
+func UsesKey(name string) string {
+	return "credential:" + name + ":uses"
+}
@@ -0,0 +1,103 @@
+package credential
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/password"
+)
+
+func TestMatch(t *testing.T) {
+	hash, err := password.Hash("hashed", password.AlgorithmArgon2id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials := []Credential{
+		{Name: "acme-recruiter", Password: "acme"},
+		{Name: "job-fair-2026", Password: "fair"},
+		{Name: "agency", Password: hash},
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		want     string
+		wantOk   bool
+	}{
+		{name: "first", password: "acme", want: "acme-recruiter", wantOk: true},
+		{name: "second", password: "fair", want: "job-fair-2026", wantOk: true},
+		{name: "hashed", password: "hashed", want: "agency", wantOk: true},
+		{name: "hash itself", password: hash, wantOk: false},
+		{name: "no match", password: "acm", wantOk: false},
+		{name: "empty", password: "", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Match(credentials, tt.password)
+			if ok != tt.wantOk || got.Name != tt.want {
+				t.Errorf("Match() = %s, %v, want %s, %v", got.Name, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestCredential_Expired(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if (Credential{}).Expired(now) {
+		t.Error("credential without expiry must not expire")
+	}
+	if !(Credential{ExpiresAt: now}).Expired(now) {
+		t.Error("credential must expire at its expiry time")
+	}
+	if (Credential{ExpiresAt: now.Add(time.Second)}).Expired(now) {
+		t.Error("credential expired too early")
+	}
+}
+
+func TestCredential_AllowsLang(t *testing.T) {
+	if !(Credential{}).AllowsLang("pl") {
+		t.Error("credential without languages must allow all")
+	}
+	c := Credential{Langs: []string{"en"}}
+	if !c.AllowsLang("en") || c.AllowsLang("pl") {
+		t.Error("AllowsLang() does not respect the language list")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials []Credential
+		wantErr     bool
+	}{
+		{name: "valid", credentials: []Credential{{Name: "default", Password: "a"}, {Name: "acme", Password: "b"}}},
+		{name: "duplicate", credentials: []Credential{{Name: "acme", Password: "a"}, {Name: "acme", Password: "b"}}, wantErr: true},
+		{name: "invalid name", credentials: []Credential{{Name: "Acme Corp", Password: "a"}}, wantErr: true},
+		{name: "missing password", credentials: []Credential{{Name: "acme"}}, wantErr: true},
+		{name: "negative cap", credentials: []Credential{{Name: "acme", Password: "a", MaxUses: -1}}, wantErr: true},
+		{name: "malformed hash", credentials: []Credential{{Name: "acme", Password: "$2b$12$abc"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.credentials); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequireHashed(t *testing.T) {
+	hashed := []Credential{
+		{Name: "argon", Password: "$argon2id$v=19$m=19456,t=2,p=1$c2FsdHNhbHRzYWx0$a2V5a2V5a2V5a2V5"},
+		{Name: "bcrypt", Password: "$2b$12$R9h/cIPz0gi.URNNX3kh2OPST9/PgBkqquzi.Ss7KIUgO2t0jWMUW"},
+	}
+	if err := RequireHashed(hashed); err != nil {
+		t.Errorf("RequireHashed() error = %v", err)
+	}
+	if err := RequireHashed(append(hashed, Credential{Name: "plain", Password: "secret"})); err == nil {
+		t.Error("RequireHashed() must reject plaintext passwords")
+	}
+}
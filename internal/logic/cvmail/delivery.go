@@ -0,0 +1,103 @@
+package cvmail
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+const (
+	maxEmailLength = 254
+
+	SubjectKey = "translations.cv_email_subject"
+	BodyKey    = "translations.cv_email_body"
+)
+
+type Delivery struct {
+	Site        string    `json:"site"`
+	Email       string    `json:"email"`
+	Lang        string    `json:"lang"`
+	Token       string    `json:"token"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	RequestedAt time.Time `json:"requestedAt"`
+}
+
+type Template struct {
+	Subject string
+	Body    string
+}
+
+func Normalize(email string, allowedDomains []string) (string, error) {
+	email = strings.TrimSpace(email)
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email || len(email) > maxEmailLength || strings.ContainsAny(email, "\r\n") {
+		return "", errors.ErrInvalidCVEmail
+	}
+
+	at := strings.LastIndexByte(email, '@')
+	email = email[:at] + "@" + strings.ToLower(email[at+1:])
+	if !DomainAllowed(Domain(email), allowedDomains) {
+		return "", errors.ErrCVEmailDomain
+	}
+
+	return email, nil
+}
+
+func Domain(email string) string {
+	return strings.ToLower(email[strings.LastIndexByte(email, '@')+1:])
+}
+
+func DomainAllowed(domain string, allowedDomains []string) bool {
+	if len(allowedDomains) == 0 {
+		return true
+	}
+
+	for _, allowed := range allowedDomains {
+		allowed = strings.ToLower(strings.TrimPrefix(allowed, "."))
+		if domain == allowed || strings.HasSuffix(domain, "."+allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func Link(base, site, lang, token string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set("token", token)
+	query.Set("lang", lang)
+	query.Set("site", site)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+func TemplateFrom(translations map[string]string) (Template, error) {
+	t := Template{Subject: translations[SubjectKey], Body: translations[BodyKey]}
+	if t.Subject == "" || t.Body == "" {
+		return Template{}, fmt.Errorf("translations %s and %s are required", SubjectKey, BodyKey)
+	}
+	if !strings.Contains(t.Body, "{link}") {
+		return Template{}, fmt.Errorf("translation %s must contain {link}", BodyKey)
+	}
+
+	return t, nil
+}
+
+func (t Template) Render(link string, expiresAt time.Time) (string, string) {
+	r := strings.NewReplacer(
+		"{link}", link,
+		"{expires}", expiresAt.UTC().Format("2006-01-02 15:04 UTC"),
+	)
+
+	return strings.Join(strings.Fields(r.Replace(t.Subject)), " "), r.Replace(t.Body)
+}
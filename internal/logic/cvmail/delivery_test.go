@@ -0,0 +1,97 @@
+package cvmail
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name           string
+		email          string
+		allowedDomains []string
+		want           string
+		wantErr        error
+	}{
+		{
+			name:  "domain is lowercased",
+			email: " Anna.Nowak@Acme.COM ",
+			want:  "Anna.Nowak@acme.com",
+		},
+		{
+			name:    "display name",
+			email:   "Anna <anna@acme.com>",
+			wantErr: errors.ErrInvalidCVEmail,
+		},
+		{
+			name:    "header injection",
+			email:   "anna@acme.com\nBcc: victim@example.com",
+			wantErr: errors.ErrInvalidCVEmail,
+		},
+		{
+			name:    "not an address",
+			email:   "anna",
+			wantErr: errors.ErrInvalidCVEmail,
+		},
+		{
+			name:           "allowed domain",
+			email:          "anna@acme.com",
+			allowedDomains: []string{"Acme.com"},
+			want:           "anna@acme.com",
+		},
+		{
+			name:           "allowed subdomain",
+			email:          "anna@hr.acme.com",
+			allowedDomains: []string{"acme.com"},
+			want:           "anna@hr.acme.com",
+		},
+		{
+			name:           "lookalike domain",
+			email:          "anna@notacme.com",
+			allowedDomains: []string{"acme.com"},
+			wantErr:        errors.ErrCVEmailDomain,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.email, tt.allowedDomains)
+			if err != tt.wantErr {
+				t.Fatalf("Normalize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLink(t *testing.T) {
+	got, err := Link("https://adrianjanczenia.dev/cv?utm_source=email", "main", "pl", "a b")
+	if err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	if want := "https://adrianjanczenia.dev/cv?lang=pl&site=main&token=a+b&utm_source=email"; got != want {
+		t.Errorf("Link() = %s, want %s", got, want)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	if _, err := TemplateFrom(map[string]string{SubjectKey: "CV", BodyKey: "no link"}); err == nil {
+		t.Error("TemplateFrom() must reject a body without {link}")
+	}
+
+	tpl, err := TemplateFrom(map[string]string{SubjectKey: "Twoje CV\r\nBcc: x", BodyKey: "Pobierz: {link}\nWażny do {expires}."})
+	if err != nil {
+		t.Fatalf("TemplateFrom() error = %v", err)
+	}
+	subject, body := tpl.Render("https://example.com/cv", time.Date(2026, 3, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600)))
+	if subject != "Twoje CV Bcc: x" {
+		t.Errorf("Render() subject = %q", subject)
+	}
+	if body != "Pobierz: https://example.com/cv\nWażny do 2026-03-01 11:30 UTC." {
+		t.Errorf("Render() body = %q", body)
+	}
+}
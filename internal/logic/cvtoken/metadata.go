@@ -0,0 +1,84 @@
+package cvtoken
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const keyPrefix = "cv_token:"
+
+const KeyPattern = keyPrefix + "*"
+
+var (
+	ErrMalformedMetadata = errors.New("malformed CV token metadata")
+	ErrLangMismatch      = errors.New("token was issued for another language")
+	ErrClientMismatch    = errors.New("token was issued to another client")
+)
+
+type Metadata struct {
+	Lang        string    `json:"lang"`
+	Document    string    `json:"document"`
+	IssuedAt    time.Time `json:"issuedAt"`
+	Credential  string    `json:"credential"`
+	CaptchaID   string    `json:"captchaId"`
+	Fingerprint string    `json:"fingerprint,omitempty"`
+}
+
+func Key(token string) string {
+	return keyPrefix + token
+}
+
+func TokenFromKey(key string) string {
+	return strings.TrimPrefix(key, keyPrefix)
+}
+
+func Fingerprint(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (m Metadata) Encode() (string, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func Decode(value string) (Metadata, error) {
+	var m Metadata
+	if err := json.Unmarshal([]byte(value), &m); err != nil || m.Lang == "" || m.IssuedAt.IsZero() {
+		return Metadata{}, ErrMalformedMetadata
+	}
+	return m, nil
+}
+
+func (m Metadata) Authorize(lang, fingerprint string) error {
+	if lang != m.Lang {
+		return ErrLangMismatch
+	}
+	if m.Fingerprint != "" && subtle.ConstantTimeCompare([]byte(m.Fingerprint), []byte(fingerprint)) != 1 {
+		return ErrClientMismatch
+	}
+	return nil
+}
+
+type Issue struct {
+	Token     string
+	Metadata  Metadata
+	ExpiresAt time.Time
+}
+
+type Use struct {
+	Metadata  Metadata
+	Remaining int64
+	Resumed   bool
+}
@@ -0,0 +1,71 @@
+package cvtoken
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMetadata_EncodeDecode(t *testing.T) {
+	m := Metadata{
+		Lang:        "pl",
+		Document:    "pl_cv.pdf",
+		IssuedAt:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Credential:  "acme-recruiter",
+		CaptchaID:   "captcha-1",
+		Fingerprint: Fingerprint("203.0.113.7|Mozilla/5.0"),
+	}
+
+	value, err := m.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	got, err := Decode(value)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got != m {
+		t.Errorf("Decode() = %+v, want %+v", got, m)
+	}
+
+	for _, value := range []string{"acme-recruiter", "{}", `{"lang":"pl"}`} {
+		if _, err := Decode(value); !errors.Is(err, ErrMalformedMetadata) {
+			t.Errorf("Decode(%q) error = %v, want %v", value, err, ErrMalformedMetadata)
+		}
+	}
+}
+
+func TestMetadata_Authorize(t *testing.T) {
+	fingerprint := Fingerprint("client-a")
+
+	tests := []struct {
+		name        string
+		metadata    Metadata
+		lang        string
+		fingerprint string
+		wantErr     error
+	}{
+		{name: "matching", metadata: Metadata{Lang: "pl", Fingerprint: fingerprint}, lang: "pl", fingerprint: fingerprint},
+		{name: "no fingerprint bound", metadata: Metadata{Lang: "pl"}, lang: "pl", fingerprint: Fingerprint("client-b")},
+		{name: "other language", metadata: Metadata{Lang: "pl"}, lang: "en", wantErr: ErrLangMismatch},
+		{name: "other client", metadata: Metadata{Lang: "pl", Fingerprint: fingerprint}, lang: "pl", fingerprint: Fingerprint("client-b"), wantErr: ErrClientMismatch},
+		{name: "missing client", metadata: Metadata{Lang: "pl", Fingerprint: fingerprint}, lang: "pl", wantErr: ErrClientMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.metadata.Authorize(tt.lang, tt.fingerprint); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Authorize() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	if Fingerprint("") != "" {
+		t.Error("empty client must have no fingerprint")
+	}
+	if got := Fingerprint("client-a"); len(got) != 32 || got == Fingerprint("client-b") {
+		t.Errorf("Fingerprint() = %s", got)
+	}
+}
@@ -0,0 +1,134 @@
+package cvtoken
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	ModeRedis          = "redis"
+	ModeSigned         = "signed"
+	minSigningKeyBytes = 32
+)
+
+var (
+	ErrMalformedToken   = errors.New("malformed signed token")
+	ErrUnknownKey       = errors.New("unknown signing key")
+	ErrInvalidSignature = errors.New("invalid token signature")
+	ErrTokenExpired     = errors.New("signed token expired")
+)
+
+var keyIDPattern = regexp.MustCompile(`^[a-zA-Z0-9-]{1,32}$`)
+
+var b64 = base64.RawURLEncoding
+
+type SigningKey struct {
+	ID     string
+	Secret []byte
+}
+
+type Claims struct {
+	Metadata
+	Site      string    `json:"site"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Nonce     string    `json:"nonce"`
+}
+
+func ValidateKeys(keys []SigningKey) error {
+	seen := map[string]bool{}
+	for _, k := range keys {
+		if !keyIDPattern.MatchString(k.ID) {
+			return fmt.Errorf("invalid signing key id %q", k.ID)
+		}
+		if seen[k.ID] {
+			return fmt.Errorf("signing key %s declared more than once", k.ID)
+		}
+		seen[k.ID] = true
+		if len(k.Secret) < minSigningKeyBytes {
+			return fmt.Errorf("signing key %s must be at least %d bytes", k.ID, minSigningKeyBytes)
+		}
+	}
+
+	return nil
+}
+
+func NewNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func IsSigned(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+func Sign(key SigningKey, claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := key.ID + "." + b64.EncodeToString(payload)
+	return signed + "." + b64.EncodeToString(signature(key.Secret, signed)), nil
+}
+
+func Verify(keys []SigningKey, token string, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrMalformedToken
+	}
+
+	var key *SigningKey
+	for i := range keys {
+		if keys[i].ID == parts[0] {
+			key = &keys[i]
+		}
+	}
+	if key == nil {
+		return Claims{}, ErrUnknownKey
+	}
+
+	sig, err := b64.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, signature(key.Secret, parts[0]+"."+parts[1])) {
+		return Claims{}, ErrInvalidSignature
+	}
+
+	payload, err := b64.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, ErrMalformedToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Lang == "" || claims.Nonce == "" {
+		return Claims{}, ErrMalformedToken
+	}
+	if !now.Before(claims.ExpiresAt) {
+		return Claims{}, ErrTokenExpired
+	}
+
+	return claims, nil
+}
+
+func NonceKey(nonce string) string {
+	return "cv_nonce:" + nonce
+}
+
+func RevocationKey(credential string) string {
+	return "cv_credential_revoked:" + credential
+}
+
+func signature(secret []byte, signed string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,89 @@
+package cvtoken
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	current := SigningKey{ID: "2026-03", Secret: bytes.Repeat([]byte("a"), 32)}
+	previous := SigningKey{ID: "2026-02", Secret: bytes.Repeat([]byte("b"), 32)}
+	claims := Claims{
+		Metadata:  Metadata{Lang: "pl", Document: "pl_cv.pdf", IssuedAt: now, Credential: "acme-recruiter", CaptchaID: "captcha-1"},
+		ExpiresAt: now.Add(time.Minute),
+		Nonce:     "n1",
+	}
+
+	token, err := Sign(current, claims)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	oldToken, _ := Sign(previous, claims)
+	foreignToken, _ := Sign(SigningKey{ID: "2026-03", Secret: bytes.Repeat([]byte("c"), 32)}, claims)
+	parts := strings.Split(token, ".")
+	tampered := parts[0] + "." + b64.EncodeToString([]byte(`{"lang":"en","nonce":"n1"}`)) + "." + parts[2]
+
+	tests := []struct {
+		name    string
+		keys    []SigningKey
+		token   string
+		now     time.Time
+		wantErr error
+	}{
+		{name: "valid", keys: []SigningKey{current, previous}, token: token, now: now},
+		{name: "rotated out key still verifies", keys: []SigningKey{current, previous}, token: oldToken, now: now},
+		{name: "retired key", keys: []SigningKey{current}, token: oldToken, now: now, wantErr: ErrUnknownKey},
+		{name: "foreign secret", keys: []SigningKey{current}, token: foreignToken, now: now, wantErr: ErrInvalidSignature},
+		{name: "tampered payload", keys: []SigningKey{current}, token: tampered, now: now, wantErr: ErrInvalidSignature},
+		{name: "expired", keys: []SigningKey{current}, token: token, now: now.Add(time.Minute), wantErr: ErrTokenExpired},
+		{name: "stored token", keys: []SigningKey{current}, token: "abcdefghijklmnopqrstuvwxyz123456", now: now, wantErr: ErrMalformedToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Verify(tt.keys, tt.token, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Metadata != claims.Metadata || got.Nonce != claims.Nonce || !got.ExpiresAt.Equal(claims.ExpiresAt)) {
+				t.Errorf("Verify() = %+v, want %+v", got, claims)
+			}
+		})
+	}
+}
+
+func TestIsSigned(t *testing.T) {
+	if IsSigned("abcdefghijklmnopqrstuvwxyz123456") {
+		t.Error("stored token detected as signed")
+	}
+	if !IsSigned("k1.payload.sig") {
+		t.Error("signed token not detected")
+	}
+}
+
+func TestValidateKeys(t *testing.T) {
+	secret := bytes.Repeat([]byte("a"), 32)
+
+	tests := []struct {
+		name    string
+		keys    []SigningKey
+		wantErr bool
+	}{
+		{name: "valid", keys: []SigningKey{{ID: "k1", Secret: secret}, {ID: "k2", Secret: secret}}},
+		{name: "duplicate", keys: []SigningKey{{ID: "k1", Secret: secret}, {ID: "k1", Secret: secret}}, wantErr: true},
+		{name: "invalid id", keys: []SigningKey{{ID: "k.1", Secret: secret}}, wantErr: true},
+		{name: "short secret", keys: []SigningKey{{ID: "k1", Secret: secret[:16]}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateKeys(tt.keys); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,7 +3,10 @@ package errors
 import (
 	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type AppError struct {
@@ -23,19 +26,74 @@ func (e *AppError) Unwrap() error {
 	return e.Err
 }
 
+type RetryAfterError struct {
+	Err        *AppError
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+func WithRetryAfter(err *AppError, retryAfter time.Duration) error {
+	return &RetryAfterError{Err: err, RetryAfter: retryAfter}
+}
+
+func RetryAfterSeconds(err error) int {
+	var retryErr *RetryAfterError
+	if !errors.As(err, &retryErr) || retryErr.RetryAfter <= 0 {
+		return 0
+	}
+
+	return int(math.Ceil(retryErr.RetryAfter.Seconds()))
+}
+
 var (
-	ErrInternalServerError = &AppError{HTTPStatus: http.StatusInternalServerError, Slug: "error_cv_server"}
-	ErrServiceUnavailable  = &AppError{HTTPStatus: http.StatusServiceUnavailable, Slug: "error_message"}
-	ErrMethodNotAllowed    = &AppError{HTTPStatus: http.StatusMethodNotAllowed, Slug: "error_message"}
-	ErrInvalidInput        = &AppError{HTTPStatus: http.StatusBadRequest, Slug: "error_message"}
-	ErrUnsupportedLanguage = &AppError{HTTPStatus: http.StatusBadRequest, Slug: "error_message"}
-	ErrInvalidPassword     = &AppError{HTTPStatus: http.StatusUnauthorized, Slug: "error_cv_auth"}
-	ErrCVNotFound          = &AppError{HTTPStatus: http.StatusNotFound, Slug: "error_cv_not_found"}
-	ErrCVExpired           = &AppError{HTTPStatus: http.StatusGone, Slug: "error_cv_expired"}
-	ErrContentNotFound     = &AppError{HTTPStatus: http.StatusNotFound, Slug: "error_message"}
-	ErrCaptchaNotFound     = &AppError{HTTPStatus: http.StatusNotFound, Slug: "error_captcha_not_found"}
-	ErrCaptchaNotSolved    = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_captcha_invalid"}
-	ErrNoTriesLeft         = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_captcha_expired"}
+	ErrInternalServerError       = &AppError{HTTPStatus: http.StatusInternalServerError, Slug: "error_cv_server"}
+	ErrServiceUnavailable        = &AppError{HTTPStatus: http.StatusServiceUnavailable, Slug: "error_message"}
+	ErrMethodNotAllowed          = &AppError{HTTPStatus: http.StatusMethodNotAllowed, Slug: "error_message"}
+	ErrInvalidInput              = &AppError{HTTPStatus: http.StatusBadRequest, Slug: "error_message"}
+	ErrUnsupportedLanguage       = &AppError{HTTPStatus: http.StatusBadRequest, Slug: "error_message"}
+	ErrInvalidPassword           = &AppError{HTTPStatus: http.StatusUnauthorized, Slug: "error_cv_auth"}
+	ErrCVNotFound                = &AppError{HTTPStatus: http.StatusNotFound, Slug: "error_cv_not_found"}
+	ErrCVExpired                 = &AppError{HTTPStatus: http.StatusGone, Slug: "error_cv_expired"}
+	ErrCVTokenMismatch           = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_cv_token_mismatch"}
+	ErrCVTokenNotFound           = &AppError{HTTPStatus: http.StatusNotFound, Slug: "error_cv_token_not_found"}
+	ErrCVTokenModeUnsupported    = &AppError{HTTPStatus: http.StatusConflict, Slug: "error_cv_token_mode_unsupported"}
+	ErrContentNotFound           = &AppError{HTTPStatus: http.StatusNotFound, Slug: "error_message"}
+	ErrCredentialExpired         = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_cv_credential_expired"}
+	ErrCredentialExhausted       = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_cv_credential_exhausted"}
+	ErrCredentialRevoked         = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_cv_credential_revoked"}
+	ErrCVLangNotAllowed          = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_cv_lang_not_allowed"}
+	ErrCaptchaNotFound           = &AppError{HTTPStatus: http.StatusNotFound, Slug: "error_captcha_not_found"}
+	ErrPowFailed                 = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_pow_failed"}
+	ErrPowSignature              = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_pow_signature"}
+	ErrPowWork                   = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_pow_work"}
+	ErrCaptchaNotSolved          = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_captcha_invalid"}
+	ErrNoTriesLeft               = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_captcha_expired"}
+	ErrSiteNotFound              = &AppError{HTTPStatus: http.StatusNotFound, Slug: "error_site_not_found"}
+	ErrExperimentNotFound        = &AppError{HTTPStatus: http.StatusNotFound, Slug: "error_experiment_not_found"}
+	ErrUnauthorized              = &AppError{HTTPStatus: http.StatusUnauthorized, Slug: "error_unauthorized"}
+	ErrAssetNotFound             = &AppError{HTTPStatus: http.StatusNotFound, Slug: "error_asset_not_found"}
+	ErrRateLimited               = &AppError{HTTPStatus: http.StatusTooManyRequests, Slug: "error_rate_limited"}
+	ErrClientLocked              = &AppError{HTTPStatus: http.StatusTooManyRequests, Slug: "error_cv_locked"}
+	ErrLockoutNotFound           = &AppError{HTTPStatus: http.StatusNotFound, Slug: "error_lockout_not_found"}
+	ErrStampNotFound             = &AppError{HTTPStatus: http.StatusNotFound, Slug: "error_cv_stamp_not_found"}
+	ErrInvalidCVEmail            = &AppError{HTTPStatus: http.StatusBadRequest, Slug: "error_cv_email"}
+	ErrCVEmailUnavailable        = &AppError{HTTPStatus: http.StatusBadRequest, Slug: "error_cv_email_unavailable"}
+	ErrCVEmailDomain             = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_cv_email_domain"}
+	ErrInvalidContactName        = &AppError{HTTPStatus: http.StatusBadRequest, Slug: "error_contact_name"}
+	ErrInvalidContactEmail       = &AppError{HTTPStatus: http.StatusBadRequest, Slug: "error_contact_email"}
+	ErrInvalidContactBody        = &AppError{HTTPStatus: http.StatusBadRequest, Slug: "error_contact_message"}
+	ErrInvalidAccessName         = &AppError{HTTPStatus: http.StatusBadRequest, Slug: "error_access_request_name"}
+	ErrInvalidAccessCompany      = &AppError{HTTPStatus: http.StatusBadRequest, Slug: "error_access_request_company"}
+	ErrInvalidAccessReason       = &AppError{HTTPStatus: http.StatusBadRequest, Slug: "error_access_request_reason"}
+	ErrAccessRequestsUnavailable = &AppError{HTTPStatus: http.StatusForbidden, Slug: "error_access_requests_unavailable"}
+	ErrAccessRequestNotFound     = &AppError{HTTPStatus: http.StatusNotFound, Slug: "error_access_request_not_found"}
 )
 
 func FromSlug(slug string) *AppError {
@@ -48,12 +106,72 @@ func FromSlug(slug string) *AppError {
 		return ErrCVNotFound
 	case "error_cv_server":
 		return ErrInternalServerError
+	case "error_cv_token_mismatch":
+		return ErrCVTokenMismatch
+	case "error_cv_token_not_found":
+		return ErrCVTokenNotFound
+	case "error_cv_token_mode_unsupported":
+		return ErrCVTokenModeUnsupported
+	case "error_cv_credential_expired":
+		return ErrCredentialExpired
+	case "error_cv_credential_exhausted":
+		return ErrCredentialExhausted
+	case "error_cv_credential_revoked":
+		return ErrCredentialRevoked
+	case "error_cv_lang_not_allowed":
+		return ErrCVLangNotAllowed
+	case "error_cv_email":
+		return ErrInvalidCVEmail
+	case "error_cv_email_unavailable":
+		return ErrCVEmailUnavailable
+	case "error_cv_email_domain":
+		return ErrCVEmailDomain
 	case "error_message":
 		return ErrServiceUnavailable
 	case "error_captcha_not_found":
 		return ErrCaptchaNotFound
 	case "error_captcha_invalid":
 		return ErrCaptchaNotSolved
+	case "error_captcha_expired":
+		return ErrNoTriesLeft
+	case "error_pow_failed":
+		return ErrPowFailed
+	case "error_pow_signature":
+		return ErrPowSignature
+	case "error_pow_work":
+		return ErrPowWork
+	case "error_site_not_found":
+		return ErrSiteNotFound
+	case "error_experiment_not_found":
+		return ErrExperimentNotFound
+	case "error_unauthorized":
+		return ErrUnauthorized
+	case "error_asset_not_found":
+		return ErrAssetNotFound
+	case "error_rate_limited":
+		return ErrRateLimited
+	case "error_cv_locked":
+		return ErrClientLocked
+	case "error_lockout_not_found":
+		return ErrLockoutNotFound
+	case "error_cv_stamp_not_found":
+		return ErrStampNotFound
+	case "error_contact_name":
+		return ErrInvalidContactName
+	case "error_contact_email":
+		return ErrInvalidContactEmail
+	case "error_contact_message":
+		return ErrInvalidContactBody
+	case "error_access_request_name":
+		return ErrInvalidAccessName
+	case "error_access_request_company":
+		return ErrInvalidAccessCompany
+	case "error_access_request_reason":
+		return ErrInvalidAccessReason
+	case "error_access_requests_unavailable":
+		return ErrAccessRequestsUnavailable
+	case "error_access_request_not_found":
+		return ErrAccessRequestNotFound
 	default:
 		return ErrInternalServerError
 	}
@@ -75,6 +193,8 @@ func FromHTTPStatus(status int) *AppError {
 		return ErrServiceUnavailable
 	case http.StatusForbidden:
 		return ErrCaptchaNotSolved
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
 	default:
 		return ErrInternalServerError
 	}
@@ -87,6 +207,9 @@ func WriteJSON(w http.ResponseWriter, err error) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if seconds := RetryAfterSeconds(err); seconds > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
 	w.WriteHeader(appErr.HTTPStatus)
 	json.NewEncoder(w).Encode(map[string]string{
 		"error": appErr.Slug,
@@ -0,0 +1,37 @@
+package errors
+
+import "testing"
+
+func TestFromSlug(t *testing.T) {
+	all := []*AppError{
+		ErrInternalServerError, ErrServiceUnavailable, ErrMethodNotAllowed, ErrInvalidInput, ErrUnsupportedLanguage,
+		ErrInvalidPassword, ErrCVNotFound, ErrCVExpired, ErrCVTokenMismatch, ErrCVTokenNotFound, ErrCVTokenModeUnsupported,
+		ErrContentNotFound, ErrCredentialExpired, ErrCredentialExhausted, ErrCredentialRevoked, ErrCVLangNotAllowed,
+		ErrCaptchaNotFound, ErrPowFailed, ErrPowSignature, ErrPowWork, ErrCaptchaNotSolved, ErrNoTriesLeft,
+		ErrSiteNotFound, ErrExperimentNotFound, ErrUnauthorized, ErrAssetNotFound, ErrRateLimited, ErrClientLocked,
+		ErrLockoutNotFound, ErrStampNotFound, ErrInvalidCVEmail, ErrCVEmailUnavailable, ErrCVEmailDomain,
+		ErrInvalidContactName, ErrInvalidContactEmail, ErrInvalidContactBody, ErrInvalidAccessName,
+		ErrInvalidAccessCompany, ErrInvalidAccessReason, ErrAccessRequestsUnavailable, ErrAccessRequestNotFound,
+	}
+
+	shared := map[string]int{}
+	for _, err := range all {
+		shared[err.Slug]++
+	}
+
+	for _, err := range all {
+		t.Run(err.Slug, func(t *testing.T) {
+			got := FromSlug(err.Slug)
+			if got.Slug != err.Slug {
+				t.Errorf("FromSlug(%q) = %q, want the same slug", err.Slug, got.Slug)
+			}
+			if shared[err.Slug] == 1 && got != err {
+				t.Errorf("FromSlug(%q) = %+v, want %+v", err.Slug, got, err)
+			}
+		})
+	}
+
+	if got := FromSlug("error_unknown"); got != ErrInternalServerError {
+		t.Errorf("FromSlug() unknown slug = %+v, want %+v", got, ErrInternalServerError)
+	}
+}
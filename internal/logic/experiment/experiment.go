@@ -0,0 +1,214 @@
+package experiment
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+var ErrInvalidExperiment = errors.New("invalid experiment")
+
+type Variant struct {
+	ID     string
+	Weight int
+	Value  any
+}
+
+type Experiment struct {
+	Name     string
+	Path     string
+	Variants []Variant
+}
+
+type Assignment struct {
+	Experiment string
+	Variant    string
+}
+
+func (e Experiment) TotalWeight() int {
+	total := 0
+	for _, v := range e.Variants {
+		total += v.Weight
+	}
+	return total
+}
+
+func (e Experiment) Assign(visitorID string) Variant {
+	sum := sha256.Sum256([]byte(e.Name + ":" + visitorID))
+	bucket := int(binary.BigEndian.Uint64(sum[:8]) % uint64(e.TotalWeight()))
+
+	for _, v := range e.Variants {
+		if bucket < v.Weight {
+			return v
+		}
+		bucket -= v.Weight
+	}
+
+	return e.Variants[len(e.Variants)-1]
+}
+
+func (e Experiment) SameVariants(other Experiment) bool {
+	if e.Path != other.Path || len(e.Variants) != len(other.Variants) {
+		return false
+	}
+	for i := range e.Variants {
+		if e.Variants[i].ID != other.Variants[i].ID || e.Variants[i].Weight != other.Variants[i].Weight {
+			return false
+		}
+	}
+	return true
+}
+
+func Extract(doc any) ([]Experiment, error) {
+	var experiments []Experiment
+	seen := map[string]bool{}
+
+	err := walk(doc, "", func(path string, block map[string]any) error {
+		e, err := parseBlock(path, block)
+		if err != nil {
+			return err
+		}
+		if seen[e.Name] {
+			return fmt.Errorf("%w: experiment %s declared more than once", ErrInvalidExperiment, e.Name)
+		}
+		seen[e.Name] = true
+		experiments = append(experiments, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(experiments, func(i, j int) bool { return experiments[i].Path < experiments[j].Path })
+
+	return experiments, nil
+}
+
+func Render(doc any, choose func(Experiment) Variant) any {
+	return render(doc, "", choose)
+}
+
+func render(node any, path string, choose func(Experiment) Variant) any {
+	switch v := node.(type) {
+	case map[string]any:
+		if isBlock(v) {
+			e, _ := parseBlock(path, v)
+			return choose(e).Value
+		}
+		out := make(map[string]any, len(v))
+		for key, child := range v {
+			out[key] = render(child, joinPath(path, key), choose)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = render(child, joinPath(path, strconv.Itoa(i)), choose)
+		}
+		return out
+	default:
+		return node
+	}
+}
+
+func walk(node any, path string, visit func(path string, block map[string]any) error) error {
+	switch v := node.(type) {
+	case map[string]any:
+		if isBlock(v) {
+			return visit(path, v)
+		}
+		for key, child := range v {
+			if err := walk(child, joinPath(path, key), visit); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, child := range v {
+			if err := walk(child, joinPath(path, strconv.Itoa(i)), visit); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func isBlock(m map[string]any) bool {
+	_, ok := m["variants"]
+	return ok
+}
+
+func parseBlock(path string, block map[string]any) (Experiment, error) {
+	e := Experiment{Name: path, Path: path}
+
+	for key := range block {
+		if key != "variants" && key != "experiment" {
+			return e, fmt.Errorf("%w: unexpected key %q at %s", ErrInvalidExperiment, key, path)
+		}
+	}
+	if name, ok := block["experiment"]; ok {
+		s, ok := name.(string)
+		if !ok || s == "" {
+			return e, fmt.Errorf("%w: experiment name at %s must be a non-empty string", ErrInvalidExperiment, path)
+		}
+		e.Name = s
+	}
+
+	variants, ok := block["variants"].([]any)
+	if !ok || len(variants) < 2 {
+		return e, fmt.Errorf("%w: %s needs at least two variants", ErrInvalidExperiment, path)
+	}
+
+	ids := map[string]bool{}
+	for i, raw := range variants {
+		v, ok := raw.(map[string]any)
+		if !ok {
+			return e, fmt.Errorf("%w: variant %d at %s must be an object", ErrInvalidExperiment, i, path)
+		}
+		id, _ := v["id"].(string)
+		if id == "" || ids[id] {
+			return e, fmt.Errorf("%w: variant %d at %s needs a unique id", ErrInvalidExperiment, i, path)
+		}
+		ids[id] = true
+
+		weight, err := parseWeight(v["weight"])
+		if err != nil {
+			return e, fmt.Errorf("%w: variant %s at %s: %v", ErrInvalidExperiment, id, path, err)
+		}
+		value, ok := v["value"]
+		if !ok {
+			return e, fmt.Errorf("%w: variant %s at %s has no value", ErrInvalidExperiment, id, path)
+		}
+
+		e.Variants = append(e.Variants, Variant{ID: id, Weight: weight, Value: value})
+	}
+
+	return e, nil
+}
+
+func parseWeight(raw any) (int, error) {
+	w, ok := raw.(float64)
+	if !ok || w != float64(int(w)) || w <= 0 {
+		return 0, fmt.Errorf("weight must be a positive integer, got %v", raw)
+	}
+
+	return int(w), nil
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+func ExposuresKey(experiment string) string {
+	return "experiment:" + experiment + ":exposures"
+}
+
+func VisitorsKey(experiment, variant string) string {
+	return "experiment:" + experiment + ":" + variant + ":visitors"
+}
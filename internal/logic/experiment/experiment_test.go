@@ -0,0 +1,120 @@
+package experiment
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name      string
+		doc       string
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name:      "named and path keyed experiments",
+			doc:       `{"profile":{"about":{"variants":[{"id":"a","weight":1,"value":"x"},{"id":"b","weight":2,"value":"y"}]},"headline":{"experiment":"hl","variants":[{"id":"a","weight":1,"value":"x"},{"id":"b","weight":1,"value":"y"}]}}}`,
+			wantNames: []string{"profile.about", "hl"},
+		},
+		{
+			name:      "no experiments",
+			doc:       `{"profile":{"headline":"x","tags":["a"]}}`,
+			wantNames: nil,
+		},
+		{
+			name:    "single variant",
+			doc:     `{"a":{"variants":[{"id":"a","weight":1,"value":"x"}]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate variant id",
+			doc:     `{"a":{"variants":[{"id":"a","weight":1,"value":"x"},{"id":"a","weight":1,"value":"y"}]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "non positive weight",
+			doc:     `{"a":{"variants":[{"id":"a","weight":0,"value":"x"},{"id":"b","weight":1,"value":"y"}]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "fractional weight",
+			doc:     `{"a":{"variants":[{"id":"a","weight":0.5,"value":"x"},{"id":"b","weight":1,"value":"y"}]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			doc:     `{"a":{"variants":[{"id":"a","weight":1},{"id":"b","weight":1,"value":"y"}]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "unexpected key",
+			doc:     `{"a":{"variants":[{"id":"a","weight":1,"value":"x"},{"id":"b","weight":1,"value":"y"}],"default":"x"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate experiment name",
+			doc:     `{"a":{"experiment":"e","variants":[{"id":"a","weight":1,"value":"x"},{"id":"b","weight":1,"value":"y"}]},"b":{"experiment":"e","variants":[{"id":"a","weight":1,"value":"x"},{"id":"b","weight":1,"value":"y"}]}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc any
+			json.Unmarshal([]byte(tt.doc), &doc)
+
+			got, err := Extract(doc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Extract() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidExperiment) {
+				t.Errorf("Extract() error = %v, want ErrInvalidExperiment", err)
+			}
+			if len(got) != len(tt.wantNames) {
+				t.Fatalf("Extract() got %d experiments, want %d", len(got), len(tt.wantNames))
+			}
+			for i, e := range got {
+				if e.Name != tt.wantNames[i] {
+					t.Errorf("Extract() name = %s, want %s", e.Name, tt.wantNames[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExperiment_Assign(t *testing.T) {
+	e := Experiment{Name: "hl", Variants: []Variant{{ID: "a", Weight: 9}, {ID: "b", Weight: 1}}}
+
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		visitor := string(rune(i)) + "-visitor"
+		v := e.Assign(visitor)
+		if again := e.Assign(visitor); again.ID != v.ID {
+			t.Fatalf("Assign() not stable for %q", visitor)
+		}
+		counts[v.ID]++
+	}
+
+	if counts["b"] < 700 || counts["b"] > 1300 {
+		t.Errorf("Assign() gave variant b %d of 10000 visitors, want about 1000", counts["b"])
+	}
+}
+
+func TestRender(t *testing.T) {
+	var doc any
+	json.Unmarshal([]byte(`{"list":[{"variants":[{"id":"a","weight":1,"value":{"x":1}},{"id":"b","weight":1,"value":"y"}]}],"keep":true}`), &doc)
+
+	got := Render(doc, func(e Experiment) Variant { return e.Variants[1] })
+	out, _ := json.Marshal(got)
+	if string(out) != `{"keep":true,"list":["y"]}` {
+		t.Errorf("Render() got = %s", out)
+	}
+
+	original, _ := json.Marshal(doc)
+	if !strings.Contains(string(original), `"variants"`) {
+		t.Error("Render() modified the source document")
+	}
+}
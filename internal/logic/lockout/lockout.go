@@ -0,0 +1,68 @@
+package lockout
+
+import "time"
+
+const (
+	IndexKey  = "lockouts"
+	maxLevels = 32
+)
+
+type Policy struct {
+	Threshold  int
+	Base       time.Duration
+	Max        time.Duration
+	Reset      time.Duration
+	AlertLevel int
+}
+
+type State struct {
+	Client      string
+	Failures    int
+	Level       int
+	LockedUntil time.Time
+	LastFailure time.Time
+}
+
+func Key(client string) string {
+	return "lockout:" + client
+}
+
+func (p Policy) Enabled() bool {
+	return p.Threshold > 0 && p.Base > 0
+}
+
+func (p Policy) Duration(level int) time.Duration {
+	if level < 1 {
+		return 0
+	}
+
+	d := p.Base
+	for i := 1; i < level; i++ {
+		if p.Max > 0 && d >= p.Max {
+			break
+		}
+		d *= 2
+	}
+	if p.Max > 0 && d > p.Max {
+		d = p.Max
+	}
+
+	return d
+}
+
+func (p Policy) Durations() []time.Duration {
+	var durations []time.Duration
+	for level := 1; level <= maxLevels; level++ {
+		d := p.Duration(level)
+		durations = append(durations, d)
+		if p.Max > 0 && d >= p.Max {
+			break
+		}
+	}
+
+	return durations
+}
+
+func (s State) Locked(now time.Time) bool {
+	return now.Before(s.LockedUntil)
+}
@@ -0,0 +1,73 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicy_Duration(t *testing.T) {
+	policy := Policy{Threshold: 5, Base: time.Minute, Max: 10 * time.Minute}
+
+	tests := []struct {
+		name  string
+		level int
+		want  time.Duration
+	}{
+		{name: "not locked", level: 0, want: 0},
+		{name: "first lockout", level: 1, want: time.Minute},
+		{name: "doubles", level: 2, want: 2 * time.Minute},
+		{name: "doubles again", level: 4, want: 8 * time.Minute},
+		{name: "capped", level: 5, want: 10 * time.Minute},
+		{name: "stays capped", level: 40, want: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Duration(tt.level); got != tt.want {
+				t.Errorf("Duration(%d) = %v, want %v", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_Durations(t *testing.T) {
+	policy := Policy{Threshold: 5, Base: time.Minute, Max: 10 * time.Minute}
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute}
+
+	got := policy.Durations()
+	if len(got) != len(want) {
+		t.Fatalf("Durations() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Durations()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if got := (Policy{Threshold: 5, Base: time.Second}).Durations(); len(got) != maxLevels {
+		t.Errorf("Durations() without a maximum has %d levels, want %d", len(got), maxLevels)
+	}
+}
+
+func TestPolicy_Enabled(t *testing.T) {
+	if (Policy{Base: time.Minute}).Enabled() {
+		t.Error("policy without threshold must be disabled")
+	}
+	if !(Policy{Threshold: 5, Base: time.Minute}).Enabled() {
+		t.Error("policy with threshold and base must be enabled")
+	}
+}
+
+func TestState_Locked(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if (State{}).Locked(now) {
+		t.Error("state without lockout must not be locked")
+	}
+	if !(State{LockedUntil: now.Add(time.Second)}).Locked(now) {
+		t.Error("state must be locked before its lockout ends")
+	}
+	if (State{LockedUntil: now}).Locked(now) {
+		t.Error("state must be unlocked once its lockout ends")
+	}
+}
@@ -0,0 +1,152 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type Algorithm string
+
+const (
+	AlgorithmArgon2id Algorithm = "argon2id"
+	AlgorithmBcrypt   Algorithm = "bcrypt"
+)
+
+const (
+	argon2Memory     = 19 * 1024
+	argon2Time       = 2
+	argon2Threads    = 1
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+	argon2MaxMemory  = 1024 * 1024
+	bcryptCost       = 12
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported password hash algorithm")
+	ErrMalformedHash        = errors.New("malformed password hash")
+)
+
+var b64 = base64.RawStdEncoding
+
+type argon2Hash struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func ParseAlgorithm(s string) (Algorithm, error) {
+	switch strings.ToLower(s) {
+	case "argon2id", "argon2":
+		return AlgorithmArgon2id, nil
+	case "bcrypt":
+		return AlgorithmBcrypt, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, s)
+	}
+}
+
+func Hash(plain string, algorithm Algorithm) (string, error) {
+	switch algorithm {
+	case AlgorithmArgon2id:
+		salt := make([]byte, argon2SaltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		key := argon2.IDKey([]byte(plain), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLength)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+			argon2.Version, argon2Memory, argon2Time, argon2Threads, b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+	case AlgorithmBcrypt:
+		hash, err := bcrypt.GenerateFromPassword([]byte(plain), bcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+}
+
+func IsHashed(stored string) bool {
+	return isArgon2id(stored) || isBcrypt(stored)
+}
+
+func Check(stored string) error {
+	switch {
+	case isArgon2id(stored):
+		_, err := parseArgon2id(stored)
+		return err
+	case isBcrypt(stored):
+		if _, err := bcrypt.Cost([]byte(stored)); err != nil {
+			return fmt.Errorf("%w: %v", ErrMalformedHash, err)
+		}
+	}
+
+	return nil
+}
+
+func Verify(stored, plain string) bool {
+	switch {
+	case isArgon2id(stored):
+		h, err := parseArgon2id(stored)
+		if err != nil {
+			return false
+		}
+		key := argon2.IDKey([]byte(plain), h.salt, h.time, h.memory, h.threads, uint32(len(h.key)))
+		return subtle.ConstantTimeCompare(key, h.key) == 1
+	case isBcrypt(stored):
+		return bcrypt.CompareHashAndPassword([]byte(stored), []byte(plain)) == nil
+	default:
+		storedDigest := sha256.Sum256([]byte(stored))
+		plainDigest := sha256.Sum256([]byte(plain))
+		return subtle.ConstantTimeCompare(storedDigest[:], plainDigest[:]) == 1
+	}
+}
+
+func isArgon2id(stored string) bool {
+	return strings.HasPrefix(stored, "$argon2id$")
+}
+
+func isBcrypt(stored string) bool {
+	return strings.HasPrefix(stored, "$2a$") || strings.HasPrefix(stored, "$2b$") || strings.HasPrefix(stored, "$2y$")
+}
+
+func parseArgon2id(stored string) (*argon2Hash, error) {
+	parts := strings.Split(stored, "$")
+	if len(parts) != 6 {
+		return nil, ErrMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, fmt.Errorf("%w: unsupported argon2 version %s", ErrMalformedHash, parts[2])
+	}
+
+	h := &argon2Hash{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &h.memory, &h.time, &h.threads); err != nil {
+		return nil, fmt.Errorf("%w: invalid argon2 parameters %s", ErrMalformedHash, parts[3])
+	}
+	if h.memory == 0 || h.memory > argon2MaxMemory || h.time == 0 || h.threads == 0 {
+		return nil, fmt.Errorf("%w: argon2 parameters out of range %s", ErrMalformedHash, parts[3])
+	}
+
+	var err error
+	if h.salt, err = b64.DecodeString(parts[4]); err != nil || len(h.salt) == 0 {
+		return nil, fmt.Errorf("%w: invalid argon2 salt", ErrMalformedHash)
+	}
+	if h.key, err = b64.DecodeString(parts[5]); err != nil || len(h.key) == 0 {
+		return nil, fmt.Errorf("%w: invalid argon2 key", ErrMalformedHash)
+	}
+
+	return h, nil
+}
@@ -0,0 +1,93 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHash(t *testing.T) {
+	hash, err := Hash("secret", AlgorithmArgon2id)
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$") {
+		t.Errorf("Hash() = %s, want argon2id PHC string", hash)
+	}
+	if other, _ := Hash("secret", AlgorithmArgon2id); other == hash {
+		t.Error("Hash() must use a random salt")
+	}
+
+	if _, err := Hash("secret", "md5"); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Errorf("Hash() error = %v, want %v", err, ErrUnsupportedAlgorithm)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	argon2Hash, err := Hash("secret", AlgorithmArgon2id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		stored string
+		plain  string
+		want   bool
+	}{
+		{name: "argon2id match", stored: argon2Hash, plain: "secret", want: true},
+		{name: "argon2id mismatch", stored: argon2Hash, plain: "secreT", want: false},
+		{name: "bcrypt match", stored: string(bcryptHash), plain: "secret", want: true},
+		{name: "bcrypt mismatch", stored: string(bcryptHash), plain: "secret2", want: false},
+		{name: "plaintext match", stored: "secret", plain: "secret", want: true},
+		{name: "plaintext mismatch", stored: "secret", plain: "secre", want: false},
+		{name: "hash is not a password", stored: argon2Hash, plain: argon2Hash, want: false},
+		{name: "malformed argon2id", stored: "$argon2id$v=19$m=0,t=2,p=1$c2FsdA$a2V5", plain: "secret", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.stored, tt.plain); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  string
+		wantErr bool
+	}{
+		{name: "plaintext", stored: "secret"},
+		{name: "argon2id", stored: "$argon2id$v=19$m=19456,t=2,p=1$c2FsdHNhbHRzYWx0$a2V5a2V5a2V5a2V5"},
+		{name: "argon2id wrong version", stored: "$argon2id$v=16$m=19456,t=2,p=1$c2FsdA$a2V5", wantErr: true},
+		{name: "argon2id missing key", stored: "$argon2id$v=19$m=19456,t=2,p=1$c2FsdA", wantErr: true},
+		{name: "argon2id excessive memory", stored: "$argon2id$v=19$m=4194304,t=2,p=1$c2FsdA$a2V5", wantErr: true},
+		{name: "bcrypt truncated", stored: "$2b$12$abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Check(tt.stored); (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	if got, err := ParseAlgorithm("BCRYPT"); err != nil || got != AlgorithmBcrypt {
+		t.Errorf("ParseAlgorithm() = %s, %v", got, err)
+	}
+	if _, err := ParseAlgorithm("sha1"); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Errorf("ParseAlgorithm() error = %v, want %v", err, ErrUnsupportedAlgorithm)
+	}
+}
@@ -0,0 +1,839 @@
+package pdfstamp
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const maxDepth = 32
+
+var (
+	ErrMalformed   = errors.New("malformed pdf")
+	ErrEncrypted   = errors.New("encrypted pdf")
+	ErrUnsupported = errors.New("unsupported pdf feature")
+)
+
+type name string
+
+type real string
+
+type ref struct {
+	num int
+	gen int
+}
+
+type array []any
+
+type dict map[string]any
+
+type stream struct {
+	dict dict
+	data []byte
+}
+
+type xrefEntry struct {
+	kind   int
+	offset int
+	gen    int
+}
+
+type document struct {
+	data       []byte
+	entries    map[int]xrefEntry
+	trailer    dict
+	startxref  int
+	xrefStream bool
+	objstms    map[int]*objectStream
+}
+
+type objectStream struct {
+	data    []byte
+	offsets map[int]int
+}
+
+func parseDocument(data []byte) (*document, error) {
+	start, err := findStartxref(data)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &document{
+		data:      data,
+		entries:   map[int]xrefEntry{},
+		startxref: start,
+		objstms:   map[int]*objectStream{},
+	}
+	visited := map[int]bool{}
+	for offset, first := start, true; offset >= 0; first = false {
+		if visited[offset] || len(visited) > maxDepth {
+			return nil, fmt.Errorf("%w: xref loop", ErrMalformed)
+		}
+		visited[offset] = true
+
+		trailer, isStream, err := d.readXref(offset)
+		if err != nil {
+			return nil, err
+		}
+		if first {
+			d.trailer = trailer
+			d.xrefStream = isStream
+		}
+		if stm, ok := trailer["XRefStm"].(int64); ok {
+			if _, _, err := d.readXref(int(stm)); err != nil {
+				return nil, err
+			}
+		}
+
+		offset = -1
+		if prev, ok := trailer["Prev"].(int64); ok {
+			offset = int(prev)
+		}
+	}
+
+	if _, ok := d.trailer["Encrypt"]; ok {
+		return nil, ErrEncrypted
+	}
+	if _, ok := d.trailer["Root"].(ref); !ok {
+		return nil, fmt.Errorf("%w: trailer without root", ErrMalformed)
+	}
+
+	return d, nil
+}
+
+func findStartxref(data []byte) (int, error) {
+	tail := data
+	if len(tail) > 2048 {
+		tail = tail[len(tail)-2048:]
+	}
+	i := bytes.LastIndex(tail, []byte("startxref"))
+	if i < 0 {
+		return 0, fmt.Errorf("%w: startxref not found", ErrMalformed)
+	}
+
+	p := &parser{data: tail, pos: i + len("startxref")}
+	v, err := p.readObject()
+	if err != nil {
+		return 0, err
+	}
+	offset, ok := v.(int64)
+	if !ok || offset < 0 || int(offset) >= len(data) {
+		return 0, fmt.Errorf("%w: invalid startxref", ErrMalformed)
+	}
+
+	return int(offset), nil
+}
+
+func (d *document) readXref(offset int) (dict, bool, error) {
+	if offset < 0 || offset >= len(d.data) {
+		return nil, false, fmt.Errorf("%w: xref offset out of range", ErrMalformed)
+	}
+
+	p := &parser{data: d.data, pos: offset}
+	p.skipSpace()
+	if p.hasKeyword("xref") {
+		p.pos += len("xref")
+		trailer, err := d.readXrefTable(p)
+		return trailer, false, err
+	}
+
+	_, v, err := d.readIndirect(offset)
+	if err != nil {
+		return nil, false, err
+	}
+	s, ok := v.(stream)
+	if !ok || s.dict["Type"] != name("XRef") {
+		return nil, false, fmt.Errorf("%w: no xref at offset %d", ErrMalformed, offset)
+	}
+	if err := d.readXrefStream(s); err != nil {
+		return nil, false, err
+	}
+
+	return s.dict, true, nil
+}
+
+func (d *document) readXrefTable(p *parser) (dict, error) {
+	for {
+		p.skipSpace()
+		if p.hasKeyword("trailer") {
+			p.pos += len("trailer")
+			v, err := p.readObject()
+			if err != nil {
+				return nil, err
+			}
+			trailer, ok := v.(dict)
+			if !ok {
+				return nil, fmt.Errorf("%w: invalid trailer", ErrMalformed)
+			}
+			return trailer, nil
+		}
+
+		first, err := p.readInt()
+		if err != nil {
+			return nil, err
+		}
+		count, err := p.readInt()
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < count; i++ {
+			offset, err := p.readInt()
+			if err != nil {
+				return nil, err
+			}
+			gen, err := p.readInt()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			if p.pos >= len(p.data) || (p.data[p.pos] != 'n' && p.data[p.pos] != 'f') {
+				return nil, fmt.Errorf("%w: invalid xref entry", ErrMalformed)
+			}
+			kind := 1
+			if p.data[p.pos] == 'f' {
+				kind = 0
+			}
+			p.pos++
+			d.setEntry(first+i, xrefEntry{kind: kind, offset: offset, gen: gen})
+		}
+	}
+}
+
+func (d *document) readXrefStream(s stream) error {
+	data, err := decodeStream(s)
+	if err != nil {
+		return err
+	}
+
+	w, ok := s.dict["W"].(array)
+	if !ok || len(w) != 3 {
+		return fmt.Errorf("%w: invalid xref stream widths", ErrMalformed)
+	}
+	var widths [3]int
+	rowLen := 0
+	for i := range widths {
+		n, ok := w[i].(int64)
+		if !ok || n < 0 || n > 8 {
+			return fmt.Errorf("%w: invalid xref stream widths", ErrMalformed)
+		}
+		widths[i] = int(n)
+		rowLen += int(n)
+	}
+	if rowLen == 0 {
+		return fmt.Errorf("%w: invalid xref stream widths", ErrMalformed)
+	}
+
+	index := array{int64(0), s.dict["Size"]}
+	if idx, ok := s.dict["Index"].(array); ok {
+		index = idx
+	}
+	pos := 0
+	for i := 0; i+1 < len(index); i += 2 {
+		first, ok1 := index[i].(int64)
+		count, ok2 := index[i+1].(int64)
+		if !ok1 || !ok2 || first < 0 || count < 0 {
+			return fmt.Errorf("%w: invalid xref stream index", ErrMalformed)
+		}
+		for n := 0; n < int(count); n++ {
+			if pos+rowLen > len(data) {
+				return fmt.Errorf("%w: truncated xref stream", ErrMalformed)
+			}
+			var fields [3]int
+			for f, width := range widths {
+				for b := 0; b < width; b++ {
+					fields[f] = fields[f]<<8 | int(data[pos])
+					pos++
+				}
+			}
+			if widths[0] == 0 {
+				fields[0] = 1
+			}
+			d.setEntry(int(first)+n, xrefEntry{kind: fields[0], offset: fields[1], gen: fields[2]})
+		}
+	}
+
+	return nil
+}
+
+func (d *document) setEntry(num int, e xrefEntry) {
+	if _, ok := d.entries[num]; !ok {
+		d.entries[num] = e
+	}
+}
+
+func (d *document) maxObject() int {
+	size := 0
+	if n, ok := d.trailer["Size"].(int64); ok {
+		size = int(n)
+	}
+	for num := range d.entries {
+		if num+1 > size {
+			size = num + 1
+		}
+	}
+
+	return size
+}
+
+func (d *document) readIndirect(offset int) (ref, any, error) {
+	p := &parser{data: d.data, pos: offset}
+	num, err := p.readInt()
+	if err != nil {
+		return ref{}, nil, err
+	}
+	gen, err := p.readInt()
+	if err != nil {
+		return ref{}, nil, err
+	}
+	p.skipSpace()
+	if !p.hasKeyword("obj") {
+		return ref{}, nil, fmt.Errorf("%w: object %d not found at offset %d", ErrMalformed, num, offset)
+	}
+	p.pos += len("obj")
+
+	v, err := p.readObject()
+	if err != nil {
+		return ref{}, nil, err
+	}
+	p.skipSpace()
+	if dct, ok := v.(dict); ok && p.hasKeyword("stream") {
+		data, err := d.readStreamData(p, dct)
+		if err != nil {
+			return ref{}, nil, err
+		}
+		v = stream{dict: dct, data: data}
+	}
+
+	return ref{num: num, gen: gen}, v, nil
+}
+
+func (d *document) readStreamData(p *parser, dct dict) ([]byte, error) {
+	p.pos += len("stream")
+	if p.pos < len(p.data) && p.data[p.pos] == '\r' {
+		p.pos++
+	}
+	if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+		p.pos++
+	}
+	start := p.pos
+
+	length := -1
+	if v, err := d.resolve(dct["Length"]); err == nil {
+		if n, ok := v.(int64); ok {
+			length = int(n)
+		}
+	}
+	if length >= 0 && start+length <= len(p.data) {
+		rest := bytes.TrimLeft(p.data[start+length:], "\r\n \t")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			return p.data[start : start+length], nil
+		}
+	}
+
+	end := bytes.Index(p.data[start:], []byte("endstream"))
+	if end < 0 {
+		return nil, fmt.Errorf("%w: unterminated stream", ErrMalformed)
+	}
+	data := p.data[start : start+end]
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	data = bytes.TrimSuffix(data, []byte("\r"))
+
+	return data, nil
+}
+
+func (d *document) object(r ref) (any, error) {
+	e, ok := d.entries[r.num]
+	if !ok || e.kind == 0 {
+		return nil, nil
+	}
+
+	if e.kind == 2 {
+		return d.objectFromStream(e.offset, e.gen, r.num)
+	}
+
+	got, v, err := d.readIndirect(e.offset)
+	if err != nil {
+		return nil, err
+	}
+	if got.num != r.num {
+		return nil, fmt.Errorf("%w: xref points object %d to object %d", ErrMalformed, r.num, got.num)
+	}
+
+	return v, nil
+}
+
+func (d *document) objectFromStream(streamNum, index, num int) (any, error) {
+	os, ok := d.objstms[streamNum]
+	if !ok {
+		e, ok := d.entries[streamNum]
+		if !ok || e.kind != 1 {
+			return nil, fmt.Errorf("%w: object stream %d not found", ErrMalformed, streamNum)
+		}
+		_, v, err := d.readIndirect(e.offset)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := v.(stream)
+		if !ok {
+			return nil, fmt.Errorf("%w: object %d is not a stream", ErrMalformed, streamNum)
+		}
+		if os, err = d.readObjectStream(s); err != nil {
+			return nil, err
+		}
+		d.objstms[streamNum] = os
+	}
+
+	offset, ok := os.offsets[num]
+	if !ok {
+		return nil, fmt.Errorf("%w: object %d not in object stream %d", ErrMalformed, num, streamNum)
+	}
+	p := &parser{data: os.data, pos: offset}
+
+	return p.readObject()
+}
+
+func (d *document) readObjectStream(s stream) (*objectStream, error) {
+	data, err := decodeStream(s)
+	if err != nil {
+		return nil, err
+	}
+	n, ok1 := s.dict["N"].(int64)
+	first, ok2 := s.dict["First"].(int64)
+	if !ok1 || !ok2 || first < 0 || int(first) > len(data) {
+		return nil, fmt.Errorf("%w: invalid object stream", ErrMalformed)
+	}
+
+	os := &objectStream{data: data, offsets: map[int]int{}}
+	p := &parser{data: data[:first]}
+	for i := 0; i < int(n); i++ {
+		num, err := p.readInt()
+		if err != nil {
+			return nil, err
+		}
+		offset, err := p.readInt()
+		if err != nil {
+			return nil, err
+		}
+		os.offsets[num] = int(first) + offset
+	}
+
+	return os, nil
+}
+
+func (d *document) resolve(v any) (any, error) {
+	for i := 0; i < maxDepth; i++ {
+		r, ok := v.(ref)
+		if !ok {
+			return v, nil
+		}
+		var err error
+		if v, err = d.object(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("%w: reference chain too deep", ErrMalformed)
+}
+
+func (d *document) resolveDict(v any) (dict, error) {
+	v, err := d.resolve(v)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case dict:
+		return v, nil
+	case stream:
+		return v.dict, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%w: expected a dictionary", ErrMalformed)
+	}
+}
+
+func decodeStream(s stream) ([]byte, error) {
+	filters, params := s.dict["Filter"], s.dict["DecodeParms"]
+	if f, ok := filters.(name); ok {
+		filters, params = array{f}, array{params}
+	}
+	list, _ := filters.(array)
+	paramList, _ := params.(array)
+
+	data := s.data
+	for i, f := range list {
+		if f != name("FlateDecode") {
+			return nil, fmt.Errorf("%w: filter %v", ErrUnsupported, f)
+		}
+		r, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		if data, err = io.ReadAll(r); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		var dp dict
+		if i < len(paramList) {
+			dp, _ = paramList[i].(dict)
+		}
+		if data, err = unpredict(data, dp); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+func unpredict(data []byte, params dict) ([]byte, error) {
+	predictor := intParam(params, "Predictor", 1)
+	if predictor < 10 {
+		if predictor > 1 {
+			return nil, fmt.Errorf("%w: predictor %d", ErrUnsupported, predictor)
+		}
+		return data, nil
+	}
+
+	colors := intParam(params, "Colors", 1)
+	bits := intParam(params, "BitsPerComponent", 8)
+	columns := intParam(params, "Columns", 1)
+	bpp := (colors*bits + 7) / 8
+	rowLen := (columns*colors*bits + 7) / 8
+	if bpp < 1 || rowLen < 1 {
+		return nil, fmt.Errorf("%w: invalid predictor parameters", ErrMalformed)
+	}
+
+	var out []byte
+	prev := make([]byte, rowLen)
+	for pos := 0; pos+rowLen+1 <= len(data); pos += rowLen + 1 {
+		kind, row := data[pos], append([]byte(nil), data[pos+1:pos+1+rowLen]...)
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = row[i-bpp], prev[i-bpp]
+			}
+			up := prev[i]
+			switch kind {
+			case 0:
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += up
+			case 3:
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[i] += paeth(left, up, upLeft)
+			default:
+				return nil, fmt.Errorf("%w: png filter %d", ErrMalformed, kind)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	default:
+		return c
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func intParam(params dict, key string, fallback int) int {
+	if n, ok := params[key].(int64); ok {
+		return int(n)
+	}
+	return fallback
+}
+
+type parser struct {
+	data []byte
+	pos  int
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isDelimiter(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if c == '%' {
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+		if !isSpace(c) {
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *parser) hasKeyword(kw string) bool {
+	end := p.pos + len(kw)
+	if end > len(p.data) || string(p.data[p.pos:end]) != kw {
+		return false
+	}
+	return end == len(p.data) || isSpace(p.data[end]) || isDelimiter(p.data[end])
+}
+
+func (p *parser) readInt() (int, error) {
+	v, err := p.readObject()
+	if err != nil {
+		return 0, err
+	}
+	n, ok := v.(int64)
+	if !ok {
+		return 0, fmt.Errorf("%w: expected an integer at offset %d", ErrMalformed, p.pos)
+	}
+	return int(n), nil
+}
+
+func (p *parser) readObject() (any, error) {
+	return p.readValue(0)
+}
+
+func (p *parser) readValue(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("%w: nesting too deep", ErrMalformed)
+	}
+
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrMalformed)
+	}
+
+	switch c := p.data[p.pos]; {
+	case c == '/':
+		return p.readName(), nil
+	case c == '(':
+		return p.readLiteralString()
+	case c == '<' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '<':
+		return p.readDict(depth)
+	case c == '<':
+		return p.readHexString()
+	case c == '[':
+		return p.readArray(depth)
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return p.readNumberOrRef()
+	case p.hasKeyword("true"):
+		p.pos += 4
+		return true, nil
+	case p.hasKeyword("false"):
+		p.pos += 5
+		return false, nil
+	case p.hasKeyword("null"):
+		p.pos += 4
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected %q at offset %d", ErrMalformed, c, p.pos)
+	}
+}
+
+func (p *parser) readName() name {
+	p.pos++
+	var b []byte
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if isSpace(c) || isDelimiter(c) {
+			break
+		}
+		if c == '#' && p.pos+2 < len(p.data) {
+			if n, err := strconv.ParseUint(string(p.data[p.pos+1:p.pos+3]), 16, 8); err == nil {
+				b = append(b, byte(n))
+				p.pos += 3
+				continue
+			}
+		}
+		b = append(b, c)
+		p.pos++
+	}
+
+	return name(b)
+}
+
+func (p *parser) readLiteralString() ([]byte, error) {
+	p.pos++
+	var b []byte
+	for depth := 1; p.pos < len(p.data); p.pos++ {
+		c := p.data[p.pos]
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				p.pos++
+				return b, nil
+			}
+		case '\\':
+			p.pos++
+			if p.pos >= len(p.data) {
+				break
+			}
+			c = p.data[p.pos]
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if p.pos+1 < len(p.data) && p.data[p.pos+1] == '\n' {
+					p.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					n := 0
+					for i := 0; i < 3 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; i++ {
+						n = n*8 + int(p.data[p.pos]-'0')
+						p.pos++
+					}
+					p.pos--
+					c = byte(n)
+				}
+			}
+		}
+		b = append(b, c)
+	}
+
+	return nil, fmt.Errorf("%w: unterminated string", ErrMalformed)
+}
+
+func (p *parser) readHexString() ([]byte, error) {
+	end := bytes.IndexByte(p.data[p.pos:], '>')
+	if end < 0 {
+		return nil, fmt.Errorf("%w: unterminated hex string", ErrMalformed)
+	}
+	var digits []byte
+	for _, c := range p.data[p.pos+1 : p.pos+end] {
+		if !isSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	p.pos += end + 1
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	b := make([]byte, len(digits)/2)
+	for i := range b {
+		n, err := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid hex string", ErrMalformed)
+		}
+		b[i] = byte(n)
+	}
+
+	return b, nil
+}
+
+func (p *parser) readArray(depth int) (array, error) {
+	p.pos++
+	a := array{}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return nil, fmt.Errorf("%w: unterminated array", ErrMalformed)
+		}
+		if p.data[p.pos] == ']' {
+			p.pos++
+			return a, nil
+		}
+		v, err := p.readValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+	}
+}
+
+func (p *parser) readDict(depth int) (dict, error) {
+	p.pos += 2
+	d := dict{}
+	for {
+		p.skipSpace()
+		if p.pos+1 < len(p.data) && p.data[p.pos] == '>' && p.data[p.pos+1] == '>' {
+			p.pos += 2
+			return d, nil
+		}
+		if p.pos >= len(p.data) || p.data[p.pos] != '/' {
+			return nil, fmt.Errorf("%w: invalid dictionary key at offset %d", ErrMalformed, p.pos)
+		}
+		key := p.readName()
+		v, err := p.readValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		d[string(key)] = v
+	}
+}
+
+func (p *parser) readNumberOrRef() (any, error) {
+	start := p.pos
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if !(c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9')) {
+			break
+		}
+		p.pos++
+	}
+	token := string(p.data[start:p.pos])
+
+	n, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		if _, err := strconv.ParseFloat(token, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid number %q", ErrMalformed, token)
+		}
+		return real(token), nil
+	}
+
+	save := p.pos
+	p.skipSpace()
+	genStart := p.pos
+	for p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos > genStart {
+		gen, _ := strconv.Atoi(string(p.data[genStart:p.pos]))
+		p.skipSpace()
+		if p.hasKeyword("R") {
+			p.pos++
+			return ref{num: int(n), gen: gen}, nil
+		}
+	}
+	p.pos = save
+
+	return n, nil
+}
@@ -0,0 +1,357 @@
+package pdfstamp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	keyPrefix = "cv_stamp:"
+
+	fontName  = "CVStamp"
+	fontSize  = 6
+	footerGap = 12
+	maxPages  = 1000
+
+	xmpNamespace = "https://adrianjanczenia.dev/ns/cvstamp/1.0/"
+)
+
+var ErrMalformedStamp = errors.New("malformed CV stamp")
+
+type Stamp struct {
+	ID         string    `json:"id"`
+	Token      string    `json:"token"`
+	Credential string    `json:"credential"`
+	Lang       string    `json:"lang"`
+	Document   string    `json:"document"`
+	Client     string    `json:"client,omitempty"`
+	StampedAt  time.Time `json:"stampedAt"`
+}
+
+func Key(id string) string {
+	return keyPrefix + id
+}
+
+func NewID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (s Stamp) Encode() (string, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func Decode(value string) (Stamp, error) {
+	var s Stamp
+	if err := json.Unmarshal([]byte(value), &s); err != nil || s.ID == "" || s.StampedAt.IsZero() {
+		return Stamp{}, ErrMalformedStamp
+	}
+	return s, nil
+}
+
+func (s Stamp) Footer() string {
+	token := s.Token
+	if len(token) > 12 {
+		token = token[:12]
+	}
+	return fmt.Sprintf("Personal copy for %s | token %s | %s UTC | ref %s",
+		s.Credential, token, s.StampedAt.UTC().Format("2006-01-02 15:04"), s.ID)
+}
+
+func Apply(pdf []byte, s Stamp) ([]byte, error) {
+	doc, err := parseDocument(pdf)
+	if err != nil {
+		return nil, err
+	}
+
+	rootRef := doc.trailer["Root"].(ref)
+	root, err := doc.resolveDict(rootRef)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, fmt.Errorf("%w: catalog not found", ErrMalformed)
+	}
+
+	var pages []page
+	if err := doc.collectPages(root["Pages"], inherited{}, map[int]bool{}, &pages); err != nil {
+		return nil, err
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("%w: no pages", ErrMalformed)
+	}
+
+	u := newUpdate(doc)
+	font := u.allocate()
+	u.write(font, dict{
+		"Type":     name("Font"),
+		"Subtype":  name("Type1"),
+		"BaseFont": name("Helvetica"),
+		"Encoding": name("WinAnsiEncoding"),
+	})
+	save := u.allocate()
+	u.write(save, stream{dict: dict{}, data: []byte("q\n")})
+
+	for _, pg := range pages {
+		if err := stampPage(doc, u, pg, font, save, s.Footer()); err != nil {
+			return nil, err
+		}
+	}
+
+	metadata := u.allocate()
+	u.write(metadata, stream{
+		dict: dict{"Type": name("Metadata"), "Subtype": name("XML")},
+		data: mergeXMP(doc, root["Metadata"], s),
+	})
+	catalog := copyDict(root)
+	catalog["Metadata"] = metadata
+	u.write(rootRef, catalog)
+
+	return u.finish(), nil
+}
+
+type inherited struct {
+	resources any
+	mediaBox  any
+	cropBox   any
+}
+
+type page struct {
+	ref       ref
+	dict      dict
+	resources any
+	box       any
+}
+
+func (d *document) collectPages(node any, attrs inherited, visited map[int]bool, pages *[]page) error {
+	r, ok := node.(ref)
+	if !ok {
+		return fmt.Errorf("%w: page tree node is not a reference", ErrMalformed)
+	}
+	if visited[r.num] || len(*pages) >= maxPages {
+		return fmt.Errorf("%w: invalid page tree", ErrMalformed)
+	}
+	visited[r.num] = true
+
+	n, err := d.resolveDict(r)
+	if err != nil {
+		return err
+	}
+	if n == nil {
+		return fmt.Errorf("%w: page tree node %d not found", ErrMalformed, r.num)
+	}
+	if v, ok := n["Resources"]; ok {
+		attrs.resources = v
+	}
+	if v, ok := n["MediaBox"]; ok {
+		attrs.mediaBox = v
+	}
+	if v, ok := n["CropBox"]; ok {
+		attrs.cropBox = v
+	}
+
+	kids, isTree := n["Kids"]
+	if n["Type"] == name("Page") || !isTree {
+		box := attrs.mediaBox
+		if attrs.cropBox != nil {
+			box = attrs.cropBox
+		}
+		*pages = append(*pages, page{ref: r, dict: n, resources: attrs.resources, box: box})
+		return nil
+	}
+
+	list, err := d.resolve(kids)
+	if err != nil {
+		return err
+	}
+	kidList, ok := list.(array)
+	if !ok {
+		return fmt.Errorf("%w: page tree kids are not an array", ErrMalformed)
+	}
+	for _, kid := range kidList {
+		if err := d.collectPages(kid, attrs, visited, pages); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func stampPage(doc *document, u *update, pg page, font, save ref, footer string) error {
+	llx, lly, err := boxOrigin(doc, pg.box)
+	if err != nil {
+		return err
+	}
+
+	contents, err := doc.resolve(pg.dict["Contents"])
+	if err != nil {
+		return err
+	}
+	var original array
+	switch c := contents.(type) {
+	case array:
+		original = c
+	case stream:
+		original = array{pg.dict["Contents"]}
+	}
+
+	var ops bytes.Buffer
+	if len(original) > 0 {
+		ops.WriteString("Q\n")
+	}
+	fmt.Fprintf(&ops, "q\n0.45 g\nBT\n/%s %d Tf\n%s %s Td\n(%s) Tj\nET\nQ\n",
+		fontName, fontSize, formatNumber(llx+footerGap*2), formatNumber(lly+footerGap), escapeText(footer))
+	footerRef := u.allocate()
+	u.write(footerRef, stream{dict: dict{}, data: ops.Bytes()})
+
+	newContents := array{footerRef}
+	if len(original) > 0 {
+		newContents = append(append(array{save}, original...), footerRef)
+	}
+
+	resources, err := doc.resolveDict(pg.resources)
+	if err != nil {
+		return err
+	}
+	resources = copyDict(resources)
+	fonts, err := doc.resolveDict(resources["Font"])
+	if err != nil {
+		return err
+	}
+	fonts = copyDict(fonts)
+	fonts[fontName] = font
+	resources["Font"] = fonts
+
+	updated := copyDict(pg.dict)
+	updated["Contents"] = newContents
+	updated["Resources"] = resources
+	u.write(pg.ref, updated)
+
+	return nil
+}
+
+func boxOrigin(doc *document, box any) (float64, float64, error) {
+	v, err := doc.resolve(box)
+	if err != nil {
+		return 0, 0, err
+	}
+	a, ok := v.(array)
+	if !ok || len(a) != 4 {
+		return 0, 0, nil
+	}
+
+	llx, err := toFloat(doc, a[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	lly, err := toFloat(doc, a[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return llx, lly, nil
+}
+
+func toFloat(doc *document, v any) (float64, error) {
+	v, err := doc.resolve(v)
+	if err != nil {
+		return 0, err
+	}
+	switch n := v.(type) {
+	case int64:
+		return float64(n), nil
+	case real:
+		var f float64
+		if _, err := fmt.Sscan(string(n), &f); err != nil {
+			return 0, fmt.Errorf("%w: invalid number %q", ErrMalformed, n)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("%w: expected a number", ErrMalformed)
+	}
+}
+
+func formatNumber(f float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", f), "0"), ".")
+}
+
+func escapeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func copyDict(d dict) dict {
+	c := make(dict, len(d)+1)
+	for k, v := range d {
+		c[k] = v
+	}
+	return c
+}
+
+func mergeXMP(doc *document, existing any, s Stamp) []byte {
+	description := xmpDescription(s)
+
+	if v, err := doc.resolve(existing); err == nil {
+		if st, ok := v.(stream); ok {
+			if data, err := decodeStream(st); err == nil {
+				if i := bytes.LastIndex(data, []byte("</rdf:RDF>")); i >= 0 {
+					merged := append([]byte(nil), data[:i]...)
+					merged = append(merged, description...)
+					return append(merged, data[i:]...)
+				}
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	b.WriteString("<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n")
+	b.Write(description)
+	b.WriteString("</rdf:RDF>\n</x:xmpmeta>\n<?xpacket end=\"w\"?>")
+
+	return b.Bytes()
+}
+
+func xmpDescription(s Stamp) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<rdf:Description rdf:about=\"\" xmlns:cvstamp=\"%s\">\n", xmpNamespace)
+	for _, field := range []struct{ key, value string }{
+		{"StampID", s.ID},
+		{"Token", s.Token},
+		{"Credential", s.Credential},
+		{"StampedAt", s.StampedAt.UTC().Format(time.RFC3339)},
+	} {
+		fmt.Fprintf(&b, "<cvstamp:%s>", field.key)
+		xml.EscapeText(&b, []byte(field.value))
+		fmt.Fprintf(&b, "</cvstamp:%s>\n", field.key)
+	}
+	b.WriteString("</rdf:Description>\n")
+
+	return b.Bytes()
+}
@@ -0,0 +1,253 @@
+package pdfstamp
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testStamp = Stamp{
+	ID:         "4b1c9e0d2a7f6e35",
+	Token:      "3f9a2c1b7d0e5a6b8c9d0e1f2a3b4c5d",
+	Credential: "acme (recruiter)",
+	Lang:       "pl",
+	Document:   "pl_cv.pdf",
+	StampedAt:  time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+}
+
+const (
+	catalogObject = "<< /Type /Catalog /Pages 2 0 R >>"
+	pagesObject   = "<< /Type /Pages /Kids [3 0 R] /Count 1 /MediaBox [0 0 595.28 841.89] >>"
+	pageObject    = "<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>"
+	fontObject    = "<< /Type /Font /Subtype /Type1 /BaseFont /Times-Roman >>"
+	contents      = "BT /F1 12 Tf 72 720 Td (Adrian Janczenia) Tj ET"
+)
+
+func streamObject(data string) string {
+	return fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(data), data)
+}
+
+func classicPDF(trailer string, objects ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+
+	start := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f\r\n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n\r\n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R%s >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, trailer, start)
+
+	return b.Bytes()
+}
+
+func compress(data []byte) []byte {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write(data)
+	w.Close()
+	return b.Bytes()
+}
+
+func xrefStreamPDF() []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.5\n")
+
+	var header, body bytes.Buffer
+	for i, obj := range []string{catalogObject, pagesObject, pageObject} {
+		fmt.Fprintf(&header, "%d %d ", i+1, body.Len())
+		body.WriteString(obj + "\n")
+	}
+	objstm := compress(append(header.Bytes(), body.Bytes()...))
+
+	offsets := map[int]int{}
+	offsets[4] = b.Len()
+	fmt.Fprintf(&b, "4 0 obj\n%s\nendobj\n", streamObject(contents))
+	offsets[5] = b.Len()
+	fmt.Fprintf(&b, "5 0 obj\n%s\nendobj\n", fontObject)
+	offsets[6] = b.Len()
+	fmt.Fprintf(&b, "6 0 obj\n<< /Type /ObjStm /N 3 /First %d /Filter /FlateDecode /Length %d >>\nstream\n", header.Len(), len(objstm))
+	b.Write(objstm)
+	b.WriteString("\nendstream\nendobj\n")
+	offsets[7] = b.Len()
+
+	rows := [][]byte{{0, 0, 0, 0, 0, 0xff, 0xff}}
+	for num := 1; num <= 7; num++ {
+		if num <= 3 {
+			rows = append(rows, []byte{2, 0, 0, 0, 6, 0, byte(num - 1)})
+			continue
+		}
+		o := offsets[num]
+		rows = append(rows, []byte{1, byte(o >> 24), byte(o >> 16), byte(o >> 8), byte(o), 0, 0})
+	}
+	var raw []byte
+	prev := make([]byte, 7)
+	for _, row := range rows {
+		raw = append(raw, 2)
+		for i := range row {
+			raw = append(raw, row[i]-prev[i])
+		}
+		prev = row
+	}
+	xref := compress(raw)
+
+	fmt.Fprintf(&b, "7 0 obj\n<< /Type /XRef /Size 8 /Root 1 0 R /W [1 4 2] /Filter /FlateDecode /DecodeParms << /Predictor 12 /Columns 7 >> /Length %d >>\nstream\n", len(xref))
+	b.Write(xref)
+	fmt.Fprintf(&b, "\nendstream\nendobj\nstartxref\n%d\n%%%%EOF\n", offsets[7])
+
+	return b.Bytes()
+}
+
+func streamData(t *testing.T, doc *document, v any) string {
+	t.Helper()
+	v, err := doc.resolve(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, ok := v.(stream)
+	if !ok {
+		t.Fatalf("expected a stream, got %T", v)
+	}
+	data, err := decodeStream(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name           string
+		pdf            []byte
+		wantXrefStream bool
+		wantMetadata   []string
+	}{
+		{
+			name: "classic xref table",
+			pdf:  classicPDF("", catalogObject, pagesObject, pageObject, streamObject(contents), fontObject),
+		},
+		{
+			name:           "xref and object streams",
+			pdf:            xrefStreamPDF(),
+			wantXrefStream: true,
+		},
+		{
+			name: "existing metadata is kept",
+			pdf: classicPDF("",
+				"<< /Type /Catalog /Pages 2 0 R /Metadata 6 0 R >>", pagesObject, pageObject, streamObject(contents), fontObject,
+				"<< /Type /Metadata /Subtype /XML /Length 88 >>\nstream\n<x:xmpmeta><rdf:RDF><rdf:Description><dc:title>CV</dc:title></rdf:Description></rdf:RDF>\nendstream"),
+			wantMetadata: []string{"<dc:title>CV</dc:title>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Apply(tt.pdf, testStamp)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if !bytes.HasPrefix(out, tt.pdf) {
+				t.Fatal("Apply() must append an incremental update to the original file")
+			}
+			again, err := Apply(tt.pdf, testStamp)
+			if err != nil || !bytes.Equal(again, out) {
+				t.Error("Apply() must be deterministic")
+			}
+
+			doc, err := parseDocument(out)
+			if err != nil {
+				t.Fatalf("stamped pdf does not parse: %v", err)
+			}
+			if doc.xrefStream != tt.wantXrefStream {
+				t.Errorf("stamped pdf xref stream = %v, want %v", doc.xrefStream, tt.wantXrefStream)
+			}
+			if doc.trailer["Prev"] == nil {
+				t.Error("stamped pdf trailer must point at the previous xref")
+			}
+
+			root, _ := doc.resolveDict(doc.trailer["Root"])
+			metadata := streamData(t, doc, root["Metadata"])
+			for _, want := range append(tt.wantMetadata, "<cvstamp:StampID>4b1c9e0d2a7f6e35</cvstamp:StampID>", "<cvstamp:Credential>acme (recruiter)</cvstamp:Credential>", "<cvstamp:StampedAt>2026-03-01T12:30:00Z</cvstamp:StampedAt>") {
+				if !strings.Contains(metadata, want) {
+					t.Errorf("metadata does not contain %s:\n%s", want, metadata)
+				}
+			}
+
+			pg, _ := doc.resolveDict(ref{num: 3})
+			parts, ok := pg["Contents"].(array)
+			if !ok || len(parts) != 3 || parts[1] != (ref{num: 4}) {
+				t.Fatalf("page contents = %v, want the original stream wrapped by the stamp", pg["Contents"])
+			}
+			if got := streamData(t, doc, parts[0]) + streamData(t, doc, parts[1]) + streamData(t, doc, parts[2]); !strings.Contains(got, "(Personal copy for acme \\(recruiter\\) | token 3f9a2c1b7d0e | 2026-03-01 12:30 UTC | ref 4b1c9e0d2a7f6e35) Tj") || !strings.HasPrefix(got, "q\n"+contents) {
+				t.Errorf("page content = %s", got)
+			}
+
+			resources, _ := pg["Resources"].(dict)
+			fonts, _ := resources["Font"].(dict)
+			if fonts["F1"] != (ref{num: 5}) || fonts[fontName] == nil {
+				t.Errorf("page fonts = %v, want F1 and %s", fonts, fontName)
+			}
+		})
+	}
+}
+
+func TestApply_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		pdf     []byte
+		wantErr error
+	}{
+		{
+			name:    "not a pdf",
+			pdf:     []byte("Adrian Janczenia CV"),
+			wantErr: ErrMalformed,
+		},
+		{
+			name:    "encrypted",
+			pdf:     classicPDF(" /Encrypt 6 0 R", catalogObject, pagesObject, pageObject, streamObject(contents), fontObject),
+			wantErr: ErrEncrypted,
+		},
+		{
+			name:    "no pages",
+			pdf:     classicPDF("", "<< /Type /Catalog /Pages 2 0 R >>", "<< /Type /Pages /Kids [] /Count 0 >>"),
+			wantErr: ErrMalformed,
+		},
+		{
+			name:    "page tree loop",
+			pdf:     classicPDF("", catalogObject, "<< /Type /Pages /Kids [2 0 R] /Count 1 >>"),
+			wantErr: ErrMalformed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Apply(tt.pdf, testStamp); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	value, err := testStamp.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	got, err := Decode(value)
+	if err != nil || got != testStamp {
+		t.Errorf("Decode() = %+v, %v, want %+v", got, err, testStamp)
+	}
+
+	if _, err := Decode(`{"id":"4b1c"}`); !errors.Is(err, ErrMalformedStamp) {
+		t.Errorf("Decode() error = %v, want %v", err, ErrMalformedStamp)
+	}
+}
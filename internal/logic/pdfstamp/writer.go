@@ -0,0 +1,177 @@
+package pdfstamp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+type update struct {
+	doc     *document
+	buf     bytes.Buffer
+	next    int
+	offsets map[int]xrefEntry
+}
+
+func newUpdate(doc *document) *update {
+	u := &update{doc: doc, next: doc.maxObject(), offsets: map[int]xrefEntry{}}
+	u.buf.Write(doc.data)
+	if !bytes.HasSuffix(doc.data, []byte("\n")) && !bytes.HasSuffix(doc.data, []byte("\r")) {
+		u.buf.WriteByte('\n')
+	}
+
+	return u
+}
+
+func (u *update) allocate() ref {
+	r := ref{num: u.next}
+	u.next++
+	return r
+}
+
+func (u *update) write(r ref, v any) {
+	u.offsets[r.num] = xrefEntry{kind: 1, offset: u.buf.Len(), gen: r.gen}
+	fmt.Fprintf(&u.buf, "%d %d obj\n", r.num, r.gen)
+	if s, ok := v.(stream); ok {
+		s.dict["Length"] = int64(len(s.data))
+		writeValue(&u.buf, s.dict)
+		u.buf.WriteString("\nstream\n")
+		u.buf.Write(s.data)
+		u.buf.WriteString("\nendstream")
+	} else {
+		writeValue(&u.buf, v)
+	}
+	u.buf.WriteString("\nendobj\n")
+}
+
+func (u *update) finish() []byte {
+	trailer := dict{"Root": u.doc.trailer["Root"], "Prev": int64(u.doc.startxref)}
+	for _, key := range []string{"Info", "ID"} {
+		if v, ok := u.doc.trailer[key]; ok {
+			trailer[key] = v
+		}
+	}
+
+	if u.doc.xrefStream {
+		u.finishStream(trailer)
+	} else {
+		u.finishTable(trailer)
+	}
+
+	return u.buf.Bytes()
+}
+
+func (u *update) finishTable(trailer dict) {
+	trailer["Size"] = int64(u.next)
+	start := u.buf.Len()
+	u.buf.WriteString("xref\n")
+	for _, section := range u.sections() {
+		fmt.Fprintf(&u.buf, "%d %d\n", section[0], len(section))
+		for _, num := range section {
+			e := u.offsets[num]
+			fmt.Fprintf(&u.buf, "%010d %05d n\r\n", e.offset, e.gen)
+		}
+	}
+	u.buf.WriteString("trailer\n")
+	writeValue(&u.buf, trailer)
+	fmt.Fprintf(&u.buf, "\nstartxref\n%d\n%%%%EOF\n", start)
+}
+
+func (u *update) finishStream(trailer dict) {
+	self := u.allocate()
+	start := u.buf.Len()
+	u.offsets[self.num] = xrefEntry{kind: 1, offset: start}
+
+	var data []byte
+	index := array{}
+	for _, section := range u.sections() {
+		index = append(index, int64(section[0]), int64(len(section)))
+		for _, num := range section {
+			e := u.offsets[num]
+			data = append(data, 1, byte(e.offset>>24), byte(e.offset>>16), byte(e.offset>>8), byte(e.offset), byte(e.gen>>8), byte(e.gen))
+		}
+	}
+
+	trailer["Type"] = name("XRef")
+	trailer["Size"] = int64(u.next)
+	trailer["W"] = array{int64(1), int64(4), int64(2)}
+	trailer["Index"] = index
+	u.write(self, stream{dict: trailer, data: data})
+	fmt.Fprintf(&u.buf, "startxref\n%d\n%%%%EOF\n", start)
+}
+
+func (u *update) sections() [][]int {
+	nums := make([]int, 0, len(u.offsets))
+	for num := range u.offsets {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+
+	var sections [][]int
+	for i, num := range nums {
+		if i == 0 || num != nums[i-1]+1 {
+			sections = append(sections, nil)
+		}
+		sections[len(sections)-1] = append(sections[len(sections)-1], num)
+	}
+
+	return sections
+}
+
+func writeValue(buf *bytes.Buffer, v any) {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case real:
+		buf.WriteString(string(v))
+	case name:
+		writeName(buf, string(v))
+	case []byte:
+		buf.WriteByte('<')
+		buf.WriteString(hex.EncodeToString(v))
+		buf.WriteByte('>')
+	case ref:
+		fmt.Fprintf(buf, "%d %d R", v.num, v.gen)
+	case array:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			writeValue(buf, item)
+		}
+		buf.WriteByte(']')
+	case dict:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buf.WriteString("<<")
+		for _, key := range keys {
+			writeName(buf, key)
+			buf.WriteByte(' ')
+			writeValue(buf, v[key])
+		}
+		buf.WriteString(">>")
+	}
+}
+
+func writeName(buf *bytes.Buffer, n string) {
+	buf.WriteByte('/')
+	for i := 0; i < len(n); i++ {
+		c := n[i]
+		if c < 0x21 || c > 0x7e || c == '#' || isDelimiter(c) {
+			fmt.Fprintf(buf, "#%02x", c)
+			continue
+		}
+		buf.WriteByte(c)
+	}
+}
@@ -0,0 +1,115 @@
+package pipeline
+
+import "fmt"
+
+const (
+	StepRateLimit      = "rate_limit"
+	StepLockout        = "lockout"
+	StepPow            = "pow"
+	StepCaptcha        = "captcha"
+	StepPassword       = "password"
+	StepConsumeCaptcha = "consume_captcha"
+	StepIssueToken     = "issue_token"
+	StepEmailLink      = "email_link"
+	StepNotify         = "notify"
+	StepAudit          = "audit"
+)
+
+var known = map[string]bool{
+	StepRateLimit:      true,
+	StepLockout:        true,
+	StepPow:            true,
+	StepCaptcha:        true,
+	StepPassword:       true,
+	StepConsumeCaptcha: true,
+	StepIssueToken:     true,
+	StepEmailLink:      true,
+	StepNotify:         true,
+	StepAudit:          true,
+}
+
+// steps that must run in this relative order whenever they are enabled
+var ordered = []string{StepCaptcha, StepPassword, StepConsumeCaptcha, StepIssueToken, StepEmailLink}
+
+// steps that must run before captcha and password whenever they are enabled
+var guards = []string{StepRateLimit, StepLockout, StepPow}
+
+type Step struct {
+	Name    string
+	Enabled bool
+}
+
+func Default() []Step {
+	return []Step{
+		{Name: StepRateLimit, Enabled: true},
+		{Name: StepLockout, Enabled: true},
+		{Name: StepPow, Enabled: true},
+		{Name: StepCaptcha, Enabled: true},
+		{Name: StepPassword, Enabled: true},
+		{Name: StepConsumeCaptcha, Enabled: true},
+		{Name: StepIssueToken, Enabled: true},
+		{Name: StepEmailLink, Enabled: true},
+		{Name: StepNotify, Enabled: true},
+		{Name: StepAudit, Enabled: true},
+	}
+}
+
+func Enabled(steps []Step, name string) bool {
+	for _, s := range steps {
+		if s.Name == name {
+			return s.Enabled
+		}
+	}
+
+	return false
+}
+
+func Validate(steps []Step) error {
+	position := make(map[string]int, len(steps))
+	for i, s := range steps {
+		if !known[s.Name] {
+			return fmt.Errorf("unknown pipeline step %q", s.Name)
+		}
+		if _, ok := position[s.Name]; ok {
+			return fmt.Errorf("pipeline step %q is declared twice", s.Name)
+		}
+		position[s.Name] = i
+	}
+
+	for _, name := range []string{StepPassword, StepIssueToken} {
+		if !Enabled(steps, name) {
+			return fmt.Errorf("pipeline step %q cannot be disabled", name)
+		}
+	}
+	if Enabled(steps, StepCaptcha) != Enabled(steps, StepConsumeCaptcha) {
+		return fmt.Errorf("pipeline steps %q and %q must be enabled together", StepCaptcha, StepConsumeCaptcha)
+	}
+
+	previous := ""
+	for _, name := range ordered {
+		if !Enabled(steps, name) {
+			continue
+		}
+		if previous != "" && position[name] < position[previous] {
+			return fmt.Errorf("pipeline step %q must come after %q", name, previous)
+		}
+		previous = name
+	}
+
+	for _, guard := range guards {
+		if !Enabled(steps, guard) {
+			continue
+		}
+		for _, name := range []string{StepCaptcha, StepPassword} {
+			if Enabled(steps, name) && position[name] < position[guard] {
+				return fmt.Errorf("pipeline step %q must come before %q", guard, name)
+			}
+		}
+	}
+
+	if Enabled(steps, StepAudit) && position[StepAudit] != len(steps)-1 {
+		return fmt.Errorf("pipeline step %q must be the last step", StepAudit)
+	}
+
+	return nil
+}
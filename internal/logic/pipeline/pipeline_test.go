@@ -0,0 +1,173 @@
+package pipeline
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []Step
+		wantErr bool
+	}{
+		{
+			name:  "default",
+			steps: Default(),
+		},
+		{
+			name: "captcha and pow disabled",
+			steps: []Step{
+				{Name: StepRateLimit, Enabled: true},
+				{Name: StepPow},
+				{Name: StepCaptcha},
+				{Name: StepPassword, Enabled: true},
+				{Name: StepConsumeCaptcha},
+				{Name: StepIssueToken, Enabled: true},
+			},
+		},
+		{
+			name: "disabled step out of order",
+			steps: []Step{
+				{Name: StepPassword, Enabled: true},
+				{Name: StepCaptcha},
+				{Name: StepIssueToken, Enabled: true},
+			},
+		},
+		{
+			name:    "unknown step",
+			steps:   append(Default(), Step{Name: "sms", Enabled: true}),
+			wantErr: true,
+		},
+		{
+			name:    "duplicate step",
+			steps:   append([]Step{{Name: StepPow, Enabled: true}}, Default()...),
+			wantErr: true,
+		},
+		{
+			name:    "password missing",
+			steps:   []Step{{Name: StepIssueToken, Enabled: true}},
+			wantErr: true,
+		},
+		{
+			name: "issue token disabled",
+			steps: []Step{
+				{Name: StepPassword, Enabled: true},
+				{Name: StepIssueToken},
+			},
+			wantErr: true,
+		},
+		{
+			name: "captcha without consume captcha",
+			steps: []Step{
+				{Name: StepCaptcha, Enabled: true},
+				{Name: StepPassword, Enabled: true},
+				{Name: StepIssueToken, Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "token issued before password",
+			steps: []Step{
+				{Name: StepIssueToken, Enabled: true},
+				{Name: StepPassword, Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "captcha consumed before password",
+			steps: []Step{
+				{Name: StepCaptcha, Enabled: true},
+				{Name: StepConsumeCaptcha, Enabled: true},
+				{Name: StepPassword, Enabled: true},
+				{Name: StepIssueToken, Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "link emailed before token issued",
+			steps: []Step{
+				{Name: StepPassword, Enabled: true},
+				{Name: StepEmailLink, Enabled: true},
+				{Name: StepIssueToken, Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "pow after password",
+			steps: []Step{
+				{Name: StepPassword, Enabled: true},
+				{Name: StepPow, Enabled: true},
+				{Name: StepIssueToken, Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rate limit after captcha",
+			steps: []Step{
+				{Name: StepCaptcha, Enabled: true},
+				{Name: StepRateLimit, Enabled: true},
+				{Name: StepPassword, Enabled: true},
+				{Name: StepConsumeCaptcha, Enabled: true},
+				{Name: StepIssueToken, Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "lockout after password",
+			steps: []Step{
+				{Name: StepPassword, Enabled: true},
+				{Name: StepLockout, Enabled: true},
+				{Name: StepIssueToken, Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "guards reordered among themselves",
+			steps: []Step{
+				{Name: StepPow, Enabled: true},
+				{Name: StepLockout, Enabled: true},
+				{Name: StepRateLimit, Enabled: true},
+				{Name: StepPassword, Enabled: true},
+				{Name: StepIssueToken, Enabled: true},
+				{Name: StepAudit, Enabled: true},
+			},
+		},
+		{
+			name: "disabled guard after password",
+			steps: []Step{
+				{Name: StepPassword, Enabled: true},
+				{Name: StepIssueToken, Enabled: true},
+				{Name: StepPow},
+			},
+		},
+		{
+			name: "audit not last",
+			steps: []Step{
+				{Name: StepAudit, Enabled: true},
+				{Name: StepPassword, Enabled: true},
+				{Name: StepIssueToken, Enabled: true},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.steps); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	steps := []Step{{Name: StepPow}, {Name: StepCaptcha, Enabled: true}}
+
+	if Enabled(steps, StepPow) {
+		t.Error("disabled step must not be enabled")
+	}
+	if !Enabled(steps, StepCaptcha) {
+		t.Error("enabled step must be enabled")
+	}
+	if Enabled(steps, StepAudit) {
+		t.Error("undeclared step must not be enabled")
+	}
+}
@@ -0,0 +1,126 @@
+package pow
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	MaxDifficulty  = 32
+	minSecretBytes = 32
+	maxNonceLength = 64
+)
+
+var (
+	ErrMissingSolution    = errors.New("missing pow solution")
+	ErrMalformedChallenge = errors.New("malformed pow challenge")
+	ErrInvalidSignature   = errors.New("invalid pow challenge signature")
+	ErrChallengeExpired   = errors.New("pow challenge expired")
+	ErrInsufficientWork   = errors.New("pow nonce does not meet the difficulty")
+)
+
+type Solution struct {
+	Challenge string
+	Signature string
+	Nonce     string
+}
+
+type Challenge struct {
+	Seed      string
+	ExpiresAt time.Time
+}
+
+func ValidateConfig(secret []byte, difficulty int) error {
+	if difficulty < 0 || difficulty > MaxDifficulty {
+		return fmt.Errorf("pow difficulty must be between 0 and %d bits", MaxDifficulty)
+	}
+	if difficulty > 0 && len(secret) < minSecretBytes {
+		return fmt.Errorf("pow secret must be at least %d bytes", minSecretBytes)
+	}
+
+	return nil
+}
+
+func NewChallenge(secret []byte, ttl time.Duration, now time.Time) (string, string, error) {
+	seed := make([]byte, 16)
+	if _, err := rand.Read(seed); err != nil {
+		return "", "", err
+	}
+
+	challenge := hex.EncodeToString(seed) + "." + strconv.FormatInt(now.Add(ttl).Unix(), 10)
+	return challenge, Sign(secret, challenge), nil
+}
+
+func Sign(secret []byte, challenge string) string {
+	return hex.EncodeToString(signature(secret, challenge))
+}
+
+func Verify(secret []byte, s Solution, difficulty int, now time.Time) (Challenge, error) {
+	if s.Challenge == "" || s.Signature == "" || s.Nonce == "" {
+		return Challenge{}, ErrMissingSolution
+	}
+
+	sig, err := hex.DecodeString(s.Signature)
+	if err != nil || !hmac.Equal(sig, signature(secret, s.Challenge)) {
+		return Challenge{}, ErrInvalidSignature
+	}
+
+	seed, expires, ok := strings.Cut(s.Challenge, ".")
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if !ok || seed == "" || err != nil || len(s.Nonce) > maxNonceLength {
+		return Challenge{}, ErrMalformedChallenge
+	}
+	challenge := Challenge{Seed: seed, ExpiresAt: time.Unix(expiresAt, 0)}
+	if !now.Before(challenge.ExpiresAt) {
+		return Challenge{}, ErrChallengeExpired
+	}
+	if LeadingZeroBits(Work(s.Challenge, s.Nonce)) < difficulty {
+		return Challenge{}, ErrInsufficientWork
+	}
+
+	return challenge, nil
+}
+
+func Work(challenge, nonce string) []byte {
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+	return sum[:]
+}
+
+func Solve(challenge string, difficulty int) string {
+	for i := 0; ; i++ {
+		nonce := strconv.Itoa(i)
+		if LeadingZeroBits(Work(challenge, nonce)) >= difficulty {
+			return nonce
+		}
+	}
+}
+
+func LeadingZeroBits(sum []byte) int {
+	n := 0
+	for _, b := range sum {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+
+	return n
+}
+
+func ReplayKey(seed string) string {
+	return "pow_challenge:" + seed
+}
+
+func signature(secret []byte, challenge string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(challenge))
+	return mac.Sum(nil)
+}
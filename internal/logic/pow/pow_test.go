@@ -0,0 +1,82 @@
+package pow
+
+import (
+	"testing"
+	"time"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	challenge, signature, err := NewChallenge(secret, time.Minute, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := Solve(challenge, 8)
+	weak := "weak"
+	for LeadingZeroBits(Work(challenge, weak)) >= 8 {
+		weak += "!"
+	}
+
+	tests := []struct {
+		name     string
+		solution Solution
+		now      time.Time
+		wantErr  error
+	}{
+		{name: "valid", solution: Solution{challenge, signature, nonce}, now: now},
+		{name: "missing", solution: Solution{}, now: now, wantErr: ErrMissingSolution},
+		{name: "tampered challenge", solution: Solution{challenge + "0", signature, nonce}, now: now, wantErr: ErrInvalidSignature},
+		{name: "bad signature encoding", solution: Solution{challenge, "zz", nonce}, now: now, wantErr: ErrInvalidSignature},
+		{name: "other secret", solution: Solution{challenge, Sign([]byte("another-secret-another-secret-xx"), challenge), nonce}, now: now, wantErr: ErrInvalidSignature},
+		{name: "malformed but signed", solution: Solution{"seed", Sign(secret, "seed"), nonce}, now: now, wantErr: ErrMalformedChallenge},
+		{name: "expired", solution: Solution{challenge, signature, nonce}, now: now.Add(time.Minute), wantErr: ErrChallengeExpired},
+		{name: "insufficient work", solution: Solution{challenge, signature, weak}, now: now, wantErr: ErrInsufficientWork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Verify(secret, tt.solution, 8, tt.now)
+			if err != tt.wantErr {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Seed == "" || !got.ExpiresAt.Equal(now.Add(time.Minute))) {
+				t.Errorf("Verify() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestLeadingZeroBits(t *testing.T) {
+	tests := []struct {
+		sum  []byte
+		want int
+	}{
+		{sum: []byte{0x80}, want: 0},
+		{sum: []byte{0x01}, want: 7},
+		{sum: []byte{0x00, 0x20}, want: 10},
+		{sum: []byte{0x00, 0x00}, want: 16},
+	}
+
+	for _, tt := range tests {
+		if got := LeadingZeroBits(tt.sum); got != tt.want {
+			t.Errorf("LeadingZeroBits(%x) = %d, want %d", tt.sum, got, tt.want)
+		}
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	if err := ValidateConfig(nil, 0); err != nil {
+		t.Errorf("disabled pow must be valid, got %v", err)
+	}
+	if err := ValidateConfig(secret, 20); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateConfig([]byte("short"), 20); err == nil {
+		t.Error("expected error for short secret")
+	}
+	if err := ValidateConfig(secret, MaxDifficulty+1); err == nil {
+		t.Error("expected error for excessive difficulty")
+	}
+}
@@ -0,0 +1,30 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+type Limit struct {
+	Max    int
+	Window time.Duration
+}
+
+type Bucket struct {
+	Key   string
+	Limit Limit
+}
+
+func (l Limit) Enabled() bool {
+	return l.Max > 0 && l.Window > 0
+}
+
+func Key(scope, name, value string) string {
+	if value == "" {
+		return "ratelimit:" + scope + ":" + name
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	return "ratelimit:" + scope + ":" + name + ":" + hex.EncodeToString(sum[:16])
+}
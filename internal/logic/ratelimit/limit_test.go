@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLimit_Enabled(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit Limit
+		want  bool
+	}{
+		{name: "enabled", limit: Limit{Max: 5, Window: time.Minute}, want: true},
+		{name: "zero max", limit: Limit{Window: time.Minute}, want: false},
+		{name: "zero window", limit: Limit{Max: 5}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limit.Enabled(); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	if got := Key("cv", "global", ""); got != "ratelimit:cv:global" {
+		t.Errorf("Key() = %s, want ratelimit:cv:global", got)
+	}
+
+	ip := Key("cv", "ip", "203.0.113.7")
+	if !strings.HasPrefix(ip, "ratelimit:cv:ip:") || strings.Contains(ip, "203.0.113.7") {
+		t.Errorf("Key() = %s, want a hashed value", ip)
+	}
+	if ip != Key("cv", "ip", "203.0.113.7") {
+		t.Error("Key() is not deterministic")
+	}
+	if ip == Key("cv", "ip", "203.0.113.8") {
+		t.Error("Key() collides for different values")
+	}
+}
@@ -0,0 +1,68 @@
+package translation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatXLIFF Format = "xliff"
+	FormatPO    Format = "po"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported translation format")
+
+type Unit struct {
+	Path   string
+	Source string
+	Target string
+	Fuzzy  bool
+}
+
+type Catalog struct {
+	SourceLang string
+	TargetLang string
+	Units      []Unit
+}
+
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "xliff", "xlf":
+		return FormatXLIFF, nil
+	case "po":
+		return FormatPO, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, s)
+	}
+}
+
+func FormatFromPath(path string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+func Encode(w io.Writer, c *Catalog, f Format) error {
+	switch f {
+	case FormatXLIFF:
+		return encodeXLIFF(w, c)
+	case FormatPO:
+		return encodePO(w, c)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, f)
+	}
+}
+
+func Decode(r io.Reader, f Format) (*Catalog, error) {
+	switch f {
+	case FormatXLIFF:
+		return decodeXLIFF(r)
+	case FormatPO:
+		return decodePO(r)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, f)
+	}
+}
@@ -0,0 +1,110 @@
+package translation
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	catalog := &Catalog{
+		SourceLang: "pl",
+		TargetLang: "en",
+		Units: []Unit{
+			{Path: "profile.headline", Source: "Programista", Target: "Developer"},
+			{Path: "profile.about", Source: "Linia \"1\"\n\nLinia 2", Target: "Line \"1\"\n\nLine 2"},
+			{Path: "profile.tags.0", Source: "Mikroserwisy <&>", Target: ""},
+			{Path: "translations.btn_back", Source: "Wróć", Target: "Back", Fuzzy: true},
+		},
+	}
+
+	for _, format := range []Format{FormatXLIFF, FormatPO} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, catalog, format); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			got, err := Decode(&buf, format)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, catalog) {
+				t.Errorf("Decode() got = %+v, want %+v", got, catalog)
+			}
+		})
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+	}{
+		{
+			name:   "xliff 1.2",
+			format: FormatXLIFF,
+			input:  `<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2"><file></file></xliff>`,
+		},
+		{
+			name:   "xliff unit without id",
+			format: FormatXLIFF,
+			input:  `<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0"><file><unit><segment><source>a</source></segment></unit></file></xliff>`,
+		},
+		{
+			name:   "po plural forms",
+			format: FormatPO,
+			input:  "msgctxt \"a\"\nmsgid \"a\"\nmsgid_plural \"as\"\nmsgstr[0] \"b\"\n",
+		},
+		{
+			name:   "po broken literal",
+			format: FormatPO,
+			input:  "msgctxt \"a\"\nmsgid \"a\nmsgstr \"b\"\n",
+		},
+		{
+			name:   "po entry without path",
+			format: FormatPO,
+			input:  "msgid \"a\"\nmsgstr \"b\"\n",
+		},
+		{
+			name:   "unsupported format",
+			format: Format("csv"),
+			input:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(strings.NewReader(tt.input), tt.format); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    Format
+		wantErr bool
+	}{
+		{path: "en.xlf", want: FormatXLIFF},
+		{path: "en.XLIFF", want: FormatXLIFF},
+		{path: "/tmp/en.po", want: FormatPO},
+		{path: "en.json", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := FormatFromPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatFromPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FormatFromPath() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
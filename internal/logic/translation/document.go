@@ -0,0 +1,393 @@
+package translation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var (
+	ErrTrailingData     = errors.New("unexpected data after top-level JSON value")
+	ErrUntranslatedItem = errors.New("untranslated string inside a new list")
+)
+
+type Entry struct {
+	Path  string
+	Value string
+}
+
+// Flatten lists the translatable strings of a document; asset references and experiment names and variant ids are left out.
+func Flatten(doc []byte) ([]Entry, error) {
+	n, err := parse(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	flatten(n, "", metadata(n), &entries)
+
+	return entries, nil
+}
+
+func flatten(n *node, path string, meta map[*node]bool, entries *[]Entry) {
+	switch {
+	case n.str != nil:
+		if !meta[n] {
+			*entries = append(*entries, Entry{Path: path, Value: *n.str})
+		}
+	case n.isObject():
+		for _, key := range n.keys {
+			flatten(n.fields[key], joinPath(path, key), meta, entries)
+		}
+	case n.isArray():
+		for i, item := range n.items {
+			flatten(item, joinPath(path, strconv.Itoa(i)), meta, entries)
+		}
+	}
+}
+
+// metadata marks the strings that identify content rather than being text: asset references and the names and variant ids of experiment blocks.
+func metadata(roots ...*node) map[*node]bool {
+	meta := map[*node]bool{}
+	for _, root := range roots {
+		markMetadata(root, meta)
+	}
+
+	return meta
+}
+
+func markMetadata(n *node, meta map[*node]bool) {
+	mark := func(n *node) {
+		if n != nil && n.str != nil {
+			meta[n] = true
+		}
+	}
+
+	switch {
+	case n.isObject():
+		mark(n.fields["asset"])
+		if variants, ok := n.fields["variants"]; ok {
+			mark(n.fields["experiment"])
+			for _, item := range variants.items {
+				if item.isObject() {
+					mark(item.fields["id"])
+				}
+			}
+		}
+		for _, key := range n.keys {
+			markMetadata(n.fields[key], meta)
+		}
+	case n.isArray():
+		for _, item := range n.items {
+			markMetadata(item, meta)
+		}
+	}
+}
+
+func writeString(out *bytes.Buffer, s string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	out.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+
+	return nil
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+type node struct {
+	keys   []string
+	fields map[string]*node
+	items  []*node
+	array  bool
+	str    *string
+	raw    string
+}
+
+func (n *node) isObject() bool {
+	return n != nil && n.fields != nil
+}
+
+func (n *node) isArray() bool {
+	return n != nil && n.array
+}
+
+// Merge writes values into the target document, keeping its structure, order and untranslated strings.
+// Source paths missing from the target are added only when they hold a translated string; source text is never copied,
+// while asset references and experiment metadata are copied as they are.
+func Merge(target, source []byte, values map[string]string) ([]byte, error) {
+	t, err := parse(target)
+	if err != nil {
+		return nil, err
+	}
+	s, err := parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := mergeNode(t, s, "", values, metadata(t, s))
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := encodeNode(&out, merged); err != nil {
+		return nil, err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, out.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+
+	return indented.Bytes(), nil
+}
+
+func mergeNode(t, s *node, path string, values map[string]string, meta map[*node]bool) (*node, error) {
+	switch {
+	case t.str != nil:
+		if v, ok := values[path]; ok && !meta[t] {
+			return &node{str: &v}, nil
+		}
+		return t, nil
+	case t.isObject():
+		merged := &node{keys: t.keys, fields: make(map[string]*node, len(t.fields))}
+		for _, key := range t.keys {
+			var child *node
+			if s.isObject() {
+				child = s.fields[key]
+			}
+			n, err := mergeNode(t.fields[key], child, joinPath(path, key), values, meta)
+			if err != nil {
+				return nil, err
+			}
+			merged.fields[key] = n
+		}
+		if s.isObject() {
+			for _, key := range s.keys {
+				if _, ok := t.fields[key]; ok {
+					continue
+				}
+				n, keep, err := buildNode(s.fields[key], joinPath(path, key), values, meta)
+				if err != nil {
+					return nil, err
+				}
+				if keep {
+					merged.keys = append(merged.keys, key)
+					merged.fields[key] = n
+				}
+			}
+		}
+		return merged, nil
+	case t.isArray():
+		merged := &node{array: true}
+		for i, item := range t.items {
+			var child *node
+			if s.isArray() && i < len(s.items) {
+				child = s.items[i]
+			}
+			n, err := mergeNode(item, child, joinPath(path, strconv.Itoa(i)), values, meta)
+			if err != nil {
+				return nil, err
+			}
+			merged.items = append(merged.items, n)
+		}
+		if s.isArray() && len(s.items) > len(t.items) {
+			tail, _, _, err := buildItems(s.items, len(t.items), path, values, meta)
+			if err != nil {
+				return nil, err
+			}
+			merged.items = append(merged.items, tail...)
+		}
+		return merged, nil
+	}
+
+	return t, nil
+}
+
+// buildNode copies a source-only subtree with its translated strings; keep is false when it holds text but none of it is translated.
+func buildNode(s *node, path string, values map[string]string, meta map[*node]bool) (*node, bool, error) {
+	n, translated, text, err := build(s, path, values, meta)
+	return n, translated || !text, err
+}
+
+func build(s *node, path string, values map[string]string, meta map[*node]bool) (n *node, translated, text bool, err error) {
+	switch {
+	case meta[s]:
+		return s, false, false, nil
+	case s.str != nil:
+		if v, ok := values[path]; ok {
+			return &node{str: &v}, true, true, nil
+		}
+		return nil, false, true, nil
+	case s.isObject():
+		n = &node{fields: make(map[string]*node, len(s.fields))}
+		for _, key := range s.keys {
+			child, childTranslated, childText, err := build(s.fields[key], joinPath(path, key), values, meta)
+			if err != nil {
+				return nil, false, false, err
+			}
+			text = text || childText
+			if childTranslated || !childText {
+				translated = translated || childTranslated
+				n.keys = append(n.keys, key)
+				n.fields[key] = child
+			}
+		}
+		return n, translated, text, nil
+	case s.isArray():
+		items, translated, text, err := buildItems(s.items, 0, path, values, meta)
+		if err != nil {
+			return nil, false, false, err
+		}
+		return &node{array: true, items: items}, translated, text, nil
+	}
+
+	return s, false, false, nil
+}
+
+// buildItems copies source array items from index start up to the last one worth keeping, so indices stay aligned with the source.
+func buildItems(items []*node, start int, path string, values map[string]string, meta map[*node]bool) (built []*node, translated, text bool, err error) {
+	last := -1
+	for i := start; i < len(items); i++ {
+		n, itemTranslated, itemText, err := build(items[i], joinPath(path, strconv.Itoa(i)), values, meta)
+		if err != nil {
+			return nil, false, false, err
+		}
+		built = append(built, n)
+		translated = translated || itemTranslated
+		text = text || itemText
+		if itemTranslated || !itemText {
+			last = len(built) - 1
+		}
+	}
+	if text && !translated {
+		return nil, false, true, nil
+	}
+
+	built = built[:last+1]
+	for i, n := range built {
+		if n == nil {
+			return nil, false, false, fmt.Errorf("%w: %s", ErrUntranslatedItem, joinPath(path, strconv.Itoa(start+i)))
+		}
+	}
+
+	return built, translated, text, nil
+}
+
+func parse(doc []byte) (*node, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	n, err := parseNode(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, ErrTrailingData
+	}
+
+	return n, nil
+}
+
+func parseNode(dec *json.Decoder) (*node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		n := &node{}
+		switch v {
+		case '{':
+			n.fields = map[string]*node{}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, ok := keyTok.(string)
+				if !ok {
+					return nil, fmt.Errorf("unexpected object key %v", keyTok)
+				}
+				child, err := parseNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				if _, ok := n.fields[key]; !ok {
+					n.keys = append(n.keys, key)
+				}
+				n.fields[key] = child
+			}
+		case '[':
+			n.array = true
+			for dec.More() {
+				child, err := parseNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.items = append(n.items, child)
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case string:
+		return &node{str: &v}, nil
+	case json.Number:
+		return &node{raw: v.String()}, nil
+	case bool:
+		return &node{raw: strconv.FormatBool(v)}, nil
+	}
+
+	return &node{raw: "null"}, nil
+}
+
+func encodeNode(out *bytes.Buffer, n *node) error {
+	switch {
+	case n.str != nil:
+		return writeString(out, *n.str)
+	case n.isObject():
+		out.WriteByte('{')
+		for i, key := range n.keys {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			if err := writeString(out, key); err != nil {
+				return err
+			}
+			out.WriteByte(':')
+			if err := encodeNode(out, n.fields[key]); err != nil {
+				return err
+			}
+		}
+		out.WriteByte('}')
+	case n.isArray():
+		out.WriteByte('[')
+		for i, item := range n.items {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			if err := encodeNode(out, item); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+	default:
+		out.WriteString(n.raw)
+	}
+
+	return nil
+}
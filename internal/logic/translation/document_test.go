@@ -0,0 +1,151 @@
+package translation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const testDocument = `{
+  "profile": {
+    "headline": "Backend Developer",
+    "tags": [
+      "Go",
+      "<PHP> & co"
+    ],
+    "years": 7,
+    "remote": true,
+    "photo": null
+  },
+  "empty": []
+}`
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		want    []Entry
+		wantErr bool
+	}{
+		{
+			name: "string leaves in document order",
+			doc:  testDocument,
+			want: []Entry{
+				{Path: "profile.headline", Value: "Backend Developer"},
+				{Path: "profile.tags.0", Value: "Go"},
+				{Path: "profile.tags.1", Value: "<PHP> & co"},
+			},
+		},
+		{
+			name: "experiment metadata and asset references skipped",
+			doc:  `{"headline":{"experiment":"headline-2026","variants":[{"id":"control","weight":50,"value":"Developer"},{"id":"go","weight":50,"value":"Go Developer"}]},"photo":{"asset":"profile.jpg","alt":"Photo"},"id":"kept"}`,
+			want: []Entry{
+				{Path: "headline.variants.0.value", Value: "Developer"},
+				{Path: "headline.variants.1.value", Value: "Go Developer"},
+				{Path: "photo.alt", Value: "Photo"},
+				{Path: "id", Value: "kept"},
+			},
+		},
+		{
+			name:    "invalid json",
+			doc:     `{"a":`,
+			wantErr: true,
+		},
+		{
+			name:    "trailing data",
+			doc:     `{"a":"b"} {}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Flatten([]byte(tt.doc))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Flatten() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Flatten() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		source  string
+		values  map[string]string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "no changes keeps the target",
+			target: testDocument,
+			source: testDocument,
+			want:   testDocument,
+		},
+		{
+			name:   "replaces listed paths",
+			target: `{"a":{"b":"x","c":["y"]}}`,
+			source: `{"a":{"b":"x","c":["y"]}}`,
+			values: map[string]string{"a.c.0": "z", "missing": "q"},
+			want:   `{"a":{"b":"x","c":["z"]}}`,
+		},
+		{
+			name:   "keeps target-only structure",
+			target: `{"a":"one","extra":{"n":1,"s":"en only"}}`,
+			source: `{"a":"jeden"}`,
+			values: map[string]string{"a": "uno"},
+			want:   `{"a":"uno","extra":{"n":1,"s":"en only"}}`,
+		},
+		{
+			name:   "adds translated source paths without source text",
+			target: `{"a":"one","list":[{"t":"x"}]}`,
+			source: `{"a":"jeden","b":{"t":"dwa","u":"trzy","n":2},"c":"cztery","list":[{"t":"iks"},{"t":"igrek","y":2020}]}`,
+			values: map[string]string{"b.t": "two", "list.1.t": "why"},
+			want:   `{"a":"one","list":[{"t":"x"},{"t":"why","y":2020}],"b":{"t":"two","n":2}}`,
+		},
+		{
+			name:   "adds experiment blocks and asset references with their metadata",
+			target: `{"a":"one"}`,
+			source: `{"a":"jeden","headline":{"experiment":"headline-2026","variants":[{"id":"control","weight":50,"value":"Programista"},{"id":"go","weight":50,"value":"Programista Go"}]},"photo":{"asset":"profile.jpg","alt":"Zdjęcie"},"logo":{"asset":"logo.png","alt":"Logo"}}`,
+			values: map[string]string{"headline.variants.0.value": "Developer", "headline.variants.1.value": "Go Developer", "photo.alt": "Photo"},
+			want:   `{"a":"one","headline":{"experiment":"headline-2026","variants":[{"id":"control","weight":50,"value":"Developer"},{"id":"go","weight":50,"value":"Go Developer"}]},"photo":{"asset":"profile.jpg","alt":"Photo"}}`,
+		},
+		{
+			name:   "keeps target metadata",
+			target: `{"photo":{"asset":"profile.jpg","alt":"Photo"}}`,
+			source: `{"photo":{"asset":"profile.jpg","alt":"Zdjęcie"}}`,
+			values: map[string]string{"photo.asset": "other.jpg", "photo.alt": "Portrait"},
+			want:   `{"photo":{"asset":"profile.jpg","alt":"Portrait"}}`,
+		},
+		{
+			name:    "untranslated string inside a new list",
+			target:  `{"list":[]}`,
+			source:  `{"list":["a","b"]}`,
+			values:  map[string]string{"list.1": "B"},
+			wantErr: ErrUntranslatedItem,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Merge([]byte(tt.target), []byte(tt.source), tt.values)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Merge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var want bytes.Buffer
+			json.Indent(&want, []byte(tt.want), "", "  ")
+			if string(got) != want.String() {
+				t.Errorf("Merge() got = %s, want %s", got, want.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package translation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type poEntry struct {
+	reference string
+	context   string
+	id        string
+	str       string
+	fuzzy     bool
+	hasID     bool
+}
+
+func encodePO(w io.Writer, c *Catalog) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, `msgid ""`)
+	fmt.Fprintln(bw, `msgstr ""`)
+	fmt.Fprintln(bw, `"Content-Type: text/plain; charset=UTF-8\n"`)
+	fmt.Fprintf(bw, "\"Language: %s\\n\"\n", c.TargetLang)
+	fmt.Fprintf(bw, "\"X-Source-Language: %s\\n\"\n", c.SourceLang)
+
+	for _, u := range c.Units {
+		fmt.Fprintln(bw)
+		fmt.Fprintf(bw, "#: %s\n", u.Path)
+		if u.Fuzzy {
+			fmt.Fprintln(bw, "#, fuzzy")
+		}
+		fmt.Fprintf(bw, "msgctxt %s\n", poQuote(u.Path))
+		fmt.Fprintf(bw, "msgid %s\n", poQuote(u.Source))
+		fmt.Fprintf(bw, "msgstr %s\n", poQuote(u.Target))
+	}
+
+	return bw.Flush()
+}
+
+func poQuote(s string) string {
+	lines := strings.SplitAfter(s, "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) <= 1 {
+		return poEscape(s)
+	}
+
+	var b strings.Builder
+	b.WriteString(`""`)
+	for _, l := range lines {
+		b.WriteString("\n")
+		b.WriteString(poEscape(l))
+	}
+
+	return b.String()
+}
+
+func poEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func decodePO(r io.Reader) (*Catalog, error) {
+	c := &Catalog{}
+	var entries []poEntry
+	cur := poEntry{}
+	var field *string
+	lineNo := 0
+
+	flush := func() {
+		if cur.hasID {
+			entries = append(entries, cur)
+		}
+		cur = poEntry{}
+		field = nil
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "#,"):
+			if cur.hasID {
+				flush()
+			}
+			for _, flag := range strings.Split(line[2:], ",") {
+				if strings.TrimSpace(flag) == "fuzzy" {
+					cur.fuzzy = true
+				}
+			}
+		case strings.HasPrefix(line, "#:"):
+			if cur.hasID {
+				flush()
+			}
+			cur.reference = strings.TrimSpace(line[2:])
+		case strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "msgctxt "):
+			if cur.hasID {
+				flush()
+			}
+			field = &cur.context
+		case strings.HasPrefix(line, "msgid_plural "), strings.HasPrefix(line, "msgstr["):
+			return nil, fmt.Errorf("line %d: plural forms are not supported", lineNo)
+		case strings.HasPrefix(line, "msgid "):
+			if cur.hasID {
+				flush()
+			}
+			cur.hasID = true
+			field = &cur.id
+		case strings.HasPrefix(line, "msgstr "):
+			field = &cur.str
+		case strings.HasPrefix(line, `"`):
+			if field == nil {
+				return nil, fmt.Errorf("line %d: unexpected string continuation", lineNo)
+			}
+		default:
+			return nil, fmt.Errorf("line %d: unrecognized syntax", lineNo)
+		}
+
+		if field != nil && !strings.HasPrefix(line, "#") && line != "" {
+			quoted := line
+			if i := strings.IndexByte(line, ' '); i >= 0 && !strings.HasPrefix(line, `"`) {
+				quoted = strings.TrimSpace(line[i+1:])
+			}
+			s, err := strconv.Unquote(quoted)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid string literal: %w", lineNo, err)
+			}
+			*field += s
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	for _, e := range entries {
+		if e.id == "" && e.context == "" {
+			parsePOHeader(c, e.str)
+			continue
+		}
+		path := e.context
+		if path == "" {
+			path = e.reference
+		}
+		if path == "" {
+			return nil, fmt.Errorf("entry %q has no msgctxt or reference", e.id)
+		}
+		c.Units = append(c.Units, Unit{Path: path, Source: e.id, Target: e.str, Fuzzy: e.fuzzy})
+	}
+
+	return c, nil
+}
+
+func parsePOHeader(c *Catalog, header string) {
+	for _, line := range strings.Split(header, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Language":
+			c.TargetLang = strings.TrimSpace(value)
+		case "X-Source-Language":
+			c.SourceLang = strings.TrimSpace(value)
+		}
+	}
+}
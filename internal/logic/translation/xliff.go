@@ -0,0 +1,95 @@
+package translation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+const xliffNamespace = "urn:oasis:names:tc:xliff:document:2.0"
+
+type xliffDocument struct {
+	XMLName xml.Name  `xml:"urn:oasis:names:tc:xliff:document:2.0 xliff"`
+	Version string    `xml:"version,attr"`
+	SrcLang string    `xml:"srcLang,attr"`
+	TrgLang string    `xml:"trgLang,attr,omitempty"`
+	File    xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	ID    string      `xml:"id,attr"`
+	Units []xliffUnit `xml:"unit"`
+}
+
+type xliffUnit struct {
+	ID      string       `xml:"id,attr"`
+	Segment xliffSegment `xml:"segment"`
+}
+
+type xliffSegment struct {
+	State  string  `xml:"state,attr,omitempty"`
+	Source string  `xml:"source"`
+	Target *string `xml:"target"`
+}
+
+func encodeXLIFF(w io.Writer, c *Catalog) error {
+	doc := xliffDocument{
+		Version: "2.0",
+		SrcLang: c.SourceLang,
+		TrgLang: c.TargetLang,
+		File:    xliffFile{ID: "content"},
+	}
+
+	for _, u := range c.Units {
+		target := u.Target
+		state := "translated"
+		if target == "" || u.Fuzzy {
+			state = "initial"
+		}
+		doc.File.Units = append(doc.File.Units, xliffUnit{
+			ID:      u.Path,
+			Segment: xliffSegment{State: state, Source: u.Source, Target: &target},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+func decodeXLIFF(r io.Reader) (*Catalog, error) {
+	var doc xliffDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("could not parse XLIFF: %w", err)
+	}
+	if doc.XMLName.Space != xliffNamespace || doc.Version != "2.0" {
+		return nil, fmt.Errorf("%w: expected XLIFF 2.0 document", ErrUnsupportedFormat)
+	}
+
+	c := &Catalog{SourceLang: doc.SrcLang, TargetLang: doc.TrgLang}
+	for _, u := range doc.File.Units {
+		if u.ID == "" {
+			return nil, fmt.Errorf("XLIFF unit without id")
+		}
+		var target string
+		if u.Segment.Target != nil {
+			target = *u.Segment.Target
+		}
+		c.Units = append(c.Units, Unit{
+			Path:   u.ID,
+			Source: u.Segment.Source,
+			Target: target,
+			Fuzzy:  target != "" && u.Segment.State == "initial",
+		})
+	}
+
+	return c, nil
+}
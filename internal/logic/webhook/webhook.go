@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+)
+
+const (
+	FormatJSON  = "json"
+	FormatSlack = "slack"
+
+	IDHeader        = "X-Webhook-ID"
+	EventHeader     = "X-Webhook-Event"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+var events = []audit.EventType{
+	audit.EventTokenIssued,
+	audit.EventTokenRedeemed,
+	audit.EventClientLocked,
+	audit.EventTriesExhausted,
+	audit.EventAccessRequested,
+}
+
+type Endpoint struct {
+	Name   string
+	URL    string
+	Format string
+	Secret []byte
+	Events []audit.EventType
+}
+
+type Delivery struct {
+	Endpoint string      `json:"endpoint"`
+	Event    audit.Event `json:"event"`
+}
+
+func Supported(t audit.EventType) bool {
+	for _, e := range events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Endpoint) Subscribed(t audit.EventType) bool {
+	if !Supported(t) {
+		return false
+	}
+	if len(e.Events) == 0 {
+		return true
+	}
+
+	for _, s := range e.Events {
+		if s == t {
+			return true
+		}
+	}
+	return false
+}
+
+func Validate(endpoints []Endpoint) error {
+	names := make(map[string]bool, len(endpoints))
+	for _, e := range endpoints {
+		if e.Name == "" {
+			return fmt.Errorf("webhook endpoint without a name")
+		}
+		if names[e.Name] {
+			return fmt.Errorf("webhook endpoint %s is declared twice", e.Name)
+		}
+		names[e.Name] = true
+
+		u, err := url.Parse(e.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhook endpoint %s requires an absolute http(s) url", e.Name)
+		}
+		switch e.Format {
+		case FormatJSON:
+			if len(e.Secret) == 0 {
+				return fmt.Errorf("webhook endpoint %s requires a secret", e.Name)
+			}
+		case FormatSlack:
+		default:
+			return fmt.Errorf("webhook endpoint %s has unsupported format %q", e.Name, e.Format)
+		}
+		for _, t := range e.Events {
+			if !Supported(t) {
+				return fmt.Errorf("webhook endpoint %s subscribes to unsupported event %q", e.Name, t)
+			}
+		}
+	}
+
+	return nil
+}
+
+func Render(format string, event audit.Event) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.Marshal(event)
+	case FormatSlack:
+		return json.Marshal(map[string]string{"text": Summary(event)})
+	default:
+		return nil, fmt.Errorf("unsupported webhook format %q", format)
+	}
+}
+
+func Summary(event audit.Event) string {
+	prefix := "[" + event.Site + "] "
+	switch event.Type {
+	case audit.EventTokenIssued:
+		return prefix + fmt.Sprintf("CV token issued via credential %s (lang %s), valid until %s", event.Credential, event.Lang, formatTime(event.ExpiresAt))
+	case audit.EventTokenRedeemed:
+		return prefix + fmt.Sprintf("CV downloaded via credential %s (lang %s, document %s, stamp %s)", event.Credential, event.Lang, event.Document, orNone(event.Detail))
+	case audit.EventClientLocked:
+		return prefix + fmt.Sprintf("Client %s locked out until %s (level %s)", short(event.Client), formatTime(event.ExpiresAt), event.Detail)
+	case audit.EventTriesExhausted:
+		return prefix + fmt.Sprintf("Captcha %s ran out of password tries (lang %s, client %s)", event.CaptchaID, event.Lang, short(event.Client))
+	case audit.EventAccessRequested:
+		return prefix + fmt.Sprintf("CV access request %s awaits approval (lang %s), expires %s", event.Detail, event.Lang, formatTime(event.ExpiresAt))
+	default:
+		return prefix + string(event.Type)
+	}
+}
+
+func Sign(secret []byte, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+func short(s string) string {
+	if len(s) > 12 {
+		return s[:12]
+	}
+	return orNone(s)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
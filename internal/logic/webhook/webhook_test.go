@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+)
+
+var issued = audit.Event{
+	ID:         "e1",
+	Type:       audit.EventTokenIssued,
+	Site:       "main",
+	Lang:       "pl",
+	Credential: "acme",
+	Token:      "5e88",
+	ExpiresAt:  time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC),
+}
+
+func TestValidate(t *testing.T) {
+	valid := Endpoint{Name: "owner", URL: "https://example.com/hooks", Format: FormatJSON, Secret: []byte("s")}
+	slack := Endpoint{Name: "slack", URL: "https://hooks.slack.com/services/T/B/X", Format: FormatSlack}
+
+	tests := []struct {
+		name      string
+		endpoints []Endpoint
+		wantErr   bool
+	}{
+		{
+			name:      "json and slack",
+			endpoints: []Endpoint{valid, slack},
+		},
+		{
+			name:      "duplicate name",
+			endpoints: []Endpoint{valid, valid},
+			wantErr:   true,
+		},
+		{
+			name:      "json without a secret",
+			endpoints: []Endpoint{{Name: "owner", URL: "https://example.com/hooks", Format: FormatJSON}},
+			wantErr:   true,
+		},
+		{
+			name:      "relative url",
+			endpoints: []Endpoint{{Name: "slack", URL: "/hooks", Format: FormatSlack}},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown format",
+			endpoints: []Endpoint{{Name: "teams", URL: "https://example.com/hooks", Format: "teams", Secret: []byte("s")}},
+			wantErr:   true,
+		},
+		{
+			name:      "unsupported event",
+			endpoints: []Endpoint{{Name: "slack", URL: "https://example.com/hooks", Format: FormatSlack, Events: []audit.EventType{audit.EventTokenRevoked}}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.endpoints); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEndpoint_Subscribed(t *testing.T) {
+	all := Endpoint{}
+	downloads := Endpoint{Events: []audit.EventType{audit.EventTokenRedeemed}}
+
+	if !all.Subscribed(audit.EventClientLocked) || !all.Subscribed(audit.EventAccessRequested) || all.Subscribed(audit.EventCaptchaVerified) {
+		t.Error("endpoint without events must receive every supported event")
+	}
+	if !downloads.Subscribed(audit.EventTokenRedeemed) || downloads.Subscribed(audit.EventTokenIssued) {
+		t.Error("endpoint must only receive its events")
+	}
+}
+
+func TestRender(t *testing.T) {
+	body, err := Render(FormatJSON, issued)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded audit.Event
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded != issued {
+		t.Errorf("Render(json) = %s", body)
+	}
+
+	body, err = Render(FormatSlack, issued)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"text":"[main] CV token issued via credential acme (lang pl), valid until 2026-03-01 13:00 UTC"}`; string(body) != want {
+		t.Errorf("Render(slack) = %s, want %s", body, want)
+	}
+}
+
+func TestSign(t *testing.T) {
+	got := Sign([]byte("secret"), time.Unix(1772370000, 0), []byte(`{"id":"e1"}`))
+	if want := "sha256=26be95956b069d3b45af02be17339c4b4b2481e0aae300661dc213f521854a6b"; got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+	if Sign([]byte("other"), time.Unix(1772370000, 0), []byte(`{"id":"e1"}`)) == got {
+		t.Error("Sign() must depend on the secret")
+	}
+	if Sign([]byte("secret"), time.Unix(1772370001, 0), []byte(`{"id":"e1"}`)) == got {
+		t.Error("Sign() must depend on the timestamp")
+	}
+}
@@ -0,0 +1,46 @@
+package deliver_contact
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/contact"
+	serviceSmtp "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/smtp"
+)
+
+type Sender interface {
+	Send(ctx context.Context, msg serviceSmtp.Message) error
+}
+
+type Process struct {
+	sender     Sender
+	recipients []string
+}
+
+func NewProcess(sender Sender, recipients []string) *Process {
+	return &Process{
+		sender:     sender,
+		recipients: recipients,
+	}
+}
+
+func (p *Process) Process(ctx context.Context, submission contact.Submission) error {
+	msg := submission.Message
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Name: %s\n", msg.Name)
+	fmt.Fprintf(&body, "Email: %s\n", msg.Email)
+	fmt.Fprintf(&body, "Site: %s\n", submission.Site)
+	fmt.Fprintf(&body, "Received: %s\n\n", submission.ReceivedAt.Format(time.RFC3339))
+	body.WriteString(msg.Body)
+	body.WriteString("\n")
+
+	return p.sender.Send(ctx, serviceSmtp.Message{
+		To:      p.recipients,
+		ReplyTo: msg.Email,
+		Subject: fmt.Sprintf("[%s] Contact form: %s", submission.Site, msg.Name),
+		Body:    body.String(),
+	})
+}
@@ -0,0 +1,54 @@
+package deliver_contact
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/contact"
+	serviceSmtp "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/smtp"
+)
+
+type mockSender struct {
+	sent []serviceSmtp.Message
+	err  error
+}
+
+func (m *mockSender) Send(ctx context.Context, msg serviceSmtp.Message) error {
+	m.sent = append(m.sent, msg)
+	return m.err
+}
+
+func TestProcess_DeliverContact(t *testing.T) {
+	submission := contact.Submission{
+		Site:       "main",
+		Message:    contact.Message{Name: "Jan", Email: "jan@example.com", Body: "Hello there"},
+		ReceivedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		sender := &mockSender{}
+		p := NewProcess(sender, []string{"owner@example.com"})
+
+		if err := p.Process(context.Background(), submission); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+
+		msg := sender.sent[0]
+		if msg.ReplyTo != "jan@example.com" || msg.Subject != "[main] Contact form: Jan" || msg.To[0] != "owner@example.com" {
+			t.Errorf("Process() sent = %+v", msg)
+		}
+		if !strings.Contains(msg.Body, "Received: 2026-03-01T12:00:00Z") || !strings.HasSuffix(msg.Body, "Hello there\n") {
+			t.Errorf("Process() body = %q", msg.Body)
+		}
+	})
+
+	t.Run("sender error", func(t *testing.T) {
+		p := NewProcess(&mockSender{err: errors.New("smtp down")}, []string{"owner@example.com"})
+		if err := p.Process(context.Background(), submission); err == nil {
+			t.Error("Process() expected error")
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package deliver_cv_email
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvmail"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/translation"
+	serviceSmtp "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/smtp"
+)
+
+type Sender interface {
+	Send(ctx context.Context, msg serviceSmtp.Message) error
+}
+
+type Site struct {
+	ContentFiles map[string]string
+	LinkURL      string
+}
+
+type Process struct {
+	sender Sender
+	sites  map[string]Site
+	now    func() time.Time
+}
+
+func NewProcess(sender Sender, sites map[string]Site) (*Process, error) {
+	p := &Process{
+		sender: sender,
+		sites:  sites,
+		now:    time.Now,
+	}
+
+	for name, site := range sites {
+		for lang := range site.ContentFiles {
+			if _, err := p.template(site, lang); err != nil {
+				return nil, fmt.Errorf("site %s: %w", name, err)
+			}
+		}
+	}
+
+	return p, nil
+}
+
+func (p *Process) Process(ctx context.Context, delivery cvmail.Delivery) error {
+	if !p.now().Before(delivery.ExpiresAt) {
+		log.Printf("INFO: dropping CV link email for site %s, the token expired at %s", delivery.Site, delivery.ExpiresAt.Format(time.RFC3339))
+		return nil
+	}
+
+	site, ok := p.sites[delivery.Site]
+	if !ok {
+		return fmt.Errorf("email delivery is not configured for site %s", delivery.Site)
+	}
+
+	tpl, err := p.template(site, delivery.Lang)
+	if err != nil {
+		return err
+	}
+	link, err := cvmail.Link(site.LinkURL, delivery.Site, delivery.Lang, delivery.Token)
+	if err != nil {
+		return err
+	}
+
+	subject, body := tpl.Render(link, delivery.ExpiresAt)
+	if err := p.sender.Send(ctx, serviceSmtp.Message{To: []string{delivery.Email}, Subject: subject, Body: body}); err != nil {
+		return err
+	}
+
+	log.Printf("INFO: emailed CV link for site %s to a recipient at %s", delivery.Site, cvmail.Domain(delivery.Email))
+	return nil
+}
+
+func (p *Process) template(site Site, lang string) (cvmail.Template, error) {
+	filePath, ok := site.ContentFiles[lang]
+	if !ok {
+		return cvmail.Template{}, fmt.Errorf("no content file for lang %s", lang)
+	}
+
+	doc, err := os.ReadFile(filePath)
+	if err != nil {
+		return cvmail.Template{}, fmt.Errorf("could not read content file for lang %s: %w", lang, err)
+	}
+	entries, err := translation.Flatten(doc)
+	if err != nil {
+		return cvmail.Template{}, fmt.Errorf("could not parse content file for lang %s: %w", lang, err)
+	}
+
+	translations := make(map[string]string, len(entries))
+	for _, e := range entries {
+		translations[e.Path] = e.Value
+	}
+
+	tpl, err := cvmail.TemplateFrom(translations)
+	if err != nil {
+		return cvmail.Template{}, fmt.Errorf("content file for lang %s: %w", lang, err)
+	}
+
+	return tpl, nil
+}
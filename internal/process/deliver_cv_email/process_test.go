@@ -0,0 +1,110 @@
+package deliver_cv_email
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvmail"
+	serviceSmtp "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/smtp"
+)
+
+type mockSender struct {
+	sent []serviceSmtp.Message
+	err  error
+}
+
+func (m *mockSender) Send(ctx context.Context, msg serviceSmtp.Message) error {
+	m.sent = append(m.sent, msg)
+	return m.err
+}
+
+func writeContent(t *testing.T, lang, translations string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), lang+".json")
+	if err := os.WriteFile(path, []byte(`{"hero":{"title":"CV"},"translations":`+translations+`}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestProcess_DeliverCvEmail(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	site := Site{
+		ContentFiles: map[string]string{
+			"pl": writeContent(t, "pl", `{"cv_email_subject":"Twoje CV","cv_email_body":"Link: {link}\nWażny do {expires}."}`),
+			"en": writeContent(t, "en", `{"cv_email_subject":"Your CV","cv_email_body":"Link: {link}\nValid until {expires}."}`),
+		},
+		LinkURL: "https://adrianjanczenia.dev/cv/download",
+	}
+	delivery := cvmail.Delivery{Site: "main", Email: "anna@acme.com", Lang: "en", Token: "3f9a", ExpiresAt: now.Add(time.Hour)}
+
+	tests := []struct {
+		name      string
+		delivery  cvmail.Delivery
+		senderErr error
+		wantSent  *serviceSmtp.Message
+		wantErr   bool
+	}{
+		{
+			name:     "localized email",
+			delivery: delivery,
+			wantSent: &serviceSmtp.Message{
+				To:      []string{"anna@acme.com"},
+				Subject: "Your CV",
+				Body:    "Link: https://adrianjanczenia.dev/cv/download?lang=en&site=main&token=3f9a\nValid until 2026-03-01 13:00 UTC.",
+			},
+		},
+		{
+			name:      "sender error is retried",
+			delivery:  delivery,
+			senderErr: errors.New("smtp down"),
+			wantErr:   true,
+		},
+		{
+			name:     "expired token is dropped",
+			delivery: cvmail.Delivery{Site: "main", Email: "anna@acme.com", Lang: "en", Token: "3f9a", ExpiresAt: now},
+		},
+		{
+			name:     "unknown site",
+			delivery: cvmail.Delivery{Site: "other", Email: "anna@acme.com", Lang: "en", Token: "3f9a", ExpiresAt: now.Add(time.Hour)},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &mockSender{err: tt.senderErr}
+			p, err := NewProcess(sender, map[string]Site{"main": site})
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.now = func() time.Time { return now }
+
+			if err := p.Process(context.Background(), tt.delivery); (err != nil) != tt.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantSent == nil {
+				return
+			}
+			if len(sender.sent) != 1 {
+				t.Fatalf("Process() sent %d messages, want 1", len(sender.sent))
+			}
+			got := sender.sent[0]
+			if got.To[0] != tt.wantSent.To[0] || got.Subject != tt.wantSent.Subject || got.Body != tt.wantSent.Body {
+				t.Errorf("Process() sent %+v, want %+v", got, *tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestNewProcess_MissingTemplate(t *testing.T) {
+	site := Site{ContentFiles: map[string]string{"pl": writeContent(t, "pl", `{"cv_email_subject":"Twoje CV"}`)}, LinkURL: "https://adrianjanczenia.dev/cv"}
+
+	if _, err := NewProcess(&mockSender{}, map[string]Site{"main": site}); err == nil {
+		t.Error("NewProcess() error = nil, want a missing template error")
+	}
+}
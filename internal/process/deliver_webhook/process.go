@@ -0,0 +1,44 @@
+package deliver_webhook
+
+import (
+	"context"
+	"log"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/webhook"
+)
+
+type Sender interface {
+	Send(ctx context.Context, endpoint webhook.Endpoint, id, eventType string, body []byte) error
+}
+
+type Process struct {
+	sender    Sender
+	endpoints map[string]webhook.Endpoint
+}
+
+func NewProcess(sender Sender, endpoints []webhook.Endpoint) *Process {
+	p := &Process{
+		sender:    sender,
+		endpoints: make(map[string]webhook.Endpoint, len(endpoints)),
+	}
+	for _, e := range endpoints {
+		p.endpoints[e.Name] = e
+	}
+
+	return p
+}
+
+func (p *Process) Process(ctx context.Context, delivery webhook.Delivery) error {
+	endpoint, ok := p.endpoints[delivery.Endpoint]
+	if !ok {
+		log.Printf("INFO: dropping %s webhook for removed endpoint %s", delivery.Event.Type, delivery.Endpoint)
+		return nil
+	}
+
+	body, err := webhook.Render(endpoint.Format, delivery.Event)
+	if err != nil {
+		return err
+	}
+
+	return p.sender.Send(ctx, endpoint, delivery.Event.ID, string(delivery.Event.Type), body)
+}
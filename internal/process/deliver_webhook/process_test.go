@@ -0,0 +1,87 @@
+package deliver_webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/webhook"
+)
+
+type sent struct {
+	endpoint  string
+	id        string
+	eventType string
+	body      string
+}
+
+type mockSender struct {
+	sent []sent
+	err  error
+}
+
+func (m *mockSender) Send(ctx context.Context, endpoint webhook.Endpoint, id, eventType string, body []byte) error {
+	m.sent = append(m.sent, sent{endpoint: endpoint.Name, id: id, eventType: eventType, body: string(body)})
+	return m.err
+}
+
+func TestProcess_DeliverWebhook(t *testing.T) {
+	endpoints := []webhook.Endpoint{
+		{Name: "owner", URL: "https://example.com/hooks", Format: webhook.FormatJSON, Secret: []byte("s")},
+		{Name: "slack", URL: "https://hooks.slack.com/services/T/B/X", Format: webhook.FormatSlack},
+	}
+	event := audit.Event{ID: "e1", Type: audit.EventTriesExhausted, Site: "main", Lang: "pl", CaptchaID: "c1"}
+
+	tests := []struct {
+		name      string
+		endpoint  string
+		senderErr error
+		wantBody  string
+		wantErr   bool
+	}{
+		{
+			name:     "generic json",
+			endpoint: "owner",
+			wantBody: `{"id":"e1","time":"0001-01-01T00:00:00Z","type":"tries_exhausted","site":"main","lang":"pl","captchaId":"c1"}`,
+		},
+		{
+			name:     "slack",
+			endpoint: "slack",
+			wantBody: `{"text":"[main] Captcha c1 ran out of password tries (lang pl, client none)"}`,
+		},
+		{
+			name:      "sender error is retried",
+			endpoint:  "owner",
+			senderErr: errors.New("connection refused"),
+			wantBody:  `{"id":"e1","time":"0001-01-01T00:00:00Z","type":"tries_exhausted","site":"main","lang":"pl","captchaId":"c1"}`,
+			wantErr:   true,
+		},
+		{
+			name:     "removed endpoint is dropped",
+			endpoint: "teams",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &mockSender{err: tt.senderErr}
+			p := NewProcess(sender, endpoints)
+
+			err := p.Process(context.Background(), webhook.Delivery{Endpoint: tt.endpoint, Event: event})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantBody == "" {
+				if len(sender.sent) != 0 {
+					t.Errorf("Process() sent %+v, want nothing", sender.sent)
+				}
+				return
+			}
+			want := sent{endpoint: tt.endpoint, id: "e1", eventType: "tries_exhausted", body: tt.wantBody}
+			if len(sender.sent) != 1 || sender.sent[0] != want {
+				t.Errorf("Process() sent %+v, want %+v", sender.sent, want)
+			}
+		})
+	}
+}
@@ -2,39 +2,136 @@ package download_cv
 
 import (
 	"context"
+	"log"
+	"time"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/analytics"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
 	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pdfstamp"
 )
 
-type TokenValidator interface {
-	ValidateAndDeleteToken(ctx context.Context, token string) (bool, error)
+type ConsumeTokenTask interface {
+	Inspect(ctx context.Context, token string) (cvtoken.Metadata, error)
+	Execute(ctx context.Context, token string, resume bool) (cvtoken.Use, error)
+}
+
+type StampPdfTask interface {
+	Resume(ctx context.Context, token, stampID string) (pdfstamp.Stamp, bool)
+	Execute(ctx context.Context, filePath string, stamp pdfstamp.Stamp) ([]byte, pdfstamp.Stamp, error)
+}
+
+type StatsRecorder interface {
+	Increment(ctx context.Context, fields ...string)
+}
+
+type AuditRecorder interface {
+	Record(ctx context.Context, event audit.Event)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, event audit.Event)
+}
+
+type Document struct {
+	FilePath string
+	Content  []byte
+	StampID  string
+	Resumed  bool
 }
 
 type Process struct {
-	tokenValidator TokenValidator
-	cvFilePaths    map[string]string
+	consumeStoredTokenTask ConsumeTokenTask
+	consumeSignedTokenTask ConsumeTokenTask
+	stampPdfTask           StampPdfTask
+	cvFilePaths            map[string]string
+	statsRecorder          StatsRecorder
+	auditRecorder          AuditRecorder
+	notifier               Notifier
 }
 
-func NewProcess(tv TokenValidator, cvPaths map[string]string) *Process {
+func NewProcess(consumeStoredTokenTask, consumeSignedTokenTask ConsumeTokenTask, stampPdfTask StampPdfTask, cvPaths map[string]string, statsRecorder StatsRecorder, auditRecorder AuditRecorder, notifier Notifier) *Process {
 	return &Process{
-		tokenValidator: tv,
-		cvFilePaths:    cvPaths,
+		consumeStoredTokenTask: consumeStoredTokenTask,
+		consumeSignedTokenTask: consumeSignedTokenTask,
+		stampPdfTask:           stampPdfTask,
+		cvFilePaths:            cvPaths,
+		statsRecorder:          statsRecorder,
+		auditRecorder:          auditRecorder,
+		notifier:               notifier,
 	}
 }
 
-func (p *Process) Process(ctx context.Context, token, lang string) (string, error) {
-	valid, err := p.tokenValidator.ValidateAndDeleteToken(ctx, token)
-	if err != nil {
-		return "", errors.ErrInternalServerError
+func (p *Process) Process(ctx context.Context, token, lang, client string, resume bool, stampID string) (Document, error) {
+	consumeTokenTask := p.consumeStoredTokenTask
+	if cvtoken.IsSigned(token) {
+		consumeTokenTask = p.consumeSignedTokenTask
 	}
-	if !valid {
-		return "", errors.ErrCVExpired
+
+	metadata, err := consumeTokenTask.Inspect(ctx, token)
+	if err != nil {
+		return Document{}, err
 	}
 
 	filePath, ok := p.cvFilePaths[lang]
 	if !ok {
-		return "", errors.ErrUnsupportedLanguage
+		return Document{}, errors.ErrUnsupportedLanguage
+	}
+
+	if err := metadata.Authorize(lang, cvtoken.Fingerprint(client)); err != nil {
+		log.Printf("INFO: rejected CV download for lang %s of token issued for lang %s via credential %s for captcha %s: %v",
+			lang, metadata.Lang, metadata.Credential, metadata.CaptchaID, err)
+		return Document{}, errors.ErrCVTokenMismatch
+	}
+
+	var resumed pdfstamp.Stamp
+	if resume {
+		resumed, resume = p.stampPdfTask.Resume(ctx, cvtoken.Fingerprint(token), stampID)
+	}
+
+	use, err := consumeTokenTask.Execute(ctx, token, resume)
+	if err != nil {
+		return Document{}, err
+	}
+	metadata = use.Metadata
+
+	stamp := pdfstamp.Stamp{
+		Token:      cvtoken.Fingerprint(token),
+		Credential: metadata.Credential,
+		Lang:       lang,
+		Document:   metadata.Document,
+		Client:     cvtoken.Fingerprint(client),
+	}
+	if use.Resumed && resumed.ID != "" {
+		stamp = resumed
+	}
+	content, stamp, err := p.stampPdfTask.Execute(ctx, filePath, stamp)
+	if err != nil {
+		return Document{}, err
+	}
+	document := Document{FilePath: filePath, Content: content, StampID: stamp.ID, Resumed: use.Resumed}
+
+	if use.Resumed {
+		log.Printf("INFO: CV download resumed for lang %s via credential %s", lang, metadata.Credential)
+		return document, nil
+	}
+
+	log.Printf("INFO: CV downloaded for lang %s (document %s) via credential %s, token issued %s for captcha %s, %d downloads left",
+		lang, metadata.Document, metadata.Credential, metadata.IssuedAt.Format(time.RFC3339), metadata.CaptchaID, use.Remaining)
+	p.statsRecorder.Increment(ctx, analytics.CvDownload(lang), analytics.CvDownloadCredential(metadata.Credential))
+	event := audit.Event{
+		Type:       audit.EventTokenRedeemed,
+		Lang:       lang,
+		Document:   metadata.Document,
+		Credential: metadata.Credential,
+		CaptchaID:  metadata.CaptchaID,
+		Client:     cvtoken.Fingerprint(client),
+		Token:      cvtoken.Fingerprint(token),
+		Detail:     stamp.ID,
 	}
+	p.auditRecorder.Record(ctx, event)
+	p.notifier.Notify(ctx, event)
 
-	return filePath, nil
+	return document, nil
 }
@@ -3,82 +3,337 @@ package download_cv
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
 	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pdfstamp"
 )
 
-type mockTokenValidator struct {
-	validateFunc func(ctx context.Context, token string) (bool, error)
+type mockConsumeTokenTask struct {
+	metadata  cvtoken.Metadata
+	remaining int64
+	err       error
 }
 
-func (m *mockTokenValidator) ValidateAndDeleteToken(ctx context.Context, token string) (bool, error) {
-	return m.validateFunc(ctx, token)
+func (m *mockConsumeTokenTask) Inspect(ctx context.Context, token string) (cvtoken.Metadata, error) {
+	return m.metadata, m.err
+}
+
+func (m *mockConsumeTokenTask) Execute(ctx context.Context, token string, resume bool) (cvtoken.Use, error) {
+	if m.err != nil {
+		return cvtoken.Use{}, m.err
+	}
+	if !resume {
+		m.remaining--
+	}
+	return cvtoken.Use{Metadata: m.metadata, Remaining: m.remaining, Resumed: resume}, nil
+}
+
+type mockStampPdfTask struct {
+	id       string
+	previous pdfstamp.Stamp
+	stamps   []pdfstamp.Stamp
+}
+
+func (m *mockStampPdfTask) Resume(ctx context.Context, token, stampID string) (pdfstamp.Stamp, bool) {
+	if m.id == "" {
+		return pdfstamp.Stamp{}, true
+	}
+	if stampID == "" || stampID != m.previous.ID || token != m.previous.Token {
+		return pdfstamp.Stamp{}, false
+	}
+	return m.previous, true
+}
+
+func (m *mockStampPdfTask) Execute(ctx context.Context, filePath string, stamp pdfstamp.Stamp) ([]byte, pdfstamp.Stamp, error) {
+	m.stamps = append(m.stamps, stamp)
+	if m.id == "" {
+		return nil, pdfstamp.Stamp{}, nil
+	}
+	if stamp.ID == "" {
+		stamp.ID = m.id
+	}
+	return []byte("%PDF stamped " + filePath), stamp, nil
+}
+
+type mockStatsRecorder struct {
+	fields []string
+}
+
+func (m *mockStatsRecorder) Increment(ctx context.Context, fields ...string) {
+	m.fields = append(m.fields, fields...)
+}
+
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(ctx context.Context, event audit.Event) {
+	m.events = append(m.events, event)
+}
+
+type mockNotifier struct {
+	events []audit.Event
+}
+
+func (m *mockNotifier) Notify(ctx context.Context, event audit.Event) {
+	m.events = append(m.events, event)
 }
 
 func TestProcess_DownloadCV(t *testing.T) {
-	cvPaths := map[string]string{"pl": "/app/cv_pl.pdf"}
+	cvPaths := map[string]string{"pl": "/app/cv_pl.pdf", "en": "/app/cv_en.pdf"}
+
+	issued := func(m cvtoken.Metadata) *mockConsumeTokenTask {
+		m.Document = "cv_pl.pdf"
+		m.IssuedAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		m.CaptchaID = "captcha-1"
+		return &mockConsumeTokenTask{metadata: m, remaining: 2}
+	}
+	unused := func() *mockConsumeTokenTask {
+		return &mockConsumeTokenTask{err: errors.New("wrong token mode")}
+	}
 
 	tests := []struct {
-		name         string
-		token        string
-		lang         string
-		validateFunc func(context.Context, string) (bool, error)
-		wantPath     string
-		wantErr      error
+		name      string
+		token     string
+		lang      string
+		client    string
+		resume    bool
+		stored    *mockConsumeTokenTask
+		signed    *mockConsumeTokenTask
+		stampID   string
+		wantPath  string
+		wantErr   error
+		wantStats []string
 	}{
 		{
-			name:  "successful download",
-			token: "valid",
-			lang:  "pl",
-			validateFunc: func(ctx context.Context, t string) (bool, error) {
-				return true, nil
-			},
+			name:      "successful download",
+			token:     "valid",
+			lang:      "pl",
+			stored:    issued(cvtoken.Metadata{Lang: "pl", Credential: "acme-recruiter"}),
+			signed:    unused(),
+			wantPath:  "/app/cv_pl.pdf",
+			wantErr:   nil,
+			wantStats: []string{"cv_download:pl", "cv_download_credential:acme-recruiter"},
+		},
+		{
+			name:      "stamped download",
+			token:     "valid",
+			lang:      "pl",
+			client:    "203.0.113.7",
+			stored:    issued(cvtoken.Metadata{Lang: "pl", Credential: "acme-recruiter"}),
+			stampID:   "4b1c",
+			wantPath:  "/app/cv_pl.pdf",
+			wantErr:   nil,
+			wantStats: []string{"cv_download:pl", "cv_download_credential:acme-recruiter"},
+		},
+		{
+			name:      "signed token",
+			token:     "k1.payload.signature",
+			lang:      "pl",
+			stored:    unused(),
+			signed:    issued(cvtoken.Metadata{Lang: "pl", Credential: "acme-recruiter"}),
+			wantPath:  "/app/cv_pl.pdf",
+			wantErr:   nil,
+			wantStats: []string{"cv_download:pl", "cv_download_credential:acme-recruiter"},
+		},
+		{
+			name:      "successful download from the issuing client",
+			token:     "valid",
+			lang:      "pl",
+			client:    "203.0.113.7",
+			stored:    issued(cvtoken.Metadata{Lang: "pl", Credential: "acme-recruiter", Fingerprint: cvtoken.Fingerprint("203.0.113.7")}),
+			wantPath:  "/app/cv_pl.pdf",
+			wantErr:   nil,
+			wantStats: []string{"cv_download:pl", "cv_download_credential:acme-recruiter"},
+		},
+		{
+			name:      "emailed link opened on another device",
+			token:     "valid",
+			lang:      "pl",
+			client:    "198.51.100.1",
+			stored:    issued(cvtoken.Metadata{Lang: "pl", Credential: "acme-recruiter"}),
+			wantPath:  "/app/cv_pl.pdf",
+			wantErr:   nil,
+			wantStats: []string{"cv_download:pl", "cv_download_credential:acme-recruiter"},
+		},
+		{
+			name:     "resumed download is not counted again",
+			token:    "valid",
+			lang:     "pl",
+			resume:   true,
+			stored:   issued(cvtoken.Metadata{Lang: "pl", Credential: "acme-recruiter"}),
 			wantPath: "/app/cv_pl.pdf",
 			wantErr:  nil,
 		},
 		{
-			name:  "invalid token",
-			token: "invalid",
-			lang:  "pl",
-			validateFunc: func(ctx context.Context, t string) (bool, error) {
-				return false, nil
-			},
+			name:     "invalid token",
+			token:    "invalid",
+			lang:     "pl",
+			stored:   &mockConsumeTokenTask{err: appErrors.ErrCVExpired},
 			wantPath: "",
 			wantErr:  appErrors.ErrCVExpired,
 		},
 		{
-			name:  "unsupported language",
-			token: "valid",
-			lang:  "en",
-			validateFunc: func(ctx context.Context, t string) (bool, error) {
-				return true, nil
-			},
+			name:     "unsupported language",
+			token:    "valid",
+			lang:     "de",
+			stored:   issued(cvtoken.Metadata{Lang: "pl", Credential: "acme-recruiter"}),
 			wantPath: "",
 			wantErr:  appErrors.ErrUnsupportedLanguage,
 		},
 		{
-			name:  "validator internal error",
-			token: "valid",
-			lang:  "pl",
-			validateFunc: func(ctx context.Context, t string) (bool, error) {
-				return false, errors.New("redis error")
-			},
+			name:     "token issued for another language",
+			token:    "valid",
+			lang:     "en",
+			stored:   issued(cvtoken.Metadata{Lang: "pl", Credential: "acme-recruiter"}),
 			wantPath: "",
-			wantErr:  appErrors.ErrInternalServerError,
+			wantErr:  appErrors.ErrCVTokenMismatch,
+		},
+		{
+			name:     "resume for another language",
+			token:    "valid",
+			lang:     "en",
+			resume:   true,
+			stored:   issued(cvtoken.Metadata{Lang: "pl", Credential: "acme-recruiter"}),
+			wantPath: "",
+			wantErr:  appErrors.ErrCVTokenMismatch,
+		},
+		{
+			name:     "token issued to another client",
+			token:    "valid",
+			lang:     "pl",
+			client:   "198.51.100.1",
+			stored:   issued(cvtoken.Metadata{Lang: "pl", Credential: "acme-recruiter", Fingerprint: cvtoken.Fingerprint("203.0.113.7")}),
+			wantPath: "",
+			wantErr:  appErrors.ErrCVTokenMismatch,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := NewProcess(&mockTokenValidator{validateFunc: tt.validateFunc}, cvPaths)
-			path, err := p.Process(context.Background(), tt.token, tt.lang)
+			stats := &mockStatsRecorder{}
+			auditRecorder := &mockAuditRecorder{}
+			notifier := &mockNotifier{}
+			stamper := &mockStampPdfTask{id: tt.stampID}
+			p := NewProcess(tt.stored, tt.signed, stamper, cvPaths, stats, auditRecorder, notifier)
+			document, err := p.Process(context.Background(), tt.token, tt.lang, tt.client, tt.resume, "")
 
 			if err != tt.wantErr {
 				t.Errorf("Process() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			if path != tt.wantPath {
-				t.Errorf("Process() path = %v, wantPath %v", path, tt.wantPath)
+			if document.FilePath != tt.wantPath {
+				t.Errorf("Process() path = %v, wantPath %v", document.FilePath, tt.wantPath)
+			}
+			if document.StampID != tt.stampID || (document.Content != nil) != (tt.stampID != "") {
+				t.Errorf("Process() document = %+v, want stamp %q", document, tt.stampID)
+			}
+			for _, stamp := range stamper.stamps {
+				want := pdfstamp.Stamp{Token: cvtoken.Fingerprint(tt.token), Credential: "acme-recruiter", Lang: tt.lang, Document: "cv_pl.pdf", Client: cvtoken.Fingerprint(tt.client)}
+				if stamp != want {
+					t.Errorf("Process() stamp = %+v, want %+v", stamp, want)
+				}
+			}
+			consumer := tt.stored
+			if cvtoken.IsSigned(tt.token) {
+				consumer = tt.signed
+			}
+			wantLeft := int64(2)
+			if tt.wantStats != nil {
+				wantLeft = 1
+			}
+			if consumer.err == nil && consumer.remaining != wantLeft {
+				t.Errorf("Process() left %d downloads, want %d", consumer.remaining, wantLeft)
+			}
+			if !reflect.DeepEqual(stats.fields, tt.wantStats) {
+				t.Errorf("Process() stats = %v, want %v", stats.fields, tt.wantStats)
+			}
+			if redeemed := tt.wantStats != nil; redeemed != (len(auditRecorder.events) == 1) {
+				t.Errorf("Process() audit = %+v, want redeemed %v", auditRecorder.events, redeemed)
+			}
+			if !reflect.DeepEqual(notifier.events, auditRecorder.events) {
+				t.Errorf("Process() notified %+v, want %+v", notifier.events, auditRecorder.events)
+			}
+			for _, e := range auditRecorder.events {
+				want := audit.Event{
+					Type:       audit.EventTokenRedeemed,
+					Lang:       tt.lang,
+					Document:   "cv_pl.pdf",
+					Credential: "acme-recruiter",
+					CaptchaID:  "captcha-1",
+					Client:     cvtoken.Fingerprint(tt.client),
+					Token:      cvtoken.Fingerprint(tt.token),
+					Detail:     tt.stampID,
+				}
+				if e != want {
+					t.Errorf("Process() audit event = %+v, want %+v", e, want)
+				}
+			}
+		})
+	}
+}
+
+func TestProcess_DownloadCV_ResumeStamp(t *testing.T) {
+	cvPaths := map[string]string{"pl": "/app/cv_pl.pdf"}
+	metadata := cvtoken.Metadata{Lang: "pl", Document: "cv_pl.pdf", Credential: "acme-recruiter", IssuedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	previous := pdfstamp.Stamp{ID: "4b1c", Token: cvtoken.Fingerprint("valid"), Credential: "acme-recruiter", Lang: "pl", Document: "cv_pl.pdf"}
+
+	tests := []struct {
+		name        string
+		stampID     string
+		previous    pdfstamp.Stamp
+		wantStampID string
+		wantResumed bool
+	}{
+		{
+			name:        "the stamp named by the client is resumed",
+			stampID:     "4b1c",
+			previous:    previous,
+			wantStampID: "4b1c",
+			wantResumed: true,
+		},
+		{
+			name:        "another stamp restarts with a new one",
+			stampID:     "9d0e",
+			previous:    previous,
+			wantStampID: "7e2f",
+		},
+		{
+			name:        "no stamp restarts with a new one",
+			previous:    previous,
+			wantStampID: "7e2f",
+		},
+		{
+			name:        "stamp of another token restarts with a new one",
+			stampID:     "4b1c",
+			previous:    pdfstamp.Stamp{ID: "4b1c", Token: cvtoken.Fingerprint("other")},
+			wantStampID: "7e2f",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consumer := &mockConsumeTokenTask{metadata: metadata, remaining: 2}
+			auditRecorder := &mockAuditRecorder{}
+			p := NewProcess(consumer, nil, &mockStampPdfTask{id: "7e2f", previous: tt.previous}, cvPaths, &mockStatsRecorder{}, auditRecorder, &mockNotifier{})
+
+			document, err := p.Process(context.Background(), "valid", "pl", "", true, tt.stampID)
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if document.StampID != tt.wantStampID || document.Resumed != tt.wantResumed {
+				t.Errorf("Process() = stamp %q resumed %v, want %q %v", document.StampID, document.Resumed, tt.wantStampID, tt.wantResumed)
+			}
+			wantLeft, wantEvents := int64(1), 1
+			if tt.wantResumed {
+				wantLeft, wantEvents = 2, 0
+			}
+			if consumer.remaining != wantLeft || len(auditRecorder.events) != wantEvents {
+				t.Errorf("Process() left %d downloads with %d audit events, want %d and %d", consumer.remaining, len(auditRecorder.events), wantLeft, wantEvents)
 			}
 		})
 	}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type NonceStore interface {
+	ClaimNonce(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error)
+}
+
+type RevocationStore interface {
+	CredentialRevokedAt(ctx context.Context, key string) (time.Time, bool, error)
+}
+
+type ConsumeSignedTokenTask struct {
+	site            string
+	keys            []cvtoken.SigningKey
+	nonceStore      NonceStore
+	revocationStore RevocationStore
+	grace           time.Duration
+	now             func() time.Time
+}
+
+func NewConsumeSignedTokenTask(site string, keys []cvtoken.SigningKey, nonceStore NonceStore, revocationStore RevocationStore, grace time.Duration) *ConsumeSignedTokenTask {
+	return &ConsumeSignedTokenTask{
+		site:            site,
+		keys:            keys,
+		nonceStore:      nonceStore,
+		revocationStore: revocationStore,
+		grace:           grace,
+		now:             time.Now,
+	}
+}
+
+func (t *ConsumeSignedTokenTask) Inspect(ctx context.Context, token string) (cvtoken.Metadata, error) {
+	claims, err := t.verify(ctx, token, t.now())
+	if err != nil {
+		return cvtoken.Metadata{}, err
+	}
+
+	return claims.Metadata, nil
+}
+
+func (t *ConsumeSignedTokenTask) Execute(ctx context.Context, token string, resume bool) (cvtoken.Use, error) {
+	now := t.now()
+	claims, err := t.verify(ctx, token, now)
+	if err != nil {
+		return cvtoken.Use{}, err
+	}
+
+	firstUse, claimed, err := t.nonceStore.ClaimNonce(ctx, cvtoken.NonceKey(claims.Nonce), claims.ExpiresAt.Sub(now)+t.grace)
+	if err != nil {
+		log.Printf("ERROR: could not claim nonce of signed CV token, accepting it without single-use check: %v", err)
+		return cvtoken.Use{Metadata: claims.Metadata}, nil
+	}
+	if claimed {
+		return cvtoken.Use{Metadata: claims.Metadata}, nil
+	}
+	if resume && now.Sub(firstUse) < t.grace {
+		return cvtoken.Use{Metadata: claims.Metadata, Resumed: true}, nil
+	}
+
+	return cvtoken.Use{}, errors.ErrCVExpired
+}
+
+func (t *ConsumeSignedTokenTask) verify(ctx context.Context, token string, now time.Time) (cvtoken.Claims, error) {
+	claims, err := cvtoken.Verify(t.keys, token, now)
+	if err != nil {
+		log.Printf("INFO: rejected signed CV token: %v", err)
+		return cvtoken.Claims{}, errors.ErrCVExpired
+	}
+	if claims.Site != t.site {
+		log.Printf("INFO: rejected signed CV token issued for site %q on site %s", claims.Site, t.site)
+		return cvtoken.Claims{}, errors.ErrCVTokenMismatch
+	}
+
+	revokedAt, revoked, err := t.revocationStore.CredentialRevokedAt(ctx, cvtoken.RevocationKey(claims.Metadata.Credential))
+	if err != nil {
+		log.Printf("ERROR: could not check revocation of credential %s, accepting the signed CV token: %v", claims.Metadata.Credential, err)
+		return claims, nil
+	}
+	if revoked && !claims.Metadata.IssuedAt.After(revokedAt) {
+		log.Printf("INFO: rejected signed CV token issued via revoked credential %s", claims.Metadata.Credential)
+		return cvtoken.Claims{}, errors.ErrCVExpired
+	}
+
+	return claims, nil
+}
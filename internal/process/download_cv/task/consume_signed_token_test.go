@@ -0,0 +1,190 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type mockNonceStore struct {
+	claimFunc func(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error)
+}
+
+func (m *mockNonceStore) ClaimNonce(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error) {
+	return m.claimFunc(ctx, key, ttl)
+}
+
+type mockRevocationStore struct {
+	revokedAt map[string]time.Time
+	err       error
+}
+
+func (m *mockRevocationStore) CredentialRevokedAt(ctx context.Context, key string) (time.Time, bool, error) {
+	if m.err != nil {
+		return time.Time{}, false, m.err
+	}
+	at, ok := m.revokedAt[key]
+	return at, ok, nil
+}
+
+func TestConsumeSignedTokenTask_Execute(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	key := cvtoken.SigningKey{ID: "k1", Secret: bytes.Repeat([]byte("k"), 32)}
+	metadata := cvtoken.Metadata{Lang: "pl", Document: "cv_pl.pdf", IssuedAt: now, Credential: "acme-recruiter"}
+	token, _ := cvtoken.Sign(key, cvtoken.Claims{Metadata: metadata, Site: "main", ExpiresAt: now.Add(time.Minute), Nonce: "n1"})
+	foreign, _ := cvtoken.Sign(cvtoken.SigningKey{ID: "k2", Secret: bytes.Repeat([]byte("x"), 32)}, cvtoken.Claims{Metadata: metadata, Site: "main", ExpiresAt: now.Add(time.Minute), Nonce: "n2"})
+	otherSite, _ := cvtoken.Sign(key, cvtoken.Claims{Metadata: metadata, Site: "blog", ExpiresAt: now.Add(time.Minute), Nonce: "n3"})
+	noSite, _ := cvtoken.Sign(key, cvtoken.Claims{Metadata: metadata, ExpiresAt: now.Add(time.Minute), Nonce: "n4"})
+	mustNotClaim := func(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error) {
+		return time.Time{}, false, errors.New("a token of another site must not claim a nonce")
+	}
+
+	claimed := func(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error) {
+		return now, true, nil
+	}
+
+	tests := []struct {
+		name        string
+		token       string
+		resume      bool
+		claimFunc   func(context.Context, string, time.Duration) (time.Time, bool, error)
+		revocations *mockRevocationStore
+		want        cvtoken.Use
+		wantErr     error
+	}{
+		{
+			name:  "first use claims the nonce",
+			token: token,
+			claimFunc: func(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error) {
+				if key != "cv_nonce:n1" || ttl != 6*time.Minute {
+					return time.Time{}, false, errors.New("unexpected claim")
+				}
+				return now, true, nil
+			},
+			want: cvtoken.Use{Metadata: metadata},
+		},
+		{
+			name:  "second use",
+			token: token,
+			claimFunc: func(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error) {
+				return now.Add(-time.Second), false, nil
+			},
+			wantErr: appErrors.ErrCVExpired,
+		},
+		{
+			name:   "range request within the grace window",
+			token:  token,
+			resume: true,
+			claimFunc: func(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error) {
+				return now.Add(-time.Minute), false, nil
+			},
+			want: cvtoken.Use{Metadata: metadata, Resumed: true},
+		},
+		{
+			name:   "range request after the grace window",
+			token:  token,
+			resume: true,
+			claimFunc: func(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error) {
+				return now.Add(-10 * time.Minute), false, nil
+			},
+			wantErr: appErrors.ErrCVExpired,
+		},
+		{
+			name:  "redis unavailable",
+			token: token,
+			claimFunc: func(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error) {
+				return time.Time{}, false, errors.New("connection refused")
+			},
+			want: cvtoken.Use{Metadata: metadata},
+		},
+		{
+			name:    "unknown key",
+			token:   foreign,
+			wantErr: appErrors.ErrCVExpired,
+		},
+		{
+			name:      "token issued for another site",
+			token:     otherSite,
+			claimFunc: mustNotClaim,
+			wantErr:   appErrors.ErrCVTokenMismatch,
+		},
+		{
+			name:      "token without a site",
+			token:     noSite,
+			claimFunc: mustNotClaim,
+			wantErr:   appErrors.ErrCVTokenMismatch,
+		},
+		{
+			name:        "credential revoked after issuance",
+			token:       token,
+			claimFunc:   claimed,
+			revocations: &mockRevocationStore{revokedAt: map[string]time.Time{"cv_credential_revoked:acme-recruiter": now.Add(time.Second)}},
+			wantErr:     appErrors.ErrCVExpired,
+		},
+		{
+			name:        "credential revoked before issuance",
+			token:       token,
+			claimFunc:   claimed,
+			revocations: &mockRevocationStore{revokedAt: map[string]time.Time{"cv_credential_revoked:acme-recruiter": now.Add(-time.Second)}},
+			want:        cvtoken.Use{Metadata: metadata},
+		},
+		{
+			name:        "revocation check unavailable",
+			token:       token,
+			claimFunc:   claimed,
+			revocations: &mockRevocationStore{err: errors.New("connection refused")},
+			want:        cvtoken.Use{Metadata: metadata},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revocations := tt.revocations
+			if revocations == nil {
+				revocations = &mockRevocationStore{}
+			}
+			task := NewConsumeSignedTokenTask("main", []cvtoken.SigningKey{key}, &mockNonceStore{claimFunc: tt.claimFunc}, revocations, 5*time.Minute)
+			task.now = func() time.Time { return now }
+
+			got, err := task.Execute(context.Background(), tt.token, tt.resume)
+			if err != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Metadata != tt.want.Metadata || got.Resumed != tt.want.Resumed {
+				t.Errorf("Execute() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsumeSignedTokenTask_Inspect(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	key := cvtoken.SigningKey{ID: "k1", Secret: bytes.Repeat([]byte("k"), 32)}
+	metadata := cvtoken.Metadata{Lang: "pl", Document: "cv_pl.pdf", IssuedAt: now, Credential: "acme-recruiter"}
+	token, _ := cvtoken.Sign(key, cvtoken.Claims{Metadata: metadata, Site: "main", ExpiresAt: now.Add(time.Minute), Nonce: "n1"})
+	claimed := func(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error) {
+		return time.Time{}, false, errors.New("inspecting must not claim the nonce")
+	}
+
+	revocations := &mockRevocationStore{revokedAt: map[string]time.Time{}}
+	task := NewConsumeSignedTokenTask("main", []cvtoken.SigningKey{key}, &mockNonceStore{claimFunc: claimed}, revocations, 5*time.Minute)
+	task.now = func() time.Time { return now }
+
+	got, err := task.Inspect(context.Background(), token)
+	if err != nil || got != metadata {
+		t.Errorf("Inspect() = %+v, %v, want %+v", got, err, metadata)
+	}
+	revocations.revokedAt["cv_credential_revoked:acme-recruiter"] = now
+	if _, err := task.Inspect(context.Background(), token); err != appErrors.ErrCVExpired {
+		t.Errorf("Inspect() revoked credential error = %v, want %v", err, appErrors.ErrCVExpired)
+	}
+	delete(revocations.revokedAt, "cv_credential_revoked:acme-recruiter")
+	if _, err := task.Inspect(context.Background(), token+"x"); err != appErrors.ErrCVExpired {
+		t.Errorf("Inspect() tampered token error = %v, want %v", err, appErrors.ErrCVExpired)
+	}
+}
@@ -0,0 +1,64 @@
+package task
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	serviceRedis "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/redis"
+)
+
+type TokenConsumer interface {
+	InspectToken(ctx context.Context, key string) (serviceRedis.StoredToken, bool, error)
+	ConsumeToken(ctx context.Context, token string, resume bool, grace time.Duration) (serviceRedis.TokenUse, bool, error)
+}
+
+type ConsumeStoredTokenTask struct {
+	tokenConsumer TokenConsumer
+	grace         time.Duration
+}
+
+func NewConsumeStoredTokenTask(tc TokenConsumer, grace time.Duration) *ConsumeStoredTokenTask {
+	return &ConsumeStoredTokenTask{
+		tokenConsumer: tc,
+		grace:         grace,
+	}
+}
+
+func (t *ConsumeStoredTokenTask) Inspect(ctx context.Context, token string) (cvtoken.Metadata, error) {
+	stored, found, err := t.tokenConsumer.InspectToken(ctx, cvtoken.Key(token))
+	if err != nil {
+		return cvtoken.Metadata{}, errors.ErrInternalServerError
+	}
+	if !found {
+		return cvtoken.Metadata{}, errors.ErrCVExpired
+	}
+
+	metadata, err := cvtoken.Decode(stored.Value)
+	if err != nil {
+		log.Printf("ERROR: could not read CV token metadata: %v", err)
+		return cvtoken.Metadata{}, errors.ErrCVExpired
+	}
+
+	return metadata, nil
+}
+
+func (t *ConsumeStoredTokenTask) Execute(ctx context.Context, token string, resume bool) (cvtoken.Use, error) {
+	use, valid, err := t.tokenConsumer.ConsumeToken(ctx, cvtoken.Key(token), resume, t.grace)
+	if err != nil {
+		return cvtoken.Use{}, errors.ErrInternalServerError
+	}
+	if !valid {
+		return cvtoken.Use{}, errors.ErrCVExpired
+	}
+
+	metadata, err := cvtoken.Decode(use.Value)
+	if err != nil {
+		log.Printf("ERROR: could not read CV token metadata: %v", err)
+		return cvtoken.Use{}, errors.ErrCVExpired
+	}
+
+	return cvtoken.Use{Metadata: metadata, Remaining: use.Remaining, Resumed: use.Resumed}, nil
+}
@@ -0,0 +1,141 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	serviceRedis "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/redis"
+)
+
+type mockTokenConsumer struct {
+	consumeFunc func(ctx context.Context, token string, resume bool, grace time.Duration) (serviceRedis.TokenUse, bool, error)
+	stored      serviceRedis.StoredToken
+	found       bool
+	inspectErr  error
+}
+
+func (m *mockTokenConsumer) InspectToken(ctx context.Context, key string) (serviceRedis.StoredToken, bool, error) {
+	if key != "cv_token:token" {
+		return serviceRedis.StoredToken{}, false, errors.New("key not passed on")
+	}
+	return m.stored, m.found, m.inspectErr
+}
+
+func (m *mockTokenConsumer) ConsumeToken(ctx context.Context, token string, resume bool, grace time.Duration) (serviceRedis.TokenUse, bool, error) {
+	return m.consumeFunc(ctx, token, resume, grace)
+}
+
+func TestConsumeStoredTokenTask_Execute(t *testing.T) {
+	metadata := cvtoken.Metadata{Lang: "pl", Document: "cv_pl.pdf", IssuedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), Credential: "acme-recruiter"}
+	value, _ := metadata.Encode()
+
+	tests := []struct {
+		name        string
+		resume      bool
+		consumeFunc func(context.Context, string, bool, time.Duration) (serviceRedis.TokenUse, bool, error)
+		want        cvtoken.Use
+		wantErr     error
+	}{
+		{
+			name: "download",
+			consumeFunc: func(ctx context.Context, token string, resume bool, grace time.Duration) (serviceRedis.TokenUse, bool, error) {
+				if token != "cv_token:token" || grace != 5*time.Minute {
+					return serviceRedis.TokenUse{}, false, errors.New("key or grace window not passed on")
+				}
+				return serviceRedis.TokenUse{Value: value, Remaining: 2}, true, nil
+			},
+			want: cvtoken.Use{Metadata: metadata, Remaining: 2},
+		},
+		{
+			name:   "resumed",
+			resume: true,
+			consumeFunc: func(ctx context.Context, token string, resume bool, grace time.Duration) (serviceRedis.TokenUse, bool, error) {
+				return serviceRedis.TokenUse{Value: value, Resumed: resume}, true, nil
+			},
+			want: cvtoken.Use{Metadata: metadata, Resumed: true},
+		},
+		{
+			name: "invalid token",
+			consumeFunc: func(ctx context.Context, token string, resume bool, grace time.Duration) (serviceRedis.TokenUse, bool, error) {
+				return serviceRedis.TokenUse{}, false, nil
+			},
+			wantErr: appErrors.ErrCVExpired,
+		},
+		{
+			name: "token without metadata",
+			consumeFunc: func(ctx context.Context, token string, resume bool, grace time.Duration) (serviceRedis.TokenUse, bool, error) {
+				return serviceRedis.TokenUse{Value: "valid"}, true, nil
+			},
+			wantErr: appErrors.ErrCVExpired,
+		},
+		{
+			name: "redis error",
+			consumeFunc: func(ctx context.Context, token string, resume bool, grace time.Duration) (serviceRedis.TokenUse, bool, error) {
+				return serviceRedis.TokenUse{}, false, errors.New("redis error")
+			},
+			wantErr: appErrors.ErrInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewConsumeStoredTokenTask(&mockTokenConsumer{consumeFunc: tt.consumeFunc}, 5*time.Minute)
+			got, err := task.Execute(context.Background(), "token", tt.resume)
+			if err != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Execute() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsumeStoredTokenTask_Inspect(t *testing.T) {
+	metadata := cvtoken.Metadata{Lang: "pl", Document: "cv_pl.pdf", IssuedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), Credential: "acme-recruiter"}
+	value, _ := metadata.Encode()
+
+	tests := []struct {
+		name     string
+		consumer *mockTokenConsumer
+		want     cvtoken.Metadata
+		wantErr  error
+	}{
+		{
+			name:     "stored token",
+			consumer: &mockTokenConsumer{stored: serviceRedis.StoredToken{Value: value, Remaining: 2}, found: true},
+			want:     metadata,
+		},
+		{
+			name:     "unknown token",
+			consumer: &mockTokenConsumer{},
+			wantErr:  appErrors.ErrCVExpired,
+		},
+		{
+			name:     "token without metadata",
+			consumer: &mockTokenConsumer{stored: serviceRedis.StoredToken{Value: "valid"}, found: true},
+			wantErr:  appErrors.ErrCVExpired,
+		},
+		{
+			name:     "redis error",
+			consumer: &mockTokenConsumer{inspectErr: errors.New("redis error")},
+			wantErr:  appErrors.ErrInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewConsumeStoredTokenTask(tt.consumer, 5*time.Minute).Inspect(context.Background(), "token")
+			if err != tt.wantErr {
+				t.Fatalf("Inspect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Inspect() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pdfstamp"
+)
+
+type StampStore interface {
+	StoreStamp(ctx context.Context, stamp pdfstamp.Stamp, ttl time.Duration) error
+	Stamp(ctx context.Context, id string) (pdfstamp.Stamp, bool, error)
+}
+
+type StampPdfTask struct {
+	stampStore StampStore
+	enabled    bool
+	retention  time.Duration
+	now        func() time.Time
+}
+
+func NewStampPdfTask(ss StampStore, enabled bool, retention time.Duration) *StampPdfTask {
+	return &StampPdfTask{
+		stampStore: ss,
+		enabled:    enabled,
+		retention:  retention,
+		now:        time.Now,
+	}
+}
+
+func (t *StampPdfTask) Resume(ctx context.Context, token, stampID string) (pdfstamp.Stamp, bool) {
+	if !t.enabled {
+		return pdfstamp.Stamp{}, true
+	}
+	if stampID == "" {
+		return pdfstamp.Stamp{}, false
+	}
+
+	stamp, found, err := t.stampStore.Stamp(ctx, stampID)
+	if err != nil {
+		log.Printf("ERROR: could not load CV stamp %s of resumed download: %v", stampID, err)
+		return pdfstamp.Stamp{}, false
+	}
+	if !found || stamp.Token != token {
+		return pdfstamp.Stamp{}, false
+	}
+
+	return stamp, true
+}
+
+func (t *StampPdfTask) Execute(ctx context.Context, filePath string, stamp pdfstamp.Stamp) ([]byte, pdfstamp.Stamp, error) {
+	if !t.enabled {
+		return nil, pdfstamp.Stamp{}, nil
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		log.Printf("ERROR: could not read CV %s: %v", filePath, err)
+		return nil, pdfstamp.Stamp{}, errors.ErrCVNotFound
+	}
+
+	if stamp.ID != "" {
+		return t.apply(data, stamp)
+	}
+
+	if stamp.ID, err = pdfstamp.NewID(); err != nil {
+		return nil, pdfstamp.Stamp{}, errors.ErrInternalServerError
+	}
+	stamp.StampedAt = t.now().UTC().Truncate(time.Second)
+
+	stamped, stamp, err := t.apply(data, stamp)
+	if err != nil || stamped == nil {
+		return stamped, stamp, err
+	}
+	if err := t.stampStore.StoreStamp(ctx, stamp, t.retention); err != nil {
+		log.Printf("ERROR: could not record CV stamp %s: %v", stamp.ID, err)
+	}
+
+	return stamped, stamp, nil
+}
+
+func (t *StampPdfTask) apply(data []byte, stamp pdfstamp.Stamp) ([]byte, pdfstamp.Stamp, error) {
+	stamped, err := pdfstamp.Apply(data, stamp)
+	if err != nil {
+		log.Printf("ERROR: could not stamp CV %s, serving it unstamped: %v", stamp.Document, err)
+		return nil, pdfstamp.Stamp{}, nil
+	}
+
+	return stamped, stamp, nil
+}
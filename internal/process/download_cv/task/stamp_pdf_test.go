@@ -0,0 +1,188 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pdfstamp"
+)
+
+type mockStampStore struct {
+	stamps   map[string]pdfstamp.Stamp
+	loadErr  error
+	storeErr error
+	stored   []pdfstamp.Stamp
+	ttl      time.Duration
+}
+
+func (m *mockStampStore) StoreStamp(ctx context.Context, stamp pdfstamp.Stamp, ttl time.Duration) error {
+	m.stored = append(m.stored, stamp)
+	m.ttl = ttl
+	return m.storeErr
+}
+
+func (m *mockStampStore) Stamp(ctx context.Context, id string) (pdfstamp.Stamp, bool, error) {
+	stamp, found := m.stamps[id]
+	return stamp, found, m.loadErr
+}
+
+func writePDF(t *testing.T) string {
+	t.Helper()
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	var offsets []int
+	for i, obj := range []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] >>",
+	} {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	start := b.Len()
+	b.WriteString("xref\n0 4\n0000000000 65535 f\r\n")
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n\r\n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size 4 /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", start)
+
+	path := filepath.Join(t.TempDir(), "cv_pl.pdf")
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestStampPdfTask_Execute(t *testing.T) {
+	pdf := writePDF(t)
+	notPDF := filepath.Join(t.TempDir(), "cv_en.pdf")
+	os.WriteFile(notPDF, []byte("Adrian Janczenia CV"), 0o644)
+	now := time.Date(2026, 3, 1, 12, 30, 15, 0, time.UTC)
+	base := pdfstamp.Stamp{Token: "3f9a", Credential: "acme", Lang: "pl", Document: "cv_pl.pdf"}
+	previous := base
+	previous.ID, previous.StampedAt = "4b1c", now.Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		disabled    bool
+		filePath    string
+		stamp       pdfstamp.Stamp
+		storeErr    error
+		wantStamped bool
+		wantID      string
+		wantStored  int
+		wantErr     error
+	}{
+		{
+			name:     "disabled",
+			disabled: true,
+			filePath: pdf,
+		},
+		{
+			name:        "download",
+			filePath:    pdf,
+			wantStamped: true,
+			wantStored:  1,
+		},
+		{
+			name:        "resumed download keeps its stamp",
+			filePath:    pdf,
+			stamp:       previous,
+			wantStamped: true,
+			wantID:      "4b1c",
+		},
+		{
+			name:        "stamp not recorded",
+			filePath:    pdf,
+			storeErr:    errors.New("redis error"),
+			wantStamped: true,
+			wantStored:  1,
+		},
+		{
+			name:     "unstampable file is served as is",
+			filePath: notPDF,
+		},
+		{
+			name:     "missing file",
+			filePath: filepath.Join(t.TempDir(), "missing.pdf"),
+			wantErr:  appErrors.ErrCVNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockStampStore{storeErr: tt.storeErr}
+			task := NewStampPdfTask(store, !tt.disabled, 90*24*time.Hour)
+			task.now = func() time.Time { return now }
+
+			stamp := base
+			if tt.stamp.ID != "" {
+				stamp = tt.stamp
+			}
+			content, stamp, err := task.Execute(context.Background(), tt.filePath, stamp)
+			if err != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (content != nil) != tt.wantStamped {
+				t.Fatalf("Execute() stamped = %v, want %v", content != nil, tt.wantStamped)
+			}
+			if len(store.stored) != tt.wantStored {
+				t.Errorf("Execute() stored %d stamps, want %d", len(store.stored), tt.wantStored)
+			}
+			if !tt.wantStamped {
+				return
+			}
+
+			if tt.wantID != "" && stamp.ID != tt.wantID {
+				t.Errorf("Execute() stamp = %s, want %s", stamp.ID, tt.wantID)
+			}
+			if tt.wantID == "" && (len(stamp.ID) != 16 || !stamp.StampedAt.Equal(now) || stamp.Credential != "acme") {
+				t.Errorf("Execute() stamp = %+v", stamp)
+			}
+			if !bytes.Contains(content, []byte("ref "+stamp.ID)) {
+				t.Error("Execute() content does not carry the stamp")
+			}
+			if tt.wantStored > 0 && (store.stored[0] != stamp || store.ttl != 90*24*time.Hour) {
+				t.Errorf("Execute() stored %+v for %v, want %+v", store.stored[0], store.ttl, stamp)
+			}
+		})
+	}
+}
+
+func TestStampPdfTask_Resume(t *testing.T) {
+	previous := pdfstamp.Stamp{ID: "4b1c", Token: "3f9a", Credential: "acme", Lang: "pl", Document: "cv_pl.pdf"}
+
+	tests := []struct {
+		name       string
+		disabled   bool
+		token      string
+		stampID    string
+		loadErr    error
+		want       pdfstamp.Stamp
+		wantResume bool
+	}{
+		{name: "stamp of the token", token: "3f9a", stampID: "4b1c", want: previous, wantResume: true},
+		{name: "unknown stamp", token: "3f9a", stampID: "9d0e"},
+		{name: "no stamp sent", token: "3f9a"},
+		{name: "stamp of another token", token: "7c21", stampID: "4b1c"},
+		{name: "redis error", token: "3f9a", stampID: "4b1c", loadErr: errors.New("redis error")},
+		{name: "unstamped copies always resume", disabled: true, token: "3f9a", wantResume: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockStampStore{stamps: map[string]pdfstamp.Stamp{"4b1c": previous}, loadErr: tt.loadErr}
+			got, resume := NewStampPdfTask(store, !tt.disabled, time.Hour).Resume(context.Background(), tt.token, tt.stampID)
+			if got != tt.want || resume != tt.wantResume {
+				t.Errorf("Resume() = %+v, %v, want %+v, %v", got, resume, tt.want, tt.wantResume)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package export_translations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/translation"
+)
+
+type Process struct {
+	contentFiles map[string]string
+}
+
+func NewProcess(contentFiles map[string]string) *Process {
+	return &Process{contentFiles: contentFiles}
+}
+
+func (p *Process) Process(ctx context.Context, w io.Writer, sourceLang, targetLang string, format translation.Format) error {
+	source, err := p.readEntries(sourceLang)
+	if err != nil {
+		return err
+	}
+	target, err := p.readEntries(targetLang)
+	if err != nil {
+		return err
+	}
+
+	targetValues := make(map[string]string, len(target))
+	for _, e := range target {
+		targetValues[e.Path] = e.Value
+	}
+
+	catalog := &translation.Catalog{SourceLang: sourceLang, TargetLang: targetLang}
+	for _, e := range source {
+		catalog.Units = append(catalog.Units, translation.Unit{
+			Path:   e.Path,
+			Source: e.Value,
+			Target: targetValues[e.Path],
+		})
+	}
+
+	return translation.Encode(w, catalog, format)
+}
+
+func (p *Process) readEntries(lang string) ([]translation.Entry, error) {
+	filePath, ok := p.contentFiles[lang]
+	if !ok {
+		return nil, fmt.Errorf("no content file configured for lang %s", lang)
+	}
+
+	doc, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read content file for lang %s: %w", lang, err)
+	}
+
+	entries, err := translation.Flatten(doc)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse content file for lang %s: %w", lang, err)
+	}
+
+	return entries, nil
+}
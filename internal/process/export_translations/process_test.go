@@ -0,0 +1,74 @@
+package export_translations
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/translation"
+)
+
+func TestProcess_ExportTranslations(t *testing.T) {
+	tmpDir := t.TempDir()
+	plPath := filepath.Join(tmpDir, "pl.json")
+	enPath := filepath.Join(tmpDir, "en.json")
+	os.WriteFile(plPath, []byte(`{"profile":{"headline":{"experiment":"headline-2026","variants":[{"id":"control","weight":50,"value":"Programista"},{"id":"go","weight":50,"value":"Programista Go"}]},"photo":{"asset":"profile.jpg","alt":"Zdjęcie"},"tags":["Go","Mikroserwisy"]}}`), 0644)
+	os.WriteFile(enPath, []byte(`{"profile":{"headline":{"experiment":"headline-2026","variants":[{"id":"control","weight":50,"value":"Developer"},{"id":"go","weight":50,"value":"Go Developer"}]},"photo":{"asset":"profile.jpg","alt":"Photo"},"tags":["Go"]}}`), 0644)
+
+	p := NewProcess(map[string]string{"pl": plPath, "en": enPath})
+
+	tests := []struct {
+		name       string
+		sourceLang string
+		targetLang string
+		want       *translation.Catalog
+		wantErr    bool
+	}{
+		{
+			name:       "source units with existing targets, without experiment metadata and asset references",
+			sourceLang: "pl",
+			targetLang: "en",
+			want: &translation.Catalog{
+				SourceLang: "pl",
+				TargetLang: "en",
+				Units: []translation.Unit{
+					{Path: "profile.headline.variants.0.value", Source: "Programista", Target: "Developer"},
+					{Path: "profile.headline.variants.1.value", Source: "Programista Go", Target: "Go Developer"},
+					{Path: "profile.photo.alt", Source: "Zdjęcie", Target: "Photo"},
+					{Path: "profile.tags.0", Source: "Go", Target: "Go"},
+					{Path: "profile.tags.1", Source: "Mikroserwisy"},
+				},
+			},
+		},
+		{
+			name:       "unconfigured language",
+			sourceLang: "pl",
+			targetLang: "de",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := p.Process(context.Background(), &buf, tt.sourceLang, tt.targetLang, translation.FormatXLIFF)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, err := translation.Decode(&buf, translation.FormatXLIFF)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Process() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package get_asset
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/asset"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"golang.org/x/image/draw"
+)
+
+const (
+	maxPixels   = 40_000_000
+	jpegQuality = 85
+)
+
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, data []byte) error
+}
+
+type Asset struct {
+	Data        []byte
+	ContentType string
+	ETag        string
+}
+
+type source struct {
+	path   string
+	format string
+	hash   string
+	width  int
+	height int
+}
+
+type Process struct {
+	sources map[string]source
+	widths  []int
+	cache   Cache
+}
+
+func NewProcess(dir string, widths []int, cache Cache) (*Process, error) {
+	p := &Process{
+		sources: make(map[string]source),
+		widths:  widths,
+		cache:   cache,
+	}
+	if dir == "" {
+		return p, nil
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() && path != dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		name := filepath.ToSlash(rel)
+		if !asset.ValidName(name) {
+			return fmt.Errorf("unsupported asset %s", name)
+		}
+
+		src, err := loadSource(path, name)
+		if err != nil {
+			return err
+		}
+		p.sources[name] = src
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not load assets: %w", err)
+	}
+
+	return p, nil
+}
+
+func loadSource(path, name string) (source, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return source{}, err
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return source{}, fmt.Errorf("asset %s is not a valid image: %w", name, err)
+	}
+	if format != asset.FormatFromName(name) {
+		return source{}, fmt.Errorf("asset %s contains %s data", name, format)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return source{}, fmt.Errorf("asset %s is too large (%dx%d)", name, cfg.Width, cfg.Height)
+	}
+	if _, _, err := image.Decode(bytes.NewReader(data)); err != nil {
+		return source{}, fmt.Errorf("asset %s is not a valid image: %w", name, err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return source{
+		path:   path,
+		format: format,
+		hash:   hex.EncodeToString(sum[:]),
+		width:  cfg.Width,
+		height: cfg.Height,
+	}, nil
+}
+
+func (p *Process) Has(name string) bool {
+	_, ok := p.sources[name]
+	return ok
+}
+
+func (p *Process) Process(ctx context.Context, name string, width int, format string) (*Asset, error) {
+	src, ok := p.sources[name]
+	if !ok {
+		return nil, errors.ErrAssetNotFound
+	}
+
+	outputFormat, ok := asset.ParseOutputFormat(format)
+	if !ok {
+		return nil, errors.ErrInvalidInput
+	}
+	if width != 0 && !slices.Contains(p.widths, width) {
+		return nil, errors.ErrInvalidInput
+	}
+
+	if width == 0 || width > src.width {
+		width = src.width
+	}
+	if outputFormat == "" {
+		outputFormat = src.format
+		if outputFormat == asset.FormatGIF && width != src.width {
+			outputFormat = asset.FormatPNG
+		}
+	}
+
+	if width == src.width && outputFormat == src.format {
+		data, err := os.ReadFile(src.path)
+		if err != nil {
+			return nil, errors.ErrInternalServerError
+		}
+		return &Asset{Data: data, ContentType: asset.ContentType(src.format), ETag: asset.ETag(src.hash, 0, src.format)}, nil
+	}
+
+	etag := asset.ETag(src.hash, width, outputFormat)
+	if data, ok := p.cache.Get(etag); ok {
+		return &Asset{Data: data, ContentType: asset.ContentType(outputFormat), ETag: etag}, nil
+	}
+
+	data, err := render(src, width, outputFormat)
+	if err != nil {
+		log.Printf("ERROR: could not render asset %s: %v", name, err)
+		return nil, errors.ErrInternalServerError
+	}
+	if err := p.cache.Put(etag, data); err != nil {
+		log.Printf("ERROR: could not cache asset %s: %v", name, err)
+	}
+
+	return &Asset{Data: data, ContentType: asset.ContentType(outputFormat), ETag: etag}, nil
+}
+
+func render(src source, width int, format string) ([]byte, error) {
+	f, err := os.Open(src.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if width != src.width {
+		dst := image.NewNRGBA(image.Rect(0, 0, width, asset.ScaledHeight(src.width, src.height, width)))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Over, nil)
+		img = dst
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case asset.FormatJPEG:
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.White, image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+		err = jpeg.Encode(&buf, flat, &jpeg.Options{Quality: jpegQuality})
+	case asset.FormatPNG:
+		err = png.Encode(&buf, img)
+	default:
+		err = fmt.Errorf("unsupported output format %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
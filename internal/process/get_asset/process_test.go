@@ -0,0 +1,203 @@
+package get_asset
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type mockCache struct {
+	entries map[string][]byte
+	puts    int
+}
+
+func (m *mockCache) Get(key string) ([]byte, bool) {
+	data, ok := m.entries[key]
+	return data, ok
+}
+
+func (m *mockCache) Put(key string, data []byte) error {
+	m.entries[key] = data
+	m.puts++
+	return nil
+}
+
+func writePNG(t *testing.T, path string, w, h int) {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, 0, color.NRGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	os.MkdirAll(filepath.Dir(path), 0o755)
+	os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+func TestNewProcess(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string][]byte
+		wantErr bool
+	}{
+		{
+			name: "valid images and hidden files",
+			files: map[string][]byte{
+				".gitkeep": nil,
+			},
+		},
+		{
+			name:    "corrupt image",
+			files:   map[string][]byte{"broken.png": []byte("not a png")},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported file",
+			files:   map[string][]byte{"notes.txt": []byte("hello")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writePNG(t, filepath.Join(dir, "projects", "cover.png"), 4, 4)
+			for name, data := range tt.files {
+				os.WriteFile(filepath.Join(dir, name), data, 0o644)
+			}
+
+			p, err := NewProcess(dir, []int{2}, &mockCache{entries: map[string][]byte{}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewProcess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !p.Has("projects/cover.png") {
+				t.Error("NewProcess() did not load nested asset")
+			}
+		})
+	}
+
+	t.Run("mismatched extension", func(t *testing.T) {
+		dir := t.TempDir()
+		writePNG(t, filepath.Join(dir, "photo.jpg"), 4, 4)
+		if _, err := NewProcess(dir, nil, &mockCache{entries: map[string][]byte{}}); err == nil {
+			t.Error("NewProcess() expected error for png data in .jpg file")
+		}
+	})
+}
+
+func TestProcess_GetAsset(t *testing.T) {
+	dir := t.TempDir()
+	writePNG(t, filepath.Join(dir, "profile.png"), 400, 200)
+	original, _ := os.ReadFile(filepath.Join(dir, "profile.png"))
+
+	cache := &mockCache{entries: map[string][]byte{}}
+	p, err := NewProcess(dir, []int{100, 800}, cache)
+	if err != nil {
+		t.Fatalf("NewProcess() error = %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		asset           string
+		width           int
+		format          string
+		wantErr         error
+		wantContentType string
+		wantSize        image.Point
+	}{
+		{
+			name:            "original",
+			asset:           "profile.png",
+			wantContentType: "image/png",
+			wantSize:        image.Point{X: 400, Y: 200},
+		},
+		{
+			name:            "resized",
+			asset:           "profile.png",
+			width:           100,
+			wantContentType: "image/png",
+			wantSize:        image.Point{X: 100, Y: 50},
+		},
+		{
+			name:            "resized and converted",
+			asset:           "profile.png",
+			width:           100,
+			format:          "jpg",
+			wantContentType: "image/jpeg",
+			wantSize:        image.Point{X: 100, Y: 50},
+		},
+		{
+			name:            "no upscaling",
+			asset:           "profile.png",
+			width:           800,
+			wantContentType: "image/png",
+			wantSize:        image.Point{X: 400, Y: 200},
+		},
+		{
+			name:    "width not allowed",
+			asset:   "profile.png",
+			width:   123,
+			wantErr: errors.ErrInvalidInput,
+		},
+		{
+			name:    "unsupported format",
+			asset:   "profile.png",
+			format:  "bmp",
+			wantErr: errors.ErrInvalidInput,
+		},
+		{
+			name:    "unknown asset",
+			asset:   "missing.png",
+			wantErr: errors.ErrAssetNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Process(context.Background(), tt.asset, tt.width, tt.format)
+			if err != tt.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.ContentType != tt.wantContentType || got.ETag == "" {
+				t.Errorf("Process() got content type %s, etag %s", got.ContentType, got.ETag)
+			}
+			cfg, _, err := image.DecodeConfig(bytes.NewReader(got.Data))
+			if err != nil {
+				t.Fatalf("Process() returned undecodable data: %v", err)
+			}
+			if cfg.Width != tt.wantSize.X || cfg.Height != tt.wantSize.Y {
+				t.Errorf("Process() size = %dx%d, want %v", cfg.Width, cfg.Height, tt.wantSize)
+			}
+		})
+	}
+
+	t.Run("original is served from disk", func(t *testing.T) {
+		got, _ := p.Process(context.Background(), "profile.png", 0, "png")
+		if !bytes.Equal(got.Data, original) {
+			t.Error("Process() re-encoded the original")
+		}
+	})
+
+	t.Run("variants are cached", func(t *testing.T) {
+		puts := cache.puts
+		first, _ := p.Process(context.Background(), "profile.png", 100, "jpeg")
+		second, _ := p.Process(context.Background(), "profile.png", 100, "jpeg")
+		if cache.puts != puts || first.ETag != second.ETag {
+			t.Errorf("Process() rendered cached variant again (%d puts)", cache.puts-puts)
+		}
+		if _, err := jpeg.Decode(bytes.NewReader(second.Data)); err != nil {
+			t.Errorf("cached variant is not a jpeg: %v", err)
+		}
+	})
+}
@@ -2,19 +2,48 @@ package get_content
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log"
 	"os"
+	"sort"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/analytics"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/asset"
 	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/experiment"
 )
 
+type ExposureRecorder interface {
+	HashIncrement(ctx context.Context, key, field string, n int64) error
+	AddUnique(ctx context.Context, key, member string) error
+}
+
+type AssetCatalog interface {
+	Has(name string) bool
+}
+
+type StatsRecorder interface {
+	Increment(ctx context.Context, fields ...string)
+}
+
+type document struct {
+	raw      []byte
+	tree     any
+	sections []string
+}
+
 type Process struct {
-	content     map[string][]byte
-	defaultLang string
+	content          map[string]document
+	defaultLang      string
+	experiments      []experiment.Experiment
+	exposureRecorder ExposureRecorder
+	statsRecorder    StatsRecorder
 }
 
-func NewProcess(contentFiles map[string]string, defaultLang string) (*Process, error) {
-	content := make(map[string][]byte)
+func NewProcess(contentFiles map[string]string, defaultLang string, assets AssetCatalog, recorder ExposureRecorder, statsRecorder StatsRecorder) (*Process, error) {
+	content := make(map[string]document)
+	experiments := make(map[string]experiment.Experiment)
 
 	for lang, filePath := range contentFiles {
 		file, err := os.ReadFile(filePath)
@@ -22,22 +51,115 @@ func NewProcess(contentFiles map[string]string, defaultLang string) (*Process, e
 			return nil, fmt.Errorf("could not read content file for lang %s: %w", lang, err)
 		}
 
-		content[lang] = file
+		var tree any
+		if err := json.Unmarshal(file, &tree); err != nil {
+			return nil, fmt.Errorf("could not parse content file for lang %s: %w", lang, err)
+		}
+
+		for _, name := range asset.Refs(tree) {
+			if !assets.Has(name) {
+				return nil, fmt.Errorf("content file for lang %s references unknown asset %s", lang, name)
+			}
+		}
+
+		found, err := experiment.Extract(tree)
+		if err != nil {
+			return nil, fmt.Errorf("content file for lang %s: %w", lang, err)
+		}
+		for _, e := range found {
+			if known, ok := experiments[e.Name]; ok && !known.SameVariants(e) {
+				return nil, fmt.Errorf("%w: %s differs between languages", experiment.ErrInvalidExperiment, e.Name)
+			}
+			experiments[e.Name] = e
+		}
+
+		doc := document{raw: file, sections: sectionFields(tree)}
+		if len(found) > 0 {
+			doc.tree = tree
+		}
+		content[lang] = doc
+	}
+
+	p := &Process{
+		content:          content,
+		defaultLang:      defaultLang,
+		exposureRecorder: recorder,
+		statsRecorder:    statsRecorder,
+	}
+	for _, e := range experiments {
+		p.experiments = append(p.experiments, e)
+	}
+	sort.Slice(p.experiments, func(i, j int) bool { return p.experiments[i].Name < p.experiments[j].Name })
+
+	return p, nil
+}
+
+func sectionFields(tree any) []string {
+	sections, _ := tree.(map[string]any)
+	fields := make([]string, 0, len(sections))
+	for section := range sections {
+		fields = append(fields, analytics.ContentSection(section))
 	}
+	sort.Strings(fields)
+
+	return fields
+}
 
-	return &Process{
-		content:     content,
-		defaultLang: defaultLang,
-	}, nil
+func (p *Process) Experiments() []experiment.Experiment {
+	return p.experiments
 }
 
-func (p *Process) Process(ctx context.Context, lang string) ([]byte, error) {
-	if content, ok := p.content[lang]; ok {
-		return content, nil
+func (p *Process) Process(ctx context.Context, lang, visitorID string) ([]byte, []experiment.Assignment, error) {
+	servedLang := lang
+	doc, ok := p.content[lang]
+	if !ok {
+		servedLang = p.defaultLang
+		doc, ok = p.content[p.defaultLang]
+	}
+	if !ok {
+		return nil, nil, errors.ErrContentNotFound
+	}
+
+	fields := []string{analytics.ContentRequested(lang), analytics.ContentServed(servedLang)}
+	if servedLang != lang {
+		fields = append(fields, analytics.ContentFallback)
+	}
+	fields = append(fields, doc.sections...)
+	p.statsRecorder.Increment(ctx, fields...)
+
+	if doc.tree == nil {
+		return doc.raw, nil, nil
 	}
-	if content, ok := p.content[p.defaultLang]; ok {
-		return content, nil
+
+	var assignments []experiment.Assignment
+	rendered := experiment.Render(doc.tree, func(e experiment.Experiment) experiment.Variant {
+		if visitorID == "" {
+			return e.Variants[0]
+		}
+		v := e.Assign(visitorID)
+		assignments = append(assignments, experiment.Assignment{Experiment: e.Name, Variant: v.ID})
+		return v
+	})
+	sort.Slice(assignments, func(i, j int) bool { return assignments[i].Experiment < assignments[j].Experiment })
+
+	content, err := json.Marshal(rendered)
+	if err != nil {
+		return nil, nil, errors.ErrInternalServerError
 	}
 
-	return nil, errors.ErrContentNotFound
+	p.recordExposures(ctx, visitorID, assignments)
+
+	return content, assignments, nil
+}
+
+func (p *Process) recordExposures(ctx context.Context, visitorID string, assignments []experiment.Assignment) {
+	for _, a := range assignments {
+		if err := p.exposureRecorder.HashIncrement(ctx, experiment.ExposuresKey(a.Experiment), a.Variant, 1); err != nil {
+			log.Printf("ERROR: could not record exposure for experiment %s: %v", a.Experiment, err)
+			continue
+		}
+		if err := p.exposureRecorder.AddUnique(ctx, experiment.VisitorsKey(a.Experiment, a.Variant), visitorID); err != nil {
+			log.Printf("ERROR: could not record visitor for experiment %s: %v", a.Experiment, err)
+		}
+	}
 }
@@ -2,15 +2,71 @@ package get_content
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
+type mockStatsRecorder struct {
+	fields []string
+}
+
+func (m *mockStatsRecorder) Increment(ctx context.Context, fields ...string) {
+	m.fields = append(m.fields, fields...)
+}
+
+type mockExposureRecorder struct {
+	exposures map[string]int64
+	visitors  map[string][]string
+	err       error
+}
+
+func (m *mockExposureRecorder) HashIncrement(ctx context.Context, key, field string, n int64) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.exposures == nil {
+		m.exposures = map[string]int64{}
+	}
+	m.exposures[key+"/"+field] += n
+	return nil
+}
+
+func (m *mockExposureRecorder) AddUnique(ctx context.Context, key, member string) error {
+	if m.visitors == nil {
+		m.visitors = map[string][]string{}
+	}
+	m.visitors[key] = append(m.visitors[key], member)
+	return nil
+}
+
+type mockAssetCatalog struct {
+	names map[string]bool
+}
+
+func (m *mockAssetCatalog) Has(name string) bool {
+	return m.names[name]
+}
+
 func TestNewProcess(t *testing.T) {
 	tmpDir := t.TempDir()
 	plPath := filepath.Join(tmpDir, "pl.json")
 	os.WriteFile(plPath, []byte(`{"hello": "cześć"}`), 0644)
+	invalidPath := filepath.Join(tmpDir, "invalid.json")
+	os.WriteFile(invalidPath, []byte(`{"hello": `), 0644)
+	badExperimentPath := filepath.Join(tmpDir, "bad_experiment.json")
+	os.WriteFile(badExperimentPath, []byte(`{"headline": {"variants": [{"id": "a", "weight": 1, "value": "x"}]}}`), 0644)
+	experimentPlPath := filepath.Join(tmpDir, "experiment_pl.json")
+	os.WriteFile(experimentPlPath, []byte(`{"headline": {"variants": [{"id": "a", "weight": 1, "value": "x"}, {"id": "b", "weight": 1, "value": "y"}]}}`), 0644)
+	assetPath := filepath.Join(tmpDir, "asset.json")
+	os.WriteFile(assetPath, []byte(`{"photo": {"asset": "profile.jpg", "alt": "Adrian"}}`), 0644)
+	missingAssetPath := filepath.Join(tmpDir, "missing_asset.json")
+	os.WriteFile(missingAssetPath, []byte(`{"photo": {"asset": "other.jpg"}}`), 0644)
+	experimentEnPath := filepath.Join(tmpDir, "experiment_en.json")
+	os.WriteFile(experimentEnPath, []byte(`{"headline": {"variants": [{"id": "a", "weight": 3, "value": "x"}, {"id": "b", "weight": 1, "value": "y"}]}}`), 0644)
 
 	tests := []struct {
 		name         string
@@ -30,11 +86,41 @@ func TestNewProcess(t *testing.T) {
 			defaultLang:  "en",
 			wantErr:      true,
 		},
+		{
+			name:         "invalid json",
+			contentFiles: map[string]string{"pl": invalidPath},
+			defaultLang:  "pl",
+			wantErr:      true,
+		},
+		{
+			name:         "invalid experiment",
+			contentFiles: map[string]string{"pl": badExperimentPath},
+			defaultLang:  "pl",
+			wantErr:      true,
+		},
+		{
+			name:         "known asset",
+			contentFiles: map[string]string{"pl": assetPath},
+			defaultLang:  "pl",
+			wantErr:      false,
+		},
+		{
+			name:         "unknown asset",
+			contentFiles: map[string]string{"pl": missingAssetPath},
+			defaultLang:  "pl",
+			wantErr:      true,
+		},
+		{
+			name:         "experiment differs between languages",
+			contentFiles: map[string]string{"pl": experimentPlPath, "en": experimentEnPath},
+			defaultLang:  "pl",
+			wantErr:      true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := NewProcess(tt.contentFiles, tt.defaultLang)
+			_, err := NewProcess(tt.contentFiles, tt.defaultLang, &mockAssetCatalog{names: map[string]bool{"profile.jpg": true}}, &mockExposureRecorder{}, &mockStatsRecorder{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewProcess() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -46,40 +132,119 @@ func TestProcess_GetContent(t *testing.T) {
 	tmpDir := t.TempDir()
 	plPath := filepath.Join(tmpDir, "pl.json")
 	enPath := filepath.Join(tmpDir, "en.json")
-	os.WriteFile(plPath, []byte("pl content"), 0644)
-	os.WriteFile(enPath, []byte("en content"), 0644)
-
-	p, _ := NewProcess(map[string]string{"pl": plPath, "en": enPath}, "en")
+	os.WriteFile(plPath, []byte(`{"profile": "pl content", "skills": ["Go"]}`), 0644)
+	os.WriteFile(enPath, []byte(`{"profile": "en content", "skills": ["Go"], "blog": []}`), 0644)
 
 	tests := []struct {
-		name    string
-		lang    string
-		want    string
-		wantErr error
+		name      string
+		lang      string
+		want      string
+		wantErr   error
+		wantStats []string
 	}{
 		{
-			name:    "get existing language",
-			lang:    "pl",
-			want:    "pl content",
-			wantErr: nil,
+			name:      "get existing language",
+			lang:      "pl",
+			want:      `{"profile": "pl content", "skills": ["Go"]}`,
+			wantErr:   nil,
+			wantStats: []string{"content_requested:pl", "content_served:pl", "content_section:profile", "content_section:skills"},
 		},
 		{
-			name:    "fallback to default language",
-			lang:    "de",
-			want:    "en content",
-			wantErr: nil,
+			name:      "fallback to default language counts its sections",
+			lang:      "de",
+			want:      `{"profile": "en content", "skills": ["Go"], "blog": []}`,
+			wantErr:   nil,
+			wantStats: []string{"content_requested:de", "content_served:en", "content_fallback", "content_section:blog", "content_section:profile", "content_section:skills"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := p.Process(context.Background(), tt.lang)
+			stats := &mockStatsRecorder{}
+			p, _ := NewProcess(map[string]string{"pl": plPath, "en": enPath}, "en", &mockAssetCatalog{}, &mockExposureRecorder{}, stats)
+
+			got, _, err := p.Process(context.Background(), tt.lang, "")
 			if err != tt.wantErr {
 				t.Errorf("Process() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			if string(got) != tt.want {
 				t.Errorf("Process() got = %v, want %v", string(got), tt.want)
 			}
+			if !reflect.DeepEqual(stats.fields, tt.wantStats) {
+				t.Errorf("Process() stats = %v, want %v", stats.fields, tt.wantStats)
+			}
 		})
 	}
 }
+
+func TestProcess_Experiments(t *testing.T) {
+	tmpDir := t.TempDir()
+	plPath := filepath.Join(tmpDir, "pl.json")
+	os.WriteFile(plPath, []byte(`{
+  "profile": {
+    "name": "Adrian",
+    "headline": {
+      "experiment": "headline",
+      "variants": [
+        {"id": "control", "weight": 1, "value": "Backend Developer"},
+        {"id": "go", "weight": 1, "value": "Go Engineer"}
+      ]
+    }
+  }
+}`), 0644)
+
+	recorder := &mockExposureRecorder{}
+	p, err := NewProcess(map[string]string{"pl": plPath}, "pl", &mockAssetCatalog{}, recorder, &mockStatsRecorder{})
+	if err != nil {
+		t.Fatalf("NewProcess() error = %v", err)
+	}
+	if got := p.Experiments(); len(got) != 1 || got[0].Name != "headline" || got[0].Path != "profile.headline" {
+		t.Fatalf("Experiments() got = %+v", got)
+	}
+
+	t.Run("anonymous visitor gets control without exposure", func(t *testing.T) {
+		got, assignments, err := p.Process(context.Background(), "pl", "")
+		if err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+		if string(got) != `{"profile":{"headline":"Backend Developer","name":"Adrian"}}` {
+			t.Errorf("Process() got = %s", got)
+		}
+		if len(assignments) != 0 || len(recorder.exposures) != 0 {
+			t.Errorf("Process() recorded exposure for anonymous visitor")
+		}
+	})
+
+	t.Run("visitor assignment is stable and counted", func(t *testing.T) {
+		seen := map[string]bool{}
+		for i := 0; i < 50; i++ {
+			visitor := "visitor-" + string(rune('a'+i%26)) + string(rune('a'+i/26))
+			_, first, _ := p.Process(context.Background(), "pl", visitor)
+			got, second, _ := p.Process(context.Background(), "pl", visitor)
+			if len(first) != 1 || first[0] != second[0] {
+				t.Fatalf("Process() assignment not stable: %v vs %v", first, second)
+			}
+			want := map[string]string{"control": "Backend Developer", "go": "Go Engineer"}[first[0].Variant]
+			if !strings.Contains(string(got), want) {
+				t.Errorf("Process() content %s does not contain %s", got, want)
+			}
+			seen[first[0].Variant] = true
+		}
+		if !seen["control"] || !seen["go"] {
+			t.Errorf("Process() did not spread visitors across variants: %v", seen)
+		}
+
+		total := recorder.exposures["experiment:headline:exposures/control"] + recorder.exposures["experiment:headline:exposures/go"]
+		if total != 100 {
+			t.Errorf("recorded %d exposures, want 100", total)
+		}
+	})
+
+	t.Run("recorder failure does not fail the request", func(t *testing.T) {
+		failing := &mockExposureRecorder{err: errors.New("redis down")}
+		p, _ := NewProcess(map[string]string{"pl": plPath}, "pl", &mockAssetCatalog{}, failing, &mockStatsRecorder{})
+		if _, _, err := p.Process(context.Background(), "pl", "visitor"); err != nil {
+			t.Errorf("Process() error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package get_cv_stamp
+
+import (
+	"context"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pdfstamp"
+)
+
+type StampStore interface {
+	Stamp(ctx context.Context, id string) (pdfstamp.Stamp, bool, error)
+}
+
+type Process struct {
+	stampStore StampStore
+}
+
+func NewProcess(stampStore StampStore) *Process {
+	return &Process{
+		stampStore: stampStore,
+	}
+}
+
+func (p *Process) Process(ctx context.Context, id string) (pdfstamp.Stamp, error) {
+	if id == "" {
+		return pdfstamp.Stamp{}, errors.ErrInvalidInput
+	}
+
+	stamp, found, err := p.stampStore.Stamp(ctx, id)
+	if err != nil {
+		return pdfstamp.Stamp{}, errors.ErrInternalServerError
+	}
+	if !found {
+		return pdfstamp.Stamp{}, errors.ErrStampNotFound
+	}
+
+	return stamp, nil
+}
@@ -0,0 +1,67 @@
+package get_cv_stamp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pdfstamp"
+)
+
+type mockStampStore struct {
+	stamps map[string]pdfstamp.Stamp
+	err    error
+}
+
+func (m *mockStampStore) Stamp(ctx context.Context, id string) (pdfstamp.Stamp, bool, error) {
+	stamp, ok := m.stamps[id]
+	return stamp, ok, m.err
+}
+
+func TestProcess_Process(t *testing.T) {
+	stamp := pdfstamp.Stamp{ID: "4b1c", Token: "3f9a", Credential: "acme", StampedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name    string
+		id      string
+		err     error
+		want    pdfstamp.Stamp
+		wantErr error
+	}{
+		{
+			name: "found",
+			id:   "4b1c",
+			want: stamp,
+		},
+		{
+			name:    "unknown",
+			id:      "9d0e",
+			wantErr: appErrors.ErrStampNotFound,
+		},
+		{
+			name:    "missing id",
+			wantErr: appErrors.ErrInvalidInput,
+		},
+		{
+			name:    "redis error",
+			id:      "4b1c",
+			err:     errors.New("redis error"),
+			wantErr: appErrors.ErrInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProcess(&mockStampStore{stamps: map[string]pdfstamp.Stamp{"4b1c": stamp}, err: tt.err})
+			got, err := p.Process(context.Background(), tt.id)
+			if err != tt.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Process() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package get_cv_token
+
+import (
+	"context"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+	serviceRedis "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/redis"
+)
+
+type State struct {
+	Request         Request
+	Client          string
+	FilePath        string
+	CaptchaVerified bool
+	Credential      string
+	Captcha         serviceRedis.RedeemedCaptcha
+	Issue           cvtoken.Issue
+	Emailed         bool
+	Lockout         lockout.State
+	Locked          bool
+	FailedStep      string
+}
+
+type Step interface {
+	Run(ctx context.Context, s *State) error
+}
+
+type Compensator interface {
+	Compensate(ctx context.Context, s *State)
+}
+
+type Finisher interface {
+	Finish(ctx context.Context, s *State, err error)
+}
+
+type stage struct {
+	name string
+	step Step
+}
+
+func (p *Process) run(ctx context.Context, s *State) error {
+	var err error
+	completed := 0
+	for _, st := range p.stages {
+		if err = st.step.Run(ctx, s); err != nil {
+			s.FailedStep = st.name
+			break
+		}
+		completed++
+	}
+
+	if err != nil {
+		for i := completed - 1; i >= 0; i-- {
+			if c, ok := p.stages[i].step.(Compensator); ok {
+				c.Compensate(ctx, s)
+			}
+		}
+	}
+
+	for _, st := range p.stages {
+		if f, ok := st.step.(Finisher); ok {
+			f.Finish(ctx, s, err)
+		}
+	}
+
+	return err
+}
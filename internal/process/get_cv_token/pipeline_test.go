@@ -0,0 +1,195 @@
+package get_cv_token
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pipeline"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pow"
+)
+
+type pipelineMocks struct {
+	captchaCalls int
+	passwordIDs  []string
+	redeemCalls  int
+	validate     *mockValidatePasswordTask
+	redeem       *mockRedeemCaptchaTask
+	lockout      *mockLockoutTask
+	audit        *mockAuditRecorder
+	notifier     *mockNotifier
+}
+
+func newPipelineProcess(t *testing.T, steps []pipeline.Step, password func() (string, error), createErr error) (*Process, *pipelineMocks) {
+	t.Helper()
+	m := &pipelineMocks{lockout: &mockLockoutTask{}, audit: &mockAuditRecorder{}, notifier: &mockNotifier{}}
+	m.validate = &mockValidatePasswordTask{executeFunc: func(ctx context.Context, p, l, id string) (string, error) {
+		m.passwordIDs = append(m.passwordIDs, id)
+		return password()
+	}}
+	m.redeem = &mockRedeemCaptchaTask{executeFunc: func(ctx context.Context, id string) error {
+		m.redeemCalls++
+		return nil
+	}}
+
+	p, err := NewProcess(
+		steps,
+		&mockRateLimitTask{},
+		m.lockout,
+		&mockVerifyPowTask{err: errors.New("pow must be disabled")},
+		&mockVerifyCaptchaTask{executeFunc: func(ctx context.Context, id string) error {
+			m.captchaCalls++
+			return nil
+		}},
+		m.validate,
+		m.redeem,
+		&mockCreateTokenTask{executeFunc: func(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
+			if createErr != nil {
+				return cvtoken.Issue{}, createErr
+			}
+			return cvtoken.Issue{Token: "token", Metadata: metadata}, nil
+		}},
+		&mockEmailLinkTask{},
+		&mockCreateTokenTask{executeFunc: func(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
+			return cvtoken.Issue{Token: "emailed-token", Metadata: metadata}, nil
+		}},
+		map[string]string{"pl": "/app/private/pl_cv.pdf"},
+		&mockStatsRecorder{},
+		m.audit,
+		m.notifier,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p, m
+}
+
+func withoutSteps(disabled ...string) []pipeline.Step {
+	steps := pipeline.Default()
+	for i := range steps {
+		for _, name := range disabled {
+			if steps[i].Name == name {
+				steps[i].Enabled = false
+			}
+		}
+	}
+
+	return steps
+}
+
+func auditTypes(r *mockAuditRecorder) []audit.EventType {
+	var types []audit.EventType
+	for _, e := range r.events {
+		types = append(types, e.Type)
+	}
+
+	return types
+}
+
+var pipelineRequest = Request{Password: "pass", Lang: "pl", CaptchaID: "id", Client: "client", IP: "203.0.113.7", Pow: pow.Solution{}}
+
+func TestProcess_Pipeline_DisabledSteps(t *testing.T) {
+	p, m := newPipelineProcess(t, withoutSteps(pipeline.StepPow, pipeline.StepCaptcha, pipeline.StepConsumeCaptcha), func() (string, error) { return "acme", nil }, nil)
+
+	token, err := p.Process(context.Background(), pipelineRequest)
+	if err != nil || token != "token" {
+		t.Fatalf("Process() = %s, %v, want token", token, err)
+	}
+	if m.captchaCalls != 0 || m.redeemCalls != 0 {
+		t.Errorf("Process() verified %d and redeemed %d captchas, want none", m.captchaCalls, m.redeemCalls)
+	}
+	if !reflect.DeepEqual(m.passwordIDs, []string{""}) {
+		t.Errorf("Process() passed captcha ids %v to the password step, want none", m.passwordIDs)
+	}
+	if got := auditTypes(m.audit); !reflect.DeepEqual(got, []audit.EventType{audit.EventTokenIssued}) {
+		t.Errorf("Process() audit = %v, want [token_issued]", got)
+	}
+}
+
+func TestProcess_Pipeline_AuditDisabled(t *testing.T) {
+	p, m := newPipelineProcess(t, withoutSteps(pipeline.StepPow, pipeline.StepAudit), func() (string, error) { return "", appErrors.ErrInvalidPassword }, nil)
+
+	if _, err := p.Process(context.Background(), pipelineRequest); !errors.Is(err, appErrors.ErrInvalidPassword) {
+		t.Fatalf("Process() error = %v, want %v", err, appErrors.ErrInvalidPassword)
+	}
+	if len(m.audit.events) != 0 {
+		t.Errorf("Process() recorded %d audit events, want none", len(m.audit.events))
+	}
+	if len(m.notifier.events) != 2 || m.notifier.events[1].Type != audit.EventPasswordFailed {
+		t.Errorf("Process() notified %v, want the outcome without the audit step", m.notifier.events)
+	}
+	if m.lockout.failures != 1 {
+		t.Errorf("Process() recorded %d lockout failures, want 1", m.lockout.failures)
+	}
+}
+
+func TestProcess_Pipeline_NotifyDisabled(t *testing.T) {
+	p, m := newPipelineProcess(t, withoutSteps(pipeline.StepPow, pipeline.StepNotify), func() (string, error) { return "acme", nil }, nil)
+
+	if _, err := p.Process(context.Background(), pipelineRequest); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if len(m.notifier.events) != 0 {
+		t.Errorf("Process() notified %v, want nothing", m.notifier.events)
+	}
+	if got := auditTypes(m.audit); !reflect.DeepEqual(got, []audit.EventType{audit.EventCaptchaVerified, audit.EventTokenIssued}) {
+		t.Errorf("Process() audit = %v", got)
+	}
+}
+
+func TestProcess_Pipeline_Compensation(t *testing.T) {
+	tests := []struct {
+		name         string
+		password     func() (string, error)
+		createErr    error
+		wantErr      error
+		wantReleased []string
+		wantRestored []string
+	}{
+		{
+			name:         "token issue failure restores captcha and releases credential",
+			password:     func() (string, error) { return "acme", nil },
+			createErr:    appErrors.ErrInternalServerError,
+			wantErr:      appErrors.ErrInternalServerError,
+			wantReleased: []string{"acme"},
+			wantRestored: []string{"id"},
+		},
+		{
+			name:     "rejected credential is not released",
+			password: func() (string, error) { return "acme", appErrors.ErrCredentialExhausted },
+			wantErr:  appErrors.ErrCredentialExhausted,
+		},
+		{
+			name:     "success runs no compensation",
+			password: func() (string, error) { return "acme", nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, m := newPipelineProcess(t, withoutSteps(pipeline.StepPow), tt.password, tt.createErr)
+
+			if _, err := p.Process(context.Background(), pipelineRequest); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Process() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(m.validate.released, tt.wantReleased) {
+				t.Errorf("Process() released %v, want %v", m.validate.released, tt.wantReleased)
+			}
+			if !reflect.DeepEqual(m.redeem.restored, tt.wantRestored) {
+				t.Errorf("Process() restored %v, want %v", m.redeem.restored, tt.wantRestored)
+			}
+		})
+	}
+}
+
+func TestNewProcess_InvalidPipeline(t *testing.T) {
+	_, err := NewProcess(withoutSteps(pipeline.StepPassword), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	if err == nil {
+		t.Error("NewProcess() error = nil, want an invalid pipeline error")
+	}
+}
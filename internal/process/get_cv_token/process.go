@@ -2,65 +2,163 @@ package get_cv_token
 
 import (
 	"context"
+	stdErrors "errors"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/analytics"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
 	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pipeline"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pow"
+	serviceRedis "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/redis"
 )
 
+type RateLimitTask interface {
+	Execute(ctx context.Context, ip, captchaID string) error
+}
+
+type LockoutTask interface {
+	Check(ctx context.Context, client string) error
+	RecordFailure(ctx context.Context, client string) (lockout.State, bool)
+	Reset(ctx context.Context, client string)
+}
+
+type VerifyPowTask interface {
+	Execute(ctx context.Context, solution pow.Solution) error
+}
+
 type VerifyCaptchaTask interface {
 	Execute(ctx context.Context, captchaID string) error
 }
 
 type ValidatePasswordTask interface {
-	Execute(ctx context.Context, password, captchaID string) error
+	Execute(ctx context.Context, password, lang, captchaID string) (string, error)
+	Release(ctx context.Context, credentialName string)
 }
 
-type DeleteCaptchaTask interface {
-	Execute(ctx context.Context, captchaID string) error
+type RedeemCaptchaTask interface {
+	Execute(ctx context.Context, captchaID string) (serviceRedis.RedeemedCaptcha, error)
+	Restore(ctx context.Context, captchaID string, captcha serviceRedis.RedeemedCaptcha)
 }
 
 type CreateTokenTask interface {
-	Execute(ctx context.Context) (string, error)
+	Execute(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error)
+}
+
+type EmailLinkTask interface {
+	Validate(email string) (string, error)
+	Execute(ctx context.Context, email string, issue cvtoken.Issue) error
+}
+
+type StatsRecorder interface {
+	Increment(ctx context.Context, fields ...string)
+}
+
+type AuditRecorder interface {
+	Record(ctx context.Context, event audit.Event)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, event audit.Event)
+}
+
+type Request struct {
+	Password  string
+	Lang      string
+	CaptchaID string
+	Client    string
+	IP        string
+	Pow       pow.Solution
+	Email     string
 }
 
 type Process struct {
-	verifyCaptchaTask    VerifyCaptchaTask
-	validatePasswordTask ValidatePasswordTask
-	deleteCaptchaTask    DeleteCaptchaTask
-	createTokenTask      CreateTokenTask
-	cvFilePaths          map[string]string
-}
-
-func NewProcess(verifyCaptchaTask VerifyCaptchaTask, validatePasswordTask ValidatePasswordTask, deleteCaptchaTask DeleteCaptchaTask, createTokenTask CreateTokenTask, cvFilePaths map[string]string) *Process {
-	return &Process{
-		verifyCaptchaTask:    verifyCaptchaTask,
-		validatePasswordTask: validatePasswordTask,
-		deleteCaptchaTask:    deleteCaptchaTask,
-		createTokenTask:      createTokenTask,
-		cvFilePaths:          cvFilePaths,
+	stages        []stage
+	emailLinkTask EmailLinkTask
+	cvFilePaths   map[string]string
+	statsRecorder StatsRecorder
+}
+
+func NewProcess(steps []pipeline.Step, rateLimitTask RateLimitTask, lockoutTask LockoutTask, verifyPowTask VerifyPowTask, verifyCaptchaTask VerifyCaptchaTask, validatePasswordTask ValidatePasswordTask, redeemCaptchaTask RedeemCaptchaTask, createTokenTask CreateTokenTask, emailLinkTask EmailLinkTask, emailTokenTask CreateTokenTask, cvFilePaths map[string]string, statsRecorder StatsRecorder, auditRecorder AuditRecorder, notifier Notifier) (*Process, error) {
+	if err := pipeline.Validate(steps); err != nil {
+		return nil, err
+	}
+
+	registry := map[string]Step{
+		pipeline.StepRateLimit:      &rateLimitStep{task: rateLimitTask},
+		pipeline.StepLockout:        &lockoutStep{task: lockoutTask},
+		pipeline.StepPow:            &powStep{task: verifyPowTask},
+		pipeline.StepCaptcha:        &captchaStep{task: verifyCaptchaTask},
+		pipeline.StepPassword:       &passwordStep{task: validatePasswordTask},
+		pipeline.StepConsumeCaptcha: &consumeCaptchaStep{task: redeemCaptchaTask},
+		pipeline.StepIssueToken:     &issueTokenStep{task: createTokenTask, emailTask: emailTokenTask},
+		pipeline.StepEmailLink:      &emailLinkStep{task: emailLinkTask},
+		pipeline.StepNotify:         &notifyStep{notifier: notifier},
+		pipeline.StepAudit:          &auditStep{recorder: auditRecorder},
+	}
+
+	var stages []stage
+	for _, s := range steps {
+		if s.Enabled {
+			stages = append(stages, stage{name: s.Name, step: registry[s.Name]})
+		}
 	}
+
+	p := &Process{
+		stages:        stages,
+		cvFilePaths:   cvFilePaths,
+		statsRecorder: statsRecorder,
+	}
+	if pipeline.Enabled(steps, pipeline.StepEmailLink) {
+		p.emailLinkTask = emailLinkTask
+	}
+
+	return p, nil
 }
 
-func (p *Process) Process(ctx context.Context, password, lang, captchaID string) (string, error) {
-	if _, ok := p.cvFilePaths[lang]; !ok {
+func (p *Process) Process(ctx context.Context, req Request) (token string, err error) {
+	defer func() {
+		p.statsRecorder.Increment(ctx, analytics.CvTokenOutcome(outcome(err)))
+	}()
+
+	filePath, ok := p.cvFilePaths[req.Lang]
+	if !ok {
 		return "", errors.ErrUnsupportedLanguage
 	}
 
-	if err := p.verifyCaptchaTask.Execute(ctx, captchaID); err != nil {
-		return "", err
+	if req.Email != "" {
+		if p.emailLinkTask == nil {
+			return "", errors.ErrCVEmailUnavailable
+		}
+		if req.Email, err = p.emailLinkTask.Validate(req.Email); err != nil {
+			return "", err
+		}
 	}
 
-	if err := p.validatePasswordTask.Execute(ctx, password, captchaID); err != nil {
+	state := &State{
+		Request:  req,
+		Client:   cvtoken.Fingerprint(req.Client),
+		FilePath: filePath,
+	}
+	if err := p.run(ctx, state); err != nil {
 		return "", err
 	}
 
-	if err := p.deleteCaptchaTask.Execute(ctx, captchaID); err != nil {
-		return "", err
+	p.statsRecorder.Increment(ctx, analytics.CvTokenCredential(state.Credential))
+
+	return state.Issue.Token, nil
+}
+
+func outcome(err error) string {
+	if err == nil {
+		return analytics.OutcomeIssued
 	}
 
-	token, err := p.createTokenTask.Execute(ctx)
-	if err != nil {
-		return "", err
+	var appErr *errors.AppError
+	if stdErrors.As(err, &appErr) {
+		return appErr.Slug
 	}
 
-	return token, nil
+	return errors.ErrInternalServerError.Slug
 }
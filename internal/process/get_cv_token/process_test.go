@@ -3,9 +3,102 @@ package get_cv_token
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pipeline"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pow"
+	serviceRedis "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/redis"
 )
 
+type mockStatsRecorder struct {
+	mu     sync.Mutex
+	fields []string
+}
+
+func (m *mockStatsRecorder) Increment(ctx context.Context, fields ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fields = append(m.fields, fields...)
+}
+
+type mockAuditRecorder struct {
+	mu     sync.Mutex
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(ctx context.Context, event audit.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+}
+
+type mockNotifier struct {
+	mu     sync.Mutex
+	events []audit.Event
+}
+
+func (m *mockNotifier) Notify(ctx context.Context, event audit.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+}
+
+type mockRateLimitTask struct {
+	err error
+}
+
+func (m *mockRateLimitTask) Execute(ctx context.Context, ip, captchaID string) error {
+	if ip != "203.0.113.7" || captchaID != "id" {
+		return errors.New("request not passed on")
+	}
+	return m.err
+}
+
+type mockLockoutTask struct {
+	err      error
+	locked   bool
+	failures int
+	resets   int
+}
+
+func (m *mockLockoutTask) Check(ctx context.Context, client string) error {
+	if client != cvtoken.Fingerprint("client") {
+		return errors.New("client not passed on")
+	}
+	return m.err
+}
+
+func (m *mockLockoutTask) RecordFailure(ctx context.Context, client string) (lockout.State, bool) {
+	m.failures++
+	if !m.locked {
+		return lockout.State{Client: client, Failures: m.failures}, false
+	}
+	return lockout.State{Client: client, Level: 2, LockedUntil: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}, true
+}
+
+func (m *mockLockoutTask) Reset(ctx context.Context, client string) {
+	m.resets++
+}
+
+type mockVerifyPowTask struct {
+	err error
+}
+
+func (m *mockVerifyPowTask) Execute(ctx context.Context, solution pow.Solution) error {
+	if solution != (pow.Solution{Challenge: "challenge", Signature: "signature", Nonce: "nonce"}) {
+		return errors.New("solution not passed on")
+	}
+	return m.err
+}
+
 type mockVerifyCaptchaTask struct {
 	executeFunc func(ctx context.Context, id string) error
 }
@@ -15,82 +108,480 @@ func (m *mockVerifyCaptchaTask) Execute(ctx context.Context, id string) error {
 }
 
 type mockValidatePasswordTask struct {
-	executeFunc func(ctx context.Context, password, captchaID string) error
+	executeFunc func(ctx context.Context, password, lang, captchaID string) (string, error)
+	mu          sync.Mutex
+	released    []string
+}
+
+func (m *mockValidatePasswordTask) Execute(ctx context.Context, password, lang, captchaID string) (string, error) {
+	return m.executeFunc(ctx, password, lang, captchaID)
 }
 
-func (m *mockValidatePasswordTask) Execute(ctx context.Context, password, captchaID string) error {
-	return m.executeFunc(ctx, password, captchaID)
+func (m *mockValidatePasswordTask) Release(ctx context.Context, credentialName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.released = append(m.released, credentialName)
 }
 
-type mockDeleteCaptchaTask struct {
+type mockRedeemCaptchaTask struct {
 	executeFunc func(ctx context.Context, id string) error
+	restored    []string
 }
 
-func (m *mockDeleteCaptchaTask) Execute(ctx context.Context, id string) error {
-	return m.executeFunc(ctx, id)
+func (m *mockRedeemCaptchaTask) Execute(ctx context.Context, id string) (serviceRedis.RedeemedCaptcha, error) {
+	if err := m.executeFunc(ctx, id); err != nil {
+		return serviceRedis.RedeemedCaptcha{}, err
+	}
+	return serviceRedis.RedeemedCaptcha{Solved: true, Value: id, TTL: time.Minute}, nil
+}
+
+func (m *mockRedeemCaptchaTask) Restore(ctx context.Context, id string, captcha serviceRedis.RedeemedCaptcha) {
+	if captcha.Value == id {
+		m.restored = append(m.restored, id)
+	}
 }
 
 type mockCreateTokenTask struct {
-	executeFunc func(ctx context.Context) (string, error)
+	executeFunc func(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error)
 }
 
-func (m *mockCreateTokenTask) Execute(ctx context.Context) (string, error) {
-	return m.executeFunc(ctx)
+func (m *mockCreateTokenTask) Execute(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
+	return m.executeFunc(ctx, metadata)
+}
+
+type mockEmailLinkTask struct {
+	validateErr error
+	executeErr  error
+	sent        []string
+}
+
+func (m *mockEmailLinkTask) Validate(email string) (string, error) {
+	if m.validateErr != nil {
+		return "", m.validateErr
+	}
+	return strings.ToLower(email), nil
+}
+
+func (m *mockEmailLinkTask) Execute(ctx context.Context, email string, issue cvtoken.Issue) error {
+	if issue.Token != "emailed-token" {
+		return errors.New("issue not passed on")
+	}
+	m.sent = append(m.sent, email)
+	return m.executeErr
 }
 
+var solution = pow.Solution{Challenge: "challenge", Signature: "signature", Nonce: "nonce"}
+
 func TestProcess_Process(t *testing.T) {
-	paths := map[string]string{"pl": "path"}
+	expiresAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	paths := map[string]string{"pl": "/app/private/pl_cv.pdf"}
 	tests := []struct {
 		name                 string
 		lang                 string
+		rateLimitErr         error
+		lockoutErr           error
+		lockoutLocks         bool
+		powErr               error
 		verifyCaptchaFunc    func(context.Context, string) error
-		validatePasswordFunc func(context.Context, string, string) error
-		deleteCaptchaFunc    func(context.Context, string) error
-		createTokenFunc      func(context.Context) (string, error)
+		validatePasswordFunc func(context.Context, string, string, string) (string, error)
+		redeemCaptchaFunc    func(context.Context, string) error
+		createTokenFunc      func(context.Context, cvtoken.Metadata) (cvtoken.Issue, error)
 		wantErr              bool
+		wantStats            []string
+		wantAudit            []audit.EventType
+		wantFailures         int
+		wantResets           int
 	}{
 		{
 			name:                 "success",
 			lang:                 "pl",
 			verifyCaptchaFunc:    func(ctx context.Context, id string) error { return nil },
-			validatePasswordFunc: func(ctx context.Context, p, id string) error { return nil },
-			deleteCaptchaFunc:    func(ctx context.Context, id string) error { return nil },
-			createTokenFunc:      func(ctx context.Context) (string, error) { return "token", nil },
-			wantErr:              false,
+			validatePasswordFunc: func(ctx context.Context, p, l, id string) (string, error) { return "acme", nil },
+			redeemCaptchaFunc:    func(ctx context.Context, id string) error { return nil },
+			createTokenFunc: func(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
+				want := cvtoken.Metadata{
+					Lang:        "pl",
+					Document:    "pl_cv.pdf",
+					Credential:  "acme",
+					CaptchaID:   "id",
+					Fingerprint: cvtoken.Fingerprint("client"),
+				}
+				if metadata != want {
+					return cvtoken.Issue{}, errors.New("metadata not passed on")
+				}
+				return cvtoken.Issue{Token: "token", Metadata: metadata, ExpiresAt: expiresAt}, nil
+			},
+			wantErr:    false,
+			wantStats:  []string{"cv_token_credential:acme", "cv_token:issued"},
+			wantAudit:  []audit.EventType{audit.EventCaptchaVerified, audit.EventTokenIssued},
+			wantResets: 1,
+		},
+		{
+			name:         "rate limited before any captcha work",
+			lang:         "pl",
+			rateLimitErr: appErrors.WithRetryAfter(appErrors.ErrRateLimited, time.Second),
+			wantErr:      true,
+			wantStats:    []string{"cv_token:error_rate_limited"},
+		},
+		{
+			name:       "locked out client before any captcha work",
+			lang:       "pl",
+			lockoutErr: appErrors.WithRetryAfter(appErrors.ErrClientLocked, time.Minute),
+			wantErr:    true,
+			wantStats:  []string{"cv_token:error_cv_locked"},
+		},
+		{
+			name:      "pow rejected before any captcha work",
+			lang:      "pl",
+			powErr:    appErrors.ErrPowWork,
+			wantErr:   true,
+			wantStats: []string{"cv_token:error_pow_work"},
 		},
 		{
-			name:    "unsupported lang",
-			lang:    "en",
-			wantErr: true,
+			name:      "unsupported lang",
+			lang:      "en",
+			wantErr:   true,
+			wantStats: []string{"cv_token:error_message"},
 		},
 		{
 			name:              "captcha fail",
 			lang:              "pl",
 			verifyCaptchaFunc: func(ctx context.Context, id string) error { return errors.New("fail") },
 			wantErr:           true,
+			wantStats:         []string{"cv_token:error_cv_server"},
 		},
 		{
 			name:                 "password fail",
 			lang:                 "pl",
 			verifyCaptchaFunc:    func(ctx context.Context, id string) error { return nil },
-			validatePasswordFunc: func(ctx context.Context, p, id string) error { return errors.New("fail") },
+			validatePasswordFunc: func(ctx context.Context, p, l, id string) (string, error) { return "", appErrors.ErrInvalidPassword },
+			wantErr:              true,
+			wantStats:            []string{"cv_token:error_cv_auth"},
+			wantAudit:            []audit.EventType{audit.EventCaptchaVerified, audit.EventPasswordFailed},
+			wantFailures:         1,
+		},
+		{
+			name:                 "password fail locks client",
+			lang:                 "pl",
+			lockoutLocks:         true,
+			verifyCaptchaFunc:    func(ctx context.Context, id string) error { return nil },
+			validatePasswordFunc: func(ctx context.Context, p, l, id string) (string, error) { return "", appErrors.ErrInvalidPassword },
 			wantErr:              true,
+			wantStats:            []string{"cv_token:error_cv_auth"},
+			wantAudit:            []audit.EventType{audit.EventCaptchaVerified, audit.EventPasswordFailed, audit.EventClientLocked},
+			wantFailures:         1,
+		},
+		{
+			name:                 "captcha redeemed by a concurrent request",
+			lang:                 "pl",
+			verifyCaptchaFunc:    func(ctx context.Context, id string) error { return nil },
+			validatePasswordFunc: func(ctx context.Context, p, l, id string) (string, error) { return "acme", nil },
+			redeemCaptchaFunc:    func(ctx context.Context, id string) error { return appErrors.ErrCaptchaNotFound },
+			wantErr:              true,
+			wantStats:            []string{"cv_token:error_captcha_not_found"},
+			wantAudit:            []audit.EventType{audit.EventCaptchaVerified},
+		},
+		{
+			name:                 "tries exhausted",
+			lang:                 "pl",
+			verifyCaptchaFunc:    func(ctx context.Context, id string) error { return nil },
+			validatePasswordFunc: func(ctx context.Context, p, l, id string) (string, error) { return "", appErrors.ErrNoTriesLeft },
+			wantErr:              true,
+			wantStats:            []string{"cv_token:error_captcha_expired"},
+			wantAudit:            []audit.EventType{audit.EventCaptchaVerified, audit.EventTriesExhausted},
+			wantFailures:         1,
+		},
+		{
+			name:              "credential expired",
+			lang:              "pl",
+			verifyCaptchaFunc: func(ctx context.Context, id string) error { return nil },
+			validatePasswordFunc: func(ctx context.Context, p, l, id string) (string, error) {
+				return "acme", appErrors.ErrCredentialExpired
+			},
+			wantErr:   true,
+			wantStats: []string{"cv_token:error_cv_credential_expired"},
+			wantAudit: []audit.EventType{audit.EventCaptchaVerified, audit.EventPasswordFailed},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := NewProcess(
+			stats := &mockStatsRecorder{}
+			auditRecorder := &mockAuditRecorder{}
+			notifier := &mockNotifier{}
+			lockoutTask := &mockLockoutTask{err: tt.lockoutErr, locked: tt.lockoutLocks}
+			p, err := NewProcess(
+				pipeline.Default(),
+				&mockRateLimitTask{err: tt.rateLimitErr},
+				lockoutTask,
+				&mockVerifyPowTask{err: tt.powErr},
 				&mockVerifyCaptchaTask{executeFunc: tt.verifyCaptchaFunc},
 				&mockValidatePasswordTask{executeFunc: tt.validatePasswordFunc},
-				&mockDeleteCaptchaTask{executeFunc: tt.deleteCaptchaFunc},
+				&mockRedeemCaptchaTask{executeFunc: tt.redeemCaptchaFunc},
 				&mockCreateTokenTask{executeFunc: tt.createTokenFunc},
+				&mockEmailLinkTask{},
+				&mockCreateTokenTask{executeFunc: func(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
+					return cvtoken.Issue{Token: "emailed-token", Metadata: metadata}, nil
+				}},
 				paths,
+				stats,
+				auditRecorder,
+				notifier,
 			)
-			_, err := p.Process(context.Background(), "pass", tt.lang, "id")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = p.Process(context.Background(), Request{Password: "pass", Lang: tt.lang, CaptchaID: "id", Client: "client", IP: "203.0.113.7", Pow: solution})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Process() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if !reflect.DeepEqual(stats.fields, tt.wantStats) {
+				t.Errorf("Process() stats = %v, want %v", stats.fields, tt.wantStats)
+			}
+			var gotAudit []audit.EventType
+			for _, e := range auditRecorder.events {
+				gotAudit = append(gotAudit, e.Type)
+				if e.Lang != tt.lang || e.CaptchaID != "id" || e.Client != cvtoken.Fingerprint("client") {
+					t.Errorf("Process() audit event = %+v", e)
+				}
+			}
+			if !reflect.DeepEqual(gotAudit, tt.wantAudit) {
+				t.Errorf("Process() audit = %v, want %v", gotAudit, tt.wantAudit)
+			}
+			if !reflect.DeepEqual(notifier.events, auditRecorder.events) {
+				t.Errorf("Process() notified %v, want the audited events", notifier.events)
+			}
+			if lockoutTask.failures != tt.wantFailures || lockoutTask.resets != tt.wantResets {
+				t.Errorf("Process() lockout failures = %d, resets = %d, want %d, %d", lockoutTask.failures, lockoutTask.resets, tt.wantFailures, tt.wantResets)
+			}
+		})
+	}
+}
+
+func TestProcess_Process_AuditsIssuedToken(t *testing.T) {
+	expiresAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	auditRecorder := &mockAuditRecorder{}
+	p, err := NewProcess(
+		pipeline.Default(),
+		&mockRateLimitTask{},
+		&mockLockoutTask{},
+		&mockVerifyPowTask{},
+		&mockVerifyCaptchaTask{executeFunc: func(ctx context.Context, id string) error { return nil }},
+		&mockValidatePasswordTask{executeFunc: func(ctx context.Context, p, l, id string) (string, error) { return "acme", nil }},
+		&mockRedeemCaptchaTask{executeFunc: func(ctx context.Context, id string) error { return nil }},
+		&mockCreateTokenTask{executeFunc: func(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
+			return cvtoken.Issue{Token: "token", Metadata: metadata, ExpiresAt: expiresAt}, nil
+		}},
+		&mockEmailLinkTask{},
+		&mockCreateTokenTask{executeFunc: func(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
+			return cvtoken.Issue{Token: "emailed-token", Metadata: metadata}, nil
+		}},
+		map[string]string{"pl": "/app/private/pl_cv.pdf"},
+		&mockStatsRecorder{},
+		auditRecorder,
+		&mockNotifier{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := p.Process(context.Background(), Request{Password: "pass", Lang: "pl", CaptchaID: "id", Client: "client", IP: "203.0.113.7", Pow: solution}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := audit.Event{
+		Type:       audit.EventTokenIssued,
+		Lang:       "pl",
+		Document:   "pl_cv.pdf",
+		Credential: "acme",
+		CaptchaID:  "id",
+		Client:     cvtoken.Fingerprint("client"),
+		Token:      cvtoken.Fingerprint("token"),
+		ExpiresAt:  expiresAt,
+	}
+	if got := auditRecorder.events[len(auditRecorder.events)-1]; got != want {
+		t.Errorf("Process() audit event = %+v, want %+v", got, want)
+	}
+}
+
+func TestProcess_Process_RedeemsCaptchaOnce(t *testing.T) {
+	const requests = 20
+	var mu sync.Mutex
+	captchas := map[string]bool{"id": true}
+	redeem := func(ctx context.Context, id string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !captchas[id] {
+			return appErrors.ErrCaptchaNotFound
+		}
+		delete(captchas, id)
+		return nil
+	}
+
+	validatePasswordTask := &mockValidatePasswordTask{executeFunc: func(ctx context.Context, p, l, id string) (string, error) { return "acme", nil }}
+	p, err := NewProcess(
+		pipeline.Default(),
+		&mockRateLimitTask{},
+		&mockLockoutTask{},
+		&mockVerifyPowTask{},
+		&mockVerifyCaptchaTask{executeFunc: func(ctx context.Context, id string) error { return nil }},
+		validatePasswordTask,
+		&mockRedeemCaptchaTask{executeFunc: redeem},
+		&mockCreateTokenTask{executeFunc: func(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
+			return cvtoken.Issue{Token: "token", Metadata: metadata}, nil
+		}},
+		&mockEmailLinkTask{},
+		&mockCreateTokenTask{executeFunc: func(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
+			return cvtoken.Issue{Token: "emailed-token", Metadata: metadata}, nil
+		}},
+		map[string]string{"pl": "/app/private/pl_cv.pdf"},
+		&mockStatsRecorder{},
+		&mockAuditRecorder{},
+		&mockNotifier{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan error, requests)
+	start := make(chan struct{})
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := p.Process(context.Background(), Request{Password: "pass", Lang: "pl", CaptchaID: "id", Client: "client", IP: "203.0.113.7", Pow: solution})
+			results <- err
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(results)
+
+	issued, lost := 0, 0
+	for err := range results {
+		switch {
+		case err == nil:
+			issued++
+		case errors.Is(err, appErrors.ErrCaptchaNotFound):
+			lost++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if issued != 1 || lost != requests-1 {
+		t.Errorf("issued %d tokens and rejected %d requests, want 1 and %d", issued, lost, requests-1)
+	}
+	if len(validatePasswordTask.released) != lost {
+		t.Errorf("released %d credential uses, want %d", len(validatePasswordTask.released), lost)
+	}
+}
+
+func TestProcess_Process_EmailsLink(t *testing.T) {
+	tests := []struct {
+		name        string
+		steps       []pipeline.Step
+		email       string
+		validateErr error
+		executeErr  error
+		wantErr     error
+		wantToken   string
+		wantSent    []string
+		wantAudit   []audit.EventType
+	}{
+		{
+			name:      "no email requested",
+			steps:     pipeline.Default(),
+			wantToken: "token",
+			wantAudit: []audit.EventType{audit.EventCaptchaVerified, audit.EventTokenIssued},
+		},
+		{
+			name:      "link queued with the email token TTL",
+			steps:     pipeline.Default(),
+			email:     "Anna@Acme.com",
+			wantToken: "emailed-token",
+			wantSent:  []string{"anna@acme.com"},
+			wantAudit: []audit.EventType{audit.EventCaptchaVerified, audit.EventTokenIssued, audit.EventLinkEmailed},
+		},
+		{
+			name:        "email rejected before any captcha work",
+			steps:       pipeline.Default(),
+			email:       "anna@example.com",
+			validateErr: appErrors.ErrCVEmailDomain,
+			wantErr:     appErrors.ErrCVEmailDomain,
+		},
+		{
+			name:    "email step disabled",
+			steps:   withoutSteps(pipeline.StepEmailLink),
+			email:   "anna@acme.com",
+			wantErr: appErrors.ErrCVEmailUnavailable,
+		},
+		{
+			name:       "queue failure still returns the token",
+			steps:      pipeline.Default(),
+			email:      "anna@acme.com",
+			executeErr: appErrors.ErrInternalServerError,
+			wantToken:  "emailed-token",
+			wantSent:   []string{"anna@acme.com"},
+			wantAudit:  []audit.EventType{audit.EventCaptchaVerified, audit.EventTokenIssued},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditRecorder := &mockAuditRecorder{}
+			notifier := &mockNotifier{}
+			emailLinkTask := &mockEmailLinkTask{validateErr: tt.validateErr, executeErr: tt.executeErr}
+			var issued cvtoken.Metadata
+			p, err := NewProcess(
+				tt.steps,
+				&mockRateLimitTask{},
+				&mockLockoutTask{},
+				&mockVerifyPowTask{},
+				&mockVerifyCaptchaTask{executeFunc: func(ctx context.Context, id string) error { return nil }},
+				&mockValidatePasswordTask{executeFunc: func(ctx context.Context, p, l, id string) (string, error) { return "acme", nil }},
+				&mockRedeemCaptchaTask{executeFunc: func(ctx context.Context, id string) error { return nil }},
+				&mockCreateTokenTask{executeFunc: func(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
+					issued = metadata
+					return cvtoken.Issue{Token: "token", Metadata: metadata}, nil
+				}},
+				emailLinkTask,
+				&mockCreateTokenTask{executeFunc: func(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
+					issued = metadata
+					return cvtoken.Issue{Token: "emailed-token", Metadata: metadata}, nil
+				}},
+				map[string]string{"pl": "/app/private/pl_cv.pdf"},
+				&mockStatsRecorder{},
+				auditRecorder,
+				notifier,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token, err := p.Process(context.Background(), Request{Password: "pass", Lang: "pl", CaptchaID: "id", Client: "client", IP: "203.0.113.7", Pow: solution, Email: tt.email})
+			if err != tt.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && token != tt.wantToken {
+				t.Errorf("Process() token = %q, want %q", token, tt.wantToken)
+			}
+			if !reflect.DeepEqual(emailLinkTask.sent, tt.wantSent) {
+				t.Errorf("Process() emailed %v, want %v", emailLinkTask.sent, tt.wantSent)
+			}
+			if got := auditTypes(auditRecorder); !reflect.DeepEqual(got, tt.wantAudit) {
+				t.Errorf("Process() audit = %v, want %v", got, tt.wantAudit)
+			}
+			if tt.wantErr == nil {
+				recipient := issued.Authorize("pl", cvtoken.Fingerprint("recipient-laptop"))
+				if emailed := tt.email != ""; emailed != (recipient == nil) {
+					t.Errorf("Process() download from another client = %v, want allowed only for emailed links", recipient)
+				}
+			}
+			if len(tt.wantSent) > 0 && tt.executeErr == nil && auditRecorder.events[2].Detail != "acme.com" {
+				t.Errorf("Process() link_emailed detail = %q, want the recipient domain", auditRecorder.events[2].Detail)
+			}
 		})
 	}
 }
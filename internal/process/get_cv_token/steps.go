@@ -0,0 +1,228 @@
+package get_cv_token
+
+import (
+	"context"
+	stdErrors "errors"
+	"log"
+	"path/filepath"
+	"strconv"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvmail"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pipeline"
+)
+
+type rateLimitStep struct {
+	task RateLimitTask
+}
+
+func (st *rateLimitStep) Run(ctx context.Context, s *State) error {
+	return st.task.Execute(ctx, s.Request.IP, s.Request.CaptchaID)
+}
+
+type lockoutStep struct {
+	task LockoutTask
+}
+
+func (st *lockoutStep) Run(ctx context.Context, s *State) error {
+	return st.task.Check(ctx, s.Client)
+}
+
+func (st *lockoutStep) Finish(ctx context.Context, s *State, err error) {
+	if err == nil {
+		st.task.Reset(ctx, s.Client)
+		return
+	}
+
+	if wrongPassword(s, err) {
+		s.Lockout, s.Locked = st.task.RecordFailure(ctx, s.Client)
+	}
+}
+
+type powStep struct {
+	task VerifyPowTask
+}
+
+func (st *powStep) Run(ctx context.Context, s *State) error {
+	return st.task.Execute(ctx, s.Request.Pow)
+}
+
+type captchaStep struct {
+	task VerifyCaptchaTask
+}
+
+func (st *captchaStep) Run(ctx context.Context, s *State) error {
+	if err := st.task.Execute(ctx, s.Request.CaptchaID); err != nil {
+		return err
+	}
+
+	s.CaptchaVerified = true
+	return nil
+}
+
+type passwordStep struct {
+	task ValidatePasswordTask
+}
+
+func (st *passwordStep) Run(ctx context.Context, s *State) error {
+	captchaID := ""
+	if s.CaptchaVerified {
+		captchaID = s.Request.CaptchaID
+	}
+
+	credentialName, err := st.task.Execute(ctx, s.Request.Password, s.Request.Lang, captchaID)
+	s.Credential = credentialName
+	if err != nil && credentialName != "" {
+		log.Printf("INFO: rejected CV token request via credential %s: %v", credentialName, err)
+	}
+
+	return err
+}
+
+func (st *passwordStep) Compensate(ctx context.Context, s *State) {
+	st.task.Release(ctx, s.Credential)
+}
+
+type consumeCaptchaStep struct {
+	task RedeemCaptchaTask
+}
+
+func (st *consumeCaptchaStep) Run(ctx context.Context, s *State) error {
+	captcha, err := st.task.Execute(ctx, s.Request.CaptchaID)
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrCaptchaNotFound) {
+			log.Printf("INFO: captcha %s was redeemed by a concurrent CV token request", s.Request.CaptchaID)
+		}
+		return err
+	}
+
+	s.Captcha = captcha
+	return nil
+}
+
+func (st *consumeCaptchaStep) Compensate(ctx context.Context, s *State) {
+	st.task.Restore(ctx, s.Request.CaptchaID, s.Captcha)
+}
+
+type issueTokenStep struct {
+	task      CreateTokenTask
+	emailTask CreateTokenTask
+}
+
+func (st *issueTokenStep) Run(ctx context.Context, s *State) error {
+	metadata := cvtoken.Metadata{
+		Lang:        s.Request.Lang,
+		Document:    filepath.Base(s.FilePath),
+		Credential:  s.Credential,
+		CaptchaID:   s.Request.CaptchaID,
+		Fingerprint: s.Client,
+	}
+	task := st.task
+	if s.Request.Email != "" {
+		// The emailed link is opened by the recipient on their own device, possibly much later.
+		metadata.Fingerprint = ""
+		task = st.emailTask
+	}
+	issue, err := task.Execute(ctx, metadata)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("INFO: issued CV token for lang %s (document %s) via credential %s for captcha %s", metadata.Lang, metadata.Document, metadata.Credential, metadata.CaptchaID)
+	s.Issue = issue
+	return nil
+}
+
+type emailLinkStep struct {
+	task EmailLinkTask
+}
+
+func (st *emailLinkStep) Run(ctx context.Context, s *State) error {
+	if s.Request.Email == "" {
+		return nil
+	}
+
+	if err := st.task.Execute(ctx, s.Request.Email, s.Issue); err != nil {
+		log.Printf("ERROR: could not queue CV link email for token issued via credential %s: %v", s.Credential, err)
+		return nil
+	}
+
+	s.Emailed = true
+	return nil
+}
+
+type auditStep struct {
+	recorder AuditRecorder
+}
+
+func (st *auditStep) Run(ctx context.Context, s *State) error {
+	return nil
+}
+
+func (st *auditStep) Finish(ctx context.Context, s *State, err error) {
+	for _, event := range outcomeEvents(s, err) {
+		st.recorder.Record(ctx, event)
+	}
+}
+
+type notifyStep struct {
+	notifier Notifier
+}
+
+func (st *notifyStep) Run(ctx context.Context, s *State) error {
+	return nil
+}
+
+func (st *notifyStep) Finish(ctx context.Context, s *State, err error) {
+	for _, event := range outcomeEvents(s, err) {
+		st.notifier.Notify(ctx, event)
+	}
+}
+
+func outcomeEvents(s *State, err error) []audit.Event {
+	var events []audit.Event
+	add := func(event audit.Event, eventType audit.EventType, detail string) {
+		event.Type = eventType
+		event.Detail = detail
+		events = append(events, event)
+	}
+
+	event := audit.Event{Lang: s.Request.Lang, CaptchaID: s.Request.CaptchaID, Client: s.Client}
+	if s.CaptchaVerified {
+		add(event, audit.EventCaptchaVerified, "")
+	}
+
+	event.Credential = s.Credential
+	if err == nil {
+		event.Document = s.Issue.Metadata.Document
+		event.Token = cvtoken.Fingerprint(s.Issue.Token)
+		event.ExpiresAt = s.Issue.ExpiresAt
+		add(event, audit.EventTokenIssued, "")
+		if s.Emailed {
+			add(event, audit.EventLinkEmailed, cvmail.Domain(s.Request.Email))
+		}
+		return events
+	}
+
+	if s.FailedStep == pipeline.StepPassword {
+		switch {
+		case stdErrors.Is(err, errors.ErrNoTriesLeft):
+			add(event, audit.EventTriesExhausted, outcome(err))
+		case stdErrors.Is(err, errors.ErrInvalidPassword) || s.Credential != "":
+			add(event, audit.EventPasswordFailed, outcome(err))
+		}
+	}
+	if s.Locked {
+		event.ExpiresAt = s.Lockout.LockedUntil
+		add(event, audit.EventClientLocked, strconv.Itoa(s.Lockout.Level))
+	}
+
+	return events
+}
+
+func wrongPassword(s *State, err error) bool {
+	return s.FailedStep == pipeline.StepPassword && s.Credential == "" &&
+		(stdErrors.Is(err, errors.ErrInvalidPassword) || stdErrors.Is(err, errors.ErrNoTriesLeft))
+}
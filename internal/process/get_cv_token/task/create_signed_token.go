@@ -0,0 +1,42 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type CreateSignedTokenTask struct {
+	key      cvtoken.SigningKey
+	site     string
+	tokenTTL time.Duration
+	now      func() time.Time
+}
+
+func NewCreateSignedTokenTask(key cvtoken.SigningKey, site string, ttl time.Duration) *CreateSignedTokenTask {
+	return &CreateSignedTokenTask{
+		key:      key,
+		site:     site,
+		tokenTTL: ttl,
+		now:      time.Now,
+	}
+}
+
+func (t *CreateSignedTokenTask) Execute(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
+	nonce, err := cvtoken.NewNonce()
+	if err != nil {
+		return cvtoken.Issue{}, errors.ErrInternalServerError
+	}
+
+	now := t.now().UTC()
+	metadata.IssuedAt = now
+	expiresAt := now.Add(t.tokenTTL)
+	token, err := cvtoken.Sign(t.key, cvtoken.Claims{Metadata: metadata, Site: t.site, ExpiresAt: expiresAt, Nonce: nonce})
+	if err != nil {
+		return cvtoken.Issue{}, errors.ErrInternalServerError
+	}
+
+	return cvtoken.Issue{Token: token, Metadata: metadata, ExpiresAt: expiresAt}, nil
+}
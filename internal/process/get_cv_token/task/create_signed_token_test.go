@@ -0,0 +1,38 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+)
+
+func TestCreateSignedTokenTask_Execute(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	key := cvtoken.SigningKey{ID: "2026-03", Secret: bytes.Repeat([]byte("k"), 32)}
+	metadata := cvtoken.Metadata{Lang: "pl", Document: "pl_cv.pdf", Credential: "acme-recruiter", CaptchaID: "captcha-1"}
+
+	task := NewCreateSignedTokenTask(key, "main", time.Minute)
+	task.now = func() time.Time { return now }
+
+	issue, err := task.Execute(context.Background(), metadata)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	other, _ := task.Execute(context.Background(), metadata)
+
+	claims, err := cvtoken.Verify([]cvtoken.SigningKey{key}, issue.Token, now)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	want := metadata
+	want.IssuedAt = now
+	if claims.Metadata != want || claims.Site != "main" || issue.Metadata != want || !claims.ExpiresAt.Equal(now.Add(time.Minute)) || !issue.ExpiresAt.Equal(claims.ExpiresAt) {
+		t.Errorf("Execute() claims = %+v, want %+v expiring %v", claims, want, now.Add(time.Minute))
+	}
+	if otherClaims, _ := cvtoken.Verify([]cvtoken.SigningKey{key}, other.Token, now); otherClaims.Nonce == claims.Nonce {
+		t.Error("Execute() must use a fresh nonce per token")
+	}
+}
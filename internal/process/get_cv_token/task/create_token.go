@@ -6,33 +6,38 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
 	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
 )
 
 type TokenService interface {
-	SetToken(ctx context.Context, token string, value interface{}, ttl time.Duration) error
+	StoreToken(ctx context.Context, token, value string, downloads int, ttl time.Duration) error
 }
 
 type CreateTokenTask struct {
 	tokenService TokenService
 	tokenTTL     time.Duration
+	maxDownloads int
+	now          func() time.Time
 }
 
-func NewCreateTokenTask(ts TokenService, ttl time.Duration) *CreateTokenTask {
+func NewCreateTokenTask(ts TokenService, ttl time.Duration, maxDownloads int) *CreateTokenTask {
 	return &CreateTokenTask{
 		tokenService: ts,
 		tokenTTL:     ttl,
+		maxDownloads: maxDownloads,
+		now:          time.Now,
 	}
 }
 
-func (t *CreateTokenTask) Execute(ctx context.Context) (string, error) {
+func (t *CreateTokenTask) Execute(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
 
 	b := make([]byte, 32)
 	for i := range b {
 		num, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
 		if err != nil {
-			return "", errors.ErrInternalServerError
+			return cvtoken.Issue{}, errors.ErrInternalServerError
 		}
 
 		b[i] = charset[num.Int64()]
@@ -40,10 +45,16 @@ func (t *CreateTokenTask) Execute(ctx context.Context) (string, error) {
 
 	token := string(b)
 
-	err := t.tokenService.SetToken(ctx, token, "valid", t.tokenTTL)
+	metadata.IssuedAt = t.now().UTC()
+	value, err := metadata.Encode()
 	if err != nil {
-		return "", errors.ErrInternalServerError
+		return cvtoken.Issue{}, errors.ErrInternalServerError
 	}
 
-	return token, nil
+	err = t.tokenService.StoreToken(ctx, cvtoken.Key(token), value, t.maxDownloads, t.tokenTTL)
+	if err != nil {
+		return cvtoken.Issue{}, errors.ErrInternalServerError
+	}
+
+	return cvtoken.Issue{Token: token, Metadata: metadata, ExpiresAt: metadata.IssuedAt.Add(t.tokenTTL)}, nil
 }
@@ -7,32 +7,41 @@ import (
 	"testing"
 	"time"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
 	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
 )
 
 type mockTokenService struct {
-	setTokenFunc func(ctx context.Context, token string, value interface{}, ttl time.Duration) error
+	storeTokenFunc func(ctx context.Context, token, value string, downloads int, ttl time.Duration) error
 }
 
-func (m *mockTokenService) SetToken(ctx context.Context, token string, value interface{}, ttl time.Duration) error {
-	return m.setTokenFunc(ctx, token, value, ttl)
+func (m *mockTokenService) StoreToken(ctx context.Context, token, value string, downloads int, ttl time.Duration) error {
+	return m.storeTokenFunc(ctx, token, value, downloads, ttl)
 }
 
 func TestCreateTokenTask_Execute(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	metadata := cvtoken.Metadata{Lang: "pl", Document: "pl_cv.pdf", Credential: "acme-recruiter", CaptchaID: "captcha-1"}
+
 	tests := []struct {
-		name         string
-		setTokenFunc func(context.Context, string, interface{}, time.Duration) error
-		wantErr      error
+		name           string
+		storeTokenFunc func(context.Context, string, string, int, time.Duration) error
+		wantErr        error
 	}{
 		{
 			name: "success",
-			setTokenFunc: func(ctx context.Context, token string, value interface{}, ttl time.Duration) error {
-				if len(token) != 32 {
-					return errors.New("invalid length")
+			storeTokenFunc: func(ctx context.Context, token, value string, downloads int, ttl time.Duration) error {
+				want := metadata
+				want.IssuedAt = now
+				if got, err := cvtoken.Decode(value); err != nil || got != want {
+					return errors.New("metadata not stored")
+				}
+				if downloads != 3 {
+					return errors.New("download count not stored")
 				}
-				match, _ := regexp.MatchString("^[a-zA-Z0-9]+$", token)
+				match, _ := regexp.MatchString("^cv_token:[a-zA-Z0-9]{32}$", token)
 				if !match {
-					return errors.New("invalid charset")
+					return errors.New("invalid token key")
 				}
 				return nil
 			},
@@ -40,7 +49,7 @@ func TestCreateTokenTask_Execute(t *testing.T) {
 		},
 		{
 			name: "redis error",
-			setTokenFunc: func(ctx context.Context, token string, value interface{}, ttl time.Duration) error {
+			storeTokenFunc: func(ctx context.Context, token, value string, downloads int, ttl time.Duration) error {
 				return errors.New("fail")
 			},
 			wantErr: appErrors.ErrInternalServerError,
@@ -49,12 +58,16 @@ func TestCreateTokenTask_Execute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := &mockTokenService{setTokenFunc: tt.setTokenFunc}
-			task := NewCreateTokenTask(m, time.Minute)
-			_, err := task.Execute(context.Background())
+			m := &mockTokenService{storeTokenFunc: tt.storeTokenFunc}
+			task := NewCreateTokenTask(m, time.Minute, 3)
+			task.now = func() time.Time { return now }
+			issue, err := task.Execute(context.Background(), metadata)
 			if err != tt.wantErr {
 				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if err == nil && (issue.Token == "" || !issue.Metadata.IssuedAt.Equal(now) || !issue.ExpiresAt.Equal(now.Add(time.Minute))) {
+				t.Errorf("Execute() issue = %+v", issue)
+			}
 		})
 	}
 }
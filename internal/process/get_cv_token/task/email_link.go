@@ -0,0 +1,57 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvmail"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type Outbox interface {
+	Enqueue(ctx context.Context, payload any) error
+}
+
+type EmailLinkTask struct {
+	outbox         Outbox
+	site           string
+	enabled        bool
+	allowedDomains []string
+	now            func() time.Time
+}
+
+func NewEmailLinkTask(outbox Outbox, site string, enabled bool, allowedDomains []string) *EmailLinkTask {
+	return &EmailLinkTask{
+		outbox:         outbox,
+		site:           site,
+		enabled:        enabled,
+		allowedDomains: allowedDomains,
+		now:            time.Now,
+	}
+}
+
+func (t *EmailLinkTask) Validate(email string) (string, error) {
+	if !t.enabled {
+		return "", errors.ErrCVEmailUnavailable
+	}
+
+	return cvmail.Normalize(email, t.allowedDomains)
+}
+
+func (t *EmailLinkTask) Execute(ctx context.Context, email string, issue cvtoken.Issue) error {
+	delivery := cvmail.Delivery{
+		Site:        t.site,
+		Email:       email,
+		Lang:        issue.Metadata.Lang,
+		Token:       issue.Token,
+		ExpiresAt:   issue.ExpiresAt,
+		RequestedAt: t.now().UTC(),
+	}
+
+	if err := t.outbox.Enqueue(ctx, delivery); err != nil {
+		return errors.ErrInternalServerError
+	}
+
+	return nil
+}
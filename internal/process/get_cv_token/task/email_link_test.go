@@ -0,0 +1,104 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvmail"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type mockOutbox struct {
+	payloads []any
+	err      error
+}
+
+func (m *mockOutbox) Enqueue(ctx context.Context, payload any) error {
+	m.payloads = append(m.payloads, payload)
+	return m.err
+}
+
+func TestEmailLinkTask_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		email   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "email delivery disabled",
+			email:   "anna@acme.com",
+			wantErr: appErrors.ErrCVEmailUnavailable,
+		},
+		{
+			name:    "allowed domain",
+			enabled: true,
+			email:   "anna@ACME.com",
+			want:    "anna@acme.com",
+		},
+		{
+			name:    "domain not allowed",
+			enabled: true,
+			email:   "anna@example.com",
+			wantErr: appErrors.ErrCVEmailDomain,
+		},
+		{
+			name:    "invalid address",
+			enabled: true,
+			email:   "anna@",
+			wantErr: appErrors.ErrInvalidCVEmail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewEmailLinkTask(&mockOutbox{}, "main", tt.enabled, []string{"acme.com"})
+
+			got, err := task.Validate(tt.email)
+			if err != tt.wantErr || got != tt.want {
+				t.Errorf("Validate() = %q, %v, want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEmailLinkTask_Execute(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	issue := cvtoken.Issue{Token: "3f9a", Metadata: cvtoken.Metadata{Lang: "en"}, ExpiresAt: now.Add(time.Hour)}
+
+	tests := []struct {
+		name      string
+		outboxErr error
+		wantErr   error
+	}{
+		{
+			name: "queued",
+		},
+		{
+			name:      "outbox error",
+			outboxErr: errors.New("redis error"),
+			wantErr:   appErrors.ErrInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outbox := &mockOutbox{err: tt.outboxErr}
+			task := NewEmailLinkTask(outbox, "main", true, nil)
+			task.now = func() time.Time { return now }
+
+			if err := task.Execute(context.Background(), "anna@acme.com", issue); err != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			want := cvmail.Delivery{Site: "main", Email: "anna@acme.com", Lang: "en", Token: "3f9a", ExpiresAt: now.Add(time.Hour), RequestedAt: now}
+			if len(outbox.payloads) != 1 || outbox.payloads[0] != want {
+				t.Errorf("Execute() queued %+v, want %+v", outbox.payloads, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package task
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+)
+
+type LockoutStore interface {
+	LockoutState(ctx context.Context, client string) (lockout.State, bool, error)
+	RecordLockoutFailure(ctx context.Context, client string, policy lockout.Policy) (lockout.State, bool, error)
+	ClearLockout(ctx context.Context, client string) (bool, error)
+}
+
+type LockoutAlerter interface {
+	LockoutAlert(ctx context.Context, state lockout.State)
+}
+
+type LockoutTask struct {
+	store   LockoutStore
+	alerter LockoutAlerter
+	policy  lockout.Policy
+	now     func() time.Time
+}
+
+func NewLockoutTask(store LockoutStore, alerter LockoutAlerter, policy lockout.Policy) *LockoutTask {
+	return &LockoutTask{
+		store:   store,
+		alerter: alerter,
+		policy:  policy,
+		now:     time.Now,
+	}
+}
+
+func (t *LockoutTask) Check(ctx context.Context, client string) error {
+	if !t.policy.Enabled() || client == "" {
+		return nil
+	}
+
+	state, found, err := t.store.LockoutState(ctx, client)
+	if err != nil {
+		return errors.ErrInternalServerError
+	}
+
+	now := t.now()
+	if found && state.Locked(now) {
+		log.Printf("INFO: rejected CV token request from locked out client %s until %s", client, state.LockedUntil.UTC().Format(time.RFC3339))
+		return errors.WithRetryAfter(errors.ErrClientLocked, state.LockedUntil.Sub(now))
+	}
+
+	return nil
+}
+
+func (t *LockoutTask) RecordFailure(ctx context.Context, client string) (lockout.State, bool) {
+	if !t.policy.Enabled() || client == "" {
+		return lockout.State{}, false
+	}
+
+	state, locked, err := t.store.RecordLockoutFailure(ctx, client, t.policy)
+	if err != nil {
+		log.Printf("ERROR: could not record failed CV password attempt for client %s: %v", client, err)
+		return lockout.State{}, false
+	}
+	if !locked {
+		return state, false
+	}
+
+	log.Printf("INFO: locked out client %s until %s (lockout %d)", client, state.LockedUntil.UTC().Format(time.RFC3339), state.Level)
+	if state.Level >= t.policy.AlertLevel {
+		t.alerter.LockoutAlert(ctx, state)
+	}
+
+	return state, true
+}
+
+func (t *LockoutTask) Reset(ctx context.Context, client string) {
+	if !t.policy.Enabled() || client == "" {
+		return
+	}
+
+	if _, err := t.store.ClearLockout(ctx, client); err != nil {
+		log.Printf("ERROR: could not reset failed CV password attempts for client %s: %v", client, err)
+	}
+}
@@ -0,0 +1,194 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+)
+
+type mockLockoutStore struct {
+	state    lockout.State
+	found    bool
+	locked   bool
+	err      error
+	recorded int
+	cleared  []string
+}
+
+func (m *mockLockoutStore) LockoutState(ctx context.Context, client string) (lockout.State, bool, error) {
+	return m.state, m.found, m.err
+}
+
+func (m *mockLockoutStore) RecordLockoutFailure(ctx context.Context, client string, policy lockout.Policy) (lockout.State, bool, error) {
+	m.recorded++
+	return m.state, m.locked, m.err
+}
+
+func (m *mockLockoutStore) ClearLockout(ctx context.Context, client string) (bool, error) {
+	m.cleared = append(m.cleared, client)
+	return true, m.err
+}
+
+type mockLockoutAlerter struct {
+	alerts []lockout.State
+}
+
+func (m *mockLockoutAlerter) LockoutAlert(ctx context.Context, state lockout.State) {
+	m.alerts = append(m.alerts, state)
+}
+
+func TestLockoutTask_Check(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := lockout.Policy{Threshold: 5, Base: time.Minute, Max: time.Hour, AlertLevel: 1}
+
+	tests := []struct {
+		name      string
+		store     *mockLockoutStore
+		policy    lockout.Policy
+		client    string
+		wantErr   error
+		wantRetry int
+	}{
+		{
+			name:   "no record",
+			store:  &mockLockoutStore{},
+			policy: policy,
+			client: "fp",
+		},
+		{
+			name:   "failures below threshold",
+			store:  &mockLockoutStore{state: lockout.State{Failures: 4}, found: true},
+			policy: policy,
+			client: "fp",
+		},
+		{
+			name:   "lockout ended",
+			store:  &mockLockoutStore{state: lockout.State{Level: 1, LockedUntil: now.Add(-time.Second)}, found: true},
+			policy: policy,
+			client: "fp",
+		},
+		{
+			name:      "locked out",
+			store:     &mockLockoutStore{state: lockout.State{Level: 2, LockedUntil: now.Add(90 * time.Second)}, found: true},
+			policy:    policy,
+			client:    "fp",
+			wantErr:   appErrors.ErrClientLocked,
+			wantRetry: 90,
+		},
+		{
+			name:   "request without client",
+			store:  &mockLockoutStore{err: errors.New("not called")},
+			policy: policy,
+		},
+		{
+			name:   "disabled policy",
+			store:  &mockLockoutStore{err: errors.New("not called")},
+			client: "fp",
+		},
+		{
+			name:    "store error",
+			store:   &mockLockoutStore{err: errors.New("redis error")},
+			policy:  policy,
+			client:  "fp",
+			wantErr: appErrors.ErrInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewLockoutTask(tt.store, &mockLockoutAlerter{}, tt.policy)
+			task.now = func() time.Time { return now }
+
+			err := task.Check(context.Background(), tt.client)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := appErrors.RetryAfterSeconds(err); got != tt.wantRetry {
+				t.Errorf("Check() retry after = %d, want %d", got, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestLockoutTask_RecordFailure(t *testing.T) {
+	lockedUntil := time.Date(2026, 3, 1, 12, 5, 0, 0, time.UTC)
+	policy := lockout.Policy{Threshold: 5, Base: time.Minute, Max: time.Hour, AlertLevel: 2}
+
+	tests := []struct {
+		name         string
+		store        *mockLockoutStore
+		client       string
+		wantLocked   bool
+		wantRecorded int
+		wantAlerts   int
+	}{
+		{
+			name:         "failure below threshold",
+			store:        &mockLockoutStore{state: lockout.State{Failures: 3}},
+			client:       "fp",
+			wantRecorded: 1,
+		},
+		{
+			name:         "lockout below alert level",
+			store:        &mockLockoutStore{state: lockout.State{Level: 1, LockedUntil: lockedUntil}, locked: true},
+			client:       "fp",
+			wantLocked:   true,
+			wantRecorded: 1,
+		},
+		{
+			name:         "lockout at alert level",
+			store:        &mockLockoutStore{state: lockout.State{Level: 2, LockedUntil: lockedUntil}, locked: true},
+			client:       "fp",
+			wantLocked:   true,
+			wantRecorded: 1,
+			wantAlerts:   1,
+		},
+		{
+			name:         "store error",
+			store:        &mockLockoutStore{err: errors.New("redis error")},
+			client:       "fp",
+			wantRecorded: 1,
+		},
+		{
+			name:  "request without client",
+			store: &mockLockoutStore{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerter := &mockLockoutAlerter{}
+			task := NewLockoutTask(tt.store, alerter, policy)
+
+			state, locked := task.RecordFailure(context.Background(), tt.client)
+			if locked != tt.wantLocked {
+				t.Errorf("RecordFailure() locked = %v, want %v", locked, tt.wantLocked)
+			}
+			if locked && !state.LockedUntil.Equal(lockedUntil) {
+				t.Errorf("RecordFailure() locked until = %v, want %v", state.LockedUntil, lockedUntil)
+			}
+			if tt.store.recorded != tt.wantRecorded {
+				t.Errorf("RecordFailure() recorded %d failures, want %d", tt.store.recorded, tt.wantRecorded)
+			}
+			if len(alerter.alerts) != tt.wantAlerts {
+				t.Errorf("RecordFailure() sent %d alerts, want %d", len(alerter.alerts), tt.wantAlerts)
+			}
+		})
+	}
+}
+
+func TestLockoutTask_Reset(t *testing.T) {
+	store := &mockLockoutStore{}
+	task := NewLockoutTask(store, &mockLockoutAlerter{}, lockout.Policy{Threshold: 5, Base: time.Minute})
+
+	task.Reset(context.Background(), "fp")
+	task.Reset(context.Background(), "")
+
+	if len(store.cleared) != 1 || store.cleared[0] != "fp" {
+		t.Errorf("Reset() cleared %v, want [fp]", store.cleared)
+	}
+}
@@ -0,0 +1,64 @@
+package task
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/ratelimit"
+)
+
+type RequestLimiter interface {
+	AllowRequest(ctx context.Context, buckets []ratelimit.Bucket, member string) (time.Duration, error)
+}
+
+type RateLimitTask struct {
+	limiter    RequestLimiter
+	perIP      ratelimit.Limit
+	perCaptcha ratelimit.Limit
+	global     ratelimit.Limit
+}
+
+func NewRateLimitTask(limiter RequestLimiter, perIP, perCaptcha, global ratelimit.Limit) *RateLimitTask {
+	return &RateLimitTask{
+		limiter:    limiter,
+		perIP:      perIP,
+		perCaptcha: perCaptcha,
+		global:     global,
+	}
+}
+
+func (t *RateLimitTask) Execute(ctx context.Context, ip, captchaID string) error {
+	var buckets []ratelimit.Bucket
+	if t.perIP.Enabled() && ip != "" {
+		buckets = append(buckets, ratelimit.Bucket{Key: ratelimit.Key("cv", "ip", ip), Limit: t.perIP})
+	}
+	if t.perCaptcha.Enabled() && captchaID != "" {
+		buckets = append(buckets, ratelimit.Bucket{Key: ratelimit.Key("cv", "captcha", captchaID), Limit: t.perCaptcha})
+	}
+	if t.global.Enabled() {
+		buckets = append(buckets, ratelimit.Bucket{Key: ratelimit.Key("cv", "global", ""), Limit: t.global})
+	}
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	member := make([]byte, 16)
+	if _, err := rand.Read(member); err != nil {
+		return errors.ErrInternalServerError
+	}
+
+	retryAfter, err := t.limiter.AllowRequest(ctx, buckets, hex.EncodeToString(member))
+	if err != nil {
+		return errors.ErrInternalServerError
+	}
+	if retryAfter > 0 {
+		log.Printf("INFO: rate limited CV token request for captcha %s, retry after %v", captchaID, retryAfter)
+		return errors.WithRetryAfter(errors.ErrRateLimited, retryAfter)
+	}
+
+	return nil
+}
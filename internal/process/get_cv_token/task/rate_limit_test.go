@@ -0,0 +1,101 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/ratelimit"
+)
+
+type mockRequestLimiter struct {
+	retryAfter time.Duration
+	err        error
+	buckets    []ratelimit.Bucket
+}
+
+func (m *mockRequestLimiter) AllowRequest(ctx context.Context, buckets []ratelimit.Bucket, member string) (time.Duration, error) {
+	m.buckets = buckets
+	return m.retryAfter, m.err
+}
+
+func TestRateLimitTask_Execute(t *testing.T) {
+	perIP := ratelimit.Limit{Max: 10, Window: 10 * time.Minute}
+	perCaptcha := ratelimit.Limit{Max: 5, Window: 10 * time.Minute}
+	global := ratelimit.Limit{Max: 100, Window: time.Minute}
+
+	tests := []struct {
+		name        string
+		limiter     *mockRequestLimiter
+		perIP       ratelimit.Limit
+		ip          string
+		wantBuckets []string
+		wantErr     error
+		wantRetry   int
+	}{
+		{
+			name:        "allowed",
+			limiter:     &mockRequestLimiter{},
+			perIP:       perIP,
+			ip:          "203.0.113.7",
+			wantBuckets: []string{ratelimit.Key("cv", "ip", "203.0.113.7"), ratelimit.Key("cv", "captcha", "c1"), "ratelimit:cv:global"},
+		},
+		{
+			name:        "request without ip",
+			limiter:     &mockRequestLimiter{},
+			perIP:       perIP,
+			wantBuckets: []string{ratelimit.Key("cv", "captcha", "c1"), "ratelimit:cv:global"},
+		},
+		{
+			name:        "disabled limit",
+			limiter:     &mockRequestLimiter{},
+			ip:          "203.0.113.7",
+			wantBuckets: []string{ratelimit.Key("cv", "captcha", "c1"), "ratelimit:cv:global"},
+		},
+		{
+			name:        "limited",
+			limiter:     &mockRequestLimiter{retryAfter: 1500 * time.Millisecond},
+			perIP:       perIP,
+			ip:          "203.0.113.7",
+			wantBuckets: []string{ratelimit.Key("cv", "ip", "203.0.113.7"), ratelimit.Key("cv", "captcha", "c1"), "ratelimit:cv:global"},
+			wantErr:     appErrors.ErrRateLimited,
+			wantRetry:   2,
+		},
+		{
+			name:        "redis error",
+			limiter:     &mockRequestLimiter{err: errors.New("fail")},
+			wantBuckets: []string{ratelimit.Key("cv", "captcha", "c1"), "ratelimit:cv:global"},
+			wantErr:     appErrors.ErrInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewRateLimitTask(tt.limiter, tt.perIP, perCaptcha, global)
+			err := task.Execute(context.Background(), tt.ip, "c1")
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := appErrors.RetryAfterSeconds(err); got != tt.wantRetry {
+				t.Errorf("Execute() retry after = %d, want %d", got, tt.wantRetry)
+			}
+			var keys []string
+			for _, b := range tt.limiter.buckets {
+				keys = append(keys, b.Key)
+			}
+			if !reflect.DeepEqual(keys, tt.wantBuckets) {
+				t.Errorf("Execute() buckets = %v, want %v", keys, tt.wantBuckets)
+			}
+		})
+	}
+
+	t.Run("all limits disabled", func(t *testing.T) {
+		limiter := &mockRequestLimiter{err: errors.New("must not be called")}
+		if err := NewRateLimitTask(limiter, ratelimit.Limit{}, ratelimit.Limit{}, ratelimit.Limit{}).Execute(context.Background(), "ip", "c1"); err != nil {
+			t.Errorf("Execute() error = %v", err)
+		}
+	})
+}
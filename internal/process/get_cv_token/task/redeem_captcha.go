@@ -0,0 +1,51 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	serviceRedis "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/redis"
+)
+
+type RedeemCaptchaRedisClient interface {
+	RedeemCaptcha(ctx context.Context, key string) (serviceRedis.RedeemedCaptcha, bool, error)
+	RestoreCaptcha(ctx context.Context, key string, captcha serviceRedis.RedeemedCaptcha) (bool, error)
+}
+
+type RedeemCaptchaTask struct {
+	client RedeemCaptchaRedisClient
+}
+
+func NewRedeemCaptchaTask(c RedeemCaptchaRedisClient) *RedeemCaptchaTask {
+	return &RedeemCaptchaTask{client: c}
+}
+
+func (t *RedeemCaptchaTask) Execute(ctx context.Context, captchaID string) (serviceRedis.RedeemedCaptcha, error) {
+	key := fmt.Sprintf("captcha:%s", captchaID)
+
+	captcha, found, err := t.client.RedeemCaptcha(ctx, key)
+	if err != nil {
+		return serviceRedis.RedeemedCaptcha{}, errors.ErrInternalServerError
+	}
+	if !found {
+		return serviceRedis.RedeemedCaptcha{}, errors.ErrCaptchaNotFound
+	}
+	if !captcha.Solved {
+		return serviceRedis.RedeemedCaptcha{}, errors.ErrCaptchaNotSolved
+	}
+
+	return captcha, nil
+}
+
+func (t *RedeemCaptchaTask) Restore(ctx context.Context, captchaID string, captcha serviceRedis.RedeemedCaptcha) {
+	restored, err := t.client.RestoreCaptcha(ctx, fmt.Sprintf("captcha:%s", captchaID), captcha)
+	if err != nil {
+		log.Printf("ERROR: could not restore captcha %s: %v", captchaID, err)
+		return
+	}
+	if !restored {
+		log.Printf("INFO: captcha %s was not restored because it already exists", captchaID)
+	}
+}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	serviceRedis "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/redis"
+)
+
+type mockRedeemCaptchaRedis struct {
+	captcha    serviceRedis.RedeemedCaptcha
+	found      bool
+	err        error
+	key        string
+	restored   []serviceRedis.RedeemedCaptcha
+	restoreErr error
+}
+
+func (m *mockRedeemCaptchaRedis) RedeemCaptcha(ctx context.Context, key string) (serviceRedis.RedeemedCaptcha, bool, error) {
+	m.key = key
+	return m.captcha, m.found, m.err
+}
+
+func (m *mockRedeemCaptchaRedis) RestoreCaptcha(ctx context.Context, key string, captcha serviceRedis.RedeemedCaptcha) (bool, error) {
+	m.key = key
+	m.restored = append(m.restored, captcha)
+	return m.restoreErr == nil, m.restoreErr
+}
+
+func TestRedeemCaptchaTask_Execute(t *testing.T) {
+	solved := serviceRedis.RedeemedCaptcha{Solved: true, Value: `{"solved":true}`, TTL: time.Minute}
+
+	tests := []struct {
+		name    string
+		mock    *mockRedeemCaptchaRedis
+		want    serviceRedis.RedeemedCaptcha
+		wantErr error
+	}{
+		{
+			name:    "redeemed",
+			mock:    &mockRedeemCaptchaRedis{captcha: solved, found: true},
+			want:    solved,
+			wantErr: nil,
+		},
+		{
+			name:    "already redeemed",
+			mock:    &mockRedeemCaptchaRedis{},
+			wantErr: appErrors.ErrCaptchaNotFound,
+		},
+		{
+			name:    "not solved",
+			mock:    &mockRedeemCaptchaRedis{found: true},
+			wantErr: appErrors.ErrCaptchaNotSolved,
+		},
+		{
+			name:    "error",
+			mock:    &mockRedeemCaptchaRedis{err: errors.New("fail")},
+			wantErr: appErrors.ErrInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewRedeemCaptchaTask(tt.mock)
+			got, err := task.Execute(context.Background(), "id")
+			if err != tt.wantErr {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Execute() = %+v, want %+v", got, tt.want)
+			}
+			if tt.mock.key != "captcha:id" {
+				t.Errorf("Execute() key = %s, want captcha:id", tt.mock.key)
+			}
+		})
+	}
+}
+
+func TestRedeemCaptchaTask_Restore(t *testing.T) {
+	captcha := serviceRedis.RedeemedCaptcha{Solved: true, Value: `{"solved":true}`, TTL: time.Minute}
+
+	for _, restoreErr := range []error{nil, errors.New("fail")} {
+		mock := &mockRedeemCaptchaRedis{restoreErr: restoreErr}
+		NewRedeemCaptchaTask(mock).Restore(context.Background(), "id", captcha)
+
+		if mock.key != "captcha:id" || len(mock.restored) != 1 || mock.restored[0] != captcha {
+			t.Errorf("Restore() wrote %v to %s, want %+v to captcha:id", mock.restored, mock.key, captcha)
+		}
+	}
+}
@@ -2,72 +2,99 @@ package task
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"log"
 	"time"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/credential"
 	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
 )
 
 type ValidatePasswordRedisClient interface {
-	GetToken(ctx context.Context, key string) (string, error)
-	SetToken(ctx context.Context, token string, value interface{}, ttl time.Duration) error
-	DelToken(ctx context.Context, key string) error
+	FailCaptchaAttempt(ctx context.Context, key string) (int64, bool, error)
 }
 
-type captcha struct {
-	Value     string `json:"value"`
-	TriesLeft int    `json:"triesLeft"`
-	Solved    bool   `json:"solved"`
+type UsageCounter interface {
+	IncrementCapped(ctx context.Context, key string, limit int64) (int64, bool, error)
+	Decrement(ctx context.Context, key string) (int64, error)
 }
 
 type ValidatePasswordTask struct {
-	correctPassword   string
-	client            ValidatePasswordRedisClient
-	captchaTtlMinutes int
+	credentials  []credential.Credential
+	client       ValidatePasswordRedisClient
+	usageCounter UsageCounter
+	now          func() time.Time
 }
 
-func NewValidatePasswordTask(correctPassword string, client ValidatePasswordRedisClient, captchaTTL int) *ValidatePasswordTask {
+func NewValidatePasswordTask(credentials []credential.Credential, client ValidatePasswordRedisClient, usageCounter UsageCounter) *ValidatePasswordTask {
 	return &ValidatePasswordTask{
-		correctPassword:   correctPassword,
-		client:            client,
-		captchaTtlMinutes: captchaTTL,
+		credentials:  credentials,
+		client:       client,
+		usageCounter: usageCounter,
+		now:          time.Now,
 	}
 }
 
-func (t *ValidatePasswordTask) Execute(ctx context.Context, password, captchaID string) error {
-	if password == t.correctPassword {
-		return nil
+func (t *ValidatePasswordTask) Execute(ctx context.Context, password, lang, captchaID string) (string, error) {
+	if c, ok := credential.Match(t.credentials, password); ok {
+		return c.Name, t.checkCredential(ctx, c, lang)
 	}
 
-	key := fmt.Sprintf("captcha:%s", captchaID)
+	return "", t.registerFailedAttempt(ctx, captchaID)
+}
 
-	data, err := t.client.GetToken(ctx, key)
-	if err != nil {
-		return errors.ErrCaptchaNotFound
+func (t *ValidatePasswordTask) Release(ctx context.Context, credentialName string) {
+	for _, c := range t.credentials {
+		if c.Name != credentialName || c.MaxUses == 0 {
+			continue
+		}
+		if _, err := t.usageCounter.Decrement(ctx, credential.UsesKey(c.Name)); err != nil {
+			log.Printf("ERROR: could not release use of credential %s: %v", c.Name, err)
+		}
+		return
 	}
+}
 
-	var c captcha
-	if err = json.Unmarshal([]byte(data), &c); err != nil {
-		return errors.ErrInternalServerError
+func (t *ValidatePasswordTask) checkCredential(ctx context.Context, c credential.Credential, lang string) error {
+	if c.Revoked {
+		return errors.ErrCredentialRevoked
+	}
+	if c.Expired(t.now()) {
+		return errors.ErrCredentialExpired
+	}
+	if !c.AllowsLang(lang) {
+		return errors.ErrCVLangNotAllowed
+	}
+	if c.MaxUses == 0 {
+		return nil
 	}
 
-	c.TriesLeft--
+	_, counted, err := t.usageCounter.IncrementCapped(ctx, credential.UsesKey(c.Name), int64(c.MaxUses))
+	if err != nil {
+		return errors.ErrInternalServerError
+	}
+	if !counted {
+		return errors.ErrCredentialExhausted
+	}
 
-	if c.TriesLeft <= 0 {
-		err = t.client.DelToken(ctx, key)
-		if err != nil {
-			return errors.ErrInternalServerError
-		}
+	return nil
+}
 
-		return errors.ErrNoTriesLeft
+func (t *ValidatePasswordTask) registerFailedAttempt(ctx context.Context, captchaID string) error {
+	if captchaID == "" {
+		return errors.ErrInvalidPassword
 	}
 
-	newData, _ := json.Marshal(c)
-	err = t.client.SetToken(ctx, key, string(newData), time.Duration(t.captchaTtlMinutes)*time.Minute)
+	triesLeft, found, err := t.client.FailCaptchaAttempt(ctx, fmt.Sprintf("captcha:%s", captchaID))
 	if err != nil {
 		return errors.ErrInternalServerError
 	}
+	if !found {
+		return errors.ErrCaptchaNotFound
+	}
+	if triesLeft <= 0 {
+		return errors.ErrNoTriesLeft
+	}
 
 	return errors.ErrInvalidPassword
 }
@@ -2,111 +2,170 @@ package task
 
 import (
 	"context"
-	"encoding/json"
+	stdErrors "errors"
 	"testing"
 	"time"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/credential"
 	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
 )
 
 type mockRedis struct {
-	getTokenFunc func(ctx context.Context, key string) (string, error)
-	setTokenFunc func(ctx context.Context, token string, value interface{}, ttl time.Duration) error
-	delTokenFunc func(ctx context.Context, key string) error
+	triesLeft int64
+	found     bool
+	err       error
+	keys      []string
 }
 
-func (m *mockRedis) GetToken(ctx context.Context, key string) (string, error) {
-	return m.getTokenFunc(ctx, key)
+func (m *mockRedis) FailCaptchaAttempt(ctx context.Context, key string) (int64, bool, error) {
+	m.keys = append(m.keys, key)
+	return m.triesLeft, m.found, m.err
 }
-func (m *mockRedis) SetToken(ctx context.Context, token string, value interface{}, ttl time.Duration) error {
-	return m.setTokenFunc(ctx, token, value, ttl)
+
+type mockUsageCounter struct {
+	uses map[string]int64
+}
+
+func (m *mockUsageCounter) IncrementCapped(ctx context.Context, key string, limit int64) (int64, bool, error) {
+	if m.uses[key] >= limit {
+		return m.uses[key], false, nil
+	}
+	m.uses[key]++
+	return m.uses[key], true, nil
 }
-func (m *mockRedis) DelToken(ctx context.Context, key string) error {
-	return m.delTokenFunc(ctx, key)
+
+func (m *mockUsageCounter) Decrement(ctx context.Context, key string) (int64, error) {
+	m.uses[key]--
+	return m.uses[key], nil
 }
 
 func TestValidatePasswordTask_Execute(t *testing.T) {
 	correctPass := "secret123"
+	credentials := []credential.Credential{{Name: credential.DefaultName, Password: correctPass}}
 	captchaID := "test-captcha"
 	ctx := context.Background()
 
 	t.Run("correct password", func(t *testing.T) {
-		task := NewValidatePasswordTask(correctPass, &mockRedis{}, 10)
-		err := task.Execute(ctx, "secret123", captchaID)
+		task := NewValidatePasswordTask(credentials, &mockRedis{}, &mockUsageCounter{})
+		name, err := task.Execute(ctx, "secret123", "pl", captchaID)
 		if err != nil {
 			t.Errorf("expected nil, got %v", err)
 		}
+		if name != credential.DefaultName {
+			t.Errorf("expected credential %s, got %s", credential.DefaultName, name)
+		}
 	})
 
-	t.Run("incorrect password - decrement tries", func(t *testing.T) {
-		c := captcha{Value: "XYZ", TriesLeft: 3, Solved: true}
-		data, _ := json.Marshal(c)
-
-		setCalled := false
-		mock := &mockRedis{
-			getTokenFunc: func(ctx context.Context, key string) (string, error) {
-				return string(data), nil
-			},
-			setTokenFunc: func(ctx context.Context, token string, value interface{}, ttl time.Duration) error {
-				setCalled = true
-				var updated captcha
-				json.Unmarshal([]byte(value.(string)), &updated)
-				if updated.TriesLeft != 2 {
-					t.Errorf("expected TriesLeft 2, got %d", updated.TriesLeft)
-				}
-				return nil
-			},
+	t.Run("incorrect password", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			mock    *mockRedis
+			wantErr error
+		}{
+			{name: "tries left", mock: &mockRedis{triesLeft: 2, found: true}, wantErr: errors.ErrInvalidPassword},
+			{name: "no tries left", mock: &mockRedis{triesLeft: 0, found: true}, wantErr: errors.ErrNoTriesLeft},
+			{name: "captcha not found", mock: &mockRedis{}, wantErr: errors.ErrCaptchaNotFound},
+			{name: "redis error", mock: &mockRedis{err: stdErrors.New("redis error")}, wantErr: errors.ErrInternalServerError},
 		}
 
-		task := NewValidatePasswordTask(correctPass, mock, 10)
-		err := task.Execute(ctx, "wrong", captchaID)
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				task := NewValidatePasswordTask(credentials, tt.mock, &mockUsageCounter{})
+				name, err := task.Execute(ctx, "wrong", "pl", captchaID)
+				if err != tt.wantErr {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				if name != "" {
+					t.Errorf("expected no credential, got %s", name)
+				}
+				if len(tt.mock.keys) != 1 || tt.mock.keys[0] != "captcha:"+captchaID {
+					t.Errorf("expected one attempt on captcha:%s, got %v", captchaID, tt.mock.keys)
+				}
+			})
+		}
+	})
 
-		if err != errors.ErrInvalidPassword {
-			t.Errorf("expected ErrInvalidPassword, got %v", err)
+	t.Run("incorrect password without captcha", func(t *testing.T) {
+		failedAttempt := &mockRedis{triesLeft: 2, found: true}
+		task := NewValidatePasswordTask(credentials, failedAttempt, &mockUsageCounter{})
+		if _, err := task.Execute(ctx, "wrong", "pl", ""); err != errors.ErrInvalidPassword {
+			t.Errorf("expected %v, got %v", errors.ErrInvalidPassword, err)
 		}
-		if !setCalled {
-			t.Error("expected SetToken to be called")
+		if len(failedAttempt.keys) != 0 {
+			t.Errorf("expected no captcha attempts, got %v", failedAttempt.keys)
 		}
 	})
 
-	t.Run("incorrect password - delete captcha when no tries left", func(t *testing.T) {
-		c := captcha{Value: "XYZ", TriesLeft: 1, Solved: true}
-		data, _ := json.Marshal(c)
-
-		delCalled := false
-		mock := &mockRedis{
-			getTokenFunc: func(ctx context.Context, key string) (string, error) {
-				return string(data), nil
-			},
-			delTokenFunc: func(ctx context.Context, key string) error {
-				delCalled = true
-				return nil
-			},
+	t.Run("named credentials", func(t *testing.T) {
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		credentials := []credential.Credential{
+			{Name: "acme-recruiter", Password: "acme", Langs: []string{"en"}, MaxUses: 1},
+			{Name: "job-fair-2025", Password: "fair", ExpiresAt: now},
+			{Name: "leaked", Password: "leaked", Revoked: true},
+			{Name: "default", Password: correctPass},
+		}
+		failedAttempt := &mockRedis{triesLeft: 2, found: true}
+		usage := &mockUsageCounter{uses: map[string]int64{}}
+		task := NewValidatePasswordTask(credentials, failedAttempt, usage)
+		task.now = func() time.Time { return now }
+
+		tests := []struct {
+			name     string
+			password string
+			lang     string
+			wantName string
+			wantErr  error
+		}{
+			{name: "matched credential", password: "acme", lang: "en", wantName: "acme-recruiter"},
+			{name: "language not allowed", password: "acme", lang: "pl", wantName: "acme-recruiter", wantErr: errors.ErrCVLangNotAllowed},
+			{name: "usage cap reached", password: "acme", lang: "en", wantName: "acme-recruiter", wantErr: errors.ErrCredentialExhausted},
+			{name: "expired", password: "fair", lang: "pl", wantName: "job-fair-2025", wantErr: errors.ErrCredentialExpired},
+			{name: "usage cap stays reached", password: "acme", lang: "en", wantName: "acme-recruiter", wantErr: errors.ErrCredentialExhausted},
+			{name: "revoked", password: "leaked", lang: "pl", wantName: "leaked", wantErr: errors.ErrCredentialRevoked},
+			{name: "other credentials still work", password: correctPass, lang: "pl", wantName: "default"},
 		}
 
-		task := NewValidatePasswordTask(correctPass, mock, 10)
-		err := task.Execute(ctx, "wrong", captchaID)
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				name, err := task.Execute(ctx, tt.password, tt.lang, captchaID)
+				if err != tt.wantErr {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				if name != tt.wantName {
+					t.Errorf("expected credential %q, got %q", tt.wantName, name)
+				}
+			})
+		}
 
-		if err != errors.ErrNoTriesLeft {
-			t.Errorf("expected ErrNoTriesLeft, got %v", err)
+		if len(failedAttempt.keys) != 0 {
+			t.Errorf("expected no captcha tries spent on known credentials, got %v", failedAttempt.keys)
 		}
-		if !delCalled {
-			t.Error("expected DelToken to be called")
+		if uses := usage.uses[credential.UsesKey("acme-recruiter")]; uses != 1 {
+			t.Errorf("expected the usage counter to stop at the cap, got %d", uses)
 		}
 	})
+}
 
-	t.Run("captcha not found", func(t *testing.T) {
-		mock := &mockRedis{
-			getTokenFunc: func(ctx context.Context, key string) (string, error) {
-				return "", errors.ErrCaptchaNotFound
-			},
-		}
-
-		task := NewValidatePasswordTask(correctPass, mock, 10)
-		err := task.Execute(ctx, "wrong", captchaID)
+func TestValidatePasswordTask_Release(t *testing.T) {
+	credentials := []credential.Credential{
+		{Name: "acme-recruiter", Password: "acme", MaxUses: 1},
+		{Name: "default", Password: "secret123"},
+	}
+	usage := &mockUsageCounter{uses: map[string]int64{}}
+	task := NewValidatePasswordTask(credentials, &mockRedis{}, usage)
+	ctx := context.Background()
 
-		if err != errors.ErrCaptchaNotFound {
-			t.Errorf("expected ErrCaptchaNotFound, got %v", err)
-		}
-	})
+	if _, err := task.Execute(ctx, "acme", "en", "c1"); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	task.Release(ctx, "acme-recruiter")
+	task.Release(ctx, "default")
+	task.Release(ctx, "unknown")
+
+	if _, err := task.Execute(ctx, "acme", "en", "c1"); err != nil {
+		t.Errorf("expected the released use to be available again, got %v", err)
+	}
+	if len(usage.uses) != 1 {
+		t.Errorf("expected only the capped credential to be counted, got %v", usage.uses)
+	}
 }
@@ -0,0 +1,60 @@
+package task
+
+import (
+	"context"
+	stdErrors "errors"
+	"log"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pow"
+)
+
+type ChallengeStore interface {
+	ClaimNonce(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error)
+}
+
+type VerifyPowTask struct {
+	secret     []byte
+	difficulty int
+	store      ChallengeStore
+	now        func() time.Time
+}
+
+func NewVerifyPowTask(secret []byte, difficulty int, store ChallengeStore) *VerifyPowTask {
+	return &VerifyPowTask{
+		secret:     secret,
+		difficulty: difficulty,
+		store:      store,
+		now:        time.Now,
+	}
+}
+
+func (t *VerifyPowTask) Execute(ctx context.Context, solution pow.Solution) error {
+	if t.difficulty == 0 {
+		return nil
+	}
+
+	now := t.now()
+	challenge, err := pow.Verify(t.secret, solution, t.difficulty, now)
+	switch {
+	case stdErrors.Is(err, pow.ErrInvalidSignature), stdErrors.Is(err, pow.ErrMalformedChallenge):
+		log.Printf("INFO: rejected CV token request: %v", err)
+		return errors.ErrPowSignature
+	case stdErrors.Is(err, pow.ErrInsufficientWork):
+		return errors.ErrPowWork
+	case err != nil:
+		return errors.ErrPowFailed
+	}
+
+	_, claimed, err := t.store.ClaimNonce(ctx, pow.ReplayKey(challenge.Seed), challenge.ExpiresAt.Sub(now))
+	if err != nil {
+		return errors.ErrInternalServerError
+	}
+	if !claimed {
+		log.Printf("INFO: rejected replayed pow challenge %s", challenge.Seed)
+		return errors.ErrPowFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pow"
+)
+
+type mockChallengeStore struct {
+	claimed map[string]time.Duration
+	err     error
+}
+
+func (m *mockChallengeStore) ClaimNonce(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error) {
+	if m.err != nil {
+		return time.Time{}, false, m.err
+	}
+	if _, ok := m.claimed[key]; ok {
+		return time.Time{}, false, nil
+	}
+	m.claimed[key] = ttl
+	return time.Time{}, true, nil
+}
+
+func TestVerifyPowTask_Execute(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	challenge, signature, err := pow.NewChallenge(secret, 2*time.Minute, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := pow.Solution{Challenge: challenge, Signature: signature, Nonce: pow.Solve(challenge, 8)}
+	weak := valid
+	weak.Nonce = "weak"
+	for pow.LeadingZeroBits(pow.Work(challenge, weak.Nonce)) >= 8 {
+		weak.Nonce += "!"
+	}
+
+	tests := []struct {
+		name       string
+		difficulty int
+		solution   pow.Solution
+		store      *mockChallengeStore
+		wantErr    error
+	}{
+		{name: "valid", difficulty: 8, solution: valid, store: &mockChallengeStore{claimed: map[string]time.Duration{}}},
+		{name: "disabled", solution: pow.Solution{}, store: &mockChallengeStore{err: errors.New("not called")}},
+		{name: "missing solution", difficulty: 8, solution: pow.Solution{}, wantErr: appErrors.ErrPowFailed},
+		{name: "bad signature", difficulty: 8, solution: pow.Solution{Challenge: challenge, Signature: "00", Nonce: valid.Nonce}, wantErr: appErrors.ErrPowSignature},
+		{name: "insufficient work", difficulty: 8, solution: weak, wantErr: appErrors.ErrPowWork},
+		{
+			name:       "replayed challenge",
+			difficulty: 8,
+			solution:   valid,
+			store:      &mockChallengeStore{claimed: map[string]time.Duration{pow.ReplayKey(challenge[:32]): time.Minute}},
+			wantErr:    appErrors.ErrPowFailed,
+		},
+		{name: "store error", difficulty: 8, solution: valid, store: &mockChallengeStore{err: errors.New("redis error")}, wantErr: appErrors.ErrInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewVerifyPowTask(secret, tt.difficulty, tt.store)
+			task.now = func() time.Time { return now }
+
+			if err := task.Execute(context.Background(), tt.solution); err != tt.wantErr {
+				t.Errorf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("expired challenge", func(t *testing.T) {
+		task := NewVerifyPowTask(secret, 8, &mockChallengeStore{claimed: map[string]time.Duration{}})
+		task.now = func() time.Time { return now.Add(2 * time.Minute) }
+
+		if err := task.Execute(context.Background(), valid); err != appErrors.ErrPowFailed {
+			t.Errorf("Execute() error = %v, want %v", err, appErrors.ErrPowFailed)
+		}
+	})
+
+	t.Run("claims the challenge until it expires", func(t *testing.T) {
+		store := &mockChallengeStore{claimed: map[string]time.Duration{}}
+		task := NewVerifyPowTask(secret, 8, store)
+		task.now = func() time.Time { return now }
+
+		if err := task.Execute(context.Background(), valid); err != nil {
+			t.Fatal(err)
+		}
+		if err := task.Execute(context.Background(), valid); err != appErrors.ErrPowFailed {
+			t.Errorf("second Execute() error = %v, want %v", err, appErrors.ErrPowFailed)
+		}
+		if ttl := store.claimed[pow.ReplayKey(challenge[:32])]; ttl != 2*time.Minute {
+			t.Errorf("claimed for %v, want 2m", ttl)
+		}
+	})
+}
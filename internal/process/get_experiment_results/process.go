@@ -0,0 +1,73 @@
+package get_experiment_results
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/experiment"
+)
+
+type ResultsReader interface {
+	HashGetAll(ctx context.Context, key string) (map[string]string, error)
+	CountUnique(ctx context.Context, key string) (int64, error)
+}
+
+type Result struct {
+	Experiment     string
+	Variant        string
+	Weight         int
+	Exposures      int64
+	UniqueVisitors int64
+}
+
+type Process struct {
+	experiments   []experiment.Experiment
+	resultsReader ResultsReader
+}
+
+func NewProcess(experiments []experiment.Experiment, reader ResultsReader) *Process {
+	return &Process{
+		experiments:   experiments,
+		resultsReader: reader,
+	}
+}
+
+func (p *Process) Process(ctx context.Context, name string) ([]Result, error) {
+	var results []Result
+	found := false
+
+	for _, e := range p.experiments {
+		if name != "" && e.Name != name {
+			continue
+		}
+		found = true
+
+		exposures, err := p.resultsReader.HashGetAll(ctx, experiment.ExposuresKey(e.Name))
+		if err != nil {
+			return nil, errors.ErrInternalServerError
+		}
+
+		for _, v := range e.Variants {
+			count, _ := strconv.ParseInt(exposures[v.ID], 10, 64)
+			visitors, err := p.resultsReader.CountUnique(ctx, experiment.VisitorsKey(e.Name, v.ID))
+			if err != nil {
+				return nil, errors.ErrInternalServerError
+			}
+
+			results = append(results, Result{
+				Experiment:     e.Name,
+				Variant:        v.ID,
+				Weight:         v.Weight,
+				Exposures:      count,
+				UniqueVisitors: visitors,
+			})
+		}
+	}
+
+	if name != "" && !found {
+		return nil, errors.ErrExperimentNotFound
+	}
+
+	return results, nil
+}
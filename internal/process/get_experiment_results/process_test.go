@@ -0,0 +1,94 @@
+package get_experiment_results
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/experiment"
+)
+
+type mockResultsReader struct {
+	hashes map[string]map[string]string
+	counts map[string]int64
+	err    error
+}
+
+func (m *mockResultsReader) HashGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return m.hashes[key], m.err
+}
+
+func (m *mockResultsReader) CountUnique(ctx context.Context, key string) (int64, error) {
+	return m.counts[key], m.err
+}
+
+func TestProcess_GetExperimentResults(t *testing.T) {
+	experiments := []experiment.Experiment{
+		{Name: "about", Variants: []experiment.Variant{{ID: "a", Weight: 1}, {ID: "b", Weight: 1}}},
+		{Name: "headline", Variants: []experiment.Variant{{ID: "control", Weight: 3}, {ID: "go", Weight: 1}}},
+	}
+	reader := &mockResultsReader{
+		hashes: map[string]map[string]string{
+			"experiment:headline:exposures": {"control": "12", "go": "4"},
+		},
+		counts: map[string]int64{
+			"experiment:headline:control:visitors": 10,
+			"experiment:headline:go:visitors":      3,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		exp     string
+		reader  *mockResultsReader
+		want    []Result
+		wantErr error
+	}{
+		{
+			name:   "single experiment",
+			exp:    "headline",
+			reader: reader,
+			want: []Result{
+				{Experiment: "headline", Variant: "control", Weight: 3, Exposures: 12, UniqueVisitors: 10},
+				{Experiment: "headline", Variant: "go", Weight: 1, Exposures: 4, UniqueVisitors: 3},
+			},
+		},
+		{
+			name:   "all experiments",
+			reader: reader,
+			want: []Result{
+				{Experiment: "about", Variant: "a", Weight: 1},
+				{Experiment: "about", Variant: "b", Weight: 1},
+				{Experiment: "headline", Variant: "control", Weight: 3, Exposures: 12, UniqueVisitors: 10},
+				{Experiment: "headline", Variant: "go", Weight: 1, Exposures: 4, UniqueVisitors: 3},
+			},
+		},
+		{
+			name:    "unknown experiment",
+			exp:     "footer",
+			reader:  reader,
+			wantErr: appErrors.ErrExperimentNotFound,
+		},
+		{
+			name:    "redis error",
+			exp:     "headline",
+			reader:  &mockResultsReader{err: errors.New("redis error")},
+			wantErr: appErrors.ErrInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProcess(experiments, tt.reader)
+			got, err := p.Process(context.Background(), tt.exp)
+			if err != tt.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Process() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
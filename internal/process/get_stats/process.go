@@ -0,0 +1,80 @@
+package get_stats
+
+import (
+	"context"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type StatsReader interface {
+	Read(ctx context.Context, day time.Time) (map[string]int64, error)
+	RetentionDays() int
+}
+
+type Day struct {
+	Date     time.Time
+	Counters map[string]int64
+}
+
+type Stats struct {
+	Days   []Day
+	Totals map[string]int64
+}
+
+const (
+	dayLayout   = "2006-01-02"
+	defaultDays = 7
+)
+
+type Process struct {
+	statsReader StatsReader
+	now         func() time.Time
+}
+
+func NewProcess(reader StatsReader) *Process {
+	return &Process{
+		statsReader: reader,
+		now:         time.Now,
+	}
+}
+
+func (p *Process) Process(ctx context.Context, fromDate, toDate string) (*Stats, error) {
+	to := p.now().UTC().Truncate(24 * time.Hour)
+	if toDate != "" {
+		var err error
+		if to, err = time.Parse(dayLayout, toDate); err != nil {
+			return nil, errors.ErrInvalidInput
+		}
+	}
+
+	from := to.AddDate(0, 0, 1-defaultDays)
+	if fromDate != "" {
+		var err error
+		if from, err = time.Parse(dayLayout, fromDate); err != nil {
+			return nil, errors.ErrInvalidInput
+		}
+	}
+
+	if to.Before(from) {
+		return nil, errors.ErrInvalidInput
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > p.statsReader.RetentionDays() {
+		return nil, errors.ErrInvalidInput
+	}
+
+	stats := &Stats{Totals: map[string]int64{}}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		counters, err := p.statsReader.Read(ctx, day)
+		if err != nil {
+			return nil, errors.ErrInternalServerError
+		}
+
+		for field, n := range counters {
+			stats.Totals[field] += n
+		}
+		stats.Days = append(stats.Days, Day{Date: day, Counters: counters})
+	}
+
+	return stats, nil
+}
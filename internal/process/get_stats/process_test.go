@@ -0,0 +1,104 @@
+package get_stats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type mockStatsReader struct {
+	days map[string]map[string]int64
+	err  error
+}
+
+func (m *mockStatsReader) Read(ctx context.Context, day time.Time) (map[string]int64, error) {
+	return m.days[day.Format("2006-01-02")], m.err
+}
+
+func (m *mockStatsReader) RetentionDays() int {
+	return 30
+}
+
+func TestProcess_GetStats(t *testing.T) {
+	reader := &mockStatsReader{days: map[string]map[string]int64{
+		"2026-10-18": {"content_requested:pl": 2, "cv_download:pl": 1},
+		"2026-10-19": {"content_requested:pl": 3},
+	}}
+
+	tests := []struct {
+		name       string
+		reader     *mockStatsReader
+		from       string
+		to         string
+		wantDays   int
+		wantTotals map[string]int64
+		wantErr    error
+	}{
+		{
+			name:       "aggregates range",
+			reader:     reader,
+			from:       "2026-10-17",
+			to:         "2026-10-19",
+			wantDays:   3,
+			wantTotals: map[string]int64{"content_requested:pl": 5, "cv_download:pl": 1},
+		},
+		{
+			name:       "defaults to the last week",
+			reader:     reader,
+			wantDays:   7,
+			wantTotals: map[string]int64{"content_requested:pl": 5, "cv_download:pl": 1},
+		},
+		{
+			name:    "invalid date",
+			reader:  reader,
+			from:    "yesterday",
+			wantErr: appErrors.ErrInvalidInput,
+		},
+		{
+			name:    "reversed range",
+			reader:  reader,
+			from:    "2026-10-19",
+			to:      "2026-10-18",
+			wantErr: appErrors.ErrInvalidInput,
+		},
+		{
+			name:    "range beyond retention",
+			reader:  reader,
+			from:    "2026-09-01",
+			to:      "2026-10-19",
+			wantErr: appErrors.ErrInvalidInput,
+		},
+		{
+			name:    "redis error",
+			reader:  &mockStatsReader{err: errors.New("redis error")},
+			from:    "2026-10-19",
+			to:      "2026-10-19",
+			wantErr: appErrors.ErrInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProcess(tt.reader)
+			p.now = func() time.Time { return time.Date(2026, 10, 19, 15, 0, 0, 0, time.UTC) }
+			got, err := p.Process(context.Background(), tt.from, tt.to)
+			if err != tt.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got.Days) != tt.wantDays {
+				t.Errorf("Process() got %d days, want %d", len(got.Days), tt.wantDays)
+			}
+			for field, n := range tt.wantTotals {
+				if got.Totals[field] != n {
+					t.Errorf("Process() total %s = %d, want %d", field, got.Totals[field], n)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package hash_password
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/password"
+)
+
+var ErrEmptyPassword = errors.New("password must not be empty")
+
+type Process struct{}
+
+func NewProcess() *Process {
+	return &Process{}
+}
+
+func (p *Process) Process(ctx context.Context, plain string, algorithm password.Algorithm) (string, error) {
+	if plain == "" {
+		return "", ErrEmptyPassword
+	}
+
+	hash, err := password.Hash(plain, algorithm)
+	if err != nil {
+		return "", err
+	}
+	if !password.Verify(hash, plain) {
+		return "", errors.New("generated hash does not verify")
+	}
+
+	return hash, nil
+}
@@ -0,0 +1,35 @@
+package hash_password
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/password"
+)
+
+func TestProcess_Process(t *testing.T) {
+	tests := []struct {
+		name      string
+		plain     string
+		algorithm password.Algorithm
+		wantErr   error
+	}{
+		{name: "argon2id", plain: "secret", algorithm: password.AlgorithmArgon2id},
+		{name: "bcrypt", plain: "secret", algorithm: password.AlgorithmBcrypt},
+		{name: "empty password", plain: "", algorithm: password.AlgorithmArgon2id, wantErr: ErrEmptyPassword},
+		{name: "unsupported algorithm", plain: "secret", algorithm: "md5", wantErr: password.ErrUnsupportedAlgorithm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := NewProcess().Process(context.Background(), tt.plain, tt.algorithm)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Process() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !password.Verify(hash, tt.plain) {
+				t.Errorf("Process() = %s, does not verify", hash)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package import_translations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/translation"
+)
+
+var (
+	ErrLanguageMismatch = errors.New("translation file language does not match")
+	ErrUnknownPath      = errors.New("translation unit not present in source document")
+	ErrDuplicatePath    = errors.New("duplicate translation unit")
+	ErrIncomplete       = errors.New("translation has stale or untranslated entries")
+)
+
+type Options struct {
+	DryRun bool
+	Strict bool
+}
+
+type Report struct {
+	Translated   []string
+	Untranslated []string
+	Stale        []string
+}
+
+type Process struct {
+	contentFiles map[string]string
+}
+
+func NewProcess(contentFiles map[string]string) *Process {
+	return &Process{contentFiles: contentFiles}
+}
+
+func (p *Process) Process(ctx context.Context, r io.Reader, format translation.Format, sourceLang, targetLang string, opts Options) (*Report, error) {
+	catalog, err := translation.Decode(r, format)
+	if err != nil {
+		return nil, err
+	}
+	if catalog.SourceLang != "" && catalog.SourceLang != sourceLang {
+		return nil, fmt.Errorf("%w: source %s, expected %s", ErrLanguageMismatch, catalog.SourceLang, sourceLang)
+	}
+	if catalog.TargetLang != "" && catalog.TargetLang != targetLang {
+		return nil, fmt.Errorf("%w: target %s, expected %s", ErrLanguageMismatch, catalog.TargetLang, targetLang)
+	}
+
+	sourceDoc, err := p.readFile(sourceLang)
+	if err != nil {
+		return nil, err
+	}
+	targetDoc, err := p.readFile(targetLang)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := translation.Flatten(sourceDoc)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse content file for lang %s: %w", sourceLang, err)
+	}
+
+	if _, err := translation.Flatten(targetDoc); err != nil {
+		return nil, fmt.Errorf("could not parse content file for lang %s: %w", targetLang, err)
+	}
+
+	sourceValues := make(map[string]string, len(source))
+	for _, e := range source {
+		sourceValues[e.Path] = e.Value
+	}
+
+	units := make(map[string]translation.Unit, len(catalog.Units))
+	for _, u := range catalog.Units {
+		if _, ok := sourceValues[u.Path]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPath, u.Path)
+		}
+		if _, ok := units[u.Path]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicatePath, u.Path)
+		}
+		units[u.Path] = u
+	}
+
+	report := &Report{}
+	values := make(map[string]string, len(units))
+	for _, e := range source {
+		u, ok := units[e.Path]
+		switch {
+		case ok && u.Source != e.Value:
+			report.Stale = append(report.Stale, e.Path)
+		case ok && u.Target != "" && !u.Fuzzy:
+			report.Translated = append(report.Translated, e.Path)
+			values[e.Path] = u.Target
+		default:
+			report.Untranslated = append(report.Untranslated, e.Path)
+		}
+	}
+
+	if opts.Strict && len(report.Stale)+len(report.Untranslated) > 0 {
+		return report, ErrIncomplete
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+
+	merged, err := translation.Merge(targetDoc, sourceDoc, values)
+	if err != nil {
+		return nil, fmt.Errorf("could not merge translations for lang %s: %w", targetLang, err)
+	}
+	if err := os.WriteFile(p.contentFiles[targetLang], merged, 0644); err != nil {
+		return nil, fmt.Errorf("could not write content file for lang %s: %w", targetLang, err)
+	}
+
+	return report, nil
+}
+
+func (p *Process) readFile(lang string) ([]byte, error) {
+	filePath, ok := p.contentFiles[lang]
+	if !ok {
+		return nil, fmt.Errorf("no content file configured for lang %s", lang)
+	}
+
+	doc, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read content file for lang %s: %w", lang, err)
+	}
+
+	return doc, nil
+}
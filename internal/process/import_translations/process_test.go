@@ -0,0 +1,98 @@
+package import_translations
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/translation"
+)
+
+const poHeader = "msgid \"\"\nmsgstr \"\"\n\"Language: en\\n\"\n\"X-Source-Language: pl\\n\"\n\n"
+
+func TestProcess_ImportTranslations(t *testing.T) {
+	tests := []struct {
+		name       string
+		po         string
+		opts       Options
+		wantErr    error
+		wantReport *Report
+		wantEn     string
+	}{
+		{
+			name: "applies translations and reports gaps",
+			po: poHeader +
+				"msgctxt \"a\"\nmsgid \"jeden\"\nmsgstr \"one\"\n\n" +
+				"msgctxt \"b\"\nmsgid \"dwa (stare)\"\nmsgstr \"two (new)\"\n\n" +
+				"#, fuzzy\nmsgctxt \"c\"\nmsgid \"trzy\"\nmsgstr \"three?\"\n\n" +
+				"msgctxt \"d\"\nmsgid \"cztery\"\nmsgstr \"four\"\n",
+			wantReport: &Report{Translated: []string{"a", "d"}, Untranslated: []string{"c"}, Stale: []string{"b"}},
+			wantEn:     "{\n  \"a\": \"one\",\n  \"b\": \"two\",\n  \"note\": \"en only\",\n  \"d\": \"four\"\n}",
+		},
+		{
+			name:       "untranslated paths keep the target without source text",
+			po:         poHeader + "msgctxt \"a\"\nmsgid \"jeden\"\nmsgstr \"one\"\n",
+			wantReport: &Report{Translated: []string{"a"}, Untranslated: []string{"b", "c", "d"}},
+			wantEn:     "{\n  \"a\": \"one\",\n  \"b\": \"two\",\n  \"note\": \"en only\"\n}",
+		},
+		{
+			name:    "unknown path",
+			po:      poHeader + "msgctxt \"z\"\nmsgid \"x\"\nmsgstr \"y\"\n",
+			wantErr: ErrUnknownPath,
+		},
+		{
+			name:    "duplicate path",
+			po:      poHeader + "msgctxt \"a\"\nmsgid \"jeden\"\nmsgstr \"one\"\n\nmsgctxt \"a\"\nmsgid \"jeden\"\nmsgstr \"one\"\n",
+			wantErr: ErrDuplicatePath,
+		},
+		{
+			name:    "language mismatch",
+			po:      strings.Replace(poHeader, "Language: en", "Language: de", 1),
+			wantErr: ErrLanguageMismatch,
+		},
+		{
+			name:       "strict rejects incomplete file",
+			po:         poHeader + "msgctxt \"a\"\nmsgid \"jeden\"\nmsgstr \"one\"\n",
+			opts:       Options{Strict: true},
+			wantErr:    ErrIncomplete,
+			wantReport: &Report{Translated: []string{"a"}, Untranslated: []string{"b", "c", "d"}},
+			wantEn:     `{"a":"one (old)","b":"two","note":"en only"}`,
+		},
+		{
+			name:       "dry run does not write",
+			po:         poHeader + "msgctxt \"a\"\nmsgid \"jeden\"\nmsgstr \"one\"\n",
+			opts:       Options{DryRun: true},
+			wantReport: &Report{Translated: []string{"a"}, Untranslated: []string{"b", "c", "d"}},
+			wantEn:     `{"a":"one (old)","b":"two","note":"en only"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			plPath := filepath.Join(tmpDir, "pl.json")
+			enPath := filepath.Join(tmpDir, "en.json")
+			os.WriteFile(plPath, []byte(`{"a":"jeden","b":"dwa","c":"trzy","d":"cztery"}`), 0644)
+			os.WriteFile(enPath, []byte(`{"a":"one (old)","b":"two","note":"en only"}`), 0644)
+
+			p := NewProcess(map[string]string{"pl": plPath, "en": enPath})
+			report, err := p.Process(context.Background(), strings.NewReader(tt.po), translation.FormatPO, "pl", "en", tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(report, tt.wantReport) {
+				t.Errorf("Process() report = %+v, want %+v", report, tt.wantReport)
+			}
+			if tt.wantEn != "" {
+				got, _ := os.ReadFile(enPath)
+				if string(got) != tt.wantEn {
+					t.Errorf("Process() wrote %s, want %s", got, tt.wantEn)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package manage_access_requests
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/accessrequest"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvmail"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type RequestStore interface {
+	ScanKeys(ctx context.Context, match string) ([]string, error)
+	AccessRequest(ctx context.Context, id string) (accessrequest.Request, bool, error)
+	TakeAccessRequest(ctx context.Context, id string) (accessrequest.Request, bool, error)
+	StoreAccessRequest(ctx context.Context, req accessrequest.Request, ttl time.Duration) error
+}
+
+type CreateTokenTask interface {
+	Execute(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error)
+}
+
+type EmailLinkTask interface {
+	Execute(ctx context.Context, email string, issue cvtoken.Issue) error
+}
+
+type AuditRecorder interface {
+	Record(ctx context.Context, event audit.Event)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, event audit.Event)
+}
+
+type Approval struct {
+	Request accessrequest.Request
+	Issue   cvtoken.Issue
+	Emailed bool
+}
+
+type Process struct {
+	requestStore    RequestStore
+	createTokenTask CreateTokenTask
+	emailLinkTask   EmailLinkTask
+	cvFilePaths     map[string]string
+	auditRecorder   AuditRecorder
+	notifier        Notifier
+	now             func() time.Time
+}
+
+func NewProcess(requestStore RequestStore, createTokenTask CreateTokenTask, emailLinkTask EmailLinkTask, cvFilePaths map[string]string, auditRecorder AuditRecorder, notifier Notifier) *Process {
+	return &Process{
+		requestStore:    requestStore,
+		createTokenTask: createTokenTask,
+		emailLinkTask:   emailLinkTask,
+		cvFilePaths:     cvFilePaths,
+		auditRecorder:   auditRecorder,
+		notifier:        notifier,
+		now:             time.Now,
+	}
+}
+
+func (p *Process) List(ctx context.Context) ([]accessrequest.Request, error) {
+	keys, err := p.requestStore.ScanKeys(ctx, accessrequest.KeyPattern)
+	if err != nil {
+		return nil, errors.ErrInternalServerError
+	}
+
+	requests := []accessrequest.Request{}
+	for _, key := range keys {
+		id := accessrequest.IDFromKey(key)
+		req, found, err := p.requestStore.AccessRequest(ctx, id)
+		if err != nil {
+			log.Printf("ERROR: could not read CV access request %s: %v", id, err)
+			continue
+		}
+		if found {
+			requests = append(requests, req)
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].CreatedAt.Before(requests[j].CreatedAt) })
+
+	return requests, nil
+}
+
+func (p *Process) Inspect(ctx context.Context, id string) (*accessrequest.Request, error) {
+	req, found, err := p.requestStore.AccessRequest(ctx, id)
+	if err != nil {
+		return nil, errors.ErrInternalServerError
+	}
+	if !found {
+		return nil, errors.ErrAccessRequestNotFound
+	}
+
+	return &req, nil
+}
+
+func (p *Process) Approve(ctx context.Context, id string) (*Approval, error) {
+	req, err := p.claim(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	filePath, ok := p.cvFilePaths[req.Lang]
+	if !ok {
+		p.restore(ctx, req)
+		return nil, errors.ErrUnsupportedLanguage
+	}
+	issue, err := p.createTokenTask.Execute(ctx, cvtoken.Metadata{
+		Lang:       req.Lang,
+		Document:   filepath.Base(filePath),
+		Credential: accessrequest.Credential,
+		CaptchaID:  req.CaptchaID,
+	})
+	if err != nil {
+		p.restore(ctx, req)
+		return nil, err
+	}
+
+	approval := &Approval{Request: req, Issue: issue}
+	if req.Email != "" {
+		if err := p.emailLinkTask.Execute(ctx, req.Email, issue); err != nil {
+			log.Printf("ERROR: could not queue CV link email for access request %s: %v", req.ID, err)
+		} else {
+			approval.Emailed = true
+		}
+	}
+
+	log.Printf("INFO: approved CV access request %s for lang %s", req.ID, req.Lang)
+	approved := audit.Event{
+		Type:       audit.EventAccessApproved,
+		Lang:       req.Lang,
+		Document:   issue.Metadata.Document,
+		Credential: accessrequest.Credential,
+		CaptchaID:  req.CaptchaID,
+		Client:     req.Client,
+		Detail:     req.ID,
+	}
+	p.auditRecorder.Record(ctx, approved)
+
+	issued := approved
+	issued.Type = audit.EventTokenIssued
+	issued.Token = cvtoken.Fingerprint(issue.Token)
+	issued.ExpiresAt = issue.ExpiresAt
+	p.auditRecorder.Record(ctx, issued)
+	p.notifier.Notify(ctx, issued)
+
+	if approval.Emailed {
+		emailed := issued
+		emailed.Type = audit.EventLinkEmailed
+		emailed.Detail = cvmail.Domain(req.Email)
+		p.auditRecorder.Record(ctx, emailed)
+	}
+
+	return approval, nil
+}
+
+func (p *Process) Deny(ctx context.Context, id string) error {
+	req, err := p.claim(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("INFO: denied CV access request %s for lang %s", req.ID, req.Lang)
+	p.auditRecorder.Record(ctx, audit.Event{Type: audit.EventAccessDenied, Lang: req.Lang, CaptchaID: req.CaptchaID, Client: req.Client, Detail: req.ID})
+
+	return nil
+}
+
+func (p *Process) claim(ctx context.Context, id string) (accessrequest.Request, error) {
+	if id == "" {
+		return accessrequest.Request{}, errors.ErrInvalidInput
+	}
+
+	req, found, err := p.requestStore.TakeAccessRequest(ctx, id)
+	if err != nil {
+		return accessrequest.Request{}, errors.ErrInternalServerError
+	}
+	if !found {
+		return accessrequest.Request{}, errors.ErrAccessRequestNotFound
+	}
+
+	return req, nil
+}
+
+func (p *Process) restore(ctx context.Context, req accessrequest.Request) {
+	ttl := req.ExpiresAt.Sub(p.now())
+	if ttl <= 0 {
+		return
+	}
+
+	if err := p.requestStore.StoreAccessRequest(ctx, req, ttl); err != nil {
+		log.Printf("ERROR: could not restore CV access request %s: %v", req.ID, err)
+	}
+}
@@ -0,0 +1,244 @@
+package manage_access_requests
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/accessrequest"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type mockRequestStore struct {
+	requests map[string]accessrequest.Request
+	err      error
+	restored []time.Duration
+}
+
+func (m *mockRequestStore) ScanKeys(ctx context.Context, match string) ([]string, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var keys []string
+	for id := range m.requests {
+		keys = append(keys, accessrequest.Key(id))
+	}
+	return keys, nil
+}
+
+func (m *mockRequestStore) AccessRequest(ctx context.Context, id string) (accessrequest.Request, bool, error) {
+	req, ok := m.requests[id]
+	return req, ok, m.err
+}
+
+func (m *mockRequestStore) TakeAccessRequest(ctx context.Context, id string) (accessrequest.Request, bool, error) {
+	req, ok := m.requests[id]
+	delete(m.requests, id)
+	return req, ok, m.err
+}
+
+func (m *mockRequestStore) StoreAccessRequest(ctx context.Context, req accessrequest.Request, ttl time.Duration) error {
+	m.requests[req.ID] = req
+	m.restored = append(m.restored, ttl)
+	return nil
+}
+
+type mockCreateTokenTask struct {
+	err      error
+	metadata []cvtoken.Metadata
+}
+
+func (m *mockCreateTokenTask) Execute(ctx context.Context, metadata cvtoken.Metadata) (cvtoken.Issue, error) {
+	if m.err != nil {
+		return cvtoken.Issue{}, m.err
+	}
+	m.metadata = append(m.metadata, metadata)
+	return cvtoken.Issue{Token: "token", Metadata: metadata, ExpiresAt: time.Date(2026, 3, 2, 13, 0, 0, 0, time.UTC)}, nil
+}
+
+type mockEmailLinkTask struct {
+	err    error
+	emails []string
+}
+
+func (m *mockEmailLinkTask) Execute(ctx context.Context, email string, issue cvtoken.Issue) error {
+	m.emails = append(m.emails, email)
+	return m.err
+}
+
+type mockRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockRecorder) Record(ctx context.Context, event audit.Event) {
+	m.events = append(m.events, event)
+}
+
+func (m *mockRecorder) Notify(ctx context.Context, event audit.Event) {
+	m.events = append(m.events, event)
+}
+
+func eventTypes(events []audit.Event) []audit.EventType {
+	var types []audit.EventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+var (
+	now     = time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	pending = accessrequest.Request{
+		ID:        "5f2c",
+		Name:      "Anna Nowak",
+		Company:   "Acme",
+		Reason:    "Hiring a Go developer.",
+		Lang:      "en",
+		Email:     "anna@acme.com",
+		CaptchaID: "captcha-1",
+		Client:    cvtoken.Fingerprint("client"),
+		CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		ExpiresAt: time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC),
+	}
+)
+
+func newProcess(store *mockRequestStore, create *mockCreateTokenTask, email *mockEmailLinkTask) (*Process, *mockRecorder, *mockRecorder) {
+	auditRecorder, notifier := &mockRecorder{}, &mockRecorder{}
+	p := NewProcess(store, create, email, map[string]string{"pl": "/app/private/pl_cv.pdf", "en": "/app/private/en_cv.pdf"}, auditRecorder, notifier)
+	p.now = func() time.Time { return now }
+
+	return p, auditRecorder, notifier
+}
+
+func TestProcess_List(t *testing.T) {
+	older := pending
+	older.ID = "1a2b"
+	older.CreatedAt = pending.CreatedAt.Add(-time.Hour)
+	p, _, _ := newProcess(&mockRequestStore{requests: map[string]accessrequest.Request{"5f2c": pending, "1a2b": older}}, nil, nil)
+
+	got, err := p.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []accessrequest.Request{older, pending}) {
+		t.Errorf("List() = %+v, want oldest first", got)
+	}
+
+	p, _, _ = newProcess(&mockRequestStore{err: errors.New("redis error")}, nil, nil)
+	if _, err := p.List(context.Background()); err != appErrors.ErrInternalServerError {
+		t.Errorf("List() error = %v, want %v", err, appErrors.ErrInternalServerError)
+	}
+}
+
+func TestProcess_Approve(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		createErr    error
+		emailErr     error
+		wantErr      error
+		wantEvents   []audit.EventType
+		wantEmailed  bool
+		wantRestored bool
+	}{
+		{
+			name:        "approval issues and emails a token",
+			id:          "5f2c",
+			wantEvents:  []audit.EventType{audit.EventAccessApproved, audit.EventTokenIssued, audit.EventLinkEmailed},
+			wantEmailed: true,
+		},
+		{
+			name:       "email failure keeps the approval",
+			id:         "5f2c",
+			emailErr:   appErrors.ErrInternalServerError,
+			wantEvents: []audit.EventType{audit.EventAccessApproved, audit.EventTokenIssued},
+		},
+		{
+			name:    "unknown request",
+			id:      "9999",
+			wantErr: appErrors.ErrAccessRequestNotFound,
+		},
+		{
+			name:    "missing id",
+			wantErr: appErrors.ErrInvalidInput,
+		},
+		{
+			name:         "token failure restores the request",
+			id:           "5f2c",
+			createErr:    appErrors.ErrInternalServerError,
+			wantErr:      appErrors.ErrInternalServerError,
+			wantRestored: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockRequestStore{requests: map[string]accessrequest.Request{"5f2c": pending}}
+			create := &mockCreateTokenTask{err: tt.createErr}
+			email := &mockEmailLinkTask{err: tt.emailErr}
+			p, auditRecorder, notifier := newProcess(store, create, email)
+
+			approval, err := p.Approve(context.Background(), tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Approve() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, pending := store.requests["5f2c"]; pending != (tt.wantErr != nil) {
+				t.Errorf("Approve() left the request pending = %v", pending)
+			}
+			if tt.wantRestored && !reflect.DeepEqual(store.restored, []time.Duration{48 * time.Hour}) {
+				t.Errorf("Approve() restored with %v, want the remaining 48h", store.restored)
+			}
+			if !reflect.DeepEqual(eventTypes(auditRecorder.events), tt.wantEvents) {
+				t.Errorf("Approve() audit = %v, want %v", eventTypes(auditRecorder.events), tt.wantEvents)
+			}
+			if tt.wantErr != nil {
+				if approval != nil || len(notifier.events) != 0 {
+					t.Errorf("Approve() = %+v, notified %v, want nothing", approval, notifier.events)
+				}
+				return
+			}
+
+			want := cvtoken.Metadata{Lang: "en", Document: "en_cv.pdf", Credential: accessrequest.Credential, CaptchaID: "captcha-1"}
+			if !reflect.DeepEqual(create.metadata, []cvtoken.Metadata{want}) {
+				t.Errorf("Approve() issued %+v, want %+v", create.metadata, want)
+			}
+			if approval.Issue.Token != "token" || approval.Request != pending || approval.Emailed != tt.wantEmailed {
+				t.Errorf("Approve() = %+v", approval)
+			}
+			if !reflect.DeepEqual(email.emails, []string{"anna@acme.com"}) {
+				t.Errorf("Approve() emailed %v", email.emails)
+			}
+			issued := auditRecorder.events[1]
+			if issued.Token != cvtoken.Fingerprint("token") || issued.Detail != "5f2c" || issued.Credential != accessrequest.Credential || issued.Client != pending.Client {
+				t.Errorf("Approve() token_issued = %+v", issued)
+			}
+			if !reflect.DeepEqual(notifier.events, []audit.Event{issued}) {
+				t.Errorf("Approve() notified %+v, want the issued token", notifier.events)
+			}
+		})
+	}
+}
+
+func TestProcess_Deny(t *testing.T) {
+	store := &mockRequestStore{requests: map[string]accessrequest.Request{"5f2c": pending}}
+	p, auditRecorder, notifier := newProcess(store, &mockCreateTokenTask{}, &mockEmailLinkTask{})
+
+	if err := p.Deny(context.Background(), "5f2c"); err != nil {
+		t.Fatalf("Deny() error = %v", err)
+	}
+	if len(store.requests) != 0 {
+		t.Error("Deny() left the request pending")
+	}
+	want := []audit.Event{{Type: audit.EventAccessDenied, Lang: "en", CaptchaID: "captcha-1", Client: pending.Client, Detail: "5f2c"}}
+	if !reflect.DeepEqual(auditRecorder.events, want) || len(notifier.events) != 0 {
+		t.Errorf("Deny() audit = %+v, notified %+v", auditRecorder.events, notifier.events)
+	}
+
+	if err := p.Deny(context.Background(), "5f2c"); !errors.Is(err, appErrors.ErrAccessRequestNotFound) {
+		t.Errorf("Deny() twice error = %v, want %v", err, appErrors.ErrAccessRequestNotFound)
+	}
+}
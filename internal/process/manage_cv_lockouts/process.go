@@ -0,0 +1,61 @@
+package manage_cv_lockouts
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+)
+
+type LockoutStore interface {
+	ListLockouts(ctx context.Context) ([]lockout.State, error)
+	ClearLockout(ctx context.Context, client string) (bool, error)
+}
+
+type AuditRecorder interface {
+	Record(ctx context.Context, event audit.Event)
+}
+
+type Process struct {
+	lockoutStore  LockoutStore
+	auditRecorder AuditRecorder
+}
+
+func NewProcess(lockoutStore LockoutStore, auditRecorder AuditRecorder) *Process {
+	return &Process{
+		lockoutStore:  lockoutStore,
+		auditRecorder: auditRecorder,
+	}
+}
+
+func (p *Process) List(ctx context.Context) ([]lockout.State, error) {
+	states, err := p.lockoutStore.ListLockouts(ctx)
+	if err != nil {
+		return nil, errors.ErrInternalServerError
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].LastFailure.After(states[j].LastFailure) })
+
+	return states, nil
+}
+
+func (p *Process) Clear(ctx context.Context, client string) error {
+	if client == "" {
+		return errors.ErrInvalidInput
+	}
+
+	cleared, err := p.lockoutStore.ClearLockout(ctx, client)
+	if err != nil {
+		return errors.ErrInternalServerError
+	}
+	if !cleared {
+		return errors.ErrLockoutNotFound
+	}
+
+	log.Printf("INFO: cleared CV lockout for client %s", client)
+	p.auditRecorder.Record(ctx, audit.Event{Type: audit.EventLockoutCleared, Client: client})
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package manage_cv_lockouts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+)
+
+type mockLockoutStore struct {
+	states []lockout.State
+	err    error
+}
+
+func (m *mockLockoutStore) ListLockouts(ctx context.Context) ([]lockout.State, error) {
+	return m.states, m.err
+}
+
+func (m *mockLockoutStore) ClearLockout(ctx context.Context, client string) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	for i, state := range m.states {
+		if state.Client == client {
+			m.states = append(m.states[:i], m.states[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(ctx context.Context, event audit.Event) {
+	m.events = append(m.events, event)
+}
+
+func TestProcess_List(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := &mockLockoutStore{states: []lockout.State{
+		{Client: "a", LastFailure: now.Add(-time.Hour)},
+		{Client: "b", LastFailure: now},
+	}}
+
+	states, err := NewProcess(store, &mockAuditRecorder{}).List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 2 || states[0].Client != "b" || states[1].Client != "a" {
+		t.Errorf("List() = %+v, want most recent failure first", states)
+	}
+
+	if _, err := NewProcess(&mockLockoutStore{err: errors.New("redis error")}, &mockAuditRecorder{}).List(context.Background()); err != appErrors.ErrInternalServerError {
+		t.Errorf("List() error = %v, want %v", err, appErrors.ErrInternalServerError)
+	}
+}
+
+func TestProcess_Clear(t *testing.T) {
+	tests := []struct {
+		name      string
+		store     *mockLockoutStore
+		client    string
+		wantErr   error
+		wantAudit bool
+	}{
+		{
+			name:      "cleared",
+			store:     &mockLockoutStore{states: []lockout.State{{Client: "fp"}}},
+			client:    "fp",
+			wantAudit: true,
+		},
+		{
+			name:    "unknown client",
+			store:   &mockLockoutStore{},
+			client:  "fp",
+			wantErr: appErrors.ErrLockoutNotFound,
+		},
+		{
+			name:    "missing client",
+			store:   &mockLockoutStore{},
+			wantErr: appErrors.ErrInvalidInput,
+		},
+		{
+			name:    "store error",
+			store:   &mockLockoutStore{err: errors.New("redis error")},
+			client:  "fp",
+			wantErr: appErrors.ErrInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditRecorder := &mockAuditRecorder{}
+			err := NewProcess(tt.store, auditRecorder).Clear(context.Background(), tt.client)
+			if err != tt.wantErr {
+				t.Errorf("Clear() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := len(auditRecorder.events) == 1 && auditRecorder.events[0] == (audit.Event{Type: audit.EventLockoutCleared, Client: "fp"}); got != tt.wantAudit {
+				t.Errorf("Clear() audit = %+v, want recorded %v", auditRecorder.events, tt.wantAudit)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package manage_cv_tokens
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	serviceRedis "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/redis"
+)
+
+type TokenStore interface {
+	ScanKeys(ctx context.Context, match string) ([]string, error)
+	InspectToken(ctx context.Context, key string) (serviceRedis.StoredToken, bool, error)
+	RevokeToken(ctx context.Context, key string) (bool, error)
+	RevokeCredentialTokens(ctx context.Context, key string, at time.Time, ttl time.Duration) error
+}
+
+type AuditRecorder interface {
+	Record(ctx context.Context, event audit.Event)
+}
+
+type TokenInfo struct {
+	Token     string
+	Metadata  cvtoken.Metadata
+	Remaining int64
+	FirstUsed time.Time
+	TTL       time.Duration
+}
+
+type Process struct {
+	tokenStore    TokenStore
+	auditRecorder AuditRecorder
+	signed        bool
+	tokenTTL      time.Duration
+	now           func() time.Time
+}
+
+func NewProcess(tokenStore TokenStore, auditRecorder AuditRecorder, tokenMode string, tokenTTL time.Duration) *Process {
+	return &Process{
+		tokenStore:    tokenStore,
+		auditRecorder: auditRecorder,
+		signed:        tokenMode == cvtoken.ModeSigned,
+		tokenTTL:      tokenTTL,
+		now:           time.Now,
+	}
+}
+
+func (p *Process) List(ctx context.Context, credential string) ([]TokenInfo, error) {
+	if p.signed {
+		return nil, errors.ErrCVTokenModeUnsupported
+	}
+
+	keys, err := p.tokenStore.ScanKeys(ctx, cvtoken.KeyPattern)
+	if err != nil {
+		return nil, errors.ErrInternalServerError
+	}
+
+	tokens := []TokenInfo{}
+	for _, key := range keys {
+		info, found, err := p.inspect(ctx, cvtoken.TokenFromKey(key))
+		if err != nil {
+			return nil, err
+		}
+		if !found || (credential != "" && info.Metadata.Credential != credential) {
+			continue
+		}
+		tokens = append(tokens, info)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Metadata.IssuedAt.Before(tokens[j].Metadata.IssuedAt) })
+
+	return tokens, nil
+}
+
+func (p *Process) Inspect(ctx context.Context, token string) (*TokenInfo, error) {
+	if p.signed {
+		return nil, errors.ErrCVTokenModeUnsupported
+	}
+
+	info, found, err := p.inspect(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.ErrCVTokenNotFound
+	}
+
+	return &info, nil
+}
+
+func (p *Process) Revoke(ctx context.Context, token string) error {
+	info, err := p.Inspect(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return p.revoke(ctx, *info)
+}
+
+func (p *Process) RevokeCredential(ctx context.Context, credential string) (int, error) {
+	if credential == "" {
+		return 0, errors.ErrInvalidInput
+	}
+	if p.signed {
+		return 0, p.revokeSigned(ctx, credential)
+	}
+
+	tokens, err := p.List(ctx, credential)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for _, info := range tokens {
+		err := p.revoke(ctx, info)
+		if err == errors.ErrCVTokenNotFound {
+			continue
+		}
+		if err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+
+	return revoked, nil
+}
+
+func (p *Process) revokeSigned(ctx context.Context, credential string) error {
+	now := p.now()
+	if err := p.tokenStore.RevokeCredentialTokens(ctx, cvtoken.RevocationKey(credential), now, p.tokenTTL); err != nil {
+		return errors.ErrInternalServerError
+	}
+
+	log.Printf("INFO: revoked signed CV tokens issued via credential %s until %s", credential, now.Format(time.RFC3339))
+	p.auditRecorder.Record(ctx, audit.Event{
+		Type:       audit.EventTokenRevoked,
+		Credential: credential,
+		Detail:     "signed tokens issued until " + now.UTC().Format(time.RFC3339),
+	})
+
+	return nil
+}
+
+func (p *Process) inspect(ctx context.Context, token string) (TokenInfo, bool, error) {
+	stored, found, err := p.tokenStore.InspectToken(ctx, cvtoken.Key(token))
+	if err != nil {
+		return TokenInfo{}, false, errors.ErrInternalServerError
+	}
+	if !found {
+		return TokenInfo{}, false, nil
+	}
+
+	metadata, err := cvtoken.Decode(stored.Value)
+	if err != nil {
+		log.Printf("ERROR: could not read metadata of CV token %s: %v", mask(token), err)
+	}
+
+	return TokenInfo{Token: token, Metadata: metadata, Remaining: stored.Remaining, FirstUsed: stored.FirstUsed, TTL: stored.TTL}, true, nil
+}
+
+func (p *Process) revoke(ctx context.Context, info TokenInfo) error {
+	revoked, err := p.tokenStore.RevokeToken(ctx, cvtoken.Key(info.Token))
+	if err != nil {
+		return errors.ErrInternalServerError
+	}
+	if !revoked {
+		return errors.ErrCVTokenNotFound
+	}
+
+	log.Printf("INFO: revoked CV token %s for lang %s issued via credential %s at %s",
+		mask(info.Token), info.Metadata.Lang, info.Metadata.Credential, info.Metadata.IssuedAt.Format(time.RFC3339))
+	p.auditRecorder.Record(ctx, audit.Event{
+		Type:       audit.EventTokenRevoked,
+		Lang:       info.Metadata.Lang,
+		Document:   info.Metadata.Document,
+		Credential: info.Metadata.Credential,
+		CaptchaID:  info.Metadata.CaptchaID,
+		Client:     info.Metadata.Fingerprint,
+		Token:      cvtoken.Fingerprint(info.Token),
+	})
+
+	return nil
+}
+
+func mask(token string) string {
+	if len(token) <= 6 {
+		return token
+	}
+	return token[:6] + "..."
+}
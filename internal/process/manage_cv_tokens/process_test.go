@@ -0,0 +1,204 @@
+package manage_cv_tokens
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	serviceRedis "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/redis"
+)
+
+type credentialRevocation struct {
+	key string
+	at  time.Time
+	ttl time.Duration
+}
+
+type mockTokenStore struct {
+	tokens      map[string]serviceRedis.StoredToken
+	scanErr     error
+	revoked     []string
+	revocations []credentialRevocation
+}
+
+func (m *mockTokenStore) ScanKeys(ctx context.Context, match string) ([]string, error) {
+	if m.scanErr != nil {
+		return nil, m.scanErr
+	}
+	var keys []string
+	for key := range m.tokens {
+		if strings.HasPrefix(key, strings.TrimSuffix(match, "*")) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (m *mockTokenStore) InspectToken(ctx context.Context, key string) (serviceRedis.StoredToken, bool, error) {
+	token, ok := m.tokens[key]
+	return token, ok, nil
+}
+
+func (m *mockTokenStore) RevokeToken(ctx context.Context, key string) (bool, error) {
+	if _, ok := m.tokens[key]; !ok {
+		return false, nil
+	}
+	delete(m.tokens, key)
+	m.revoked = append(m.revoked, key)
+	return true, nil
+}
+
+func (m *mockTokenStore) RevokeCredentialTokens(ctx context.Context, key string, at time.Time, ttl time.Duration) error {
+	m.revocations = append(m.revocations, credentialRevocation{key: key, at: at, ttl: ttl})
+	return nil
+}
+
+type mockAuditRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRecorder) Record(ctx context.Context, event audit.Event) {
+	m.events = append(m.events, event)
+}
+
+func newStore() *mockTokenStore {
+	issued := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	stored := func(credential string, offset time.Duration) serviceRedis.StoredToken {
+		value, _ := cvtoken.Metadata{Lang: "pl", IssuedAt: issued.Add(offset), Credential: credential}.Encode()
+		return serviceRedis.StoredToken{Value: value, Remaining: 1, TTL: time.Minute}
+	}
+
+	return &mockTokenStore{tokens: map[string]serviceRedis.StoredToken{
+		"cv_token:t1": stored("acme", 2*time.Second),
+		"cv_token:t2": stored("agency", time.Second),
+		"cv_token:t3": stored("acme", 0),
+	}}
+}
+
+func TestProcess_List(t *testing.T) {
+	tests := []struct {
+		name       string
+		credential string
+		scanErr    error
+		want       []string
+		wantErr    error
+	}{
+		{name: "all tokens oldest first", want: []string{"t3", "t2", "t1"}},
+		{name: "by credential", credential: "acme", want: []string{"t3", "t1"}},
+		{name: "unknown credential", credential: "other", want: []string{}},
+		{name: "redis error", scanErr: errors.New("redis error"), wantErr: appErrors.ErrInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newStore()
+			store.scanErr = tt.scanErr
+
+			tokens, err := NewProcess(store, &mockAuditRecorder{}, cvtoken.ModeRedis, time.Hour).List(context.Background(), tt.credential)
+			if err != tt.wantErr {
+				t.Fatalf("List() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got := []string{}
+			for _, info := range tokens {
+				got = append(got, info.Token)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcess_Inspect(t *testing.T) {
+	p := NewProcess(newStore(), &mockAuditRecorder{}, cvtoken.ModeRedis, time.Hour)
+
+	info, err := p.Inspect(context.Background(), "t2")
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+	if info.Metadata.Credential != "agency" || info.Remaining != 1 || info.TTL != time.Minute {
+		t.Errorf("Inspect() = %+v", info)
+	}
+
+	if _, err := p.Inspect(context.Background(), "missing"); err != appErrors.ErrCVTokenNotFound {
+		t.Errorf("Inspect() error = %v, want %v", err, appErrors.ErrCVTokenNotFound)
+	}
+}
+
+func TestProcess_Revoke(t *testing.T) {
+	store := newStore()
+	auditRecorder := &mockAuditRecorder{}
+	p := NewProcess(store, auditRecorder, cvtoken.ModeRedis, time.Hour)
+
+	if err := p.Revoke(context.Background(), "t2"); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if err := p.Revoke(context.Background(), "t2"); err != appErrors.ErrCVTokenNotFound {
+		t.Errorf("Revoke() error = %v, want %v", err, appErrors.ErrCVTokenNotFound)
+	}
+	if !reflect.DeepEqual(store.revoked, []string{"cv_token:t2"}) {
+		t.Errorf("revoked = %v", store.revoked)
+	}
+	want := audit.Event{Type: audit.EventTokenRevoked, Lang: "pl", Credential: "agency", Token: cvtoken.Fingerprint("t2")}
+	if len(auditRecorder.events) != 1 || auditRecorder.events[0] != want {
+		t.Errorf("audit = %+v, want %+v", auditRecorder.events, want)
+	}
+}
+
+func TestProcess_RevokeCredential(t *testing.T) {
+	store := newStore()
+	p := NewProcess(store, &mockAuditRecorder{}, cvtoken.ModeRedis, time.Hour)
+
+	n, err := p.RevokeCredential(context.Background(), "acme")
+	if err != nil || n != 2 {
+		t.Fatalf("RevokeCredential() = %d, %v, want 2", n, err)
+	}
+	if _, ok := store.tokens["cv_token:t2"]; !ok {
+		t.Error("RevokeCredential() revoked a token of another credential")
+	}
+
+	if _, err := p.RevokeCredential(context.Background(), ""); err != appErrors.ErrInvalidInput {
+		t.Errorf("RevokeCredential() error = %v, want %v", err, appErrors.ErrInvalidInput)
+	}
+}
+
+func TestProcess_SignedMode(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := newStore()
+	auditRecorder := &mockAuditRecorder{}
+	p := NewProcess(store, auditRecorder, cvtoken.ModeSigned, time.Hour)
+	p.now = func() time.Time { return now }
+
+	if _, err := p.List(context.Background(), ""); err != appErrors.ErrCVTokenModeUnsupported {
+		t.Errorf("List() error = %v, want %v", err, appErrors.ErrCVTokenModeUnsupported)
+	}
+	if _, err := p.Inspect(context.Background(), "t1"); err != appErrors.ErrCVTokenModeUnsupported {
+		t.Errorf("Inspect() error = %v, want %v", err, appErrors.ErrCVTokenModeUnsupported)
+	}
+	if err := p.Revoke(context.Background(), "t1"); err != appErrors.ErrCVTokenModeUnsupported {
+		t.Errorf("Revoke() error = %v, want %v", err, appErrors.ErrCVTokenModeUnsupported)
+	}
+
+	if _, err := p.RevokeCredential(context.Background(), "acme"); err != nil {
+		t.Fatalf("RevokeCredential() error = %v", err)
+	}
+	want := []credentialRevocation{{key: "cv_credential_revoked:acme", at: now, ttl: time.Hour}}
+	if !reflect.DeepEqual(store.revocations, want) {
+		t.Errorf("revocations = %+v, want %+v", store.revocations, want)
+	}
+	if len(store.revoked) != 0 {
+		t.Errorf("RevokeCredential() deleted stored tokens %v in signed mode", store.revoked)
+	}
+	if len(auditRecorder.events) != 1 || auditRecorder.events[0].Credential != "acme" || auditRecorder.events[0].Type != audit.EventTokenRevoked {
+		t.Errorf("audit = %+v", auditRecorder.events)
+	}
+}
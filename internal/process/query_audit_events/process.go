@@ -0,0 +1,101 @@
+package query_audit_events
+
+import (
+	"context"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type AuditReader interface {
+	Query(ctx context.Context, filter audit.Filter) ([]audit.Event, error)
+}
+
+type Query struct {
+	From          string
+	To            string
+	Types         []string
+	Credential    string
+	CorrelationID string
+	Limit         int
+}
+
+const (
+	dayLayout    = "2006-01-02"
+	defaultDays  = 7
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+type Process struct {
+	auditReader AuditReader
+	now         func() time.Time
+}
+
+func NewProcess(reader AuditReader) *Process {
+	return &Process{
+		auditReader: reader,
+		now:         time.Now,
+	}
+}
+
+func (p *Process) Process(ctx context.Context, q Query) ([]audit.Event, error) {
+	filter := audit.Filter{
+		Credential:    q.Credential,
+		CorrelationID: q.CorrelationID,
+		Limit:         q.Limit,
+	}
+
+	var err error
+	if filter.To, err = parseTime(q.To, p.now().UTC(), true); err != nil {
+		return nil, errors.ErrInvalidInput
+	}
+	if filter.From, err = parseTime(q.From, filter.To.AddDate(0, 0, -defaultDays), false); err != nil {
+		return nil, errors.ErrInvalidInput
+	}
+	if !filter.From.Before(filter.To) {
+		return nil, errors.ErrInvalidInput
+	}
+
+	for _, t := range q.Types {
+		eventType := audit.EventType(t)
+		if !audit.ValidType(eventType) {
+			return nil, errors.ErrInvalidInput
+		}
+		filter.Types = append(filter.Types, eventType)
+	}
+
+	if filter.Limit < 0 || filter.Limit > maxLimit {
+		return nil, errors.ErrInvalidInput
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultLimit
+	}
+
+	events, err := p.auditReader.Query(ctx, filter)
+	if err != nil {
+		return nil, errors.ErrInternalServerError
+	}
+
+	return events, nil
+}
+
+func parseTime(value string, fallback time.Time, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	day, err := time.Parse(dayLayout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return day, nil
+}
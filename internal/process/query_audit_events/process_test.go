@@ -0,0 +1,83 @@
+package query_audit_events
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type mockAuditReader struct {
+	filter audit.Filter
+	err    error
+}
+
+func (m *mockAuditReader) Query(ctx context.Context, filter audit.Filter) ([]audit.Event, error) {
+	m.filter = filter
+	return []audit.Event{{ID: "1"}}, m.err
+}
+
+func TestProcess_QueryAuditEvents(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		query      Query
+		readerErr  error
+		wantFilter audit.Filter
+		wantErr    error
+	}{
+		{
+			name:       "defaults to the last week",
+			wantFilter: audit.Filter{From: now.AddDate(0, 0, -7), To: now, Limit: 100},
+		},
+		{
+			name:  "days are inclusive",
+			query: Query{From: "2026-10-01", To: "2026-10-02", Types: []string{"token_issued", "token_redeemed"}, Credential: "acme", CorrelationID: "c1", Limit: 5},
+			wantFilter: audit.Filter{
+				From:          time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+				To:            time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC),
+				Types:         []audit.EventType{audit.EventTokenIssued, audit.EventTokenRedeemed},
+				Credential:    "acme",
+				CorrelationID: "c1",
+				Limit:         5,
+			},
+		},
+		{
+			name:       "timestamps",
+			query:      Query{From: "2026-10-19T10:00:00Z", To: "2026-10-19T11:00:00Z"},
+			wantFilter: audit.Filter{From: time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC), To: time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC), Limit: 100},
+		},
+		{name: "invalid date", query: Query{From: "yesterday"}, wantErr: appErrors.ErrInvalidInput},
+		{name: "reversed range", query: Query{From: "2026-10-19", To: "2026-10-18"}, wantErr: appErrors.ErrInvalidInput},
+		{name: "unknown type", query: Query{Types: []string{"downloaded"}}, wantErr: appErrors.ErrInvalidInput},
+		{name: "limit too large", query: Query{Limit: 5000}, wantErr: appErrors.ErrInvalidInput},
+		{name: "store error", readerErr: errors.New("fail"), wantErr: appErrors.ErrInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &mockAuditReader{err: tt.readerErr}
+			p := NewProcess(reader)
+			p.now = func() time.Time { return now }
+
+			events, err := p.Process(context.Background(), tt.query)
+			if err != tt.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(events) != 1 {
+				t.Errorf("Process() events = %v", events)
+			}
+			if !reflect.DeepEqual(reader.filter, tt.wantFilter) {
+				t.Errorf("Process() filter = %+v, want %+v", reader.filter, tt.wantFilter)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package submit_access_request
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/accessrequest"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	serviceRedis "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/redis"
+)
+
+type RedeemCaptchaTask interface {
+	Execute(ctx context.Context, captchaID string) (serviceRedis.RedeemedCaptcha, error)
+	Restore(ctx context.Context, captchaID string, captcha serviceRedis.RedeemedCaptcha)
+}
+
+type EmailLinkTask interface {
+	Validate(email string) (string, error)
+}
+
+type StoreRequestTask interface {
+	Execute(ctx context.Context, req accessrequest.Request) (accessrequest.Request, error)
+}
+
+type AuditRecorder interface {
+	Record(ctx context.Context, event audit.Event)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, event audit.Event)
+}
+
+type Request struct {
+	Name      string
+	Company   string
+	Reason    string
+	Lang      string
+	Email     string
+	CaptchaID string
+	Client    string
+}
+
+type Process struct {
+	enabled           bool
+	redeemCaptchaTask RedeemCaptchaTask
+	emailLinkTask     EmailLinkTask
+	storeRequestTask  StoreRequestTask
+	cvFilePaths       map[string]string
+	auditRecorder     AuditRecorder
+	notifier          Notifier
+}
+
+func NewProcess(enabled bool, redeemCaptchaTask RedeemCaptchaTask, emailLinkTask EmailLinkTask, storeRequestTask StoreRequestTask, cvFilePaths map[string]string, auditRecorder AuditRecorder, notifier Notifier) *Process {
+	return &Process{
+		enabled:           enabled,
+		redeemCaptchaTask: redeemCaptchaTask,
+		emailLinkTask:     emailLinkTask,
+		storeRequestTask:  storeRequestTask,
+		cvFilePaths:       cvFilePaths,
+		auditRecorder:     auditRecorder,
+		notifier:          notifier,
+	}
+}
+
+func (p *Process) Process(ctx context.Context, req Request) (string, error) {
+	if !p.enabled {
+		return "", errors.ErrAccessRequestsUnavailable
+	}
+	if _, ok := p.cvFilePaths[req.Lang]; !ok {
+		return "", errors.ErrUnsupportedLanguage
+	}
+
+	pending, err := accessrequest.Sanitize(req.Name, req.Company, req.Reason)
+	if err != nil {
+		return "", err
+	}
+	if req.Email != "" {
+		if pending.Email, err = p.emailLinkTask.Validate(req.Email); err != nil {
+			return "", err
+		}
+	}
+	pending.Lang = req.Lang
+	pending.CaptchaID = req.CaptchaID
+	pending.Client = cvtoken.Fingerprint(req.Client)
+
+	captcha, err := p.redeemCaptchaTask.Execute(ctx, req.CaptchaID)
+	if err != nil {
+		return "", err
+	}
+
+	stored, err := p.storeRequestTask.Execute(ctx, pending)
+	if err != nil {
+		p.redeemCaptchaTask.Restore(ctx, req.CaptchaID, captcha)
+		return "", err
+	}
+
+	log.Printf("INFO: stored CV access request %s for lang %s, pending until %s", stored.ID, stored.Lang, stored.ExpiresAt.Format(time.RFC3339))
+	event := audit.Event{
+		Type:      audit.EventAccessRequested,
+		Lang:      stored.Lang,
+		CaptchaID: stored.CaptchaID,
+		Client:    stored.Client,
+		Detail:    stored.ID,
+		ExpiresAt: stored.ExpiresAt,
+	}
+	p.auditRecorder.Record(ctx, event)
+	p.notifier.Notify(ctx, event)
+
+	return stored.ID, nil
+}
@@ -0,0 +1,186 @@
+package submit_access_request
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/accessrequest"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+	serviceRedis "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/service/redis"
+)
+
+type mockRedeemCaptchaTask struct {
+	err      error
+	redeemed []string
+	restored []string
+}
+
+func (m *mockRedeemCaptchaTask) Execute(ctx context.Context, captchaID string) (serviceRedis.RedeemedCaptcha, error) {
+	if m.err != nil {
+		return serviceRedis.RedeemedCaptcha{}, m.err
+	}
+	m.redeemed = append(m.redeemed, captchaID)
+	return serviceRedis.RedeemedCaptcha{Solved: true}, nil
+}
+
+func (m *mockRedeemCaptchaTask) Restore(ctx context.Context, captchaID string, captcha serviceRedis.RedeemedCaptcha) {
+	m.restored = append(m.restored, captchaID)
+}
+
+type mockEmailLinkTask struct {
+	err error
+}
+
+func (m *mockEmailLinkTask) Validate(email string) (string, error) {
+	return email, m.err
+}
+
+type mockStoreRequestTask struct {
+	err    error
+	stored []accessrequest.Request
+}
+
+func (m *mockStoreRequestTask) Execute(ctx context.Context, req accessrequest.Request) (accessrequest.Request, error) {
+	if m.err != nil {
+		return accessrequest.Request{}, m.err
+	}
+	req.ID = "5f2c"
+	req.CreatedAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	req.ExpiresAt = req.CreatedAt.Add(72 * time.Hour)
+	m.stored = append(m.stored, req)
+	return req, nil
+}
+
+type mockRecorder struct {
+	events []audit.Event
+}
+
+func (m *mockRecorder) Record(ctx context.Context, event audit.Event) {
+	m.events = append(m.events, event)
+}
+
+func (m *mockRecorder) Notify(ctx context.Context, event audit.Event) {
+	m.events = append(m.events, event)
+}
+
+func TestProcess_Process(t *testing.T) {
+	valid := Request{Name: "Anna Nowak", Company: "Acme", Reason: "Hiring a Go developer.", Lang: "en", Email: "anna@acme.com", CaptchaID: "captcha-1", Client: "client"}
+
+	tests := []struct {
+		name         string
+		enabled      bool
+		req          Request
+		redeemErr    error
+		emailErr     error
+		storeErr     error
+		wantErr      error
+		wantRedeemed bool
+		wantRestored bool
+	}{
+		{
+			name:         "success",
+			enabled:      true,
+			req:          valid,
+			wantRedeemed: true,
+		},
+		{
+			name:    "disabled",
+			req:     valid,
+			wantErr: appErrors.ErrAccessRequestsUnavailable,
+		},
+		{
+			name:    "unsupported language",
+			enabled: true,
+			req:     Request{Name: "Anna", Company: "Acme", Reason: "Hiring a Go developer.", Lang: "de", CaptchaID: "captcha-1"},
+			wantErr: appErrors.ErrUnsupportedLanguage,
+		},
+		{
+			name:    "invalid input is rejected before captcha",
+			enabled: true,
+			req:     Request{Name: "Anna", Company: "Acme", Reason: "CV", Lang: "en", CaptchaID: "captcha-1"},
+			wantErr: appErrors.ErrInvalidAccessReason,
+		},
+		{
+			name:     "email refused",
+			enabled:  true,
+			req:      valid,
+			emailErr: appErrors.ErrCVEmailUnavailable,
+			wantErr:  appErrors.ErrCVEmailUnavailable,
+		},
+		{
+			name:      "captcha not solved",
+			enabled:   true,
+			req:       valid,
+			redeemErr: appErrors.ErrCaptchaNotSolved,
+			wantErr:   appErrors.ErrCaptchaNotSolved,
+		},
+		{
+			name:         "store failure restores captcha",
+			enabled:      true,
+			req:          valid,
+			storeErr:     appErrors.ErrInternalServerError,
+			wantErr:      appErrors.ErrInternalServerError,
+			wantRedeemed: true,
+			wantRestored: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redeem := &mockRedeemCaptchaTask{err: tt.redeemErr}
+			store := &mockStoreRequestTask{err: tt.storeErr}
+			auditRecorder := &mockRecorder{}
+			notifier := &mockRecorder{}
+			p := NewProcess(tt.enabled, redeem, &mockEmailLinkTask{err: tt.emailErr}, store, map[string]string{"pl": "/app/pl_cv.pdf", "en": "/app/en_cv.pdf"}, auditRecorder, notifier)
+
+			id, err := p.Process(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Process() error = %v, want %v", err, tt.wantErr)
+			}
+			if (len(redeem.redeemed) == 1) != tt.wantRedeemed || (len(redeem.restored) == 1) != tt.wantRestored {
+				t.Errorf("Process() redeemed %v and restored %v", redeem.redeemed, redeem.restored)
+			}
+			if tt.wantErr != nil {
+				if id != "" || len(auditRecorder.events) != 0 || len(notifier.events) != 0 {
+					t.Errorf("Process() = %q with events %v, want nothing recorded", id, auditRecorder.events)
+				}
+				return
+			}
+
+			if id != "5f2c" {
+				t.Errorf("Process() id = %q, want 5f2c", id)
+			}
+			want := accessrequest.Request{
+				ID:        "5f2c",
+				Name:      "Anna Nowak",
+				Company:   "Acme",
+				Reason:    "Hiring a Go developer.",
+				Lang:      "en",
+				Email:     "anna@acme.com",
+				CaptchaID: "captcha-1",
+				Client:    cvtoken.Fingerprint("client"),
+				CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+				ExpiresAt: time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC),
+			}
+			if store.stored[0] != want {
+				t.Errorf("Process() stored %+v, want %+v", store.stored[0], want)
+			}
+			wantEvents := []audit.Event{{
+				Type:      audit.EventAccessRequested,
+				Lang:      "en",
+				CaptchaID: "captcha-1",
+				Client:    cvtoken.Fingerprint("client"),
+				Detail:    "5f2c",
+				ExpiresAt: want.ExpiresAt,
+			}}
+			if !reflect.DeepEqual(auditRecorder.events, wantEvents) || !reflect.DeepEqual(notifier.events, wantEvents) {
+				t.Errorf("Process() audit = %+v, notified %+v, want %+v", auditRecorder.events, notifier.events, wantEvents)
+			}
+		})
+	}
+}
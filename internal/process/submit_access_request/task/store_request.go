@@ -0,0 +1,45 @@
+package task
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/accessrequest"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type RequestStore interface {
+	StoreAccessRequest(ctx context.Context, req accessrequest.Request, ttl time.Duration) error
+}
+
+type StoreRequestTask struct {
+	store RequestStore
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+func NewStoreRequestTask(store RequestStore, ttl time.Duration) *StoreRequestTask {
+	return &StoreRequestTask{
+		store: store,
+		ttl:   ttl,
+		now:   time.Now,
+	}
+}
+
+func (t *StoreRequestTask) Execute(ctx context.Context, req accessrequest.Request) (accessrequest.Request, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return accessrequest.Request{}, errors.ErrInternalServerError
+	}
+
+	req.ID = hex.EncodeToString(id)
+	req.CreatedAt = t.now().UTC()
+	req.ExpiresAt = req.CreatedAt.Add(t.ttl)
+	if err := t.store.StoreAccessRequest(ctx, req, t.ttl); err != nil {
+		return accessrequest.Request{}, errors.ErrInternalServerError
+	}
+
+	return req, nil
+}
@@ -0,0 +1,68 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/accessrequest"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type mockRequestStore struct {
+	stored accessrequest.Request
+	ttl    time.Duration
+	err    error
+}
+
+func (m *mockRequestStore) StoreAccessRequest(ctx context.Context, req accessrequest.Request, ttl time.Duration) error {
+	m.stored, m.ttl = req, ttl
+	return m.err
+}
+
+func TestStoreRequestTask_Execute(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ttl := 72 * time.Hour
+	pending := accessrequest.Request{Name: "Anna", Company: "Acme", Reason: "Hiring a Go developer.", Lang: "en", CaptchaID: "captcha-1"}
+
+	tests := []struct {
+		name     string
+		storeErr error
+		wantErr  error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:     "store error",
+			storeErr: errors.New("redis error"),
+			wantErr:  appErrors.ErrInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockRequestStore{err: tt.storeErr}
+			task := NewStoreRequestTask(store, ttl)
+			task.now = func() time.Time { return now }
+
+			got, err := task.Execute(context.Background(), pending)
+			if err != tt.wantErr {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if len(got.ID) != 32 || !got.CreatedAt.Equal(now) || !got.ExpiresAt.Equal(now.Add(ttl)) {
+				t.Errorf("Execute() = %+v", got)
+			}
+			want := pending
+			want.ID, want.CreatedAt, want.ExpiresAt = got.ID, got.CreatedAt, got.ExpiresAt
+			if store.stored != want || got != want || store.ttl != ttl {
+				t.Errorf("Execute() stored %+v for %v, want %+v for %v", store.stored, store.ttl, want, ttl)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package submit_contact
+
+import (
+	"context"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/contact"
+)
+
+type VerifyCaptchaTask interface {
+	Execute(ctx context.Context, captchaID string) error
+}
+
+type RateLimitTask interface {
+	Execute(ctx context.Context, captchaID, email string) error
+}
+
+type EnqueueMessageTask interface {
+	Execute(ctx context.Context, msg contact.Message) error
+}
+
+type Process struct {
+	verifyCaptchaTask  VerifyCaptchaTask
+	rateLimitTask      RateLimitTask
+	enqueueMessageTask EnqueueMessageTask
+}
+
+func NewProcess(verifyCaptchaTask VerifyCaptchaTask, rateLimitTask RateLimitTask, enqueueMessageTask EnqueueMessageTask) *Process {
+	return &Process{
+		verifyCaptchaTask:  verifyCaptchaTask,
+		rateLimitTask:      rateLimitTask,
+		enqueueMessageTask: enqueueMessageTask,
+	}
+}
+
+func (p *Process) Process(ctx context.Context, name, email, message, captchaID string) error {
+	msg, err := contact.Sanitize(name, email, message)
+	if err != nil {
+		return err
+	}
+
+	if err := p.verifyCaptchaTask.Execute(ctx, captchaID); err != nil {
+		return err
+	}
+
+	if err := p.rateLimitTask.Execute(ctx, captchaID, msg.Email); err != nil {
+		return err
+	}
+
+	return p.enqueueMessageTask.Execute(ctx, msg)
+}
@@ -0,0 +1,93 @@
+package submit_contact
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/contact"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type mockVerifyCaptchaTask struct {
+	executeFunc func(ctx context.Context, id string) error
+}
+
+func (m *mockVerifyCaptchaTask) Execute(ctx context.Context, id string) error {
+	return m.executeFunc(ctx, id)
+}
+
+type mockRateLimitTask struct {
+	executeFunc func(ctx context.Context, captchaID, email string) error
+}
+
+func (m *mockRateLimitTask) Execute(ctx context.Context, captchaID, email string) error {
+	return m.executeFunc(ctx, captchaID, email)
+}
+
+type mockEnqueueMessageTask struct {
+	enqueued []contact.Message
+}
+
+func (m *mockEnqueueMessageTask) Execute(ctx context.Context, msg contact.Message) error {
+	m.enqueued = append(m.enqueued, msg)
+	return nil
+}
+
+func TestProcess_Process(t *testing.T) {
+	ok := func(ctx context.Context, id string) error { return nil }
+	notLimited := func(ctx context.Context, captchaID, email string) error { return nil }
+
+	tests := []struct {
+		name              string
+		email             string
+		verifyCaptchaFunc func(context.Context, string) error
+		rateLimitFunc     func(context.Context, string, string) error
+		wantErr           error
+		wantEnqueued      int
+	}{
+		{
+			name:              "success",
+			email:             "jan@example.com",
+			verifyCaptchaFunc: ok,
+			rateLimitFunc:     notLimited,
+			wantEnqueued:      1,
+		},
+		{
+			name:    "invalid input is rejected before captcha",
+			email:   "not-an-email",
+			wantErr: errors.ErrInvalidContactEmail,
+		},
+		{
+			name:              "captcha not solved",
+			email:             "jan@example.com",
+			verifyCaptchaFunc: func(ctx context.Context, id string) error { return errors.ErrCaptchaNotSolved },
+			wantErr:           errors.ErrCaptchaNotSolved,
+		},
+		{
+			name:              "rate limited",
+			email:             "jan@example.com",
+			verifyCaptchaFunc: ok,
+			rateLimitFunc:     func(ctx context.Context, captchaID, email string) error { return errors.ErrRateLimited },
+			wantErr:           errors.ErrRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enqueue := &mockEnqueueMessageTask{}
+			p := NewProcess(
+				&mockVerifyCaptchaTask{executeFunc: tt.verifyCaptchaFunc},
+				&mockRateLimitTask{executeFunc: tt.rateLimitFunc},
+				enqueue,
+			)
+
+			err := p.Process(context.Background(), "Jan", tt.email, "Hello, I have an offer for you.", "captcha")
+			if err != tt.wantErr {
+				t.Errorf("Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(enqueue.enqueued) != tt.wantEnqueued {
+				t.Errorf("Process() enqueued %d messages, want %d", len(enqueue.enqueued), tt.wantEnqueued)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/contact"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type Outbox interface {
+	Enqueue(ctx context.Context, payload any) error
+}
+
+type EnqueueMessageTask struct {
+	outbox Outbox
+	site   string
+	now    func() time.Time
+}
+
+func NewEnqueueMessageTask(outbox Outbox, site string) *EnqueueMessageTask {
+	return &EnqueueMessageTask{
+		outbox: outbox,
+		site:   site,
+		now:    time.Now,
+	}
+}
+
+func (t *EnqueueMessageTask) Execute(ctx context.Context, msg contact.Message) error {
+	submission := contact.Submission{
+		Site:       t.site,
+		Message:    msg,
+		ReceivedAt: t.now().UTC(),
+	}
+
+	if err := t.outbox.Enqueue(ctx, submission); err != nil {
+		return errors.ErrInternalServerError
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/contact"
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type mockOutbox struct {
+	enqueueFunc func(ctx context.Context, payload any) error
+}
+
+func (m *mockOutbox) Enqueue(ctx context.Context, payload any) error {
+	return m.enqueueFunc(ctx, payload)
+}
+
+func TestEnqueueMessageTask_Execute(t *testing.T) {
+	msg := contact.Message{Name: "Jan", Email: "jan@example.com", Body: "Hello there"}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		enqueueFunc func(context.Context, any) error
+		wantErr     error
+	}{
+		{
+			name: "success",
+			enqueueFunc: func(ctx context.Context, payload any) error {
+				s, ok := payload.(contact.Submission)
+				if !ok || s.Site != "main" || s.Message != msg || !s.ReceivedAt.Equal(now) {
+					return errors.New("unexpected payload")
+				}
+				return nil
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "outbox error",
+			enqueueFunc: func(ctx context.Context, payload any) error { return errors.New("redis error") },
+			wantErr:     appErrors.ErrInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewEnqueueMessageTask(&mockOutbox{enqueueFunc: tt.enqueueFunc}, "main")
+			task.now = func() time.Time { return now }
+			if err := task.Execute(context.Background(), msg); err != tt.wantErr {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package task
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type WindowCounter interface {
+	IncrementWindow(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+type RateLimitTask struct {
+	counter    WindowCounter
+	window     time.Duration
+	perCaptcha int64
+	perEmail   int64
+}
+
+func NewRateLimitTask(counter WindowCounter, window time.Duration, perCaptcha, perEmail int) *RateLimitTask {
+	return &RateLimitTask{
+		counter:    counter,
+		window:     window,
+		perCaptcha: int64(perCaptcha),
+		perEmail:   int64(perEmail),
+	}
+}
+
+func (t *RateLimitTask) Execute(ctx context.Context, captchaID, email string) error {
+	sum := sha256.Sum256([]byte(strings.ToLower(email)))
+
+	limits := []struct {
+		key   string
+		limit int64
+	}{
+		{key: "contact:limit:captcha:" + captchaID, limit: t.perCaptcha},
+		{key: "contact:limit:email:" + hex.EncodeToString(sum[:]), limit: t.perEmail},
+	}
+
+	for _, l := range limits {
+		n, err := t.counter.IncrementWindow(ctx, l.key, t.window)
+		if err != nil {
+			return errors.ErrInternalServerError
+		}
+		if n > l.limit {
+			return errors.ErrRateLimited
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	appErrors "github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/errors"
+)
+
+type mockWindowCounter struct {
+	counts map[string]int64
+	err    error
+}
+
+func (m *mockWindowCounter) IncrementWindow(ctx context.Context, key string, window time.Duration) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.counts[key]++
+	return m.counts[key], nil
+}
+
+func TestRateLimitTask_Execute(t *testing.T) {
+	t.Run("limits per captcha", func(t *testing.T) {
+		task := NewRateLimitTask(&mockWindowCounter{counts: map[string]int64{}}, time.Hour, 1, 5)
+
+		if err := task.Execute(context.Background(), "c1", "jan@example.com"); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if err := task.Execute(context.Background(), "c1", "jan@example.com"); err != appErrors.ErrRateLimited {
+			t.Errorf("Execute() error = %v, want %v", err, appErrors.ErrRateLimited)
+		}
+	})
+
+	t.Run("limits per email regardless of case", func(t *testing.T) {
+		counter := &mockWindowCounter{counts: map[string]int64{}}
+		task := NewRateLimitTask(counter, time.Hour, 1, 2)
+
+		task.Execute(context.Background(), "c1", "jan@example.com")
+		task.Execute(context.Background(), "c2", "JAN@example.com")
+		if err := task.Execute(context.Background(), "c3", "jan@example.com"); err != appErrors.ErrRateLimited {
+			t.Errorf("Execute() error = %v, want %v", err, appErrors.ErrRateLimited)
+		}
+		for key := range counter.counts {
+			if strings.Contains(key, "jan@") {
+				t.Errorf("email stored in plain text in key %s", key)
+			}
+		}
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		task := NewRateLimitTask(&mockWindowCounter{err: errors.New("redis error")}, time.Hour, 1, 1)
+		if err := task.Execute(context.Background(), "c1", "jan@example.com"); err != appErrors.ErrInternalServerError {
+			t.Errorf("Execute() error = %v, want %v", err, appErrors.ErrInternalServerError)
+		}
+	})
+}
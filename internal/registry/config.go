@@ -1,11 +1,24 @@
 package registry
 
 import (
+	"encoding/base64"
+	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/credential"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/cvtoken"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pipeline"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pow"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/ratelimit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/webhook"
 	"gopkg.in/yaml.v3"
 )
 
@@ -33,7 +46,68 @@ type RabbitMQTopologyConfig struct {
 	Bindings  []BindingConfig        `yaml:"bindings"`
 }
 
+type ContentConfig struct {
+	DefaultLang string
+	Files       map[string]string
+	AssetsDir   string
+}
+
+type CvConfig struct {
+	Password       string
+	Credentials    []credential.Credential
+	TokenTTL       time.Duration
+	TokenMode      string
+	SigningKeys    []cvtoken.SigningKey
+	MaxDownloads   int
+	DownloadGrace  time.Duration
+	Files          map[string]string `yaml:"files"`
+	DownloadName   string
+	RateLimit      CvRateLimitConfig
+	Lockout        lockout.Policy
+	Pow            PowConfig
+	Pipeline       []pipeline.Step
+	ReplyWindow    time.Duration
+	Watermark      WatermarkConfig
+	Email          EmailConfig
+	AccessRequests AccessRequestConfig
+}
+
+type WatermarkConfig struct {
+	Enabled   bool
+	Retention time.Duration
+}
+
+type EmailConfig struct {
+	Enabled        bool
+	LinkURL        string
+	AllowedDomains []string
+	TokenTTL       time.Duration
+}
+
+type AccessRequestConfig struct {
+	Enabled  bool
+	TTL      time.Duration
+	TokenTTL time.Duration
+}
+
+type PowConfig struct {
+	Secret     []byte
+	Difficulty int
+}
+
+type CvRateLimitConfig struct {
+	PerIP      ratelimit.Limit
+	PerCaptcha ratelimit.Limit
+	Global     ratelimit.Limit
+}
+
+type SiteConfig struct {
+	Content ContentConfig
+	Cv      CvConfig
+}
+
 type Config struct {
+	Env    string
 	Server struct {
 		GRPCPort string
 		HTTPPort string
@@ -54,23 +128,106 @@ type Config struct {
 		}
 		Topology RabbitMQTopologyConfig
 	}
-	Content struct {
-		DefaultLang string
-		Files       map[string]string
+	Content     ContentConfig
+	Cv          CvConfig
+	DefaultSite string
+	Sites       map[string]SiteConfig
+	Stats       struct {
+		RetentionDays int
+	}
+	Audit struct {
+		Storage       string
+		Path          string
+		RetentionDays int
+	}
+	Admin struct {
+		Token string
 	}
-	Cv struct {
+	Contact struct {
+		Recipients []string
+		RateLimit  struct {
+			Window     time.Duration
+			PerCaptcha int
+			PerEmail   int
+		}
+	}
+	SMTP struct {
+		Host     string
+		Port     int
+		Username string
 		Password string
-		TokenTTL time.Duration
-		Files    map[string]string `yaml:"files"`
+		From     string
+	}
+	Outbox struct {
+		MaxAttempts int
+		RetryDelay  time.Duration
 	}
-	Captcha struct {
-		TtlMinutes int `yaml:"ttlMinutes"`
+	Webhooks struct {
+		Timeout   time.Duration
+		Endpoints []webhook.Endpoint
+	}
+	Assets struct {
+		CacheDir string
+		Widths   []int
 	}
 }
 
 var Cfg *Config
 
+// site names end up in Redis key prefixes, SCAN patterns and environment variable names
+var siteNamePattern = regexp.MustCompile(`^[a-z0-9-]{1,32}$`)
+
+const (
+	EnvProduction             = "production"
+	EnvLocal                  = "local"
+	defaultDownloadName       = "cv.pdf"
+	defaultMaxDownloads       = 1
+	defaultStatsRetentionDays = 90
+	defaultAuditRetentionDays = 365
+	defaultLockoutReset       = 24 * time.Hour
+	defaultStampRetentionDays = 365
+	defaultAccessRequestTTL   = 7 * 24 * time.Hour
+	defaultEmailTokenTTL      = 24 * time.Hour
+	defaultApprovalTokenTTL   = 72 * time.Hour
+	defaultWebhookTimeout     = 5 * time.Second
+)
+
 func LoadConfig() (*Config, error) {
+	type yamlContent struct {
+		DefaultLang string            `yaml:"defaultLang"`
+		Files       map[string]string `yaml:"files"`
+		AssetsDir   string            `yaml:"assetsDir"`
+	}
+	type yamlCv struct {
+		Password     string            `yaml:"password"`
+		Credentials  []yamlCredential  `yaml:"credentials"`
+		TokenTTL     int               `yaml:"tokenTTLSeconds"`
+		TokenMode    string            `yaml:"tokenMode"`
+		SigningKeys  []yamlSigningKey  `yaml:"signingKeys"`
+		MaxDownloads int               `yaml:"maxDownloads"`
+		GraceSeconds int               `yaml:"downloadGraceSeconds"`
+		Files        map[string]string `yaml:"files"`
+		DownloadName string            `yaml:"downloadName"`
+		RateLimit    struct {
+			PerIP      yamlLimit `yaml:"perIp"`
+			PerCaptcha yamlLimit `yaml:"perCaptcha"`
+			Global     yamlLimit `yaml:"global"`
+		} `yaml:"rateLimit"`
+		Lockout yamlLockout `yaml:"lockout"`
+		Pow     struct {
+			Secret     string `yaml:"secret"`
+			Difficulty int    `yaml:"difficulty"`
+		} `yaml:"pow"`
+		Pipeline           []yamlStep         `yaml:"pipeline"`
+		ReplyWindowSeconds int                `yaml:"replyWindowSeconds"`
+		Watermark          yamlWatermark      `yaml:"watermark"`
+		Email              yamlEmail          `yaml:"email"`
+		AccessRequests     yamlAccessRequests `yaml:"accessRequests"`
+	}
+	type yamlSite struct {
+		Content yamlContent `yaml:"content"`
+		Cv      yamlCv      `yaml:"cv"`
+	}
 	type yamlConfig struct {
 		Server struct {
 			GRPCPort string `yaml:"grpcPort"`
@@ -92,23 +249,53 @@ func LoadConfig() (*Config, error) {
 			} `yaml:"consumers"`
 			Topology RabbitMQTopologyConfig `yaml:"topology"`
 		} `yaml:"rabbitmq"`
-		Content struct {
-			DefaultLang string            `yaml:"defaultLang"`
-			Files       map[string]string `yaml:"files"`
-		} `yaml:"content"`
-		Cv struct {
-			Password string            `yaml:"password"`
-			TokenTTL int               `yaml:"tokenTTLSeconds"`
-			Files    map[string]string `yaml:"files"`
-		} `yaml:"cv"`
-		Captcha struct {
-			TtlMinutes int `yaml:"ttlMinutes"`
-		} `yaml:"captcha"`
+		Content     yamlContent         `yaml:"content"`
+		Cv          yamlCv              `yaml:"cv"`
+		DefaultSite string              `yaml:"defaultSite"`
+		Sites       map[string]yamlSite `yaml:"sites"`
+		Stats       struct {
+			RetentionDays int `yaml:"retentionDays"`
+		} `yaml:"stats"`
+		Audit struct {
+			Storage       string `yaml:"storage"`
+			Path          string `yaml:"path"`
+			RetentionDays int    `yaml:"retentionDays"`
+		} `yaml:"audit"`
+		Admin struct {
+			Token string `yaml:"token"`
+		} `yaml:"admin"`
+		Contact struct {
+			Recipients []string `yaml:"recipients"`
+			RateLimit  struct {
+				WindowMinutes int `yaml:"windowMinutes"`
+				PerCaptcha    int `yaml:"perCaptcha"`
+				PerEmail      int `yaml:"perEmail"`
+			} `yaml:"rateLimit"`
+		} `yaml:"contact"`
+		SMTP struct {
+			Host     string `yaml:"host"`
+			Port     int    `yaml:"port"`
+			Username string `yaml:"username"`
+			Password string `yaml:"password"`
+			From     string `yaml:"from"`
+		} `yaml:"smtp"`
+		Outbox struct {
+			MaxAttempts       int `yaml:"maxAttempts"`
+			RetryDelaySeconds int `yaml:"retryDelaySeconds"`
+		} `yaml:"outbox"`
+		Webhooks struct {
+			TimeoutSeconds int           `yaml:"timeoutSeconds"`
+			Endpoints      []yamlWebhook `yaml:"endpoints"`
+		} `yaml:"webhooks"`
+		Assets struct {
+			CacheDir string `yaml:"cacheDir"`
+			Widths   []int  `yaml:"widths"`
+		} `yaml:"assets"`
 	}
 
 	env := os.Getenv("APP_ENV")
-	if env != "production" {
-		env = "local"
+	if env != EnvProduction {
+		env = EnvLocal
 	}
 	configPath := filepath.Join("config", env, "config.yml")
 	log.Printf("INFO: loading configuration from %s", configPath)
@@ -125,6 +312,7 @@ func LoadConfig() (*Config, error) {
 	}
 
 	cfg := &Config{}
+	cfg.Env = env
 	cfg.Server.GRPCPort = yc.Server.GRPCPort
 	cfg.Server.HTTPPort = yc.Server.HTTPPort
 	cfg.Infrastructure.Retry.MaxAttempts = yc.Infrastructure.Retry.MaxAttempts
@@ -135,18 +323,475 @@ func LoadConfig() (*Config, error) {
 	cfg.RabbitMQ.Topology = yc.RabbitMQ.Topology
 	cfg.Content.DefaultLang = yc.Content.DefaultLang
 	cfg.Content.Files = yc.Content.Files
+	cfg.Content.AssetsDir = yc.Content.AssetsDir
 	cfg.Cv.Password = yc.Cv.Password
 	cfg.Cv.TokenTTL = time.Duration(yc.Cv.TokenTTL) * time.Second
+	cfg.Cv.MaxDownloads = yc.Cv.MaxDownloads
+	if cfg.Cv.MaxDownloads <= 0 {
+		cfg.Cv.MaxDownloads = defaultMaxDownloads
+	}
+	cfg.Cv.DownloadGrace = time.Duration(yc.Cv.GraceSeconds) * time.Second
+	cfg.Cv.ReplyWindow = time.Duration(yc.Cv.ReplyWindowSeconds) * time.Second
 	cfg.Cv.Files = yc.Cv.Files
-	cfg.Captcha.TtlMinutes = yc.Captcha.TtlMinutes
+	cfg.Cv.DownloadName = yc.Cv.DownloadName
+	if cfg.Cv.DownloadName == "" {
+		cfg.Cv.DownloadName = defaultDownloadName
+	}
+	cfg.Cv.RateLimit.PerIP = yc.Cv.RateLimit.PerIP.limit()
+	cfg.Cv.RateLimit.PerCaptcha = yc.Cv.RateLimit.PerCaptcha.limit()
+	cfg.Cv.RateLimit.Global = yc.Cv.RateLimit.Global.limit()
+	cfg.Cv.Lockout = yc.Cv.Lockout.policy()
+	cfg.Cv.Watermark = yc.Cv.Watermark.config(WatermarkConfig{Retention: defaultStampRetentionDays * 24 * time.Hour})
+	cfg.Cv.Email = yc.Cv.Email.config(EmailConfig{TokenTTL: defaultEmailTokenTTL})
+	if err := checkEmail(cfg.Cv.Email); err != nil {
+		return nil, err
+	}
+	cfg.Cv.AccessRequests = yc.Cv.AccessRequests.config(AccessRequestConfig{TTL: defaultAccessRequestTTL, TokenTTL: defaultApprovalTokenTTL})
+	cfg.Stats.RetentionDays = yc.Stats.RetentionDays
+	if cfg.Stats.RetentionDays <= 0 {
+		cfg.Stats.RetentionDays = defaultStatsRetentionDays
+	}
+	cfg.Audit.Storage = yc.Audit.Storage
+	if cfg.Audit.Storage == "" {
+		cfg.Audit.Storage = audit.StorageRedis
+	}
+	cfg.Audit.Path = yc.Audit.Path
+	cfg.Audit.RetentionDays = yc.Audit.RetentionDays
+	if cfg.Audit.RetentionDays <= 0 {
+		cfg.Audit.RetentionDays = defaultAuditRetentionDays
+	}
+	if err := checkAuditStorage(cfg.Audit.Storage, cfg.Audit.Path); err != nil {
+		return nil, err
+	}
+	cfg.Admin.Token = yc.Admin.Token
+	cfg.Contact.Recipients = yc.Contact.Recipients
+	cfg.Contact.RateLimit.Window = time.Duration(yc.Contact.RateLimit.WindowMinutes) * time.Minute
+	cfg.Contact.RateLimit.PerCaptcha = yc.Contact.RateLimit.PerCaptcha
+	cfg.Contact.RateLimit.PerEmail = yc.Contact.RateLimit.PerEmail
+	cfg.SMTP.Host = yc.SMTP.Host
+	cfg.SMTP.Port = yc.SMTP.Port
+	cfg.SMTP.Username = yc.SMTP.Username
+	cfg.SMTP.Password = yc.SMTP.Password
+	cfg.SMTP.From = yc.SMTP.From
+	cfg.Outbox.MaxAttempts = yc.Outbox.MaxAttempts
+	cfg.Outbox.RetryDelay = time.Duration(yc.Outbox.RetryDelaySeconds) * time.Second
+	cfg.Webhooks.Timeout = time.Duration(yc.Webhooks.TimeoutSeconds) * time.Second
+	if cfg.Webhooks.Timeout <= 0 {
+		cfg.Webhooks.Timeout = defaultWebhookTimeout
+	}
+	if cfg.Webhooks.Endpoints, err = buildWebhooks(yc.Webhooks.Endpoints); err != nil {
+		return nil, err
+	}
+	cfg.Assets.CacheDir = yc.Assets.CacheDir
+	cfg.Assets.Widths = yc.Assets.Widths
 
 	overrideFromEnv("CV_PASSWORD", &cfg.Cv.Password)
+	overrideFromEnv("ADMIN_TOKEN", &cfg.Admin.Token)
+	overrideFromEnv("SMTP_PASSWORD", &cfg.SMTP.Password)
 	overrideFromEnv("REDIS_URL", &cfg.Redis.URL)
 	overrideFromEnv("RABBITMQ_URL", &cfg.RabbitMQ.URL)
 
+	if cfg.Cv.Credentials, err = buildCredentials(cfg.Cv.Password, yc.Cv.Credentials); err != nil {
+		return nil, err
+	}
+	cfg.Cv.TokenMode = yc.Cv.TokenMode
+	if cfg.Cv.TokenMode == "" {
+		cfg.Cv.TokenMode = cvtoken.ModeRedis
+	}
+	if cfg.Cv.SigningKeys, err = buildSigningKeys(yc.Cv.SigningKeys, "CV_SIGNING_KEYS"); err != nil {
+		return nil, err
+	}
+	if err := checkTokenMode(cfg.Cv); err != nil {
+		return nil, err
+	}
+	if cfg.Cv.Pow, err = buildPow(yc.Cv.Pow.Secret, yc.Cv.Pow.Difficulty, "CV_POW_SECRET"); err != nil {
+		return nil, err
+	}
+	if cfg.Cv.Pipeline, err = buildPipeline(yc.Cv.Pipeline, pipeline.Default()); err != nil {
+		return nil, err
+	}
+
+	cfg.DefaultSite = yc.DefaultSite
+	if cfg.DefaultSite == "" {
+		cfg.DefaultSite = "default"
+	}
+	if err := checkSiteName(cfg.DefaultSite); err != nil {
+		return nil, err
+	}
+	cfg.Sites = map[string]SiteConfig{
+		cfg.DefaultSite: {Content: cfg.Content, Cv: cfg.Cv},
+	}
+	for name, ys := range yc.Sites {
+		if name == cfg.DefaultSite {
+			return nil, fmt.Errorf("site %s is already defined by the top-level content and cv sections", name)
+		}
+		if err := checkSiteName(name); err != nil {
+			return nil, err
+		}
+
+		site := SiteConfig{}
+		site.Content.DefaultLang = ys.Content.DefaultLang
+		site.Content.Files = ys.Content.Files
+		site.Content.AssetsDir = ys.Content.AssetsDir
+		site.Cv.Password = ys.Cv.Password
+		site.Cv.TokenTTL = time.Duration(ys.Cv.TokenTTL) * time.Second
+		if site.Cv.TokenTTL == 0 {
+			site.Cv.TokenTTL = cfg.Cv.TokenTTL
+		}
+		site.Cv.MaxDownloads = ys.Cv.MaxDownloads
+		if site.Cv.MaxDownloads <= 0 {
+			site.Cv.MaxDownloads = cfg.Cv.MaxDownloads
+		}
+		site.Cv.DownloadGrace = time.Duration(ys.Cv.GraceSeconds) * time.Second
+		if site.Cv.DownloadGrace == 0 {
+			site.Cv.DownloadGrace = cfg.Cv.DownloadGrace
+		}
+		site.Cv.Files = ys.Cv.Files
+		site.Cv.DownloadName = ys.Cv.DownloadName
+		if site.Cv.DownloadName == "" {
+			site.Cv.DownloadName = defaultDownloadName
+		}
+		site.Cv.RateLimit.PerIP = inheritLimit(ys.Cv.RateLimit.PerIP.limit(), cfg.Cv.RateLimit.PerIP)
+		site.Cv.RateLimit.PerCaptcha = inheritLimit(ys.Cv.RateLimit.PerCaptcha.limit(), cfg.Cv.RateLimit.PerCaptcha)
+		site.Cv.RateLimit.Global = inheritLimit(ys.Cv.RateLimit.Global.limit(), cfg.Cv.RateLimit.Global)
+		site.Cv.Lockout = ys.Cv.Lockout.policy()
+		if !site.Cv.Lockout.Enabled() {
+			site.Cv.Lockout = cfg.Cv.Lockout
+		}
+		site.Cv.Watermark = ys.Cv.Watermark.config(cfg.Cv.Watermark)
+		site.Cv.Email = ys.Cv.Email.config(cfg.Cv.Email)
+		if err := checkEmail(site.Cv.Email); err != nil {
+			return nil, fmt.Errorf("site %s: %w", name, err)
+		}
+		site.Cv.AccessRequests = ys.Cv.AccessRequests.config(cfg.Cv.AccessRequests)
+		overrideFromEnv(siteEnvKey("CV_PASSWORD", name), &site.Cv.Password)
+		if site.Cv.Credentials, err = buildCredentials(site.Cv.Password, ys.Cv.Credentials); err != nil {
+			return nil, fmt.Errorf("site %s: %w", name, err)
+		}
+		site.Cv.TokenMode = ys.Cv.TokenMode
+		if site.Cv.TokenMode == "" {
+			site.Cv.TokenMode = cfg.Cv.TokenMode
+		}
+		if site.Cv.SigningKeys, err = buildSigningKeys(ys.Cv.SigningKeys, siteEnvKey("CV_SIGNING_KEYS", name)); err != nil {
+			return nil, fmt.Errorf("site %s: %w", name, err)
+		}
+		if err := checkTokenMode(site.Cv); err != nil {
+			return nil, fmt.Errorf("site %s: %w", name, err)
+		}
+		if site.Cv.Pow, err = buildPow(ys.Cv.Pow.Secret, ys.Cv.Pow.Difficulty, siteEnvKey("CV_POW_SECRET", name)); err != nil {
+			return nil, fmt.Errorf("site %s: %w", name, err)
+		}
+		if site.Cv.Pow.Difficulty == 0 {
+			site.Cv.Pow = cfg.Cv.Pow
+		}
+		if site.Cv.Pipeline, err = buildPipeline(ys.Cv.Pipeline, cfg.Cv.Pipeline); err != nil {
+			return nil, fmt.Errorf("site %s: %w", name, err)
+		}
+
+		cfg.Sites[name] = site
+	}
+
 	return cfg, nil
 }
 
+type yamlCredential struct {
+	Name      string   `yaml:"name"`
+	Password  string   `yaml:"password"`
+	ExpiresAt string   `yaml:"expiresAt"`
+	Langs     []string `yaml:"langs"`
+	MaxUses   int      `yaml:"maxUses"`
+	Revoked   bool     `yaml:"revoked"`
+}
+
+func buildCredentials(password string, entries []yamlCredential) ([]credential.Credential, error) {
+	var credentials []credential.Credential
+	if password != "" {
+		credentials = append(credentials, credential.Credential{Name: credential.DefaultName, Password: password})
+	}
+
+	for _, e := range entries {
+		c := credential.Credential{
+			Name:     e.Name,
+			Password: e.Password,
+			Langs:    e.Langs,
+			MaxUses:  e.MaxUses,
+			Revoked:  e.Revoked,
+		}
+		if e.ExpiresAt != "" {
+			day, err := time.Parse("2006-01-02", e.ExpiresAt)
+			if err != nil {
+				return nil, fmt.Errorf("credential %s: invalid expiresAt %q", e.Name, e.ExpiresAt)
+			}
+			c.ExpiresAt = day.AddDate(0, 0, 1)
+		}
+		credentials = append(credentials, c)
+	}
+
+	if err := credential.Validate(credentials); err != nil {
+		return nil, err
+	}
+
+	return credentials, nil
+}
+
+type yamlSigningKey struct {
+	ID     string `yaml:"id"`
+	Secret string `yaml:"secret"`
+}
+
+func buildSigningKeys(entries []yamlSigningKey, envKey string) ([]cvtoken.SigningKey, error) {
+	if value, exists := os.LookupEnv(envKey); exists && value != "" {
+		entries = nil
+		for _, pair := range strings.Split(value, ",") {
+			id, secret, _ := strings.Cut(strings.TrimSpace(pair), ":")
+			entries = append(entries, yamlSigningKey{ID: id, Secret: secret})
+		}
+	}
+
+	var keys []cvtoken.SigningKey
+	for _, e := range entries {
+		secret, err := base64.StdEncoding.DecodeString(e.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: secret is not valid base64", e.ID)
+		}
+		keys = append(keys, cvtoken.SigningKey{ID: e.ID, Secret: secret})
+	}
+
+	if err := cvtoken.ValidateKeys(keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+type yamlLimit struct {
+	Limit         int `yaml:"limit"`
+	WindowSeconds int `yaml:"windowSeconds"`
+}
+
+func (l yamlLimit) limit() ratelimit.Limit {
+	return ratelimit.Limit{Max: l.Limit, Window: time.Duration(l.WindowSeconds) * time.Second}
+}
+
+func inheritLimit(site, fallback ratelimit.Limit) ratelimit.Limit {
+	if site.Enabled() {
+		return site
+	}
+	return fallback
+}
+
+type yamlLockout struct {
+	Threshold          int `yaml:"threshold"`
+	LockoutSeconds     int `yaml:"lockoutSeconds"`
+	MaxLockoutSeconds  int `yaml:"maxLockoutSeconds"`
+	ResetSeconds       int `yaml:"resetSeconds"`
+	AlertAfterLockouts int `yaml:"alertAfterLockouts"`
+}
+
+func (l yamlLockout) policy() lockout.Policy {
+	policy := lockout.Policy{
+		Threshold:  l.Threshold,
+		Base:       time.Duration(l.LockoutSeconds) * time.Second,
+		Max:        time.Duration(l.MaxLockoutSeconds) * time.Second,
+		Reset:      time.Duration(l.ResetSeconds) * time.Second,
+		AlertLevel: l.AlertAfterLockouts,
+	}
+	if policy.Reset <= 0 {
+		policy.Reset = defaultLockoutReset
+	}
+	if policy.AlertLevel <= 0 {
+		policy.AlertLevel = 1
+	}
+
+	return policy
+}
+
+type yamlWatermark struct {
+	Enabled       *bool `yaml:"enabled"`
+	RetentionDays int   `yaml:"retentionDays"`
+}
+
+func (w yamlWatermark) config(fallback WatermarkConfig) WatermarkConfig {
+	watermark := fallback
+	if w.Enabled != nil {
+		watermark.Enabled = *w.Enabled
+	}
+	if w.RetentionDays > 0 {
+		watermark.Retention = time.Duration(w.RetentionDays) * 24 * time.Hour
+	}
+
+	return watermark
+}
+
+type yamlAccessRequests struct {
+	Enabled       *bool `yaml:"enabled"`
+	TTLHours      int   `yaml:"ttlHours"`
+	TokenTTLHours int   `yaml:"tokenTTLHours"`
+}
+
+func (a yamlAccessRequests) config(fallback AccessRequestConfig) AccessRequestConfig {
+	access := fallback
+	if a.Enabled != nil {
+		access.Enabled = *a.Enabled
+	}
+	if a.TTLHours > 0 {
+		access.TTL = time.Duration(a.TTLHours) * time.Hour
+	}
+	if a.TokenTTLHours > 0 {
+		access.TokenTTL = time.Duration(a.TokenTTLHours) * time.Hour
+	}
+
+	return access
+}
+
+type yamlEmail struct {
+	Enabled         *bool    `yaml:"enabled"`
+	LinkURL         string   `yaml:"linkUrl"`
+	AllowedDomains  []string `yaml:"allowedDomains"`
+	TokenTTLSeconds int      `yaml:"tokenTTLSeconds"`
+}
+
+func (e yamlEmail) config(fallback EmailConfig) EmailConfig {
+	email := fallback
+	if e.Enabled != nil {
+		email.Enabled = *e.Enabled
+	}
+	if e.LinkURL != "" {
+		email.LinkURL = e.LinkURL
+	}
+	if len(e.AllowedDomains) > 0 {
+		email.AllowedDomains = e.AllowedDomains
+	}
+	if e.TokenTTLSeconds > 0 {
+		email.TokenTTL = time.Duration(e.TokenTTLSeconds) * time.Second
+	}
+
+	return email
+}
+
+func checkEmail(email EmailConfig) error {
+	if !email.Enabled {
+		return nil
+	}
+
+	u, err := url.Parse(email.LinkURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("cv email requires an absolute linkUrl, got %q", email.LinkURL)
+	}
+	for _, domain := range email.AllowedDomains {
+		if domain == "" || strings.ContainsAny(domain, "@/ ") {
+			return fmt.Errorf("cv email allowed domain %q is invalid", domain)
+		}
+	}
+
+	return nil
+}
+
+func buildPow(secret string, difficulty int, envKey string) (PowConfig, error) {
+	overrideFromEnv(envKey, &secret)
+	decoded, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return PowConfig{}, fmt.Errorf("pow secret is not valid base64")
+	}
+	if err := pow.ValidateConfig(decoded, difficulty); err != nil {
+		return PowConfig{}, err
+	}
+
+	return PowConfig{Secret: decoded, Difficulty: difficulty}, nil
+}
+
+type yamlWebhook struct {
+	Name   string   `yaml:"name"`
+	URL    string   `yaml:"url"`
+	Format string   `yaml:"format"`
+	Secret string   `yaml:"secret"`
+	Events []string `yaml:"events"`
+}
+
+func buildWebhooks(entries []yamlWebhook) ([]webhook.Endpoint, error) {
+	endpoints := make([]webhook.Endpoint, 0, len(entries))
+	for _, e := range entries {
+		overrideFromEnv(siteEnvKey("WEBHOOK_URL", e.Name), &e.URL)
+		overrideFromEnv(siteEnvKey("WEBHOOK_SECRET", e.Name), &e.Secret)
+
+		endpoint := webhook.Endpoint{Name: e.Name, URL: e.URL, Format: e.Format, Secret: []byte(e.Secret)}
+		if endpoint.Format == "" {
+			endpoint.Format = webhook.FormatJSON
+		}
+		for _, event := range e.Events {
+			endpoint.Events = append(endpoint.Events, audit.EventType(event))
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	if err := webhook.Validate(endpoints); err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+type yamlStep struct {
+	Step    string `yaml:"step"`
+	Enabled *bool  `yaml:"enabled"`
+}
+
+func buildPipeline(steps []yamlStep, fallback []pipeline.Step) ([]pipeline.Step, error) {
+	if len(steps) == 0 {
+		return fallback, nil
+	}
+
+	built := make([]pipeline.Step, 0, len(steps))
+	for _, s := range steps {
+		built = append(built, pipeline.Step{Name: s.Step, Enabled: s.Enabled == nil || *s.Enabled})
+	}
+	if err := pipeline.Validate(built); err != nil {
+		return nil, err
+	}
+
+	return built, nil
+}
+
+func checkTokenMode(cv CvConfig) error {
+	switch cv.TokenMode {
+	case cvtoken.ModeRedis:
+		return nil
+	case cvtoken.ModeSigned:
+		if len(cv.SigningKeys) == 0 {
+			return fmt.Errorf("cv token mode %s requires at least one signing key", cv.TokenMode)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported cv token mode %q", cv.TokenMode)
+	}
+}
+
+func checkSiteName(name string) error {
+	if !siteNamePattern.MatchString(name) {
+		return fmt.Errorf("site name %q must be 1-32 lowercase letters, digits or hyphens", name)
+	}
+
+	return nil
+}
+
+func checkAuditStorage(storage, path string) error {
+	switch storage {
+	case audit.StorageRedis:
+		return nil
+	case audit.StorageFile, audit.StorageSQLite:
+		if path == "" {
+			return fmt.Errorf("audit storage %s requires a path", storage)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported audit storage %q", storage)
+	}
+}
+
+func siteEnvKey(prefix, site string) string {
+	return prefix + "_" + strings.ToUpper(strings.ReplaceAll(site, "-", "_"))
+}
+
 func overrideFromEnv(envKey string, configValue *string) {
 	if value, exists := os.LookupEnv(envKey); exists && value != "" {
 		*configValue = value
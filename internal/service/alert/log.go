@@ -0,0 +1,22 @@
+package alert
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+)
+
+type LogAlerter struct {
+	site string
+}
+
+func NewLogAlerter(site string) *LogAlerter {
+	return &LogAlerter{site: site}
+}
+
+func (a *LogAlerter) LockoutAlert(ctx context.Context, state lockout.State) {
+	log.Printf("ERROR: security alert for site %s: client %s locked out of CV token requests until %s (lockout %d)",
+		a.site, state.Client, state.LockedUntil.UTC().Format(time.RFC3339), state.Level)
+}
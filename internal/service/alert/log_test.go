@@ -0,0 +1,32 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+)
+
+func TestLogAlerter_LockoutAlert(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	NewLogAlerter("main").LockoutAlert(context.Background(), lockout.State{
+		Client:      "fp",
+		Level:       3,
+		LockedUntil: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	})
+
+	got := buf.String()
+	for _, want := range []string{"ERROR: security alert for site main", "client fp", "2026-03-01T12:00:00Z", "lockout 3"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("LockoutAlert() logged %q, want it to contain %q", got, want)
+		}
+	}
+}
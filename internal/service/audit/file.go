@@ -0,0 +1,145 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+)
+
+const maxLineSize = 1024 * 1024
+
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+func NewFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, err
+	}
+
+	s := &FileStore{path: path}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *FileStore) Append(ctx context.Context, event audit.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+
+	return err
+}
+
+func (s *FileStore) Query(ctx context.Context, filter audit.Filter) ([]audit.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []audit.Event
+	err := s.scan(func(line []byte, event audit.Event, ok bool) {
+		if ok && filter.Match(event) {
+			events = append(events, event)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newestFirst(events, filter.Limit), nil
+}
+
+func (s *FileStore) Prune(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var kept bytes.Buffer
+	var pruned int64
+	err := s.scan(func(line []byte, event audit.Event, ok bool) {
+		if ok && event.Time.Before(before) {
+			pruned++
+			return
+		}
+		kept.Write(line)
+		kept.WriteByte('\n')
+	})
+	if err != nil || pruned == 0 {
+		return 0, err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, kept.Bytes(), 0o600); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return 0, err
+	}
+	_ = s.file.Close()
+
+	return pruned, s.open()
+}
+
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+func (s *FileStore) open() error {
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	s.file = file
+
+	return nil
+}
+
+func (s *FileStore) scan(fn func(line []byte, event audit.Event, ok bool)) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var event audit.Event
+		err := json.Unmarshal(line, &event)
+		fn(line, event, err == nil)
+	}
+
+	return scanner.Err()
+}
+
+func newestFirst(events []audit.Event, limit int) []audit.Event {
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+
+	return events
+}
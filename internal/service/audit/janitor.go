@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+)
+
+const (
+	sweepInterval = time.Minute
+	pruneInterval = time.Hour
+	claimBatch    = 100
+)
+
+type Janitor struct {
+	store     Store
+	scheduler Scheduler
+	retention time.Duration
+	now       func() time.Time
+}
+
+func NewJanitor(store Store, scheduler Scheduler, retentionDays int) *Janitor {
+	return &Janitor{
+		store:     store,
+		scheduler: scheduler,
+		retention: time.Duration(retentionDays) * 24 * time.Hour,
+		now:       time.Now,
+	}
+}
+
+func (j *Janitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	var lastPrune time.Time
+	for {
+		if j.now().Sub(lastPrune) >= pruneInterval {
+			if n, err := j.Prune(ctx); err != nil {
+				log.Printf("ERROR: could not prune audit log: %v", err)
+			} else if n > 0 {
+				log.Printf("INFO: pruned %d audit events older than %v", n, j.retention)
+			}
+			lastPrune = j.now()
+		}
+		if _, err := j.Sweep(ctx); err != nil {
+			log.Printf("ERROR: could not record expired CV tokens: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *Janitor) Sweep(ctx context.Context) (int, error) {
+	swept := 0
+	for {
+		now := j.now().UTC()
+		values, err := j.scheduler.ScheduleClaimDue(ctx, pendingKey, now, claimBatch)
+		if err != nil {
+			return swept, err
+		}
+
+		for _, raw := range values {
+			var event audit.Event
+			if err := json.Unmarshal([]byte(raw), &event); err != nil {
+				log.Printf("ERROR: skipping malformed pending audit event: %v", err)
+				continue
+			}
+			event.ID = newID()
+			event.Time = now
+			event.Type = audit.EventTokenExpiredUnused
+			if err := j.store.Append(ctx, event); err != nil {
+				return swept, err
+			}
+			swept++
+		}
+
+		if len(values) < claimBatch {
+			return swept, nil
+		}
+	}
+}
+
+func (j *Janitor) Prune(ctx context.Context) (int64, error) {
+	return j.store.Prune(ctx, j.now().Add(-j.retention))
+}
@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+)
+
+func TestJanitor_Sweep(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	store := &mockStore{}
+	scheduler := newMockScheduler()
+	r := NewRecorder(store, scheduler, "main")
+	r.now = func() time.Time { return now }
+	ctx := audit.WithCorrelationID(context.Background(), "corr-1")
+
+	r.Record(ctx, audit.Event{Type: audit.EventTokenIssued, Token: "expired", Credential: "acme", ExpiresAt: now.Add(time.Minute)})
+	r.Record(ctx, audit.Event{Type: audit.EventTokenIssued, Token: "redeemed", ExpiresAt: now.Add(time.Minute)})
+	r.Record(ctx, audit.Event{Type: audit.EventTokenIssued, Token: "valid", ExpiresAt: now.Add(time.Hour)})
+	r.Record(ctx, audit.Event{Type: audit.EventTokenRedeemed, Token: "redeemed"})
+
+	j := NewJanitor(store, scheduler, 30)
+	j.now = func() time.Time { return now.Add(2 * time.Minute) }
+
+	n, err := j.Sweep(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("Sweep() = %d, %v, want 1", n, err)
+	}
+
+	e := store.events[len(store.events)-1]
+	if e.Type != audit.EventTokenExpiredUnused || e.Token != "expired" || e.Credential != "acme" || e.CorrelationID != "corr-1" || !e.Time.Equal(now.Add(2*time.Minute)) {
+		t.Errorf("Sweep() recorded %+v", e)
+	}
+	if e.ID == store.events[0].ID {
+		t.Error("Sweep() must record a new event ID")
+	}
+
+	if n, _ := j.Sweep(context.Background()); n != 0 {
+		t.Errorf("Sweep() recorded %d events twice", n)
+	}
+}
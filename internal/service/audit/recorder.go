@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+)
+
+type Store interface {
+	Append(ctx context.Context, event audit.Event) error
+	Query(ctx context.Context, filter audit.Filter) ([]audit.Event, error)
+	Prune(ctx context.Context, before time.Time) (int64, error)
+	Close() error
+}
+
+type Scheduler interface {
+	ScheduleAdd(ctx context.Context, key, member, value string, due time.Time) error
+	ScheduleRemove(ctx context.Context, key, member string) error
+	ScheduleClaimDue(ctx context.Context, key string, now time.Time, limit int) ([]string, error)
+}
+
+const pendingKey = "audit:pending"
+
+type Recorder struct {
+	store     Store
+	scheduler Scheduler
+	site      string
+	now       func() time.Time
+}
+
+func NewRecorder(store Store, scheduler Scheduler, site string) *Recorder {
+	return &Recorder{
+		store:     store,
+		scheduler: scheduler,
+		site:      site,
+		now:       time.Now,
+	}
+}
+
+func (r *Recorder) Record(ctx context.Context, event audit.Event) {
+	event.ID = newID()
+	event.Time = r.now().UTC()
+	event.Site = r.site
+	if event.CorrelationID == "" {
+		event.CorrelationID = audit.CorrelationID(ctx)
+	}
+
+	if err := r.store.Append(ctx, event); err != nil {
+		log.Printf("ERROR: could not record audit event %s: %v", event.Type, err)
+	}
+	if event.Token == "" {
+		return
+	}
+
+	var err error
+	switch event.Type {
+	case audit.EventTokenIssued:
+		if event.ExpiresAt.IsZero() {
+			return
+		}
+		raw, _ := json.Marshal(event)
+		err = r.scheduler.ScheduleAdd(ctx, pendingKey, pendingMember(event), string(raw), event.ExpiresAt)
+	case audit.EventTokenRedeemed, audit.EventTokenRevoked:
+		err = r.scheduler.ScheduleRemove(ctx, pendingKey, pendingMember(event))
+	}
+	if err != nil {
+		log.Printf("ERROR: could not track expiry of token %s: %v", event.Token, err)
+	}
+}
+
+func (r *Recorder) Query(ctx context.Context, filter audit.Filter) ([]audit.Event, error) {
+	filter.Site = r.site
+	return r.store.Query(ctx, filter)
+}
+
+func pendingMember(event audit.Event) string {
+	return event.Site + ":" + event.Token
+}
+
+func newID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,126 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+)
+
+type mockStore struct {
+	events []audit.Event
+	filter audit.Filter
+	err    error
+}
+
+func (m *mockStore) Append(ctx context.Context, event audit.Event) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *mockStore) Query(ctx context.Context, filter audit.Filter) ([]audit.Event, error) {
+	m.filter = filter
+	return m.events, m.err
+}
+
+func (m *mockStore) Prune(ctx context.Context, before time.Time) (int64, error) {
+	return 0, m.err
+}
+
+func (m *mockStore) Close() error {
+	return nil
+}
+
+type mockScheduler struct {
+	due     map[string]time.Time
+	values  map[string]string
+	claimed []string
+}
+
+func newMockScheduler() *mockScheduler {
+	return &mockScheduler{due: map[string]time.Time{}, values: map[string]string{}}
+}
+
+func (m *mockScheduler) ScheduleAdd(ctx context.Context, key, member, value string, due time.Time) error {
+	m.due[member] = due
+	m.values[member] = value
+	return nil
+}
+
+func (m *mockScheduler) ScheduleRemove(ctx context.Context, key, member string) error {
+	delete(m.due, member)
+	delete(m.values, member)
+	return nil
+}
+
+func (m *mockScheduler) ScheduleClaimDue(ctx context.Context, key string, now time.Time, limit int) ([]string, error) {
+	var values []string
+	for member, due := range m.due {
+		if !due.After(now) && len(values) < limit {
+			values = append(values, m.values[member])
+			delete(m.due, member)
+			delete(m.values, member)
+		}
+	}
+	return values, nil
+}
+
+func TestRecorder_Record(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	ctx := audit.WithCorrelationID(context.Background(), "corr-1")
+
+	t.Run("stamps and tracks issued tokens until redeemed", func(t *testing.T) {
+		store := &mockStore{}
+		scheduler := newMockScheduler()
+		r := NewRecorder(store, scheduler, "main")
+		r.now = func() time.Time { return now }
+
+		r.Record(ctx, audit.Event{Type: audit.EventTokenIssued, Token: "ref", ExpiresAt: now.Add(time.Minute)})
+
+		e := store.events[0]
+		if e.ID == "" || !e.Time.Equal(now) || e.Site != "main" || e.CorrelationID != "corr-1" {
+			t.Errorf("Record() stored %+v", e)
+		}
+		var pending audit.Event
+		if err := json.Unmarshal([]byte(scheduler.values["main:ref"]), &pending); err != nil || pending.ID != e.ID {
+			t.Errorf("Record() did not schedule the issued token: %v", scheduler.values)
+		}
+		if !scheduler.due["main:ref"].Equal(now.Add(time.Minute)) {
+			t.Errorf("Record() scheduled at %v", scheduler.due["main:ref"])
+		}
+
+		r.Record(context.Background(), audit.Event{Type: audit.EventTokenRedeemed, Token: "ref", CorrelationID: "download-1"})
+		if len(scheduler.due) != 0 {
+			t.Error("Record() did not unschedule the redeemed token")
+		}
+		if store.events[1].CorrelationID != "download-1" {
+			t.Errorf("Record() overwrote the correlation ID: %s", store.events[1].CorrelationID)
+		}
+	})
+
+	t.Run("store errors are not fatal", func(t *testing.T) {
+		scheduler := newMockScheduler()
+		r := NewRecorder(&mockStore{err: errors.New("disk full")}, scheduler, "main")
+
+		r.Record(ctx, audit.Event{Type: audit.EventTokenIssued, Token: "ref", ExpiresAt: now})
+		if len(scheduler.due) != 1 {
+			t.Error("Record() must still track the token")
+		}
+	})
+}
+
+func TestRecorder_Query(t *testing.T) {
+	store := &mockStore{}
+	r := NewRecorder(store, newMockScheduler(), "main")
+
+	r.Query(context.Background(), audit.Filter{Site: "other", Credential: "acme"})
+	if store.filter.Site != "main" || store.filter.Credential != "acme" {
+		t.Errorf("Query() filter = %+v", store.filter)
+	}
+}
@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS audit_events (
+	id TEXT PRIMARY KEY,
+	time INTEGER NOT NULL,
+	type TEXT NOT NULL,
+	site TEXT NOT NULL,
+	credential TEXT NOT NULL,
+	correlation_id TEXT NOT NULL,
+	event TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS audit_events_site_time ON audit_events (site, time);
+`
+
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Append(ctx context.Context, event audit.Event) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO audit_events (id, time, type, site, credential, correlation_id, event) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		event.ID, event.Time.UnixNano(), string(event.Type), event.Site, event.Credential, event.CorrelationID, string(raw))
+
+	return err
+}
+
+func (s *SQLiteStore) Query(ctx context.Context, filter audit.Filter) ([]audit.Event, error) {
+	var conditions []string
+	var args []any
+	if filter.Site != "" {
+		conditions, args = append(conditions, "site = ?"), append(args, filter.Site)
+	}
+	if !filter.From.IsZero() {
+		conditions, args = append(conditions, "time >= ?"), append(args, filter.From.UnixNano())
+	}
+	if !filter.To.IsZero() {
+		conditions, args = append(conditions, "time < ?"), append(args, filter.To.UnixNano())
+	}
+	if filter.Credential != "" {
+		conditions, args = append(conditions, "credential = ?"), append(args, filter.Credential)
+	}
+	if filter.CorrelationID != "" {
+		conditions, args = append(conditions, "correlation_id = ?"), append(args, filter.CorrelationID)
+	}
+	if len(filter.Types) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(filter.Types)), ", ")
+		conditions = append(conditions, "type IN ("+placeholders+")")
+		for _, t := range filter.Types {
+			args = append(args, string(t))
+		}
+	}
+
+	query := "SELECT event FROM audit_events"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY time DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []audit.Event
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		var event audit.Event
+		if err := json.Unmarshal([]byte(raw), &event); err == nil {
+			events = append(events, event)
+		}
+	}
+
+	return events, rows.Err()
+}
+
+func (s *SQLiteStore) Prune(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM audit_events WHERE time < ?`, before.UnixNano())
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,147 @@
+package audit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+)
+
+type mockStreamClient struct {
+	entries []time.Time
+	values  []string
+}
+
+func (m *mockStreamClient) StreamAdd(ctx context.Context, key, value string) error {
+	m.entries = append(m.entries, time.Now())
+	m.values = append(m.values, value)
+	return nil
+}
+
+func (m *mockStreamClient) StreamRange(ctx context.Context, key string, from, to time.Time) ([]string, error) {
+	return m.values, nil
+}
+
+func (m *mockStreamClient) StreamTrim(ctx context.Context, key string, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"jsonl": func(t *testing.T) Store {
+			s, err := NewFileStore(filepath.Join(t.TempDir(), "audit", "cv.jsonl"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return s
+		},
+		"sqlite": func(t *testing.T) Store {
+			s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "audit", "cv.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return s
+		},
+		"redis": func(t *testing.T) Store {
+			return NewStreamStore(&mockStreamClient{})
+		},
+	}
+
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	events := []audit.Event{
+		{ID: "1", Time: base, Type: audit.EventTokenIssued, Site: "main", Credential: "acme", CorrelationID: "c1"},
+		{ID: "2", Time: base.Add(time.Hour), Type: audit.EventTokenRedeemed, Site: "main", Credential: "acme", CorrelationID: "c2"},
+		{ID: "3", Time: base.Add(2 * time.Hour), Type: audit.EventPasswordFailed, Site: "main", CorrelationID: "c3"},
+		{ID: "4", Time: base.Add(3 * time.Hour), Type: audit.EventTokenIssued, Site: "other", Credential: "acme"},
+		{ID: "5", Time: base.Add(4 * time.Hour), Type: audit.EventTokenIssued, Site: "main", Credential: "default", ExpiresAt: base.Add(5 * time.Hour)},
+	}
+
+	tests := []struct {
+		name   string
+		filter audit.Filter
+		want   []string
+	}{
+		{name: "site newest first", filter: audit.Filter{Site: "main"}, want: []string{"5", "3", "2", "1"}},
+		{name: "limit", filter: audit.Filter{Site: "main", Limit: 2}, want: []string{"5", "3"}},
+		{name: "types", filter: audit.Filter{Site: "main", Types: []audit.EventType{audit.EventTokenIssued, audit.EventTokenRedeemed}}, want: []string{"5", "2", "1"}},
+		{name: "credential", filter: audit.Filter{Site: "main", Credential: "acme"}, want: []string{"2", "1"}},
+		{name: "correlation", filter: audit.Filter{CorrelationID: "c3"}, want: []string{"3"}},
+		{name: "time range", filter: audit.Filter{From: base.Add(time.Hour), To: base.Add(3 * time.Hour)}, want: []string{"3", "2"}},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+			defer store.Close()
+
+			for _, e := range events {
+				if err := store.Append(ctx, e); err != nil {
+					t.Fatalf("Append() error = %v", err)
+				}
+			}
+
+			for _, tt := range tests {
+				got, err := store.Query(ctx, tt.filter)
+				if err != nil {
+					t.Fatalf("%s: Query() error = %v", tt.name, err)
+				}
+				if ids := eventIDs(got); !equal(ids, tt.want) {
+					t.Errorf("%s: Query() = %v, want %v", tt.name, ids, tt.want)
+				}
+			}
+
+			got, _ := store.Query(ctx, audit.Filter{CorrelationID: "c1"})
+			if len(got) != 1 || !got[0].Time.Equal(base) || got[0].Credential != "acme" {
+				t.Errorf("Query() did not round-trip the event: %+v", got)
+			}
+		})
+	}
+
+	for _, name := range []string{"jsonl", "sqlite"} {
+		t.Run(name+" prune", func(t *testing.T) {
+			ctx := context.Background()
+			store := stores[name](t)
+			defer store.Close()
+
+			for _, e := range events {
+				store.Append(ctx, e)
+			}
+
+			n, err := store.Prune(ctx, base.Add(2*time.Hour))
+			if err != nil || n != 2 {
+				t.Fatalf("Prune() = %d, %v, want 2", n, err)
+			}
+			if err := store.Append(ctx, audit.Event{ID: "6", Time: base.Add(5 * time.Hour), Site: "main"}); err != nil {
+				t.Fatalf("Append() after Prune() error = %v", err)
+			}
+
+			got, _ := store.Query(ctx, audit.Filter{Site: "main"})
+			if ids := eventIDs(got); !equal(ids, []string{"6", "5", "3"}) {
+				t.Errorf("Query() after Prune() = %v", ids)
+			}
+		})
+	}
+}
+
+func eventIDs(events []audit.Event) []string {
+	ids := []string{}
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+)
+
+const streamKey = "audit:events"
+
+type StreamClient interface {
+	StreamAdd(ctx context.Context, key, value string) error
+	StreamRange(ctx context.Context, key string, from, to time.Time) ([]string, error)
+	StreamTrim(ctx context.Context, key string, before time.Time) (int64, error)
+}
+
+type StreamStore struct {
+	client StreamClient
+}
+
+func NewStreamStore(client StreamClient) *StreamStore {
+	return &StreamStore{client: client}
+}
+
+func (s *StreamStore) Append(ctx context.Context, event audit.Event) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return s.client.StreamAdd(ctx, streamKey, string(raw))
+}
+
+func (s *StreamStore) Query(ctx context.Context, filter audit.Filter) ([]audit.Event, error) {
+	values, err := s.client.StreamRange(ctx, streamKey, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []audit.Event
+	for _, raw := range values {
+		var event audit.Event
+		if err := json.Unmarshal([]byte(raw), &event); err == nil && filter.Match(event) {
+			events = append(events, event)
+		}
+	}
+
+	return newestFirst(events, filter.Limit), nil
+}
+
+func (s *StreamStore) Prune(ctx context.Context, before time.Time) (int64, error) {
+	return s.client.StreamTrim(ctx, streamKey, before)
+}
+
+func (s *StreamStore) Close() error {
+	return nil
+}
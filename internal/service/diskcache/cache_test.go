@@ -0,0 +1,32 @@
+package diskcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	c, err := NewCache(dir)
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+
+	if _, ok := c.Get("../profile.jpg"); ok {
+		t.Fatal("Get() hit on empty cache")
+	}
+	if err := c.Put("../profile.jpg", []byte("data")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	data, ok := c.Get("../profile.jpg")
+	if !ok || string(data) != "data" {
+		t.Errorf("Get() = %q, %v", data, ok)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("cache dir has %d entries, want 1", len(entries))
+	}
+}
@@ -0,0 +1,236 @@
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+type Store interface {
+	ListPush(ctx context.Context, key, value string) error
+	ListMove(ctx context.Context, source, destination string, timeout time.Duration) (string, bool, error)
+	ListRemove(ctx context.Context, key, value string) error
+	ListRange(ctx context.Context, key string) ([]string, error)
+	ScheduleAdd(ctx context.Context, key, member, value string, due time.Time) error
+	ScheduleClaimDue(ctx context.Context, key string, now time.Time, limit int) ([]string, error)
+}
+
+type Entry struct {
+	ID        string          `json:"id"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+type DeliverFunc func(ctx context.Context, payload json.RawMessage) error
+
+var errDeliveryFailed = errors.New("delivery failed")
+
+const (
+	claimTimeout  = 5 * time.Second
+	maxRetryDelay = 15 * time.Minute
+	retryBatch    = 100
+)
+
+type Outbox struct {
+	store       Store
+	name        string
+	maxAttempts int
+	retryDelay  time.Duration
+	now         func() time.Time
+}
+
+func NewOutbox(store Store, name string, maxAttempts int, retryDelay time.Duration) *Outbox {
+	return &Outbox{
+		store:       store,
+		name:        name,
+		maxAttempts: maxAttempts,
+		retryDelay:  retryDelay,
+		now:         time.Now,
+	}
+}
+
+func (o *Outbox) Name() string {
+	return o.name
+}
+
+func (o *Outbox) Enqueue(ctx context.Context, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+
+	entry, err := json.Marshal(Entry{ID: hex.EncodeToString(id), Payload: raw, CreatedAt: o.now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	return o.store.ListPush(ctx, o.pendingKey(), string(entry))
+}
+
+func (o *Outbox) Run(ctx context.Context, deliver DeliverFunc) error {
+	if err := o.recover(ctx); err != nil {
+		return err
+	}
+
+	log.Printf("INFO: delivering outbox: %s", o.name)
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		claimed, err := o.DeliverNext(ctx, deliver)
+		switch {
+		case errors.Is(err, errDeliveryFailed):
+			log.Printf("ERROR: outbox %s: %v", o.name, err)
+		case err != nil && ctx.Err() == nil:
+			failures++
+			log.Printf("ERROR: outbox %s: %v", o.name, err)
+			o.sleep(ctx, o.backoff(failures))
+		case err == nil && claimed:
+			failures = 0
+		}
+	}
+}
+
+func (o *Outbox) DeliverNext(ctx context.Context, deliver DeliverFunc) (bool, error) {
+	if err := o.requeueDue(ctx); err != nil {
+		return false, err
+	}
+
+	raw, ok, err := o.store.ListMove(ctx, o.pendingKey(), o.processingKey(), claimTimeout)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	var entry Entry
+	if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+		if moveErr := o.moveTo(ctx, raw, o.deadKey(), raw); moveErr != nil {
+			return true, moveErr
+		}
+		return true, fmt.Errorf("malformed entry moved to dead letters: %w", err)
+	}
+
+	if err := deliver(ctx, entry.Payload); err != nil {
+		entry.Attempts++
+		retried, _ := json.Marshal(entry)
+
+		if entry.Attempts >= o.maxAttempts {
+			if moveErr := o.moveTo(ctx, raw, o.deadKey(), string(retried)); moveErr != nil {
+				return true, moveErr
+			}
+		} else {
+			due := o.now().Add(o.backoff(entry.Attempts))
+			if scheduleErr := o.store.ScheduleAdd(ctx, o.retryKey(), entry.ID, string(retried), due); scheduleErr != nil {
+				return true, scheduleErr
+			}
+			if removeErr := o.store.ListRemove(ctx, o.processingKey(), raw); removeErr != nil {
+				return true, removeErr
+			}
+		}
+		return true, fmt.Errorf("%w: entry %s (attempt %d/%d): %v", errDeliveryFailed, entry.ID, entry.Attempts, o.maxAttempts, err)
+	}
+
+	return true, o.store.ListRemove(ctx, o.processingKey(), raw)
+}
+
+func (o *Outbox) requeueDue(ctx context.Context) error {
+	now := o.now()
+	due, err := o.store.ScheduleClaimDue(ctx, o.retryKey(), now, retryBatch)
+	if err != nil {
+		return err
+	}
+
+	for i, raw := range due {
+		if err := o.store.ListPush(ctx, o.pendingKey(), raw); err != nil {
+			o.reschedule(ctx, due[i:], now)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *Outbox) reschedule(ctx context.Context, entries []string, due time.Time) {
+	for _, raw := range entries {
+		var entry Entry
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+			log.Printf("ERROR: outbox %s: dropped malformed retry entry: %v", o.name, err)
+			continue
+		}
+		if err := o.store.ScheduleAdd(ctx, o.retryKey(), entry.ID, raw, due); err != nil {
+			log.Printf("ERROR: outbox %s: could not reschedule entry %s: %v", o.name, entry.ID, err)
+		}
+	}
+}
+
+func (o *Outbox) recover(ctx context.Context) error {
+	entries, err := o.store.ListRange(ctx, o.processingKey())
+	if err != nil {
+		return err
+	}
+
+	for _, raw := range entries {
+		if err := o.moveTo(ctx, raw, o.pendingKey(), raw); err != nil {
+			return err
+		}
+	}
+	if len(entries) > 0 {
+		log.Printf("INFO: outbox %s: requeued %d unfinished entries", o.name, len(entries))
+	}
+
+	return nil
+}
+
+func (o *Outbox) moveTo(ctx context.Context, raw, key, value string) error {
+	if err := o.store.ListPush(ctx, key, value); err != nil {
+		return err
+	}
+
+	return o.store.ListRemove(ctx, o.processingKey(), raw)
+}
+
+func (o *Outbox) backoff(failures int) time.Duration {
+	delay := o.retryDelay
+	for i := 1; i < failures && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return max(min(delay, maxRetryDelay), o.retryDelay)
+}
+
+func (o *Outbox) sleep(ctx context.Context, delay time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+}
+
+func (o *Outbox) pendingKey() string {
+	return "outbox:" + o.name + ":pending"
+}
+
+func (o *Outbox) processingKey() string {
+	return "outbox:" + o.name + ":processing"
+}
+
+func (o *Outbox) retryKey() string {
+	return "outbox:" + o.name + ":retry"
+}
+
+func (o *Outbox) deadKey() string {
+	return "outbox:" + o.name + ":dead"
+}
@@ -0,0 +1,259 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+type scheduled struct {
+	value string
+	due   time.Time
+}
+
+type mockListStore struct {
+	lists    map[string][]string
+	schedule map[string]scheduled
+}
+
+func (m *mockListStore) ListPush(ctx context.Context, key, value string) error {
+	m.lists[key] = append([]string{value}, m.lists[key]...)
+	return nil
+}
+
+func (m *mockListStore) ListMove(ctx context.Context, source, destination string, timeout time.Duration) (string, bool, error) {
+	list := m.lists[source]
+	if len(list) == 0 {
+		return "", false, nil
+	}
+	value := list[len(list)-1]
+	m.lists[source] = list[:len(list)-1]
+	m.lists[destination] = append([]string{value}, m.lists[destination]...)
+	return value, true, nil
+}
+
+func (m *mockListStore) ListRemove(ctx context.Context, key, value string) error {
+	for i, v := range m.lists[key] {
+		if v == value {
+			m.lists[key] = append(m.lists[key][:i], m.lists[key][i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *mockListStore) ListRange(ctx context.Context, key string) ([]string, error) {
+	return m.lists[key], nil
+}
+
+func (m *mockListStore) ScheduleAdd(ctx context.Context, key, member, value string, due time.Time) error {
+	if m.schedule == nil {
+		m.schedule = map[string]scheduled{}
+	}
+	m.schedule[key+"|"+member] = scheduled{value: value, due: due}
+	return nil
+}
+
+func (m *mockListStore) ScheduleClaimDue(ctx context.Context, key string, now time.Time, limit int) ([]string, error) {
+	var members []string
+	for member, entry := range m.schedule {
+		if !entry.due.After(now) {
+			members = append(members, member)
+		}
+	}
+	sort.Strings(members)
+
+	var values []string
+	for _, member := range members {
+		values = append(values, m.schedule[member].value)
+		delete(m.schedule, member)
+	}
+	return values, nil
+}
+
+func TestOutbox_DeliverNext(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("delivers in order and removes entries", func(t *testing.T) {
+		store := &mockListStore{lists: map[string][]string{}}
+		o := NewOutbox(store, "mail", 3, time.Millisecond)
+		o.Enqueue(ctx, map[string]string{"n": "1"})
+		o.Enqueue(ctx, map[string]string{"n": "2"})
+
+		var got []string
+		deliver := func(ctx context.Context, payload json.RawMessage) error {
+			got = append(got, string(payload))
+			return nil
+		}
+		for i := 0; i < 3; i++ {
+			if _, err := o.DeliverNext(ctx, deliver); err != nil {
+				t.Fatalf("DeliverNext() error = %v", err)
+			}
+		}
+
+		if len(got) != 2 || got[0] != `{"n":"1"}` || got[1] != `{"n":"2"}` {
+			t.Errorf("delivered %v", got)
+		}
+		if len(store.lists["outbox:mail:pending"]) != 0 || len(store.lists["outbox:mail:processing"]) != 0 {
+			t.Errorf("entries left behind: %v", store.lists)
+		}
+	})
+
+	t.Run("retries then dead-letters", func(t *testing.T) {
+		store := &mockListStore{lists: map[string][]string{}}
+		o := NewOutbox(store, "mail", 2, time.Millisecond)
+		o.Enqueue(ctx, "hello")
+
+		now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		o.now = func() time.Time { return now }
+
+		failing := func(ctx context.Context, payload json.RawMessage) error { return errors.New("smtp down") }
+		for i := 0; i < 2; i++ {
+			if _, err := o.DeliverNext(ctx, failing); !errors.Is(err, errDeliveryFailed) {
+				t.Fatalf("DeliverNext() error = %v, want %v", err, errDeliveryFailed)
+			}
+			now = now.Add(time.Second)
+		}
+
+		if len(store.lists["outbox:mail:pending"]) != 0 || len(store.lists["outbox:mail:processing"]) != 0 {
+			t.Fatalf("entry not moved out: %v", store.lists)
+		}
+		dead := store.lists["outbox:mail:dead"]
+		if len(dead) != 1 {
+			t.Fatalf("dead letters = %v", dead)
+		}
+		var entry Entry
+		json.Unmarshal([]byte(dead[0]), &entry)
+		if entry.Attempts != 2 || string(entry.Payload) != `"hello"` {
+			t.Errorf("dead entry = %+v", entry)
+		}
+	})
+
+	t.Run("empty outbox", func(t *testing.T) {
+		o := NewOutbox(&mockListStore{lists: map[string][]string{}}, "mail", 2, time.Millisecond)
+		handled, err := o.DeliverNext(ctx, func(ctx context.Context, payload json.RawMessage) error { return nil })
+		if handled || err != nil {
+			t.Errorf("DeliverNext() = %v, %v", handled, err)
+		}
+	})
+}
+
+func TestOutbox_DeliverNext_BacksOffPerEntry(t *testing.T) {
+	ctx := context.Background()
+	store := &mockListStore{lists: map[string][]string{}}
+	o := NewOutbox(store, "webhook", 5, time.Minute)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	o.now = func() time.Time { return now }
+	o.Enqueue(ctx, "dead-endpoint")
+	o.Enqueue(ctx, "healthy-endpoint")
+
+	var delivered []string
+	deliver := func(ctx context.Context, payload json.RawMessage) error {
+		if string(payload) == `"dead-endpoint"` {
+			return errors.New("connection refused")
+		}
+		delivered = append(delivered, string(payload))
+		return nil
+	}
+
+	if _, err := o.DeliverNext(ctx, deliver); !errors.Is(err, errDeliveryFailed) {
+		t.Fatalf("DeliverNext() error = %v, want %v", err, errDeliveryFailed)
+	}
+	if _, err := o.DeliverNext(ctx, deliver); err != nil {
+		t.Fatalf("DeliverNext() error = %v", err)
+	}
+	if !reflect.DeepEqual(delivered, []string{`"healthy-endpoint"`}) {
+		t.Errorf("delivered %v, want the healthy endpoint while the other backs off", delivered)
+	}
+
+	if claimed, err := o.DeliverNext(ctx, deliver); claimed || err != nil {
+		t.Errorf("DeliverNext() before the retry is due = %v, %v", claimed, err)
+	}
+
+	now = now.Add(time.Minute)
+	if claimed, err := o.DeliverNext(ctx, deliver); !claimed || !errors.Is(err, errDeliveryFailed) {
+		t.Fatalf("DeliverNext() once due = %v, %v", claimed, err)
+	}
+	var retry Entry
+	for _, entry := range store.schedule {
+		json.Unmarshal([]byte(entry.value), &retry)
+		if retry.Attempts != 2 || !entry.due.Equal(now.Add(2*time.Minute)) {
+			t.Errorf("rescheduled %+v for %v, want attempt 2 due in 2m", retry, entry.due)
+		}
+	}
+	if len(store.schedule) != 1 || len(store.lists["outbox:webhook:pending"]) != 0 || len(store.lists["outbox:webhook:processing"]) != 0 {
+		t.Errorf("entries = %v, schedule = %v", store.lists, store.schedule)
+	}
+}
+
+func TestOutbox_Run(t *testing.T) {
+	store := &mockListStore{lists: map[string][]string{
+		"outbox:mail:processing": {`{"id":"stale","payload":"left over"}`},
+	}}
+	o := NewOutbox(store, "mail", 3, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	delivered := make(chan string, 1)
+	go o.Run(ctx, func(ctx context.Context, payload json.RawMessage) error {
+		delivered <- string(payload)
+		cancel()
+		return nil
+	})
+
+	select {
+	case got := <-delivered:
+		if got != `"left over"` {
+			t.Errorf("delivered %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("unfinished entry was not requeued")
+	}
+}
+
+func TestOutbox_Run_KeepsDeliveringWhileAnEntryBacksOff(t *testing.T) {
+	store := &mockListStore{lists: map[string][]string{}}
+	o := NewOutbox(store, "webhook", 5, time.Hour)
+	o.Enqueue(context.Background(), "dead-endpoint")
+	o.Enqueue(context.Background(), "healthy-endpoint")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	delivered := make(chan string, 1)
+	go o.Run(ctx, func(ctx context.Context, payload json.RawMessage) error {
+		if string(payload) == `"dead-endpoint"` {
+			return errors.New("connection refused")
+		}
+		delivered <- string(payload)
+		cancel()
+		return nil
+	})
+
+	select {
+	case got := <-delivered:
+		if got != `"healthy-endpoint"` {
+			t.Errorf("delivered %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("a failing entry held up the rest of the outbox")
+	}
+}
+
+func TestOutbox_Backoff(t *testing.T) {
+	o := NewOutbox(&mockListStore{}, "mail", 3, 30*time.Second)
+
+	for failures, want := range map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		4:  4 * time.Minute,
+		6:  maxRetryDelay,
+		40: maxRetryDelay,
+	} {
+		if got := o.backoff(failures); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", failures, got, want)
+		}
+	}
+}
@@ -2,8 +2,15 @@ package redis
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/accessrequest"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pdfstamp"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/ratelimit"
 	"github.com/go-redis/redis/v8"
 )
 
@@ -11,12 +18,55 @@ type redisUniversalClient interface {
 	Ping(ctx context.Context) *redis.StatusCmd
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
 	Get(ctx context.Context, key string) *redis.StringCmd
+	GetDel(ctx context.Context, key string) *redis.StringCmd
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd
 	Del(ctx context.Context, keys ...string) *redis.IntCmd
+	Incr(ctx context.Context, key string) *redis.IntCmd
+	Decr(ctx context.Context, key string) *redis.IntCmd
+	EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *redis.Cmd
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd
+	ScriptExists(ctx context.Context, hashes ...string) *redis.BoolSliceCmd
+	ScriptLoad(ctx context.Context, script string) *redis.StringCmd
+	HIncrBy(ctx context.Context, key, field string, incr int64) *redis.IntCmd
+	HGetAll(ctx context.Context, key string) *redis.StringStringMapCmd
+	Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd
+	PFAdd(ctx context.Context, key string, els ...interface{}) *redis.IntCmd
+	PFCount(ctx context.Context, keys ...string) *redis.IntCmd
+	TxPipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
+	LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd
+	BRPopLPush(ctx context.Context, source, destination string, timeout time.Duration) *redis.StringCmd
+	LRem(ctx context.Context, key string, count int64, value interface{}) *redis.IntCmd
+	LRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd
+	XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd
+	XRange(ctx context.Context, stream, start, stop string) *redis.XMessageSliceCmd
+	XTrimMinID(ctx context.Context, key string, minID string) *redis.IntCmd
+	ZRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd
+	ZRemRangeByScore(ctx context.Context, key, min, max string) *redis.IntCmd
 	Close() error
 }
 
+type TokenUse struct {
+	Value     string
+	Remaining int64
+	Resumed   bool
+}
+
+type StoredToken struct {
+	Value     string
+	Remaining int64
+	FirstUsed time.Time
+	TTL       time.Duration
+}
+
+type RedeemedCaptcha struct {
+	Solved bool
+	Value  string
+	TTL    time.Duration
+}
+
 type Client struct {
 	client redisUniversalClient
+	prefix string
 }
 
 func NewClient(redisURL string) (*Client, error) {
@@ -29,29 +79,528 @@ func NewClient(redisURL string) (*Client, error) {
 	return &Client{client: rdb}, nil
 }
 
+func (c *Client) WithPrefix(prefix string) *Client {
+	return &Client{client: c.client, prefix: c.prefix + prefix}
+}
+
 func (c *Client) Ping(ctx context.Context) error {
 	return c.client.Ping(ctx).Err()
 }
 
 func (c *Client) SetToken(ctx context.Context, token string, value interface{}, ttl time.Duration) error {
-	return c.client.Set(ctx, token, value, ttl).Err()
+	return c.client.Set(ctx, c.prefix+token, value, ttl).Err()
 }
 
 func (c *Client) GetToken(ctx context.Context, key string) (string, error) {
-	return c.client.Get(ctx, key).Result()
+	return c.client.Get(ctx, c.prefix+key).Result()
 }
 
 func (c *Client) DelToken(ctx context.Context, key string) error {
-	return c.client.Del(ctx, key).Err()
+	return c.client.Del(ctx, c.prefix+key).Err()
+}
+
+func (c *Client) StoreToken(ctx context.Context, token, value string, downloads int, ttl time.Duration) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, c.prefix+token, "metadata", value, "remaining", downloads)
+		pipe.PExpire(ctx, c.prefix+token, ttl)
+		return nil
+	})
+
+	return err
+}
+
+func (c *Client) ConsumeToken(ctx context.Context, token string, resume bool, grace time.Duration) (TokenUse, bool, error) {
+	resumeFlag := "0"
+	if resume {
+		resumeFlag = "1"
+	}
+
+	res, err := consumeTokenScript.Run(ctx, c.client, []string{c.prefix + token}, resumeFlag, grace.Milliseconds()).Result()
+	if err == redis.Nil {
+		return TokenUse{}, false, nil
+	}
+	if err != nil {
+		return TokenUse{}, false, err
+	}
+
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 3 {
+		return TokenUse{}, false, fmt.Errorf("unexpected token script reply %v", res)
+	}
+	value, _ := values[0].(string)
+	remaining, _ := values[1].(int64)
+	resumed, _ := values[2].(int64)
+
+	return TokenUse{Value: value, Remaining: remaining, Resumed: resumed == 1}, true, nil
+}
+
+func (c *Client) InspectToken(ctx context.Context, key string) (StoredToken, bool, error) {
+	var fields *redis.StringStringMapCmd
+	var ttl *redis.DurationCmd
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		fields = pipe.HGetAll(ctx, c.prefix+key)
+		ttl = pipe.PTTL(ctx, c.prefix+key)
+		return nil
+	})
+	if err != nil {
+		return StoredToken{}, false, err
+	}
+
+	values := fields.Val()
+	if values["metadata"] == "" {
+		return StoredToken{}, false, nil
+	}
+
+	token := StoredToken{Value: values["metadata"], TTL: ttl.Val()}
+	token.Remaining, _ = strconv.ParseInt(values["remaining"], 10, 64)
+	if firstUsed, err := strconv.ParseInt(values["first_used"], 10, 64); err == nil {
+		token.FirstUsed = time.UnixMilli(firstUsed)
+	}
+
+	return token, true, nil
+}
+
+func (c *Client) RevokeToken(ctx context.Context, key string) (bool, error) {
+	n, err := c.client.Del(ctx, c.prefix+key).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+func (c *Client) ScanKeys(ctx context.Context, match string) ([]string, error) {
+	var keys []string
+	var cursor uint64
+	for {
+		batch, next, err := c.client.Scan(ctx, cursor, c.prefix+match, 100).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range batch {
+			keys = append(keys, strings.TrimPrefix(key, c.prefix))
+		}
+		if next == 0 {
+			return keys, nil
+		}
+		cursor = next
+	}
+}
+
+func (c *Client) ClaimNonce(ctx context.Context, key string, ttl time.Duration) (time.Time, bool, error) {
+	now := time.Now()
+	claimed, err := c.client.SetNX(ctx, c.prefix+key, now.UnixMilli(), ttl).Result()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if claimed {
+		return now, true, nil
+	}
+
+	firstUse, err := c.client.Get(ctx, c.prefix+key).Int64()
+	if err == redis.Nil {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return time.UnixMilli(firstUse), false, nil
+}
+
+func (c *Client) RevokeCredentialTokens(ctx context.Context, key string, at time.Time, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, at.UnixMilli(), ttl).Err()
+}
+
+func (c *Client) CredentialRevokedAt(ctx context.Context, key string) (time.Time, bool, error) {
+	revokedAt, err := c.client.Get(ctx, c.prefix+key).Int64()
+	if err == redis.Nil {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return time.UnixMilli(revokedAt), true, nil
+}
+
+func (c *Client) ClaimReply(ctx context.Context, key string, lease time.Duration) (string, bool, error) {
+	res, err := claimReplyScript.Run(ctx, c.client, []string{c.prefix + key}, lease.Milliseconds()).Result()
+	if err != nil {
+		return "", false, err
+	}
+
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 2 {
+		return "", false, fmt.Errorf("unexpected reply script reply %v", res)
+	}
+	claimed, _ := values[0].(int64)
+	reply, _ := values[1].(string)
+
+	return reply, claimed == 1, nil
+}
+
+func (c *Client) StoreReply(ctx context.Context, key, reply string, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, reply, ttl).Err()
+}
+
+func (c *Client) ReleaseReply(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.prefix+key).Err()
+}
+
+func (c *Client) FailCaptchaAttempt(ctx context.Context, key string) (int64, bool, error) {
+	tries, err := captchaFailedAttemptScript.Run(ctx, c.client, []string{c.prefix + key}).Int64()
+	if err != nil {
+		return 0, false, err
+	}
+	if tries < 0 {
+		return 0, false, nil
+	}
+
+	return tries, true, nil
+}
+
+func (c *Client) RedeemCaptcha(ctx context.Context, key string) (RedeemedCaptcha, bool, error) {
+	res, err := redeemCaptchaScript.Run(ctx, c.client, []string{c.prefix + key}).Result()
+	if err != nil {
+		return RedeemedCaptcha{}, false, err
+	}
+
+	values, ok := res.([]interface{})
+	if !ok || len(values) == 0 {
+		return RedeemedCaptcha{}, false, fmt.Errorf("unexpected captcha script reply %v", res)
+	}
+	status, _ := values[0].(int64)
+	if status < 0 {
+		return RedeemedCaptcha{}, false, nil
+	}
+	if status == 0 {
+		return RedeemedCaptcha{}, true, nil
+	}
+	if len(values) != 3 {
+		return RedeemedCaptcha{}, false, fmt.Errorf("unexpected captcha script reply %v", res)
+	}
+	value, _ := values[1].(string)
+	ttl, _ := values[2].(int64)
+
+	return RedeemedCaptcha{Solved: true, Value: value, TTL: time.Duration(ttl) * time.Millisecond}, true, nil
+}
+
+func (c *Client) RestoreCaptcha(ctx context.Context, key string, captcha RedeemedCaptcha) (bool, error) {
+	ttl := captcha.TTL
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	return c.client.SetNX(ctx, c.prefix+key, captcha.Value, ttl).Result()
+}
+
+func (c *Client) Increment(ctx context.Context, key string) (int64, error) {
+	return c.client.Incr(ctx, c.prefix+key).Result()
+}
+
+func (c *Client) IncrementCapped(ctx context.Context, key string, limit int64) (int64, bool, error) {
+	res, err := incrementCappedScript.Run(ctx, c.client, []string{c.prefix + key}, limit).Result()
+	if err != nil {
+		return 0, false, err
+	}
+
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 2 {
+		return 0, false, fmt.Errorf("unexpected counter script reply %v", res)
+	}
+	count, _ := values[0].(int64)
+	incremented, _ := values[1].(int64)
+
+	return count, incremented == 1, nil
+}
+
+func (c *Client) Decrement(ctx context.Context, key string) (int64, error) {
+	return c.client.Decr(ctx, c.prefix+key).Result()
+}
+
+func (c *Client) HashIncrement(ctx context.Context, key, field string, n int64) error {
+	return c.client.HIncrBy(ctx, c.prefix+key, field, n).Err()
+}
+
+func (c *Client) HashIncrementExpire(ctx context.Context, key, field string, n int64, ttl time.Duration) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HIncrBy(ctx, c.prefix+key, field, n)
+		pipe.Expire(ctx, c.prefix+key, ttl)
+		return nil
+	})
+	return err
+}
+
+func (c *Client) HashGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return c.client.HGetAll(ctx, c.prefix+key).Result()
+}
+
+func (c *Client) AddUnique(ctx context.Context, key, member string) error {
+	return c.client.PFAdd(ctx, c.prefix+key, member).Err()
+}
+
+func (c *Client) CountUnique(ctx context.Context, key string) (int64, error) {
+	return c.client.PFCount(ctx, c.prefix+key).Result()
 }
 
-func (c *Client) ValidateAndDeleteToken(ctx context.Context, token string) (bool, error) {
-	deletedCount, err := c.client.Del(ctx, token).Result()
+func (c *Client) IncrementWindow(ctx context.Context, key string, window time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetNX(ctx, c.prefix+key, 0, window)
+		incr = pipe.Incr(ctx, c.prefix+key)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+func (c *Client) AllowRequest(ctx context.Context, buckets []ratelimit.Bucket, member string) (time.Duration, error) {
+	keys := make([]string, 0, len(buckets))
+	args := make([]interface{}, 0, 1+2*len(buckets))
+	args = append(args, member)
+	for _, b := range buckets {
+		keys = append(keys, c.prefix+b.Key)
+		args = append(args, b.Limit.Max, b.Limit.Window.Milliseconds())
+	}
+
+	retryAfter, err := slidingWindowScript.Run(ctx, c.client, keys, args...).Int64()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(retryAfter) * time.Millisecond, nil
+}
+
+func (c *Client) RecordLockoutFailure(ctx context.Context, client string, policy lockout.Policy) (lockout.State, bool, error) {
+	durations := policy.Durations()
+	args := make([]interface{}, 0, 3+len(durations))
+	args = append(args, client, policy.Threshold, policy.Reset.Milliseconds())
+	for _, d := range durations {
+		args = append(args, d.Milliseconds())
+	}
+
+	res, err := lockoutFailureScript.Run(ctx, c.client, []string{c.prefix + lockout.Key(client), c.prefix + lockout.IndexKey}, args...).Int64Slice()
+	if err != nil {
+		return lockout.State{}, false, err
+	}
+	if len(res) != 4 {
+		return lockout.State{}, false, fmt.Errorf("unexpected lockout script reply %v", res)
+	}
+
+	state := lockout.State{Client: client, Failures: int(res[0]), Level: int(res[1])}
+	if res[2] > 0 {
+		state.LockedUntil = time.UnixMilli(res[2])
+	}
+
+	return state, res[3] == 1, nil
+}
+
+func (c *Client) LockoutState(ctx context.Context, client string) (lockout.State, bool, error) {
+	values, err := c.client.HGetAll(ctx, c.prefix+lockout.Key(client)).Result()
+	if err != nil {
+		return lockout.State{}, false, err
+	}
+	if len(values) == 0 {
+		return lockout.State{}, false, nil
+	}
+
+	state := lockout.State{Client: client}
+	state.Failures, _ = strconv.Atoi(values["failures"])
+	state.Level, _ = strconv.Atoi(values["level"])
+	if lockedUntil, err := strconv.ParseInt(values["locked_until"], 10, 64); err == nil && lockedUntil > 0 {
+		state.LockedUntil = time.UnixMilli(lockedUntil)
+	}
+	if lastFailure, err := strconv.ParseInt(values["last_failure"], 10, 64); err == nil {
+		state.LastFailure = time.UnixMilli(lastFailure)
+	}
+
+	return state, true, nil
+}
+
+func (c *Client) ListLockouts(ctx context.Context) ([]lockout.State, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	if err := c.client.ZRemRangeByScore(ctx, c.prefix+lockout.IndexKey, "-inf", now).Err(); err != nil {
+		return nil, err
+	}
+
+	clients, err := c.client.ZRange(ctx, c.prefix+lockout.IndexKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var states []lockout.State
+	for _, client := range clients {
+		state, found, err := c.LockoutState(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			states = append(states, state)
+		}
+	}
+
+	return states, nil
+}
+
+func (c *Client) ClearLockout(ctx context.Context, client string) (bool, error) {
+	var del *redis.IntCmd
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		del = pipe.Del(ctx, c.prefix+lockout.Key(client))
+		pipe.ZRem(ctx, c.prefix+lockout.IndexKey, client)
+		return nil
+	})
 	if err != nil {
 		return false, err
 	}
 
-	return deletedCount > 0, nil
+	return del.Val() > 0, nil
+}
+
+func (c *Client) StoreStamp(ctx context.Context, stamp pdfstamp.Stamp, ttl time.Duration) error {
+	value, err := stamp.Encode()
+	if err != nil {
+		return err
+	}
+
+	return c.client.Set(ctx, c.prefix+pdfstamp.Key(stamp.ID), value, ttl).Err()
+}
+
+func (c *Client) Stamp(ctx context.Context, id string) (pdfstamp.Stamp, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+pdfstamp.Key(id)).Result()
+	if err == redis.Nil {
+		return pdfstamp.Stamp{}, false, nil
+	}
+	if err != nil {
+		return pdfstamp.Stamp{}, false, err
+	}
+
+	stamp, err := pdfstamp.Decode(value)
+	if err != nil {
+		return pdfstamp.Stamp{}, false, err
+	}
+
+	return stamp, true, nil
+}
+
+func (c *Client) StoreAccessRequest(ctx context.Context, req accessrequest.Request, ttl time.Duration) error {
+	value, err := req.Encode()
+	if err != nil {
+		return err
+	}
+
+	return c.client.Set(ctx, c.prefix+accessrequest.Key(req.ID), value, ttl).Err()
+}
+
+func (c *Client) AccessRequest(ctx context.Context, id string) (accessrequest.Request, bool, error) {
+	return decodeAccessRequest(c.client.Get(ctx, c.prefix+accessrequest.Key(id)))
+}
+
+func (c *Client) TakeAccessRequest(ctx context.Context, id string) (accessrequest.Request, bool, error) {
+	return decodeAccessRequest(c.client.GetDel(ctx, c.prefix+accessrequest.Key(id)))
+}
+
+func decodeAccessRequest(cmd *redis.StringCmd) (accessrequest.Request, bool, error) {
+	value, err := cmd.Result()
+	if err == redis.Nil {
+		return accessrequest.Request{}, false, nil
+	}
+	if err != nil {
+		return accessrequest.Request{}, false, err
+	}
+
+	req, err := accessrequest.Decode(value)
+	if err != nil {
+		return accessrequest.Request{}, false, err
+	}
+
+	return req, true, nil
+}
+
+func (c *Client) ListPush(ctx context.Context, key, value string) error {
+	return c.client.LPush(ctx, c.prefix+key, value).Err()
+}
+
+func (c *Client) ListMove(ctx context.Context, source, destination string, timeout time.Duration) (string, bool, error) {
+	value, err := c.client.BRPopLPush(ctx, c.prefix+source, c.prefix+destination, timeout).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return value, true, nil
+}
+
+func (c *Client) ListRemove(ctx context.Context, key, value string) error {
+	return c.client.LRem(ctx, c.prefix+key, 1, value).Err()
+}
+
+func (c *Client) ListRange(ctx context.Context, key string) ([]string, error) {
+	return c.client.LRange(ctx, c.prefix+key, 0, -1).Result()
+}
+
+func (c *Client) StreamAdd(ctx context.Context, key, value string) error {
+	return c.client.XAdd(ctx, &redis.XAddArgs{Stream: c.prefix + key, Values: []interface{}{"value", value}}).Err()
+}
+
+func (c *Client) StreamRange(ctx context.Context, key string, from, to time.Time) ([]string, error) {
+	start, stop := "-", "+"
+	if !from.IsZero() {
+		start = strconv.FormatInt(from.UnixMilli(), 10)
+	}
+	if !to.IsZero() {
+		stop = strconv.FormatInt(to.UnixMilli(), 10)
+	}
+
+	messages, err := c.client.XRange(ctx, c.prefix+key, start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, len(messages))
+	for _, m := range messages {
+		if value, ok := m.Values["value"].(string); ok {
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
+func (c *Client) StreamTrim(ctx context.Context, key string, before time.Time) (int64, error) {
+	return c.client.XTrimMinID(ctx, c.prefix+key, strconv.FormatInt(before.UnixMilli(), 10)).Result()
+}
+
+func (c *Client) ScheduleAdd(ctx context.Context, key, member, value string, due time.Time) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, c.prefix+key, &redis.Z{Score: float64(due.UnixMilli()), Member: member})
+		pipe.HSet(ctx, c.prefix+key+":values", member, value)
+		return nil
+	})
+	return err
+}
+
+func (c *Client) ScheduleRemove(ctx context.Context, key, member string) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, c.prefix+key, member)
+		pipe.HDel(ctx, c.prefix+key+":values", member)
+		return nil
+	})
+	return err
+}
+
+func (c *Client) ScheduleClaimDue(ctx context.Context, key string, now time.Time, limit int) ([]string, error) {
+	res, err := claimDueScript.Run(ctx, c.client, []string{c.prefix + key, c.prefix + key + ":values"}, now.UnixMilli(), limit).StringSlice()
+	if err == redis.Nil {
+		return nil, nil
+	}
+
+	return res, err
 }
 
 func (c *Client) Close() error {
@@ -3,9 +3,16 @@ package redis
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/accessrequest"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/lockout"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/pdfstamp"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/ratelimit"
+	"github.com/go-redis/redis/v8"
 	"github.com/go-redis/redismock/v8"
 )
 
@@ -105,28 +112,76 @@ func TestClient_DelToken(t *testing.T) {
 	})
 }
 
-func TestClient_ValidateAndDeleteToken(t *testing.T) {
+func TestClient_StoreToken(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := (&Client{client: db}).WithPrefix("site:a:")
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectTxPipeline()
+		mock.ExpectHSet("site:a:token", "metadata", `{"lang":"pl"}`, "remaining", 3).SetVal(2)
+		mock.ExpectPExpire("site:a:token", time.Minute).SetVal(true)
+		mock.ExpectTxPipelineExec()
+
+		if err := client.StoreToken(ctx, "token", `{"lang":"pl"}`, 3, time.Minute); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectTxPipeline()
+		mock.ExpectHSet("site:a:token", "metadata", `{"lang":"pl"}`, "remaining", 3).SetErr(errors.New("redis error"))
+		mock.ExpectPExpire("site:a:token", time.Minute).SetVal(true)
+		mock.ExpectTxPipelineExec()
+
+		if err := client.StoreToken(ctx, "token", `{"lang":"pl"}`, 3, time.Minute); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestClient_ConsumeToken(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 	client := &Client{client: db}
 	ctx := context.Background()
 	token := "test-token"
+	sha := consumeTokenScript.Hash()
 
-	t.Run("success", func(t *testing.T) {
-		mock.ExpectDel(token).SetVal(1)
+	t.Run("download", func(t *testing.T) {
+		mock.ExpectEvalSha(sha, []string{token}, "0", int64(300000)).SetVal([]interface{}{`{"lang":"pl"}`, int64(2), int64(0)})
 
-		valid, err := client.ValidateAndDeleteToken(ctx, token)
+		use, valid, err := client.ConsumeToken(ctx, token, false, 5*time.Minute)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
-		if !valid {
-			t.Error("expected valid to be true")
+		want := TokenUse{Value: `{"lang":"pl"}`, Remaining: 2}
+		if !valid || use != want {
+			t.Errorf("expected %+v, got %+v (valid %v)", want, use, valid)
 		}
 	})
 
-	t.Run("token not found", func(t *testing.T) {
-		mock.ExpectDel(token).SetVal(0)
+	t.Run("resumed range request", func(t *testing.T) {
+		mock.ExpectEvalSha(sha, []string{token}, "1", int64(300000)).SetVal([]interface{}{`{"lang":"pl"}`, int64(0), int64(1)})
 
-		valid, err := client.ValidateAndDeleteToken(ctx, token)
+		use, valid, err := client.ConsumeToken(ctx, token, true, 5*time.Minute)
+		if err != nil || !valid || !use.Resumed {
+			t.Errorf("expected resumed use, got %+v, %v, %v", use, valid, err)
+		}
+	})
+
+	t.Run("script not loaded", func(t *testing.T) {
+		mock.ExpectEvalSha(sha, []string{token}, "0", int64(0)).SetErr(errors.New("NOSCRIPT No matching script"))
+		mock.Regexp().ExpectEval(`HGET`, []string{token}, "0", int64(0)).SetVal([]interface{}{`{"lang":"pl"}`, int64(0), int64(0)})
+
+		if _, valid, err := client.ConsumeToken(ctx, token, false, 0); err != nil || !valid {
+			t.Errorf("expected fallback to EVAL, got %v, %v", valid, err)
+		}
+	})
+
+	t.Run("token not usable", func(t *testing.T) {
+		mock.ExpectEvalSha(sha, []string{token}, "0", int64(0)).RedisNil()
+
+		_, valid, err := client.ConsumeToken(ctx, token, false, 0)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -135,10 +190,10 @@ func TestClient_ValidateAndDeleteToken(t *testing.T) {
 		}
 	})
 
-	t.Run("delete error", func(t *testing.T) {
-		mock.ExpectDel(token).SetErr(errors.New("delete error"))
+	t.Run("redis error", func(t *testing.T) {
+		mock.ExpectEvalSha(sha, []string{token}, "0", int64(0)).SetErr(errors.New("redis error"))
 
-		valid, err := client.ValidateAndDeleteToken(ctx, token)
+		_, valid, err := client.ConsumeToken(ctx, token, false, 0)
 		if err == nil {
 			t.Error("expected error, got nil")
 		}
@@ -146,4 +201,809 @@ func TestClient_ValidateAndDeleteToken(t *testing.T) {
 			t.Error("expected valid to be false")
 		}
 	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_InspectToken(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := (&Client{client: db}).WithPrefix("site:a:")
+	ctx := context.Background()
+	firstUse := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("used token", func(t *testing.T) {
+		mock.ExpectTxPipeline()
+		mock.ExpectHGetAll("site:a:cv_token:abc").SetVal(map[string]string{
+			"metadata":   `{"lang":"pl"}`,
+			"remaining":  "1",
+			"first_used": strconv.FormatInt(firstUse.UnixMilli(), 10),
+		})
+		mock.ExpectPTTL("site:a:cv_token:abc").SetVal(45 * time.Second)
+		mock.ExpectTxPipelineExec()
+
+		got, found, err := client.InspectToken(ctx, "cv_token:abc")
+		want := StoredToken{Value: `{"lang":"pl"}`, Remaining: 1, FirstUsed: firstUse, TTL: 45 * time.Second}
+		if err != nil || !found || got.Value != want.Value || got.Remaining != want.Remaining || !got.FirstUsed.Equal(want.FirstUsed) || got.TTL != want.TTL {
+			t.Errorf("got %+v, %v, %v, want %+v", got, found, err, want)
+		}
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		mock.ExpectTxPipeline()
+		mock.ExpectHGetAll("site:a:cv_token:abc").SetVal(map[string]string{})
+		mock.ExpectPTTL("site:a:cv_token:abc").SetVal(-2)
+		mock.ExpectTxPipelineExec()
+
+		if _, found, err := client.InspectToken(ctx, "cv_token:abc"); err != nil || found {
+			t.Errorf("got %v, %v, want not found", found, err)
+		}
+	})
+}
+
+func TestClient_RevokeToken(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := &Client{client: db}
+	ctx := context.Background()
+
+	mock.ExpectDel("cv_token:abc").SetVal(1)
+	if revoked, err := client.RevokeToken(ctx, "cv_token:abc"); err != nil || !revoked {
+		t.Errorf("got %v, %v, want revoked", revoked, err)
+	}
+
+	mock.ExpectDel("cv_token:abc").SetVal(0)
+	if revoked, err := client.RevokeToken(ctx, "cv_token:abc"); err != nil || revoked {
+		t.Errorf("got %v, %v, want not revoked", revoked, err)
+	}
+
+	mock.ExpectDel("cv_token:abc").SetErr(errors.New("redis error"))
+	if _, err := client.RevokeToken(ctx, "cv_token:abc"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestClient_ScanKeys(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := (&Client{client: db}).WithPrefix("site:a:")
+	ctx := context.Background()
+
+	mock.ExpectScan(0, "site:a:cv_token:*", 100).SetVal([]string{"site:a:cv_token:a", "site:a:cv_token:b"}, 7)
+	mock.ExpectScan(7, "site:a:cv_token:*", 100).SetVal([]string{"site:a:cv_token:c"}, 0)
+
+	keys, err := client.ScanKeys(ctx, "cv_token:*")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"cv_token:a", "cv_token:b", "cv_token:c"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("got %v, want %v", keys, want)
+	}
+
+	mock.ExpectScan(0, "site:a:cv_token:*", 100).SetErr(errors.New("redis error"))
+	if _, err := client.ScanKeys(ctx, "cv_token:*"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestClient_ClaimNonce(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := &Client{client: db}
+	ctx := context.Background()
+	firstUse := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.Regexp().ExpectSetNX("cv_nonce:n1", `^\d+$`, time.Minute).SetVal(true)
+	if _, claimed, err := client.ClaimNonce(ctx, "cv_nonce:n1", time.Minute); err != nil || !claimed {
+		t.Errorf("got %v, %v, want claimed", claimed, err)
+	}
+
+	mock.Regexp().ExpectSetNX("cv_nonce:n1", `^\d+$`, time.Minute).SetVal(false)
+	mock.ExpectGet("cv_nonce:n1").SetVal(strconv.FormatInt(firstUse.UnixMilli(), 10))
+	got, claimed, err := client.ClaimNonce(ctx, "cv_nonce:n1", time.Minute)
+	if err != nil || claimed || !got.Equal(firstUse) {
+		t.Errorf("got %v, %v, %v, want first use %v", got, claimed, err, firstUse)
+	}
+
+	mock.Regexp().ExpectSetNX("cv_nonce:n1", `^\d+$`, time.Minute).SetErr(errors.New("redis error"))
+	if _, _, err := client.ClaimNonce(ctx, "cv_nonce:n1", time.Minute); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_CredentialRevocation(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := &Client{client: db}
+	ctx := context.Background()
+	revokedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	millis := strconv.FormatInt(revokedAt.UnixMilli(), 10)
+
+	mock.ExpectSet("cv_credential_revoked:acme", revokedAt.UnixMilli(), time.Hour).SetVal("OK")
+	if err := client.RevokeCredentialTokens(ctx, "cv_credential_revoked:acme", revokedAt, time.Hour); err != nil {
+		t.Errorf("RevokeCredentialTokens() error = %v", err)
+	}
+
+	mock.ExpectGet("cv_credential_revoked:acme").SetVal(millis)
+	got, revoked, err := client.CredentialRevokedAt(ctx, "cv_credential_revoked:acme")
+	if err != nil || !revoked || !got.Equal(revokedAt) {
+		t.Errorf("got %v, %v, %v, want revoked at %v", got, revoked, err, revokedAt)
+	}
+
+	mock.ExpectGet("cv_credential_revoked:other").RedisNil()
+	if _, revoked, err := client.CredentialRevokedAt(ctx, "cv_credential_revoked:other"); err != nil || revoked {
+		t.Errorf("got %v, %v, want not revoked", revoked, err)
+	}
+
+	mock.ExpectGet("cv_credential_revoked:acme").SetErr(errors.New("redis error"))
+	if _, _, err := client.CredentialRevokedAt(ctx, "cv_credential_revoked:acme"); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_ClaimReply(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := &Client{client: db}
+	ctx := context.Background()
+	sha := claimReplyScript.Hash()
+
+	mock.ExpectEvalSha(sha, []string{"cv_reply:c1"}, int64(30000)).SetVal([]interface{}{int64(1), ""})
+	if reply, claimed, err := client.ClaimReply(ctx, "cv_reply:c1", 30*time.Second); err != nil || !claimed || reply != "" {
+		t.Errorf("got %q, %v, %v, want claimed", reply, claimed, err)
+	}
+
+	mock.ExpectEvalSha(sha, []string{"cv_reply:c1"}, int64(30000)).SetVal([]interface{}{int64(0), `{"token":"t"}`})
+	if reply, claimed, err := client.ClaimReply(ctx, "cv_reply:c1", 30*time.Second); err != nil || claimed || reply != `{"token":"t"}` {
+		t.Errorf("got %q, %v, %v, want the cached reply", reply, claimed, err)
+	}
+
+	mock.ExpectEvalSha(sha, []string{"cv_reply:c1"}, int64(30000)).SetErr(errors.New("redis error"))
+	if _, _, err := client.ClaimReply(ctx, "cv_reply:c1", 30*time.Second); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	mock.ExpectSet("cv_reply:c1", `{"token":"t"}`, time.Hour).SetVal("OK")
+	if err := client.StoreReply(ctx, "cv_reply:c1", `{"token":"t"}`, time.Hour); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	mock.ExpectDel("cv_reply:c1").SetVal(1)
+	if err := client.ReleaseReply(ctx, "cv_reply:c1"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_Increment(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := (&Client{client: db}).WithPrefix("site:a:")
+	ctx := context.Background()
+
+	mock.ExpectIncr("site:a:uses").SetVal(2)
+	if n, err := client.Increment(ctx, "uses"); err != nil || n != 2 {
+		t.Errorf("got %d, %v, want 2", n, err)
+	}
+
+	mock.ExpectIncr("site:a:uses").SetErr(errors.New("redis error"))
+	if _, err := client.Increment(ctx, "uses"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestClient_Decrement(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := (&Client{client: db}).WithPrefix("site:a:")
+	ctx := context.Background()
+
+	mock.ExpectDecr("site:a:uses").SetVal(1)
+	if n, err := client.Decrement(ctx, "uses"); err != nil || n != 1 {
+		t.Errorf("got %d, %v, want 1", n, err)
+	}
+
+	mock.ExpectDecr("site:a:uses").SetErr(errors.New("redis error"))
+	if _, err := client.Decrement(ctx, "uses"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestClient_WithPrefix(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := (&Client{client: db}).WithPrefix("site:a:").WithPrefix("x:")
+	ctx := context.Background()
+
+	mock.ExpectSet("site:a:x:token", "valid", time.Minute).SetVal("OK")
+	if err := client.SetToken(ctx, "token", "valid", time.Minute); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	mock.ExpectGet("site:a:x:token").SetVal("valid")
+	if got, err := client.GetToken(ctx, "token"); err != nil || got != "valid" {
+		t.Errorf("got %s, %v, want valid", got, err)
+	}
+
+	mock.ExpectEvalSha(consumeTokenScript.Hash(), []string{"site:a:x:token"}, "0", int64(0)).SetVal([]interface{}{"valid", int64(0), int64(0)})
+	if _, valid, err := client.ConsumeToken(ctx, "token", false, 0); err != nil || !valid {
+		t.Errorf("got %v, %v, want true", valid, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_HashIncrement(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := &Client{client: db}
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectHIncrBy("hash", "field", 1).SetVal(1)
+		if err := client.HashIncrement(ctx, "hash", "field", 1); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectHIncrBy("hash", "field", 1).SetErr(errors.New("redis error"))
+		if err := client.HashIncrement(ctx, "hash", "field", 1); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestClient_HashGetAll(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := &Client{client: db}
+	ctx := context.Background()
+
+	mock.ExpectHGetAll("hash").SetVal(map[string]string{"a": "1"})
+	got, err := client.HashGetAll(ctx, "hash")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got["a"] != "1" {
+		t.Errorf("got %v, want a=1", got)
+	}
+}
+
+func TestClient_Unique(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := &Client{client: db}
+	ctx := context.Background()
+
+	mock.ExpectPFAdd("visitors", "v1").SetVal(1)
+	if err := client.AddUnique(ctx, "visitors", "v1"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	mock.ExpectPFCount("visitors").SetVal(1)
+	got, err := client.CountUnique(ctx, "visitors")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got != 1 {
+		t.Errorf("got %d, want 1", got)
+	}
+}
+
+func TestClient_HashIncrementExpire(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := (&Client{client: db}).WithPrefix("site:a:")
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectTxPipeline()
+		mock.ExpectHIncrBy("site:a:stats", "field", 2).SetVal(2)
+		mock.ExpectExpire("site:a:stats", time.Hour).SetVal(true)
+		mock.ExpectTxPipelineExec()
+
+		if err := client.HashIncrementExpire(ctx, "stats", "field", 2, time.Hour); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectTxPipeline()
+		mock.ExpectHIncrBy("site:a:stats", "field", 2).SetErr(errors.New("redis error"))
+		mock.ExpectExpire("site:a:stats", time.Hour).SetVal(true)
+		mock.ExpectTxPipelineExec()
+
+		if err := client.HashIncrementExpire(ctx, "stats", "field", 2, time.Hour); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestClient_IncrementWindow(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := (&Client{client: db}).WithPrefix("site:a:")
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectTxPipeline()
+		mock.ExpectSetNX("site:a:limit", 0, time.Hour).SetVal(false)
+		mock.ExpectIncr("site:a:limit").SetVal(3)
+		mock.ExpectTxPipelineExec()
+
+		n, err := client.IncrementWindow(ctx, "limit", time.Hour)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 3 {
+			t.Errorf("expected 3, got %d", n)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectTxPipeline()
+		mock.ExpectSetNX("site:a:limit", 0, time.Hour).SetErr(errors.New("redis error"))
+		mock.ExpectIncr("site:a:limit").SetVal(1)
+		mock.ExpectTxPipelineExec()
+
+		if _, err := client.IncrementWindow(ctx, "limit", time.Hour); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestClient_List(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := &Client{client: db}
+	ctx := context.Background()
+
+	t.Run("push", func(t *testing.T) {
+		mock.ExpectLPush("pending", "a").SetVal(1)
+		if err := client.ListPush(ctx, "pending", "a"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("move", func(t *testing.T) {
+		mock.ExpectBRPopLPush("pending", "processing", time.Second).SetVal("a")
+		value, ok, err := client.ListMove(ctx, "pending", "processing", time.Second)
+		if err != nil || !ok || value != "a" {
+			t.Errorf("unexpected result: %q %v %v", value, ok, err)
+		}
+	})
+
+	t.Run("move timeout", func(t *testing.T) {
+		mock.ExpectBRPopLPush("pending", "processing", time.Second).SetErr(redis.Nil)
+		_, ok, err := client.ListMove(ctx, "pending", "processing", time.Second)
+		if err != nil || ok {
+			t.Errorf("unexpected result: %v %v", ok, err)
+		}
+	})
+
+	t.Run("move error", func(t *testing.T) {
+		mock.ExpectBRPopLPush("pending", "processing", time.Second).SetErr(errors.New("redis error"))
+		if _, _, err := client.ListMove(ctx, "pending", "processing", time.Second); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		mock.ExpectLRem("processing", 1, "a").SetVal(1)
+		if err := client.ListRemove(ctx, "processing", "a"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("range", func(t *testing.T) {
+		mock.ExpectLRange("processing", 0, -1).SetVal([]string{"a", "b"})
+		values, err := client.ListRange(ctx, "processing")
+		if err != nil || len(values) != 2 {
+			t.Errorf("unexpected result: %v %v", values, err)
+		}
+	})
+}
+
+func TestClient_Stream(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := &Client{client: db}
+	ctx := context.Background()
+	from := time.UnixMilli(1000)
+	to := time.UnixMilli(2000)
+
+	t.Run("add", func(t *testing.T) {
+		mock.ExpectXAdd(&redis.XAddArgs{Stream: "events", Values: []interface{}{"value", "a"}}).SetVal("1000-0")
+		if err := client.StreamAdd(ctx, "events", "a"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("range", func(t *testing.T) {
+		mock.ExpectXRange("events", "1000", "2000").SetVal([]redis.XMessage{
+			{ID: "1000-0", Values: map[string]interface{}{"value": "a"}},
+			{ID: "1500-0", Values: map[string]interface{}{"other": "x"}},
+			{ID: "1999-0", Values: map[string]interface{}{"value": "b"}},
+		})
+		values, err := client.StreamRange(ctx, "events", from, to)
+		if err != nil || len(values) != 2 || values[0] != "a" || values[1] != "b" {
+			t.Errorf("unexpected result: %v %v", values, err)
+		}
+	})
+
+	t.Run("unbounded range", func(t *testing.T) {
+		mock.ExpectXRange("events", "-", "+").SetVal(nil)
+		if _, err := client.StreamRange(ctx, "events", time.Time{}, time.Time{}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("trim", func(t *testing.T) {
+		mock.ExpectXTrimMinID("events", "2000").SetVal(3)
+		n, err := client.StreamTrim(ctx, "events", to)
+		if err != nil || n != 3 {
+			t.Errorf("unexpected result: %d %v", n, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_Schedule(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := &Client{client: db}
+	ctx := context.Background()
+	due := time.UnixMilli(5000)
+
+	t.Run("add", func(t *testing.T) {
+		mock.ExpectTxPipeline()
+		mock.ExpectZAdd("pending", &redis.Z{Score: 5000, Member: "m"}).SetVal(1)
+		mock.ExpectHSet("pending:values", "m", "v").SetVal(1)
+		mock.ExpectTxPipelineExec()
+		if err := client.ScheduleAdd(ctx, "pending", "m", "v", due); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		mock.ExpectTxPipeline()
+		mock.ExpectZRem("pending", "m").SetVal(1)
+		mock.ExpectHDel("pending:values", "m").SetVal(1)
+		mock.ExpectTxPipelineExec()
+		if err := client.ScheduleRemove(ctx, "pending", "m"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("claim due", func(t *testing.T) {
+		mock.ExpectEvalSha(claimDueScript.Hash(), []string{"pending", "pending:values"}, int64(5000), 10).SetVal([]interface{}{"v"})
+		values, err := client.ScheduleClaimDue(ctx, "pending", due, 10)
+		if err != nil || len(values) != 1 || values[0] != "v" {
+			t.Errorf("unexpected result: %v %v", values, err)
+		}
+	})
+
+	t.Run("claim error", func(t *testing.T) {
+		mock.ExpectEvalSha(claimDueScript.Hash(), []string{"pending", "pending:values"}, int64(5000), 10).SetErr(errors.New("redis error"))
+		if _, err := client.ScheduleClaimDue(ctx, "pending", due, 10); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_AllowRequest(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := (&Client{client: db}).WithPrefix("site:main:")
+	ctx := context.Background()
+	sha := slidingWindowScript.Hash()
+	buckets := []ratelimit.Bucket{
+		{Key: "ratelimit:cv:ip:x", Limit: ratelimit.Limit{Max: 5, Window: time.Minute}},
+		{Key: "ratelimit:cv:global", Limit: ratelimit.Limit{Max: 100, Window: time.Second}},
+	}
+	keys := []string{"site:main:ratelimit:cv:ip:x", "site:main:ratelimit:cv:global"}
+
+	t.Run("allowed", func(t *testing.T) {
+		mock.ExpectEvalSha(sha, keys, "req-1", 5, int64(60000), 100, int64(1000)).SetVal(int64(0))
+		retryAfter, err := client.AllowRequest(ctx, buckets, "req-1")
+		if err != nil || retryAfter != 0 {
+			t.Errorf("unexpected result: %v %v", retryAfter, err)
+		}
+	})
+
+	t.Run("limited", func(t *testing.T) {
+		mock.ExpectEvalSha(sha, keys, "req-2", 5, int64(60000), 100, int64(1000)).SetVal(int64(1500))
+		retryAfter, err := client.AllowRequest(ctx, buckets, "req-2")
+		if err != nil || retryAfter != 1500*time.Millisecond {
+			t.Errorf("unexpected result: %v %v", retryAfter, err)
+		}
+	})
+
+	t.Run("redis error", func(t *testing.T) {
+		mock.ExpectEvalSha(sha, keys, "req-3", 5, int64(60000), 100, int64(1000)).SetErr(errors.New("redis error"))
+		if _, err := client.AllowRequest(ctx, buckets, "req-3"); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_Lockout(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := (&Client{client: db}).WithPrefix("site:main:")
+	ctx := context.Background()
+	sha := lockoutFailureScript.Hash()
+	policy := lockout.Policy{Threshold: 3, Base: time.Minute, Max: 2 * time.Minute, Reset: time.Hour}
+	keys := []string{"site:main:lockout:fp", "site:main:lockouts"}
+	lockedUntil := time.UnixMilli(1772366400000)
+
+	t.Run("failure below threshold", func(t *testing.T) {
+		mock.ExpectEvalSha(sha, keys, "fp", 3, int64(3600000), int64(60000), int64(120000)).SetVal([]interface{}{int64(2), int64(0), int64(0), int64(0)})
+		state, locked, err := client.RecordLockoutFailure(ctx, "fp", policy)
+		if err != nil || locked || state.Failures != 2 || !state.LockedUntil.IsZero() {
+			t.Errorf("unexpected result: %+v %v %v", state, locked, err)
+		}
+	})
+
+	t.Run("failure locks client", func(t *testing.T) {
+		mock.ExpectEvalSha(sha, keys, "fp", 3, int64(3600000), int64(60000), int64(120000)).SetVal([]interface{}{int64(0), int64(2), lockedUntil.UnixMilli(), int64(1)})
+		state, locked, err := client.RecordLockoutFailure(ctx, "fp", policy)
+		if err != nil || !locked || state.Level != 2 || !state.LockedUntil.Equal(lockedUntil) {
+			t.Errorf("unexpected result: %+v %v %v", state, locked, err)
+		}
+	})
+
+	t.Run("failure redis error", func(t *testing.T) {
+		mock.ExpectEvalSha(sha, keys, "fp", 3, int64(3600000), int64(60000), int64(120000)).SetErr(errors.New("redis error"))
+		if _, _, err := client.RecordLockoutFailure(ctx, "fp", policy); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("state", func(t *testing.T) {
+		mock.ExpectHGetAll("site:main:lockout:fp").SetVal(map[string]string{
+			"failures":     "1",
+			"level":        "2",
+			"locked_until": strconv.FormatInt(lockedUntil.UnixMilli(), 10),
+			"last_failure": "1000",
+		})
+		state, found, err := client.LockoutState(ctx, "fp")
+		want := lockout.State{Client: "fp", Failures: 1, Level: 2, LockedUntil: lockedUntil, LastFailure: time.UnixMilli(1000)}
+		if err != nil || !found || state != want {
+			t.Errorf("got %+v, %v, %v, want %+v", state, found, err, want)
+		}
+	})
+
+	t.Run("missing state", func(t *testing.T) {
+		mock.ExpectHGetAll("site:main:lockout:fp").SetVal(map[string]string{})
+		if _, found, err := client.LockoutState(ctx, "fp"); err != nil || found {
+			t.Errorf("got %v, %v, want not found", found, err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		mock.Regexp().ExpectZRemRangeByScore("site:main:lockouts", `-inf`, `\d+`).SetVal(1)
+		mock.ExpectZRange("site:main:lockouts", 0, -1).SetVal([]string{"fp", "gone"})
+		mock.ExpectHGetAll("site:main:lockout:fp").SetVal(map[string]string{"failures": "2"})
+		mock.ExpectHGetAll("site:main:lockout:gone").SetVal(map[string]string{})
+		states, err := client.ListLockouts(ctx)
+		if err != nil || len(states) != 1 || states[0].Client != "fp" || states[0].Failures != 2 {
+			t.Errorf("unexpected result: %+v %v", states, err)
+		}
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		mock.ExpectTxPipeline()
+		mock.ExpectDel("site:main:lockout:fp").SetVal(1)
+		mock.ExpectZRem("site:main:lockouts", "fp").SetVal(1)
+		mock.ExpectTxPipelineExec()
+		if cleared, err := client.ClearLockout(ctx, "fp"); err != nil || !cleared {
+			t.Errorf("got %v, %v, want cleared", cleared, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_Stamp(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := (&Client{client: db}).WithPrefix("site:main:")
+	ctx := context.Background()
+	stamp := pdfstamp.Stamp{ID: "4b1c", Token: "3f9a", Credential: "acme", Lang: "pl", StampedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	value, _ := stamp.Encode()
+
+	t.Run("store", func(t *testing.T) {
+		mock.ExpectSet("site:main:cv_stamp:4b1c", value, time.Hour).SetVal("OK")
+		if err := client.StoreStamp(ctx, stamp, time.Hour); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("load", func(t *testing.T) {
+		mock.ExpectGet("site:main:cv_stamp:4b1c").SetVal(value)
+		got, found, err := client.Stamp(ctx, "4b1c")
+		if err != nil || !found || got != stamp {
+			t.Errorf("got %+v, %v, %v, want %+v", got, found, err, stamp)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		mock.ExpectGet("site:main:cv_stamp:4b1c").RedisNil()
+		if _, found, err := client.Stamp(ctx, "4b1c"); err != nil || found {
+			t.Errorf("got %v, %v, want not found", found, err)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		mock.ExpectGet("site:main:cv_stamp:4b1c").SetVal("acme")
+		if _, _, err := client.Stamp(ctx, "4b1c"); !errors.Is(err, pdfstamp.ErrMalformedStamp) {
+			t.Errorf("got %v, want %v", err, pdfstamp.ErrMalformedStamp)
+		}
+	})
+
+	t.Run("redis error", func(t *testing.T) {
+		mock.ExpectGet("site:main:cv_stamp:4b1c").SetErr(errors.New("redis error"))
+		if _, _, err := client.Stamp(ctx, "4b1c"); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_AccessRequest(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := (&Client{client: db}).WithPrefix("site:main:")
+	ctx := context.Background()
+	req := accessrequest.Request{ID: "5f2c", Name: "Anna", Company: "Acme", Reason: "Hiring a Go developer.", Lang: "en", CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	value, _ := req.Encode()
+
+	t.Run("store", func(t *testing.T) {
+		mock.ExpectSet("site:main:cv_access_request:5f2c", value, time.Hour).SetVal("OK")
+		if err := client.StoreAccessRequest(ctx, req, time.Hour); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		mock.ExpectGet("site:main:cv_access_request:5f2c").SetVal(value)
+		got, found, err := client.AccessRequest(ctx, "5f2c")
+		if err != nil || !found || got != req {
+			t.Errorf("got %+v, %v, %v, want %+v", got, found, err, req)
+		}
+	})
+
+	t.Run("take", func(t *testing.T) {
+		mock.ExpectGetDel("site:main:cv_access_request:5f2c").SetVal(value)
+		got, found, err := client.TakeAccessRequest(ctx, "5f2c")
+		if err != nil || !found || got != req {
+			t.Errorf("got %+v, %v, %v, want %+v", got, found, err, req)
+		}
+	})
+
+	t.Run("take missing", func(t *testing.T) {
+		mock.ExpectGetDel("site:main:cv_access_request:5f2c").RedisNil()
+		if _, found, err := client.TakeAccessRequest(ctx, "5f2c"); err != nil || found {
+			t.Errorf("got %v, %v, want not found", found, err)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		mock.ExpectGet("site:main:cv_access_request:5f2c").SetVal("anna")
+		if _, _, err := client.AccessRequest(ctx, "5f2c"); !errors.Is(err, accessrequest.ErrMalformedRequest) {
+			t.Errorf("got %v, want %v", err, accessrequest.ErrMalformedRequest)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_FailCaptchaAttempt(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := &Client{client: db}
+	ctx := context.Background()
+	sha := captchaFailedAttemptScript.Hash()
+
+	mock.ExpectEvalSha(sha, []string{"captcha:c1"}).SetVal(int64(2))
+	if tries, found, err := client.FailCaptchaAttempt(ctx, "captcha:c1"); err != nil || !found || tries != 2 {
+		t.Errorf("got %v, %v, %v, want 2 tries left", tries, found, err)
+	}
+
+	mock.ExpectEvalSha(sha, []string{"captcha:c1"}).SetVal(int64(-1))
+	if _, found, err := client.FailCaptchaAttempt(ctx, "captcha:c1"); err != nil || found {
+		t.Errorf("got %v, %v, want not found", found, err)
+	}
+
+	mock.ExpectEvalSha(sha, []string{"captcha:c1"}).SetErr(errors.New("redis error"))
+	if _, _, err := client.FailCaptchaAttempt(ctx, "captcha:c1"); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_RedeemCaptcha(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := &Client{client: db}
+	ctx := context.Background()
+	sha := redeemCaptchaScript.Hash()
+
+	tests := []struct {
+		name      string
+		reply     []interface{}
+		want      RedeemedCaptcha
+		wantFound bool
+		wantErr   bool
+	}{
+		{
+			name:      "redeemed",
+			reply:     []interface{}{int64(1), `{"solved":true}`, int64(90000)},
+			want:      RedeemedCaptcha{Solved: true, Value: `{"solved":true}`, TTL: 90 * time.Second},
+			wantFound: true,
+		},
+		{name: "not solved", reply: []interface{}{int64(0)}, wantFound: true},
+		{name: "missing", reply: []interface{}{int64(-1)}},
+		{name: "malformed reply", reply: []interface{}{int64(1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectEvalSha(sha, []string{"captcha:c1"}).SetVal(tt.reply)
+			got, found, err := client.RedeemCaptcha(ctx, "captcha:c1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RedeemCaptcha() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("got %+v, %v, want %+v, %v", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+
+	mock.ExpectEvalSha(sha, []string{"captcha:c1"}).SetErr(errors.New("redis error"))
+	if _, _, err := client.RedeemCaptcha(ctx, "captcha:c1"); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_RestoreCaptcha(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	client := &Client{client: db}
+	ctx := context.Background()
+
+	mock.ExpectSetNX("captcha:c1", "data", time.Minute).SetVal(true)
+	if restored, err := client.RestoreCaptcha(ctx, "captcha:c1", RedeemedCaptcha{Value: "data", TTL: time.Minute}); err != nil || !restored {
+		t.Errorf("got %v, %v, want restored", restored, err)
+	}
+
+	mock.ExpectSetNX("captcha:c1", "data", 0).SetVal(false)
+	if restored, err := client.RestoreCaptcha(ctx, "captcha:c1", RedeemedCaptcha{Value: "data", TTL: -time.Millisecond}); err != nil || restored {
+		t.Errorf("got %v, %v, want not restored", restored, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
 }
@@ -0,0 +1,180 @@
+package redis
+
+import "github.com/go-redis/redis/v8"
+
+// KEYS[1] token hash, ARGV[1] "1" for a Range request, ARGV[2] grace window in ms; returns {metadata, remaining, resumed}.
+var consumeTokenScript = redis.NewScript(`
+local metadata = redis.call('HGET', KEYS[1], 'metadata')
+if not metadata then
+	return false
+end
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local grace = tonumber(ARGV[2])
+local first = tonumber(redis.call('HGET', KEYS[1], 'first_used') or '0')
+local remaining = tonumber(redis.call('HGET', KEYS[1], 'remaining') or '0')
+
+if ARGV[1] == '1' and first > 0 and now - first < grace then
+	return {metadata, remaining, 1}
+end
+if remaining <= 0 then
+	return false
+end
+
+remaining = redis.call('HINCRBY', KEYS[1], 'remaining', -1)
+if first == 0 then
+	first = now
+	redis.call('HSET', KEYS[1], 'first_used', now)
+end
+
+local graceLeft = first + grace - now
+if remaining == 0 and graceLeft <= 0 then
+	redis.call('DEL', KEYS[1])
+elseif remaining == 0 or redis.call('PTTL', KEYS[1]) < graceLeft then
+	redis.call('PEXPIRE', KEYS[1], graceLeft)
+end
+
+return {metadata, remaining, 0}
+`)
+
+// KEYS[1] schedule sorted set, KEYS[2] values hash, ARGV[1] now in ms, ARGV[2] limit; removes and returns due values.
+var claimDueScript = redis.NewScript(`
+local members = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+local values = {}
+for _, member in ipairs(members) do
+	redis.call('ZREM', KEYS[1], member)
+	local value = redis.call('HGET', KEYS[2], member)
+	if value then
+		redis.call('HDEL', KEYS[2], member)
+		table.insert(values, value)
+	end
+end
+
+return values
+`)
+
+// KEYS bucket sorted sets, ARGV[1] request member, then limit and window in ms per key; returns the wait in ms, 0 when allowed.
+var slidingWindowScript = redis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local retry = 0
+
+for i, key in ipairs(KEYS) do
+	local limit = tonumber(ARGV[i * 2])
+	local window = tonumber(ARGV[i * 2 + 1])
+	redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+	if redis.call('ZCARD', key) >= limit then
+		local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+		local wait = tonumber(oldest[2]) + window - now
+		if wait > retry then
+			retry = wait
+		end
+	end
+end
+if retry > 0 then
+	return retry
+end
+
+for i, key in ipairs(KEYS) do
+	redis.call('ZADD', key, now, ARGV[1])
+	redis.call('PEXPIRE', key, ARGV[i * 2 + 1])
+end
+
+return 0
+`)
+
+// KEYS[1] lockout hash, KEYS[2] lockout index, ARGV[1] client, ARGV[2] threshold, ARGV[3] reset window in ms, ARGV[4..] lockout per level in ms;
+// returns {failures, level, locked_until, locked}.
+var lockoutFailureScript = redis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local threshold = tonumber(ARGV[2])
+local reset = tonumber(ARGV[3])
+
+local failures = redis.call('HINCRBY', KEYS[1], 'failures', 1)
+local level = tonumber(redis.call('HGET', KEYS[1], 'level') or '0')
+local lockedUntil = tonumber(redis.call('HGET', KEYS[1], 'locked_until') or '0')
+local locked = 0
+
+if failures >= threshold then
+	level = redis.call('HINCRBY', KEYS[1], 'level', 1)
+	lockedUntil = now + tonumber(ARGV[math.min(level, #ARGV - 3) + 3])
+	failures = 0
+	locked = 1
+	redis.call('HSET', KEYS[1], 'failures', 0, 'locked_until', lockedUntil)
+end
+redis.call('HSET', KEYS[1], 'last_failure', now)
+
+local ttl = reset
+if lockedUntil > now then
+	ttl = lockedUntil - now + reset
+end
+redis.call('PEXPIRE', KEYS[1], ttl)
+redis.call('ZADD', KEYS[2], now + ttl, ARGV[1])
+
+return {failures, level, lockedUntil, locked}
+`)
+
+// KEYS[1] captcha JSON; decrements triesLeft keeping the TTL and deletes the captcha at zero; returns the tries left, -1 when missing.
+var captchaFailedAttemptScript = redis.NewScript(`
+local data = redis.call('GET', KEYS[1])
+if not data then
+	return -1
+end
+
+local captcha = cjson.decode(data)
+local tries = (tonumber(captcha.triesLeft) or 0) - 1
+if tries <= 0 then
+	redis.call('DEL', KEYS[1])
+	return 0
+end
+
+captcha.triesLeft = tries
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], cjson.encode(captcha), 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], cjson.encode(captcha))
+end
+
+return tries
+`)
+
+// KEYS[1] captcha JSON; deletes the captcha only when it is solved, so exactly one caller redeems it; returns {1, captcha, pttl} redeemed, {0} not solved, {-1} missing.
+var redeemCaptchaScript = redis.NewScript(`
+local data = redis.call('GET', KEYS[1])
+if not data then
+	return {-1}
+end
+
+local captcha = cjson.decode(data)
+if captcha.solved ~= true then
+	return {0}
+end
+
+local ttl = redis.call('PTTL', KEYS[1])
+redis.call('DEL', KEYS[1])
+return {1, data, ttl}
+`)
+
+// KEYS[1] cached reply, ARGV[1] lease in ms; claims the key with an empty pending marker unless it exists; returns {1, ""} claimed, {0, reply} otherwise.
+var claimReplyScript = redis.NewScript(`
+local reply = redis.call('GET', KEYS[1])
+if reply then
+	return {0, reply}
+end
+
+redis.call('SET', KEYS[1], '', 'PX', ARGV[1])
+return {1, ''}
+`)
+
+// KEYS[1] counter, ARGV[1] limit; increments only below the limit; returns {count, 1} incremented, {count, 0} at the limit.
+var incrementCappedScript = redis.NewScript(`
+local count = tonumber(redis.call('GET', KEYS[1]) or '0')
+if count >= tonumber(ARGV[1]) then
+	return {count, 0}
+end
+
+return {redis.call('INCR', KEYS[1]), 1}
+`)
@@ -0,0 +1,260 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func newScriptClient(t *testing.T) (*Client, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	db := redis.NewClient(&redis.Options{Addr: mr.Addr(), PoolSize: 32})
+	t.Cleanup(func() { db.Close() })
+
+	return &Client{client: db}, mr
+}
+
+func setCaptcha(t *testing.T, mr *miniredis.Miniredis, key string, triesLeft int, ttl time.Duration) {
+	t.Helper()
+	data, _ := json.Marshal(map[string]any{"value": "XYZ", "triesLeft": triesLeft, "solved": true})
+	if err := mr.Set(key, string(data)); err != nil {
+		t.Fatal(err)
+	}
+	mr.SetTTL(key, ttl)
+}
+
+func TestCaptchaFailedAttemptScript(t *testing.T) {
+	client, mr := newScriptClient(t)
+	ctx := context.Background()
+
+	t.Run("decrements and keeps the captcha", func(t *testing.T) {
+		setCaptcha(t, mr, "captcha:a", 3, 3*time.Minute)
+
+		tries, found, err := client.FailCaptchaAttempt(ctx, "captcha:a")
+		if err != nil || !found || tries != 2 {
+			t.Fatalf("got %v, %v, %v, want 2 tries left", tries, found, err)
+		}
+
+		var stored struct {
+			Value     string `json:"value"`
+			TriesLeft int    `json:"triesLeft"`
+			Solved    bool   `json:"solved"`
+		}
+		data, _ := mr.Get("captcha:a")
+		if err := json.Unmarshal([]byte(data), &stored); err != nil {
+			t.Fatal(err)
+		}
+		if stored.Value != "XYZ" || stored.TriesLeft != 2 || !stored.Solved {
+			t.Errorf("stored captcha = %+v", stored)
+		}
+	})
+
+	t.Run("does not extend the ttl", func(t *testing.T) {
+		setCaptcha(t, mr, "captcha:b", 3, 3*time.Minute)
+		mr.FastForward(time.Minute)
+
+		if _, _, err := client.FailCaptchaAttempt(ctx, "captcha:b"); err != nil {
+			t.Fatal(err)
+		}
+		if ttl := mr.TTL("captcha:b"); ttl != 2*time.Minute {
+			t.Errorf("ttl = %v, want 2m", ttl)
+		}
+	})
+
+	t.Run("deletes the captcha on the last try", func(t *testing.T) {
+		setCaptcha(t, mr, "captcha:c", 1, 3*time.Minute)
+
+		tries, found, err := client.FailCaptchaAttempt(ctx, "captcha:c")
+		if err != nil || !found || tries != 0 {
+			t.Fatalf("got %v, %v, %v, want no tries left", tries, found, err)
+		}
+		if mr.Exists("captcha:c") {
+			t.Error("expected captcha to be deleted")
+		}
+	})
+
+	t.Run("missing captcha", func(t *testing.T) {
+		if _, found, err := client.FailCaptchaAttempt(ctx, "captcha:missing"); err != nil || found {
+			t.Errorf("got %v, %v, want not found", found, err)
+		}
+	})
+
+	t.Run("malformed captcha", func(t *testing.T) {
+		mr.Set("captcha:d", "not json")
+		if _, _, err := client.FailCaptchaAttempt(ctx, "captcha:d"); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestCaptchaFailedAttemptScript_Concurrent(t *testing.T) {
+	client, mr := newScriptClient(t)
+	ctx := context.Background()
+	const tries, attempts = 5, 40
+	setCaptcha(t, mr, "captcha:race", tries, 3*time.Minute)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := map[int64]int{}
+	notFound := 0
+	start := make(chan struct{})
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			left, found, err := client.FailCaptchaAttempt(ctx, "captcha:race")
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case !found:
+				notFound++
+			default:
+				seen[left]++
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	for left := int64(0); left < tries; left++ {
+		if seen[left] != 1 {
+			t.Errorf("%d attempts saw %d tries left, want exactly 1", seen[left], left)
+		}
+	}
+	if notFound != attempts-tries {
+		t.Errorf("%d attempts found no captcha, want %d", notFound, attempts-tries)
+	}
+	if mr.Exists("captcha:race") {
+		t.Error("expected captcha to be deleted after the last try")
+	}
+}
+
+func TestRedeemCaptchaScript(t *testing.T) {
+	client, mr := newScriptClient(t)
+	ctx := context.Background()
+
+	setCaptcha(t, mr, "captcha:solved", 3, time.Minute)
+	redeemed, found, err := client.RedeemCaptcha(ctx, "captcha:solved")
+	if err != nil || !found || !redeemed.Solved {
+		t.Fatalf("got %+v, %v, %v, want redeemed", redeemed, found, err)
+	}
+	if redeemed.TTL != time.Minute {
+		t.Errorf("expected remaining TTL %v, got %v", time.Minute, redeemed.TTL)
+	}
+	if mr.Exists("captcha:solved") {
+		t.Error("expected redeemed captcha to be deleted")
+	}
+
+	if restored, err := client.RestoreCaptcha(ctx, "captcha:solved", redeemed); err != nil || !restored {
+		t.Fatalf("got %v, %v, want restored", restored, err)
+	}
+	if mr.TTL("captcha:solved") != time.Minute {
+		t.Errorf("expected restored TTL %v, got %v", time.Minute, mr.TTL("captcha:solved"))
+	}
+	if again, _, err := client.RedeemCaptcha(ctx, "captcha:solved"); err != nil || again.Value != redeemed.Value {
+		t.Errorf("got %+v, %v, want the restored captcha to be redeemable", again, err)
+	}
+
+	mr.Set("captcha:unsolved", `{"value":"XYZ","triesLeft":3,"solved":false}`)
+	if redeemed, found, err := client.RedeemCaptcha(ctx, "captcha:unsolved"); err != nil || !found || redeemed.Solved {
+		t.Errorf("got %+v, %v, %v, want not solved", redeemed, found, err)
+	}
+	if !mr.Exists("captcha:unsolved") {
+		t.Error("expected unsolved captcha to be kept")
+	}
+
+	if _, found, err := client.RedeemCaptcha(ctx, "captcha:missing"); err != nil || found {
+		t.Errorf("got %v, %v, want not found", found, err)
+	}
+}
+
+func TestRedeemCaptchaScript_Concurrent(t *testing.T) {
+	client, mr := newScriptClient(t)
+	ctx := context.Background()
+	const callers = 40
+	setCaptcha(t, mr, "captcha:race", 3, time.Minute)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	redeemed, notFound := 0, 0
+	start := make(chan struct{})
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			captcha, found, err := client.RedeemCaptcha(ctx, "captcha:race")
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case found && captcha.Solved:
+				redeemed++
+			case !found:
+				notFound++
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if redeemed != 1 || notFound != callers-1 {
+		t.Errorf("redeemed %d times and missed %d times, want 1 and %d", redeemed, notFound, callers-1)
+	}
+}
+
+func TestClaimReplyScript(t *testing.T) {
+	client, mr := newScriptClient(t)
+	ctx := context.Background()
+
+	if _, claimed, err := client.ClaimReply(ctx, "cv_reply:c1", 30*time.Second); err != nil || !claimed {
+		t.Fatalf("got %v, %v, want claimed", claimed, err)
+	}
+	if mr.TTL("cv_reply:c1") != 30*time.Second {
+		t.Errorf("expected lease %v, got %v", 30*time.Second, mr.TTL("cv_reply:c1"))
+	}
+
+	if reply, claimed, err := client.ClaimReply(ctx, "cv_reply:c1", 30*time.Second); err != nil || claimed || reply != "" {
+		t.Errorf("got %q, %v, %v, want pending", reply, claimed, err)
+	}
+
+	if err := client.StoreReply(ctx, "cv_reply:c1", `{"token":"t"}`, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if reply, claimed, err := client.ClaimReply(ctx, "cv_reply:c1", 30*time.Second); err != nil || claimed || reply != `{"token":"t"}` {
+		t.Errorf("got %q, %v, %v, want the stored reply", reply, claimed, err)
+	}
+
+	mr.FastForward(time.Hour)
+	if _, claimed, err := client.ClaimReply(ctx, "cv_reply:c1", 30*time.Second); err != nil || !claimed {
+		t.Errorf("got %v, %v, want claimed after the window", claimed, err)
+	}
+}
+
+func TestIncrementCappedScript(t *testing.T) {
+	client, mr := newScriptClient(t)
+	ctx := context.Background()
+
+	for i, want := range []struct {
+		count       int64
+		incremented bool
+	}{{1, true}, {2, true}, {2, false}, {2, false}} {
+		count, incremented, err := client.IncrementCapped(ctx, "credential:acme:uses", 2)
+		if err != nil || count != want.count || incremented != want.incremented {
+			t.Errorf("call %d = %v, %v, %v, want %v, %v", i+1, count, incremented, err, want.count, want.incremented)
+		}
+	}
+	if got, _ := mr.Get("credential:acme:uses"); got != "2" {
+		t.Errorf("counter = %s, want it held at the limit", got)
+	}
+}
@@ -0,0 +1,136 @@
+package smtp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+type receivedMail struct {
+	from string
+	to   []string
+	data string
+}
+
+func startSMTPServer(t *testing.T) (int, <-chan receivedMail) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan receivedMail, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+		var m receivedMail
+
+		reply("220 localhost ready")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.TrimRight(line, "\r\n")
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				m.from = strings.Trim(strings.TrimPrefix(cmd, "MAIL FROM:"), "<>")
+				reply("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				m.to = append(m.to, strings.Trim(strings.TrimPrefix(cmd, "RCPT TO:"), "<>"))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				m.data = data.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 bye")
+				received <- m
+				return
+			default:
+				reply("500 unknown")
+			}
+		}
+	}()
+
+	return ln.Addr().(*net.TCPAddr).Port, received
+}
+
+func TestSender_Send(t *testing.T) {
+	port, received := startSMTPServer(t)
+	s := NewSender("127.0.0.1", port, "", "", "no-reply@example.com")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.Send(ctx, Message{
+		To:      []string{"owner@example.com"},
+		ReplyTo: "jan@example.com",
+		Subject: "Wiadomość od Jana",
+		Body:    "Dzień dobry,\nproszę o kontakt.",
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	m := <-received
+	if m.from != "no-reply@example.com" || len(m.to) != 1 || m.to[0] != "owner@example.com" {
+		t.Fatalf("envelope = %+v", m)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(m.data))
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if subject != "Wiadomość od Jana" {
+		t.Errorf("Subject = %q", subject)
+	}
+	if parsed.Header.Get("Reply-To") != "jan@example.com" {
+		t.Errorf("Reply-To = %q", parsed.Header.Get("Reply-To"))
+	}
+	body, _ := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if strings.TrimRight(string(body), "\r\n") != "Dzień dobry,\r\nproszę o kontakt." {
+		t.Errorf("Body = %q", body)
+	}
+}
+
+func TestSender_RejectsHeaderInjection(t *testing.T) {
+	s := NewSender("127.0.0.1", 1, "", "", "no-reply@example.com")
+
+	err := s.Send(context.Background(), Message{
+		To:      []string{"owner@example.com"},
+		Subject: "Hi\r\nBcc: victim@example.com",
+		Body:    "x",
+	})
+	if err == nil {
+		t.Error("Send() expected error for header with line break")
+	}
+}
@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+)
+
+const dayLayout = "2006-01-02"
+
+type HashStore interface {
+	HashIncrementExpire(ctx context.Context, key, field string, n int64, ttl time.Duration) error
+	HashGetAll(ctx context.Context, key string) (map[string]string, error)
+}
+
+type Recorder struct {
+	store     HashStore
+	retention time.Duration
+	now       func() time.Time
+}
+
+func NewRecorder(store HashStore, retentionDays int) *Recorder {
+	return &Recorder{
+		store:     store,
+		retention: time.Duration(retentionDays) * 24 * time.Hour,
+		now:       time.Now,
+	}
+}
+
+func (r *Recorder) Increment(ctx context.Context, fields ...string) {
+	key := DayKey(r.now())
+	for _, field := range fields {
+		if err := r.store.HashIncrementExpire(ctx, key, field, 1, r.retention+24*time.Hour); err != nil {
+			log.Printf("ERROR: could not record stats counter %s: %v", field, err)
+			return
+		}
+	}
+}
+
+func (r *Recorder) Read(ctx context.Context, day time.Time) (map[string]int64, error) {
+	raw, err := r.store.HashGetAll(ctx, DayKey(day))
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(map[string]int64, len(raw))
+	for field, value := range raw {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		counters[field] = n
+	}
+
+	return counters, nil
+}
+
+func (r *Recorder) RetentionDays() int {
+	return int(r.retention / (24 * time.Hour))
+}
+
+func DayKey(t time.Time) string {
+	return "stats:" + t.UTC().Format(dayLayout)
+}
+
+func ParseDay(s string) (time.Time, error) {
+	return time.Parse(dayLayout, s)
+}
+
+func FormatDay(t time.Time) string {
+	return t.UTC().Format(dayLayout)
+}
@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type mockHashStore struct {
+	hashes map[string]map[string]int64
+	ttls   map[string]time.Duration
+	err    error
+}
+
+func (m *mockHashStore) HashIncrementExpire(ctx context.Context, key, field string, n int64, ttl time.Duration) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.hashes[key] == nil {
+		m.hashes[key] = map[string]int64{}
+	}
+	m.hashes[key][field] += n
+	m.ttls[key] = ttl
+	return nil
+}
+
+func (m *mockHashStore) HashGetAll(ctx context.Context, key string) (map[string]string, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	out := map[string]string{"broken": "x"}
+	for f, v := range m.hashes[key] {
+		out[f] = strconv.FormatInt(v, 10)
+	}
+	return out, nil
+}
+
+func TestRecorder_IncrementAndRead(t *testing.T) {
+	store := &mockHashStore{hashes: map[string]map[string]int64{}, ttls: map[string]time.Duration{}}
+	r := NewRecorder(store, 30)
+	day := time.Date(2026, 10, 19, 23, 30, 0, 0, time.UTC)
+	r.now = func() time.Time { return day }
+
+	r.Increment(context.Background(), "content_requested:pl", "content_served:pl")
+	r.Increment(context.Background(), "content_requested:pl")
+
+	if got := store.ttls["stats:2026-10-19"]; got != 31*24*time.Hour {
+		t.Errorf("ttl = %v, want 31 days", got)
+	}
+
+	counters, err := r.Read(context.Background(), day)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if counters["content_requested:pl"] != 2 || counters["content_served:pl"] != 1 {
+		t.Errorf("Read() got = %v", counters)
+	}
+	if _, ok := counters["broken"]; ok {
+		t.Error("Read() kept a non-numeric counter")
+	}
+	if r.RetentionDays() != 30 {
+		t.Errorf("RetentionDays() = %d, want 30", r.RetentionDays())
+	}
+}
+
+func TestRecorder_Errors(t *testing.T) {
+	r := NewRecorder(&mockHashStore{err: errors.New("redis error")}, 30)
+
+	r.Increment(context.Background(), "content_fallback")
+	if _, err := r.Read(context.Background(), time.Now()); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestDayKey(t *testing.T) {
+	warsaw := time.FixedZone("CEST", 2*60*60)
+	if got := DayKey(time.Date(2026, 10, 20, 1, 0, 0, 0, warsaw)); got != "stats:2026-10-19" {
+		t.Errorf("DayKey() = %s, want stats:2026-10-19", got)
+	}
+}
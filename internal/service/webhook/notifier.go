@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/webhook"
+)
+
+type Outbox interface {
+	Enqueue(ctx context.Context, payload any) error
+}
+
+type Notifier struct {
+	outbox    Outbox
+	endpoints []webhook.Endpoint
+	site      string
+	now       func() time.Time
+}
+
+func NewNotifier(outbox Outbox, endpoints []webhook.Endpoint, site string) *Notifier {
+	return &Notifier{
+		outbox:    outbox,
+		endpoints: endpoints,
+		site:      site,
+		now:       time.Now,
+	}
+}
+
+func (n *Notifier) Notify(ctx context.Context, event audit.Event) {
+	if !webhook.Supported(event.Type) {
+		return
+	}
+
+	event.ID = newID()
+	event.Time = n.now().UTC()
+	event.Site = n.site
+	if event.CorrelationID == "" {
+		event.CorrelationID = audit.CorrelationID(ctx)
+	}
+
+	for _, e := range n.endpoints {
+		if !e.Subscribed(event.Type) {
+			continue
+		}
+		if err := n.outbox.Enqueue(ctx, webhook.Delivery{Endpoint: e.Name, Event: event}); err != nil {
+			log.Printf("ERROR: could not queue %s webhook for endpoint %s: %v", event.Type, e.Name, err)
+		}
+	}
+}
+
+func newID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
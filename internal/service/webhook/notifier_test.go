@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/audit"
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/webhook"
+)
+
+type mockOutbox struct {
+	deliveries []webhook.Delivery
+	err        error
+}
+
+func (m *mockOutbox) Enqueue(ctx context.Context, payload any) error {
+	m.deliveries = append(m.deliveries, payload.(webhook.Delivery))
+	return m.err
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	endpoints := []webhook.Endpoint{
+		{Name: "owner", Format: webhook.FormatJSON},
+		{Name: "slack", Format: webhook.FormatSlack, Events: []audit.EventType{audit.EventTokenRedeemed}},
+	}
+
+	tests := []struct {
+		name      string
+		event     audit.EventType
+		outboxErr error
+		want      []string
+	}{
+		{
+			name:  "every subscribed endpoint",
+			event: audit.EventTokenRedeemed,
+			want:  []string{"owner", "slack"},
+		},
+		{
+			name:  "filtered by endpoint events",
+			event: audit.EventClientLocked,
+			want:  []string{"owner"},
+		},
+		{
+			name:  "unsupported event",
+			event: audit.EventCaptchaVerified,
+		},
+		{
+			name:      "queue failure is not fatal",
+			event:     audit.EventTokenRedeemed,
+			outboxErr: errors.New("redis error"),
+			want:      []string{"owner", "slack"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outbox := &mockOutbox{err: tt.outboxErr}
+			n := NewNotifier(outbox, endpoints, "main")
+			n.now = func() time.Time { return now }
+
+			ctx := audit.WithCorrelationID(context.Background(), "req-1")
+			n.Notify(ctx, audit.Event{Type: tt.event, Lang: "pl"})
+
+			if len(outbox.deliveries) != len(tt.want) {
+				t.Fatalf("Notify() queued %d deliveries, want %d", len(outbox.deliveries), len(tt.want))
+			}
+			for i, d := range outbox.deliveries {
+				if d.Endpoint != tt.want[i] {
+					t.Errorf("Notify() endpoint = %s, want %s", d.Endpoint, tt.want[i])
+				}
+				e := d.Event
+				if e.ID == "" || e.ID != outbox.deliveries[0].Event.ID || e.Site != "main" || !e.Time.Equal(now) || e.CorrelationID != "req-1" || e.Lang != "pl" {
+					t.Errorf("Notify() event = %+v", e)
+				}
+			}
+		})
+	}
+}
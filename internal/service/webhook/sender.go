@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/webhook"
+)
+
+const userAgent = "adrianjanczenia.dev-content-service/webhook"
+
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{
+		client: &http.Client{Timeout: timeout},
+		now:    time.Now,
+	}
+}
+
+func (s *Sender) Send(ctx context.Context, endpoint webhook.Endpoint, id, eventType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(webhook.IDHeader, id)
+	req.Header.Set(webhook.EventHeader, eventType)
+	if len(endpoint.Secret) > 0 {
+		timestamp := s.now()
+		req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+		req.Header.Set(webhook.SignatureHeader, webhook.Sign(endpoint.Secret, timestamp, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook endpoint %s answered %s", endpoint.Name, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AdrianJanczenia/adrianjanczenia.dev_content-service/internal/logic/webhook"
+)
+
+func TestSender_Send(t *testing.T) {
+	now := time.Unix(1772370000, 0)
+	body := []byte(`{"id":"e1"}`)
+
+	tests := []struct {
+		name          string
+		secret        []byte
+		status        int
+		wantSignature string
+		wantErr       bool
+	}{
+		{
+			name:          "signed",
+			secret:        []byte("secret"),
+			status:        http.StatusNoContent,
+			wantSignature: webhook.Sign([]byte("secret"), now, body),
+		},
+		{
+			name:   "unsigned",
+			status: http.StatusOK,
+		},
+		{
+			name:    "endpoint error is retried",
+			status:  http.StatusBadGateway,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			var gotBody []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				gotBody, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			s := NewSender(time.Second)
+			s.now = func() time.Time { return now }
+			endpoint := webhook.Endpoint{Name: "owner", URL: server.URL, Secret: tt.secret}
+
+			err := s.Send(context.Background(), endpoint, "e1", "token_redeemed", body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Method != http.MethodPost || string(gotBody) != string(body) || got.Header.Get(webhook.IDHeader) != "e1" || got.Header.Get(webhook.EventHeader) != "token_redeemed" {
+				t.Errorf("Send() request = %s %s %v", got.Method, gotBody, got.Header)
+			}
+			if got.Header.Get(webhook.SignatureHeader) != tt.wantSignature {
+				t.Errorf("Send() signature = %q, want %q", got.Header.Get(webhook.SignatureHeader), tt.wantSignature)
+			}
+			if tt.wantSignature != "" && got.Header.Get(webhook.TimestampHeader) != "1772370000" {
+				t.Errorf("Send() timestamp = %q", got.Header.Get(webhook.TimestampHeader))
+			}
+		})
+	}
+}
@@ -21,6 +21,13 @@ func main() {
 	}
 	registry.Cfg = cfg
 
+	if len(os.Args) > 1 {
+		if err := app.RunCommand(context.Background(), registry.Cfg, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatalf("FATAL: %s: %v", os.Args[1], err)
+		}
+		return
+	}
+
 	application, err := app.Build(registry.Cfg)
 	if err != nil {
 		log.Fatalf("FATAL: could not build application: %v", err)
@@ -44,6 +51,18 @@ func main() {
 		}
 	}()
 
+	go func() {
+		if err := application.RunOutboxWorkers(); err != nil {
+			log.Printf("ERROR: outbox workers failed: %v", err)
+		}
+	}()
+
+	go func() {
+		if err := application.RunAuditJanitor(); err != nil {
+			log.Printf("ERROR: audit janitor failed: %v", err)
+		}
+	}()
+
 	shutdownChannel := make(chan os.Signal, 1)
 	signal.Notify(shutdownChannel, syscall.SIGINT, syscall.SIGTERM)
 	sig := <-shutdownChannel